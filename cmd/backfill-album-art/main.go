@@ -14,6 +14,14 @@ import (
 	"songshare/internal/services"
 )
 
+const (
+	// backfillPageSize is how many candidates are loaded at a time
+	backfillPageSize = 100
+
+	// backfillLogInterval is how often progress is logged, in songs
+	backfillLogInterval = 100
+)
+
 func main() {
 	// Load .env file for local development
 	_ = godotenv.Load()
@@ -46,8 +54,16 @@ func main() {
 	defer cache.Close()
 
 	// Initialize platform services
-	_ = services.NewSpotifyService(cfg.SpotifyClientID, cfg.SpotifyClientSecret, cache)
-	_ = services.NewAppleMusicService(cfg.AppleMusicKeyID, cfg.AppleMusicTeamID, cfg.AppleMusicKeyFile, cache)
+	spotifyService := services.NewSpotifyService(cfg.SpotifyClientID, cfg.SpotifyClientSecret, cache)
+	appleMusicService := services.NewAppleMusicService(cfg.AppleMusicKeyID, cfg.AppleMusicTeamID, cfg.AppleMusicKeyFile, cache)
+	for _, service := range []services.PlatformService{spotifyService, appleMusicService} {
+		platformConfig, _ := cfg.GetPlatformConfig(service.GetPlatformName())
+		services.ConfigureNegativeCache(service, platformConfig)
+		if err := services.ConfigurePlatformHTTP(service, platformConfig); err != nil {
+			slog.Error("Failed to configure platform HTTP client", "platform", service.GetPlatformName(), "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Initialize repository
 	songRepo := repositories.NewMongoSongRepository(db)
@@ -56,35 +72,32 @@ func main() {
 
 	slog.Info("Starting album art backfill process...")
 
-	// Get all songs from database
-	// Note: This is a simple implementation. For large datasets, you'd want to use pagination
-	count, err := songRepo.Count(ctx)
-	if err != nil {
-		slog.Error("Failed to count songs", "error", err)
-		os.Exit(1)
-	}
-
-	slog.Info("Found songs in database", "count", count)
-
-	// For this example, we'll process songs in batches
-	// In a real implementation, you might want to add pagination
-	limit := int(count)
-	if limit > 1000 {
-		limit = 1000 // Process in smaller batches for large datasets
-		slog.Info("Processing first batch only", "limit", limit)
-	}
-
-	// Get songs that might need backfill (this is a simplified approach)
-	// In a real implementation, you'd want to query specifically for songs missing album art
+	// Only songs without art that have links to fetch it from are candidates.
+	// Songs no platform has art for stay candidates, so pages are walked by
+	// _id cursor: each run visits every candidate once and then stops.
 	updated := 0
 	processed := 0
+	lastID := ""
+	for {
+		songs, err := songRepo.FindMissingAlbumArt(ctx, lastID, backfillPageSize)
+		if err != nil {
+			slog.Error("Failed to find songs missing album art", "after", lastID, "error", err)
+			os.Exit(1)
+		}
+		if len(songs) == 0 {
+			break
+		}
 
-	// Process songs one by one
-	for i := 0; i < int(count) && processed < limit; i++ {
-		// This is a simplified approach - normally you'd paginate through songs
-		// For demo purposes, we'll skip this implementation as it would require
-		// adding pagination methods to the repository
-		break
+		for _, song := range songs {
+			processed++
+			if backfillSongAlbumArt(ctx, song, spotifyService, appleMusicService, songRepo) {
+				updated++
+			}
+			if processed%backfillLogInterval == 0 {
+				slog.Info("Backfill progress", "processed", processed, "updated", updated, "cursor", song.ID.Hex())
+			}
+		}
+		lastID = songs[len(songs)-1].ID.Hex()
 	}
 
 	slog.Info("Album art backfill completed",
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"songshare/internal/cache"
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+	"songshare/internal/services"
+)
+
+const (
+	// enrichPageSize is how many missing-platform songs are fetched per query
+	enrichPageSize = 100
+
+	// enrichLookupTimeout bounds each platform ISRC lookup
+	enrichLookupTimeout = 10 * time.Second
+)
+
+// enrich-platforms adds links on one platform to every stored song that has an
+// ISRC but no link there yet, looking each ISRC up on the platform. Songs are
+// walked in catalog order, so an interrupted run can continue with
+// -resume-from set to the last resume_from ID it logged.
+func main() {
+	platform := flag.String("platform", "", "platform to add missing links for, e.g. tidal")
+	resumeFrom := flag.String("resume-from", "", "skip songs up to and including this song ID")
+	dryRun := flag.Bool("dry-run", false, "look links up without saving them")
+	concurrency := flag.Int("concurrency", 4, "songs looked up at once")
+	rate := flag.Float64("rate", 5, "platform lookups per second; 0 disables pacing")
+	flag.Parse()
+
+	// Load .env file for local development
+	_ = godotenv.Load()
+
+	// Initialize structured logging
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	if *platform == "" {
+		slog.Error("Missing -platform")
+		os.Exit(2)
+	}
+
+	var resumeID primitive.ObjectID
+	if *resumeFrom != "" {
+		id, err := primitive.ObjectIDFromHex(*resumeFrom)
+		if err != nil {
+			slog.Error("Invalid -resume-from song ID", "id", *resumeFrom, "error", err)
+			os.Exit(2)
+		}
+		resumeID = id
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize database
+	db, err := models.NewDatabase(context.Background(), cfg.MongodbURL, "songshare")
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close(context.Background())
+
+	// Initialize simple cache
+	cache, err := cache.NewSimpleCache(cfg.ValkeyURL)
+	if err != nil {
+		slog.Error("Failed to initialize cache", "error", err)
+		os.Exit(1)
+	}
+	defer cache.Close()
+
+	service, err := newPlatformService(cfg, *platform, cache)
+	if err != nil {
+		slog.Error("Failed to initialize platform service", "platform", *platform, "error", err)
+		os.Exit(1)
+	}
+
+	run := &enrichRun{
+		songRepo:    repositories.NewMongoSongRepository(db),
+		service:     service,
+		resumeFrom:  resumeID,
+		dryRun:      *dryRun,
+		pageSize:    enrichPageSize,
+		concurrency: *concurrency,
+		rate:        *rate,
+	}
+
+	slog.Info("Starting platform enrichment", "platform", *platform, "resume_from", *resumeFrom, "dry_run", *dryRun, "concurrency", *concurrency, "rate", *rate)
+
+	summary, err := run.run(context.Background())
+	if err != nil {
+		slog.Error("Platform enrichment failed", "error", err, "processed", summary.Processed, "resume_from", summary.ResumeFrom)
+		os.Exit(1)
+	}
+
+	slog.Info("Platform enrichment completed",
+		"platform", *platform,
+		"processed", summary.Processed,
+		"linked", summary.Linked,
+		"not_found", summary.NotFound,
+		"failed", summary.Failed,
+		"dry_run", *dryRun)
+
+	fmt.Println("Platform enrichment completed!")
+	fmt.Printf("Processed: %d songs\n", summary.Processed)
+	fmt.Printf("Linked: %d songs\n", summary.Linked)
+	fmt.Printf("Not found: %d songs\n", summary.NotFound)
+	fmt.Printf("Failed: %d songs\n", summary.Failed)
+}
+
+// newPlatformService builds the service for platform from the configuration
+func newPlatformService(cfg *config.Config, platform string, cache cache.Cache) (services.PlatformService, error) {
+	platformConfig, ok := cfg.GetPlatformConfig(platform)
+
+	switch platform {
+	case "spotify":
+		service := services.NewSpotifyService(cfg.SpotifyClientID, cfg.SpotifyClientSecret, cache)
+		services.ConfigureNegativeCache(service, platformConfig)
+		return service, services.ConfigurePlatformHTTP(service, platformConfig)
+	case "apple_music":
+		service := services.NewAppleMusicService(cfg.AppleMusicKeyID, cfg.AppleMusicTeamID, cfg.AppleMusicKeyFile, cache)
+		services.ConfigureNegativeCache(service, platformConfig)
+		return service, services.ConfigurePlatformHTTP(service, platformConfig)
+	}
+
+	if !ok || !platformConfig.Enabled {
+		return nil, fmt.Errorf("platform %s is not configured", platform)
+	}
+	switch platform {
+	case "tidal":
+		return services.NewTidalService(platformConfig, cache)
+	case "youtube_music":
+		return services.NewYouTubeMusicService(platformConfig)
+	case "deezer":
+		return services.NewDeezerService(platformConfig)
+	}
+	return nil, fmt.Errorf("unsupported platform %s", platform)
+}
+
+// enrichRun walks the songs missing a platform link and links those the
+// platform knows the ISRC of
+type enrichRun struct {
+	songRepo    repositories.SongRepository
+	service     services.PlatformService
+	resumeFrom  primitive.ObjectID // songs up to and including this ID are skipped
+	dryRun      bool
+	pageSize    int
+	concurrency int
+	rate        float64 // lookups per second; 0 disables pacing
+}
+
+// enrichSummary counts the outcomes of a run
+type enrichSummary struct {
+	Processed int
+	Linked    int
+	NotFound  int
+	Failed    int
+	// ResumeFrom is the last song of the last finished page
+	ResumeFrom string
+}
+
+// enrichOutcome is the result of enriching one song
+type enrichOutcome int
+
+const (
+	enrichLinked enrichOutcome = iota
+	enrichNotFound
+	enrichFailed
+)
+
+// run enriches every missing-platform song page by page, finishing each page
+// before fetching the next
+func (r *enrichRun) run(ctx context.Context) (enrichSummary, error) {
+	var summary enrichSummary
+	platform := r.service.GetPlatformName()
+
+	var pace <-chan time.Time
+	if r.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / r.rate))
+		defer ticker.Stop()
+		pace = ticker.C
+	}
+
+	// Pages continue after the last song seen, so songs linked meanwhile
+	// can't shift later songs out of the walk
+	afterID := ""
+	if !r.resumeFrom.IsZero() {
+		afterID = r.resumeFrom.Hex()
+	}
+	for {
+		songs, err := r.songRepo.FindMissingPlatform(ctx, platform, afterID, r.pageSize)
+		if err != nil {
+			return summary, fmt.Errorf("failed to find songs missing %s: %w", platform, err)
+		}
+
+		for _, outcome := range r.enrichPage(ctx, songs, pace) {
+			summary.Processed++
+			switch outcome {
+			case enrichLinked:
+				summary.Linked++
+			case enrichNotFound:
+				summary.NotFound++
+			case enrichFailed:
+				summary.Failed++
+			}
+		}
+
+		if len(songs) > 0 {
+			afterID = songs[len(songs)-1].ID.Hex()
+			summary.ResumeFrom = afterID
+		}
+		slog.Info("Enrichment progress", "processed", summary.Processed, "linked", summary.Linked, "resume_from", summary.ResumeFrom)
+
+		if len(songs) < r.pageSize {
+			return summary, nil
+		}
+	}
+}
+
+// enrichPage enriches songs on up to concurrency workers, returning each
+// song's outcome in order
+func (r *enrichRun) enrichPage(ctx context.Context, songs []*models.Song, pace <-chan time.Time) []enrichOutcome {
+	outcomes := make([]enrichOutcome, len(songs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < max(r.concurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if pace != nil {
+					<-pace
+				}
+				outcomes[j] = r.enrichSong(ctx, songs[j])
+			}
+		}()
+	}
+	for j := range songs {
+		jobs <- j
+	}
+	close(jobs)
+	wg.Wait()
+	return outcomes
+}
+
+// enrichSong looks the song's ISRC up on the platform and saves the link found
+func (r *enrichRun) enrichSong(ctx context.Context, song *models.Song) enrichOutcome {
+	platform := r.service.GetPlatformName()
+
+	lookupCtx, cancel := context.WithTimeout(services.WithISRCHint(ctx, song.Title, song.Artist), enrichLookupTimeout)
+	track := services.LookupISRCAllPlatforms(lookupCtx, song.ISRC, []services.PlatformService{r.service})[platform]
+	cancel()
+	if track == nil {
+		return enrichNotFound
+	}
+
+	if err := song.AddPlatformLink(platform, track.ExternalID, track.URL, track.LinkConfidence()); err != nil {
+		slog.Warn("Rejected platform link", "song_id", song.ID.Hex(), "platform", platform, "track_id", track.ExternalID, "error", err)
+		return enrichFailed
+	}
+	if r.dryRun {
+		slog.Info("Would link song", "song_id", song.ID.Hex(), "isrc", song.ISRC, "platform", platform, "url", track.URL)
+		return enrichLinked
+	}
+
+	song.RecomputePrimary(nil)
+	if err := r.songRepo.Update(ctx, song); err != nil {
+		slog.Error("Failed to save enriched song", "song_id", song.ID.Hex(), "error", err)
+		return enrichFailed
+	}
+	return enrichLinked
+}
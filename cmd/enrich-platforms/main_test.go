@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"songshare/internal/models"
+	"songshare/internal/testutil"
+)
+
+// missingTidalSong is a stored Spotify song with no Tidal link
+func missingTidalSong(id, isrc string) *models.Song {
+	return testutil.NewSongBuilder().
+		WithID(id).
+		WithISRC(isrc).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+}
+
+// expectTidalTrack makes tidal find a track for isrc
+func expectTidalTrack(tidal *testutil.MockPlatformService, isrc, trackID string) {
+	track := testutil.NewTrackInfoBuilder().
+		WithPlatform("tidal").
+		WithExternalID(trackID).
+		WithURL("https://tidal.com/browse/track/" + trackID).
+		WithISRC(isrc).
+		Build()
+	tidal.On("GetTrackByISRC", mock.Anything, isrc).Return(track, nil)
+}
+
+func TestEnrichRun_LinksFoundTracksAndPagesPastTheRest(t *testing.T) {
+	linked := missingTidalSong("64b7f0c2a1b2c3d4e5f60701", testutil.TestISRC1)
+	notFound := missingTidalSong("64b7f0c2a1b2c3d4e5f60702", testutil.TestISRC2)
+	last := missingTidalSong("64b7f0c2a1b2c3d4e5f60703", testutil.TestISRC3)
+
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindMissingPlatform", mock.Anything, "tidal", "", 2).Return([]*models.Song{linked, notFound}, nil)
+	// The next page continues after the last song seen, linked or not
+	repo.On("FindMissingPlatform", mock.Anything, "tidal", notFound.ID.Hex(), 2).Return([]*models.Song{last}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	tidal := testutil.NewMockPlatformService("tidal")
+	expectTidalTrack(tidal, testutil.TestISRC1, "77646168")
+	expectTidalTrack(tidal, testutil.TestISRC3, "77646169")
+	tidal.On("GetTrackByISRC", mock.Anything, testutil.TestISRC2).Return(nil, assert.AnError)
+
+	run := &enrichRun{songRepo: repo, service: tidal, pageSize: 2, concurrency: 2}
+	summary, err := run.run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, summary.Processed)
+	assert.Equal(t, 2, summary.Linked)
+	assert.Equal(t, 1, summary.NotFound)
+	assert.Equal(t, last.ID.Hex(), summary.ResumeFrom)
+	assert.True(t, linked.HasPlatform("tidal"))
+	assert.True(t, last.HasPlatform("tidal"))
+	assert.False(t, notFound.HasPlatform("tidal"))
+	repo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestEnrichRun_ResumeAndDryRun(t *testing.T) {
+	done := missingTidalSong("64b7f0c2a1b2c3d4e5f60701", testutil.TestISRC1)
+	pending := missingTidalSong("64b7f0c2a1b2c3d4e5f60702", testutil.TestISRC2)
+
+	repo := &testutil.MockSongRepository{}
+	// The query starts after the resume ID, so the done song never comes back
+	repo.On("FindMissingPlatform", mock.Anything, "tidal", done.ID.Hex(), 10).Return([]*models.Song{pending}, nil)
+
+	tidal := testutil.NewMockPlatformService("tidal")
+	expectTidalTrack(tidal, testutil.TestISRC2, "77646168")
+
+	resumeFrom, err := primitive.ObjectIDFromHex("64b7f0c2a1b2c3d4e5f60701")
+	require.NoError(t, err)
+	run := &enrichRun{songRepo: repo, service: tidal, resumeFrom: resumeFrom, dryRun: true, pageSize: 10, concurrency: 1}
+	summary, err := run.run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, summary.Processed)
+	assert.Equal(t, 1, summary.Linked)
+	tidal.AssertNotCalled(t, "GetTrackByISRC", mock.Anything, testutil.TestISRC1)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
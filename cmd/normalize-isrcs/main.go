@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/joho/godotenv"
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+)
+
+// normalize-isrcs rewrites stored ISRCs into canonical uppercase form, so
+// songs saved with lowercase or hyphenated codes are found by ISRC lookups.
+// Run the consistency check afterwards to catch songs that now share an ISRC.
+func main() {
+	// Load .env file for local development
+	_ = godotenv.Load()
+
+	// Initialize structured logging
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize database
+	db, err := models.NewDatabase(context.Background(), cfg.MongodbURL, "songshare")
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close(context.Background())
+
+	songRepo := repositories.NewMongoSongRepository(db)
+
+	slog.Info("Starting ISRC normalization...")
+
+	updated, skipped, err := songRepo.NormalizeISRCs(context.Background())
+	if err != nil {
+		slog.Error("ISRC normalization failed", "updated", updated, "skipped", skipped, "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("ISRC normalization completed", "updated", updated, "skipped", skipped)
+	fmt.Printf("Normalized ISRCs: %d songs updated, %d skipped as duplicates\n", updated, skipped)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"songshare/internal/metrics"
+)
+
+// instrumentedCache records the outcome of each Get and Set in the cache
+// operation metrics, labelled with the component using the cache
+type instrumentedCache struct {
+	Cache
+	source string
+}
+
+// Instrument wraps c so its gets and sets are counted under source (one of
+// the metrics.CacheSource values). A nil c stays nil.
+func Instrument(c Cache, source string) Cache {
+	if c == nil {
+		return nil
+	}
+	return &instrumentedCache{Cache: c, source: source}
+}
+
+// Get looks key up in the wrapped cache; a nil value without error is a miss
+func (c *instrumentedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.Cache.Get(ctx, key)
+	switch {
+	case err != nil:
+		metrics.RecordCacheOperation(c.source, "get", metrics.CacheError)
+	case data == nil:
+		metrics.RecordCacheOperation(c.source, "get", metrics.CacheMiss)
+	default:
+		metrics.RecordCacheOperation(c.source, "get", metrics.CacheHit)
+	}
+	return data, err
+}
+
+// Set stores value in the wrapped cache
+func (c *instrumentedCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	err := c.Cache.Set(ctx, key, value, expiration)
+	if err != nil {
+		metrics.RecordCacheOperation(c.source, "set", metrics.CacheError)
+	} else {
+		metrics.RecordCacheOperation(c.source, "set", metrics.CacheOK)
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrument(t *testing.T) {
+	assert.Nil(t, Instrument(nil, metrics.CacheSourcePlatform))
+
+	ctx := context.Background()
+	c := Instrument(NewMockCache(), metrics.CacheSourceSongRepository)
+	require.NoError(t, c.Set(ctx, "song:id:1", []byte("value"), time.Minute))
+
+	data, err := c.Get(ctx, "song:id:1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), data)
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	assert.Contains(t, body, `songshare_cache_operations_total{operation="get",result="hit",source="song_repository"}`)
+	assert.Contains(t, body, `songshare_cache_operations_total{operation="set",result="ok",source="song_repository"}`)
+}
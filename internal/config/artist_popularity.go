@@ -0,0 +1,26 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// LoadArtistPopularityOverrides reads a JSON object mapping artist names to
+// relevance points, e.g. {"Taylor Swift": 950}. A missing file yields no
+// overrides, leaving ranking to platform-reported popularity.
+func LoadArtistPopularityOverrides(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var overrides map[string]int
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
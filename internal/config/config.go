@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -25,7 +30,7 @@ type PlatformConfig struct {
 
 	// OAuth2 credentials (Spotify-style)
 	ClientID     string `json:"client_id,omitempty"`
-	ClientSecret string `json:"client_secret,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty" redact:"true"`
 	TokenURL     string `json:"token_url,omitempty"`
 
 	// JWT credentials (Apple Music-style)
@@ -34,18 +39,41 @@ type PlatformConfig struct {
 	KeyFile string `json:"key_file,omitempty"`
 
 	// API Key credentials (simple)
-	APIKey    string `json:"api_key,omitempty"`
-	APISecret string `json:"api_secret,omitempty"`
+	APIKey    string `json:"api_key,omitempty" redact:"true"`
+	APISecret string `json:"api_secret,omitempty" redact:"true"`
 
 	// Basic Auth credentials
 	Username string `json:"username,omitempty"`
-	Password string `json:"password,omitempty"`
+	Password string `json:"password,omitempty" redact:"true"`
 
 	// Additional configuration
 	BaseURL     string            `json:"base_url,omitempty"`
+	Country     string            `json:"country,omitempty"`    // default catalog region (ISO 3166-1 alpha-2)
 	RateLimit   int               `json:"rate_limit,omitempty"` // requests per minute
 	Timeout     int               `json:"timeout,omitempty"`    // seconds
-	ExtraConfig map[string]string `json:"extra_config,omitempty"`
+	ExtraConfig map[string]string `json:"extra_config,omitempty" redact:"true"`
+
+	// Outbound HTTP settings for the platform's API calls
+	ProxyURL string            `json:"proxy_url,omitempty" redact:"url"` // http, https or socks5 proxy
+	Headers  map[string]string `json:"headers,omitempty" redact:"true"`  // static headers added to every request
+
+	// Regions are the catalog regions (ISO 3166-1 alpha-2) the platform serves;
+	// requests for other regions fall back to the default. Empty uses the
+	// platform's built-in list.
+	Regions []string `json:"regions,omitempty"`
+
+	// NegativeCacheTTL is how long a "not found" track lookup is cached, in
+	// seconds; 0 uses the service default
+	NegativeCacheTTL int `json:"negative_cache_ttl,omitempty"`
+}
+
+// SearchConfig trades search responsiveness against completeness: a shorter
+// timeout returns sooner without slow platforms, and fewer concurrent
+// platform searches spare upstream quotas at the cost of latency. A
+// concurrency of 0 searches every platform at once.
+type SearchConfig struct {
+	PerPlatformTimeout     time.Duration `envconfig:"SEARCH_PLATFORM_TIMEOUT" default:"10s"`
+	MaxConcurrentPlatforms int           `envconfig:"SEARCH_MAX_CONCURRENCY" default:"0"`
 }
 
 // Config holds all configuration for the application
@@ -54,12 +82,15 @@ type Config struct {
 	Port       string `envconfig:"PORT" default:"8080"`
 	GinMode    string `envconfig:"GIN_MODE" default:"debug"`
 	BaseURL    string `envconfig:"BASE_URL" default:"http://localhost:8080"`
-	MongodbURL string `envconfig:"MONGODB_URL" required:"true"`
-	ValkeyURL  string `envconfig:"VALKEY_URL" required:"true"`
+	MongodbURL string `envconfig:"MONGODB_URL" required:"true" redact:"url"`
+	ValkeyURL  string `envconfig:"VALKEY_URL" required:"true" redact:"url"`
+
+	// Hosts whose Host header may replace BaseURL in generated links (multi-domain deployments)
+	AllowedHosts []string `envconfig:"ALLOWED_HOSTS"`
 
 	// Legacy platform credentials (for backward compatibility)
 	SpotifyClientID     string `envconfig:"SPOTIFY_CLIENT_ID"`
-	SpotifyClientSecret string `envconfig:"SPOTIFY_CLIENT_SECRET"`
+	SpotifyClientSecret string `envconfig:"SPOTIFY_CLIENT_SECRET" redact:"true"`
 	AppleMusicKeyID     string `envconfig:"APPLE_MUSIC_KEY_ID"`
 	AppleMusicTeamID    string `envconfig:"APPLE_MUSIC_TEAM_ID"`
 	AppleMusicKeyFile   string `envconfig:"APPLE_MUSIC_KEY_FILE"`
@@ -67,7 +98,144 @@ type Config struct {
 	// Tidal configuration
 	TidalEnabled      bool   `envconfig:"TIDAL_ENABLED" default:"false"`
 	TidalClientID     string `envconfig:"TIDAL_CLIENT_ID"`
-	TidalClientSecret string `envconfig:"TIDAL_CLIENT_SECRET"`
+	TidalClientSecret string `envconfig:"TIDAL_CLIENT_SECRET" redact:"true"`
+	TidalCountry      string `envconfig:"PLATFORM_TIDAL_COUNTRY" default:"US"` // Catalog region (ISO 3166-1 alpha-2)
+
+	// Search Tidal by title and artist when its ISRC filter finds nothing. Off by
+	// default: the match carries a different ISRC, so it is linked at reduced confidence.
+	TidalISRCSearchFallback bool `envconfig:"PLATFORM_TIDAL_ISRC_SEARCH_FALLBACK" default:"false"`
+
+	// Album art backfill pacing (token bucket, separate from platform rate limits)
+	BackfillRatePerSecond float64 `envconfig:"BACKFILL_RATE_PER_SECOND" default:"2"`
+	BackfillBurst         int     `envconfig:"BACKFILL_BURST" default:"5"`
+	BackfillMaxConcurrent int     `envconfig:"BACKFILL_MAX_CONCURRENT" default:"4"` // Backfills running at once; one per song
+
+	// Search result filtering
+	SearchMinPlatforms   int `envconfig:"SEARCH_MIN_PLATFORMS" default:"1"`    // Hide grouped songs on fewer platforms
+	SearchMinQueryLength int `envconfig:"SEARCH_MIN_QUERY_LENGTH" default:"2"` // Shorter normalized queries skip the platform fan-out
+
+	// Extra entity kinds platform searches return alongside tracks ("album", "artist");
+	// empty keeps searches track-only
+	SearchIncludeKinds []string `envconfig:"SEARCH_INCLUDE_KINDS"`
+
+	// Per-platform search query strategy overrides, e.g. "spotify:field_scoped,tidal:combined"
+	SearchQueryStrategies map[string]string `envconfig:"SEARCH_QUERY_STRATEGIES"`
+
+	// Platform search fan-out limits
+	SearchConfig
+
+	// Most tracks a resolved playlist or album collection keeps; longer playlists are truncated
+	CollectionMaxTracks int `envconfig:"COLLECTION_MAX_TRACKS" default:"5000"`
+
+	// Score (0-1) a title+artist match needs before a resolved track without an
+	// ISRC match is merged into an existing song instead of stored separately;
+	// above 1 disables these merges
+	CrossLinkMergeThreshold float64 `envconfig:"CROSS_LINK_MERGE_THRESHOLD" default:"0.9"`
+
+	// How long a failed platform health check keeps that platform out of searches;
+	// health is re-checked twice per TTL
+	PlatformHealthTTL time.Duration `envconfig:"PLATFORM_HEALTH_TTL" default:"30s"`
+
+	// Retention windows for in-memory data, enforced by the cleanup worker
+	SearchCacheTTL  time.Duration `envconfig:"SEARCH_CACHE_TTL" default:"5m"`
+	CleanupInterval time.Duration `envconfig:"CLEANUP_INTERVAL" default:"10m"`
+
+	// Cross-platform enrichment worker pool
+	EnrichmentWorkers   int    `envconfig:"ENRICHMENT_WORKERS" default:"4"`
+	EnrichmentQueueSize int    `envconfig:"ENRICHMENT_QUEUE_SIZE" default:"100"`
+	EnrichmentQueueMode string `envconfig:"ENRICHMENT_QUEUE_MODE" default:"drop"` // "drop" or "block" when the queue is full
+
+	// Platform artist images on artist pages; when disabled the most common album
+	// art among the artist's songs is used instead
+	ArtistImagesEnabled bool          `envconfig:"ARTIST_IMAGES_ENABLED" default:"false"`
+	ArtistImageTTL      time.Duration `envconfig:"ARTIST_IMAGE_TTL" default:"24h"`
+
+	// Optional third-party lyrics links, looked up during enrichment ("musixmatch");
+	// disabled unless both the provider and its API key are set
+	LyricsProvider string `envconfig:"LYRICS_PROVIDER"`
+	LyricsAPIKey   string `envconfig:"LYRICS_API_KEY" redact:"true"`
+
+	// Operator diagnostics (grouping diagnostics and /api/v1/debug endpoints)
+	DebugEnabled bool `envconfig:"DEBUG_ENABLED" default:"false"`
+
+	// Share of Spotify, Apple Music and Tidal API responses logged raw (with
+	// credentials redacted) to debug parsing problems; 0 disables sampling.
+	// With the header enabled, admin requests sending X-Debug-Raw-Responses
+	// have all of their platform responses logged.
+	RawResponseSampleRate  float64 `envconfig:"RAW_RESPONSE_SAMPLE_RATE" default:"0"`
+	RawResponseDebugHeader bool    `envconfig:"RAW_RESPONSE_DEBUG_HEADER" default:"false"`
+
+	// Crawler detection (comma-separated User-Agent substrings; empty uses built-in list)
+	BotUserAgents    []string      `envconfig:"BOT_USER_AGENTS"`
+	BotCacheMaxAge   time.Duration `envconfig:"BOT_CACHE_MAX_AGE" default:"1h"`
+	HumanCacheMaxAge time.Duration `envconfig:"HUMAN_CACHE_MAX_AGE" default:"5m"`
+
+	// Age after which resolving a stored link re-fetches it to pick up ISRC corrections
+	LinkRefreshAge time.Duration `envconfig:"LINK_REFRESH_AGE" default:"168h"`
+
+	// Songs whose links are all found delisted on re-fetch: retiring keeps their
+	// share page up as "no longer available"; excluding also drops them from search
+	RetireUnavailableSongs bool `envconfig:"RETIRE_UNAVAILABLE_SONGS" default:"true"`
+	SearchExcludeRetired   bool `envconfig:"SEARCH_EXCLUDE_RETIRED" default:"false"`
+
+	// Universal links (/s/:id) to ISRCs not in the catalog: resolve them live
+	// across platforms and save the song instead of answering 404, paced by a
+	// token bucket separate from the album art backfill's
+	SongLinkLiveResolve              bool    `envconfig:"SONG_LINK_LIVE_RESOLVE" default:"false"`
+	SongLinkLiveResolveRatePerSecond float64 `envconfig:"SONG_LINK_LIVE_RESOLVE_RATE_PER_SECOND" default:"1"`
+	SongLinkLiveResolveBurst         int     `envconfig:"SONG_LINK_LIVE_RESOLVE_BURST" default:"5"`
+
+	// Query parameters stripped from resolve URLs before lookup (comma-separated,
+	// "utm_*" style prefixes allowed); empty uses the built-in list
+	ResolveTrackingParams []string `envconfig:"RESOLVE_TRACKING_PARAMS"`
+
+	// How recently every link must have been verified for a song to show as verified
+	VerifiedMaxAge time.Duration `envconfig:"VERIFIED_MAX_AGE" default:"720h"`
+
+	// Retry queue for songs whose save fails during resolution
+	// Pending saves are persisted to Valkey and retried after a restart
+	SaveRetryQueueSize   int `envconfig:"SAVE_RETRY_QUEUE_SIZE" default:"1000"`
+	SaveRetryMaxAttempts int `envconfig:"SAVE_RETRY_MAX_ATTEMPTS" default:"10"`
+
+	// How often universal link view counts are written to the database; views
+	// from crawlers are not counted
+	ViewFlushInterval time.Duration `envconfig:"VIEW_FLUSH_INTERVAL" default:"30s"`
+
+	// Background check for documents sharing an ISRC; auto-merge folds them into the oldest
+	ConsistencyCheckInterval time.Duration `envconfig:"CONSISTENCY_CHECK_INTERVAL" default:"1h"`
+	ConsistencyAutoMerge     bool          `envconfig:"CONSISTENCY_AUTO_MERGE" default:"false"`
+
+	// Branding for the song and search pages (footer HTML is sanitized to basic inline tags)
+	ThemeSiteName     string `envconfig:"THEME_SITE_NAME" default:"SongShare"`
+	ThemePrimaryColor string `envconfig:"THEME_PRIMARY_COLOR"`
+	ThemeLogoURL      string `envconfig:"THEME_LOGO_URL"`
+	ThemeFooterHTML   string `envconfig:"THEME_FOOTER_HTML"`
+	ThemeOGImageURL   string `envconfig:"THEME_OG_IMAGE_URL"` // Link preview image for pages without album art
+
+	// Display caps for absurdly long titles and artist lists on pages; stored
+	// and API values are never shortened
+	DisplayMaxTitleLength int `envconfig:"DISPLAY_MAX_TITLE_LENGTH" default:"100"` // Characters before an ellipsis
+	DisplayMaxArtists     int `envconfig:"DISPLAY_MAX_ARTISTS" default:"3"`        // Further artists show as "+N more"
+
+	// Collections included in the admin db-stats breakdown (comma-separated)
+	AdminStatsCollections []string `envconfig:"ADMIN_STATS_COLLECTIONS" default:"songs,collections"`
+
+	// Album art proxy fetch limits; slower or larger upstream images get a placeholder
+	ArtProxyTimeout  time.Duration `envconfig:"ART_PROXY_TIMEOUT" default:"5s"`
+	ArtProxyMaxBytes int64         `envconfig:"ART_PROXY_MAX_BYTES" default:"5242880"`
+
+	// Access log: one structured line per request, except for the skipped paths
+	AccessLogLevel     string   `envconfig:"ACCESS_LOG_LEVEL" default:"info"`
+	AccessLogSkipPaths []string `envconfig:"ACCESS_LOG_SKIP_PATHS" default:"/health,/healthz,/metrics"`
+
+	// Bearer token for /api/v1/admin endpoints; admin endpoints are disabled when empty
+	AdminToken string `envconfig:"ADMIN_TOKEN" redact:"true"`
+
+	// JSON file of artist names to relevance points, curated by operators;
+	// without it artists get no boost beyond platform-reported popularity
+	ArtistPopularityFile string `envconfig:"ARTIST_POPULARITY_FILE"`
+	// Artist boosts read from ArtistPopularityFile
+	ArtistPopularityOverrides map[string]int `ignored:"true" json:"-"`
 
 	// Platform configurations (dynamically loaded)
 	Platforms map[string]*PlatformConfig `json:"-"`
@@ -94,9 +262,21 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to load dynamic platforms: %w", err)
 	}
 
+	if cfg.ArtistPopularityFile != "" {
+		overrides, err := LoadArtistPopularityOverrides(cfg.ArtistPopularityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load artist popularity overrides: %w", err)
+		}
+		cfg.ArtistPopularityOverrides = overrides
+	}
+
 	return &cfg, nil
 }
 
+// ExtraConfigISRCSearchFallback is the PlatformConfig.ExtraConfig key that
+// lets a platform search by title and artist when an ISRC lookup misses
+const ExtraConfigISRCSearchFallback = "isrc_search_fallback"
+
 // loadBuiltinPlatforms loads configuration for known platforms
 func (c *Config) loadBuiltinPlatforms() error {
 	// Spotify configuration
@@ -139,19 +319,65 @@ func (c *Config) loadBuiltinPlatforms() error {
 			ClientSecret: c.TidalClientSecret,
 			TokenURL:     "https://auth.tidal.com/v1/oauth2/token",
 			BaseURL:      "https://openapi.tidal.com/v2",
+			Country:      c.TidalCountry,
 			RateLimit:    100, // requests per minute
 			Timeout:      10,  // seconds
+			ExtraConfig: map[string]string{
+				ExtraConfigISRCSearchFallback: strconv.FormatBool(c.TidalISRCSearchFallback),
+			},
+		}
+	}
+
+	for name, platformConfig := range c.Platforms {
+		if err := loadPlatformOverridesFromEnvironment(platformConfig); err != nil {
+			return fmt.Errorf("invalid %s configuration: %w", name, err)
 		}
 	}
 
 	return nil
 }
 
+// loadPlatformOverridesFromEnvironment reads a builtin platform's outbound
+// proxy, extra headers, negative cache TTL and supported regions from
+// PLATFORM_<NAME>_PROXY_URL, PLATFORM_<NAME>_HEADERS,
+// PLATFORM_<NAME>_NEGATIVE_CACHE_TTL and PLATFORM_<NAME>_REGIONS
+func loadPlatformOverridesFromEnvironment(config *PlatformConfig) error {
+	var envConfig struct {
+		ProxyURL         string            `envconfig:"PROXY_URL"`
+		Headers          map[string]string `envconfig:"HEADERS"`
+		NegativeCacheTTL int               `envconfig:"NEGATIVE_CACHE_TTL"`
+		Regions          []string          `envconfig:"REGIONS"`
+	}
+	if err := envconfig.Process(fmt.Sprintf("PLATFORM_%s", strings.ToUpper(config.Name)), &envConfig); err != nil {
+		return err
+	}
+
+	config.ProxyURL = envConfig.ProxyURL
+	config.Headers = envConfig.Headers
+	config.NegativeCacheTTL = envConfig.NegativeCacheTTL
+	config.Regions = envConfig.Regions
+	return validatePlatformOverrides(config)
+}
+
+// dynamicPlatforms are configured only through PLATFORM_<NAME>_* variables
+// and stay off unless PLATFORM_<NAME>_ENABLED is set
+var dynamicPlatforms = []string{"youtube_music", "deezer"}
+
 // loadDynamicPlatforms loads platform configurations from environment variables
 // Format: PLATFORM_<PLATFORM_NAME>_<CONFIG_KEY>=value
 func (c *Config) loadDynamicPlatforms() error {
-	// This would be implemented to scan environment variables for platform configs
-	// For now, it's a placeholder for future extensibility
+	for _, name := range dynamicPlatforms {
+		if _, exists := c.Platforms[name]; exists {
+			continue
+		}
+		platformConfig, err := ConfigFromEnvironment(name)
+		if err != nil {
+			return fmt.Errorf("invalid %s configuration: %w", name, err)
+		}
+		if platformConfig != nil {
+			c.Platforms[name] = platformConfig
+		}
+	}
 	return nil
 }
 
@@ -203,9 +429,82 @@ func ValidatePlatformConfig(config *PlatformConfig) error {
 		return fmt.Errorf("base_url is required")
 	}
 
+	return validatePlatformOverrides(config)
+}
+
+// validatePlatformOverrides checks a platform's outbound proxy, extra headers
+// and negative cache TTL
+func validatePlatformOverrides(config *PlatformConfig) error {
+	if config.ProxyURL != "" {
+		if _, err := ParseProxyURL(config.ProxyURL); err != nil {
+			return err
+		}
+	}
+	for name := range config.Headers {
+		if !validHeaderName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+	if config.NegativeCacheTTL < 0 {
+		return fmt.Errorf("negative_cache_ttl cannot be negative")
+	}
+	for _, region := range config.Regions {
+		if !validRegionCode(strings.TrimSpace(region)) {
+			return fmt.Errorf("invalid region %q: must be a two-letter country code", region)
+		}
+	}
+	if config.Country != "" && len(config.Regions) > 0 && !slices.ContainsFunc(config.Regions, func(region string) bool {
+		return strings.EqualFold(strings.TrimSpace(region), strings.TrimSpace(config.Country))
+	}) {
+		return fmt.Errorf("country %q is not one of the supported regions", config.Country)
+	}
 	return nil
 }
 
+// validRegionCode accepts two-letter country codes in either case
+func validRegionCode(region string) bool {
+	if len(region) != 2 {
+		return false
+	}
+	for _, r := range region {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseProxyURL parses a platform proxy URL, which must be an absolute http,
+// https or socks5 URL with a host
+func ParseProxyURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url: %w", err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy_url %q: scheme must be http, https or socks5", redactURL(raw))
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid proxy_url %q: missing host", redactURL(raw))
+	}
+	return parsed, nil
+}
+
+// validHeaderName reports whether name is a non-empty HTTP header token
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return false
+		}
+	}
+	return true
+}
+
 // RegisterPlatformConfig registers a new platform configuration
 func (c *Config) RegisterPlatformConfig(platform string, config *PlatformConfig) error {
 	if err := ValidatePlatformConfig(config); err != nil {
@@ -279,8 +578,16 @@ func ConfigFromEnvironment(platformName string) (*PlatformConfig, error) {
 		Password string `envconfig:"PASSWORD"`
 
 		BaseURL   string `envconfig:"BASE_URL"`
+		Country   string `envconfig:"COUNTRY"`
 		RateLimit int    `envconfig:"RATE_LIMIT" default:"60"`
 		Timeout   int    `envconfig:"TIMEOUT" default:"10"`
+
+		ProxyURL string            `envconfig:"PROXY_URL"`
+		Headers  map[string]string `envconfig:"HEADERS"`
+
+		NegativeCacheTTL int `envconfig:"NEGATIVE_CACHE_TTL"`
+
+		Regions []string `envconfig:"REGIONS"`
 	}
 
 	if err := envconfig.Process(prefix, &envConfig); err != nil {
@@ -306,8 +613,15 @@ func ConfigFromEnvironment(platformName string) (*PlatformConfig, error) {
 		Username:     envConfig.Username,
 		Password:     envConfig.Password,
 		BaseURL:      envConfig.BaseURL,
+		Country:      envConfig.Country,
 		RateLimit:    envConfig.RateLimit,
 		Timeout:      envConfig.Timeout,
+		ProxyURL:     envConfig.ProxyURL,
+		Headers:      envConfig.Headers,
+
+		NegativeCacheTTL: envConfig.NegativeCacheTTL,
+
+		Regions: envConfig.Regions,
 	}
 
 	return config, ValidatePlatformConfig(config)
@@ -17,21 +17,56 @@ import (
 type RankingConfig struct {
 	// Scales how much raw popularity (0-100) contributes in the engine ranker
 	// Example: 0.8 means popularity contributes up to 80 points
-	RankerPopularityScale float64 `toml:"ranker_popularity_scale"`
+	RankerPopularityScale float64 `toml:"ranker_popularity_scale" json:"ranker_popularity_scale,omitempty"`
 
 	// Platform preference weights used as tertiary tiebreakers
-	PlatformWeights map[string]float64 `toml:"platform_weights"`
+	// The "local" weight applies to songs already in the local catalog
+	PlatformWeights map[string]float64 `toml:"platform_weights" json:"platform_weights,omitempty"`
+
+	// Maximum results kept per source before grouping, so a noisy platform's
+	// long tail can't crowd out a reliable one. Missing or 0 keeps them all.
+	PlatformResultCaps map[string]int `toml:"platform_result_caps" json:"platform_result_caps,omitempty"`
 
 	// Consider scores within this epsilon as ties, then break using popularity
-	TieEpsilon float64 `toml:"tie_epsilon"`
+	TieEpsilon float64 `toml:"tie_epsilon" json:"tie_epsilon,omitempty"`
+
+	// Adds platform-reported popularity (up to 35 points) to relevance scores.
+	// Off by default, so popularity only breaks ties between close matches.
+	PopularityBoostEnabled bool `toml:"popularity_boost_enabled" json:"popularity_boost_enabled,omitempty"`
 
 	// Multiplier applied to the scorer's popularity boost after thresholding
 	// 1.0 keeps default behavior; >1.0 increases popularity influence
-	PopularityBoostMultiplier float64 `toml:"popularity_boost_multiplier"`
+	PopularityBoostMultiplier float64 `toml:"popularity_boost_multiplier" json:"popularity_boost_multiplier,omitempty"`
+
+	// Multiplier applied to the contextual signals (recency and album art)
+	// 1.0 keeps default behavior; <1.0 lets platforms and popularity dominate
+	ContextMultiplier float64 `toml:"context_multiplier" json:"context_multiplier,omitempty"`
+
+	// Multiplier applied to the text match points (title and artist vs query)
+	// 1.0 keeps default behavior; >1.0 lets close title matches outrank availability
+	TextMatchMultiplier float64 `toml:"text_match_multiplier" json:"text_match_multiplier,omitempty"`
 
 	// Weights for aggregating popularity across platforms for the same ISRC
 	// Used by scorer when computing a single popularity from multiple platforms
-	PopularityPlatformWeights map[string]float64 `toml:"popularity_platform_weights"`
+	PopularityPlatformWeights map[string]float64 `toml:"popularity_platform_weights" json:"popularity_platform_weights,omitempty"`
+
+	// Half-life in years for decaying the popularity of old releases
+	// 0 disables decay; e.g. 20 halves a 20-year-old track's effective popularity
+	PopularityDecayHalfLifeYears float64 `toml:"popularity_decay_half_life_years" json:"popularity_decay_half_life_years,omitempty"`
+
+	// Display order of platform badges within a grouped song
+	// Empty keeps the built-in order (apple_music, spotify, tidal)
+	PlatformOrder []string `toml:"platform_order" json:"platform_order,omitempty"`
+
+	// Score added to songs already in the local catalog, which is a result
+	// source rather than a platform. Defaults to one platform's worth (100).
+	LibraryBoost int `toml:"library_boost" json:"library_boost,omitempty"`
+
+	// Order of the keys ("title", "artist", "album", "isrc", "platforms")
+	// that break ties left after popularity and platform weight. ISRC and the
+	// platform list always decide last so ties sort deterministically.
+	// Empty keeps the built-in order, which lists them all as above.
+	TieBreakOrder []string `toml:"tie_break_order" json:"tie_break_order,omitempty"`
 }
 
 // DefaultRankingConfig returns hard-coded safe defaults
@@ -46,11 +81,14 @@ func DefaultRankingConfig() *RankingConfig {
 		},
 		TieEpsilon:                2.5,
 		PopularityBoostMultiplier: 1.0,
+		ContextMultiplier:         1.0,
+		TextMatchMultiplier:       1.0,
 		PopularityPlatformWeights: map[string]float64{
 			"spotify":     1.0,
 			"tidal":       0.8,
 			"apple_music": 0.0,
 		},
+		LibraryBoost: 100,
 	}
 }
 
@@ -89,6 +127,16 @@ func GetRankingConfig() *RankingConfig {
 	return cfg
 }
 
+// SetRankingConfig replaces the active ranking config. Rankings are computed
+// per request, so the next search (including cached ones) uses the new weights.
+func SetRankingConfig(cfg *RankingConfig) {
+	// Consume the one-time load so it can't overwrite cfg later
+	rankingCfgOnce.Do(func() {})
+	rankingCfgMu.Lock()
+	rankingCfg = cfg
+	rankingCfgMu.Unlock()
+}
+
 func loadRankingConfigFromPath(path string) (*RankingConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -119,12 +167,29 @@ func mergeRankingConfig(base, override *RankingConfig) {
 			base.PlatformWeights[k] = v
 		}
 	}
+	if override.PlatformResultCaps != nil {
+		if base.PlatformResultCaps == nil {
+			base.PlatformResultCaps = map[string]int{}
+		}
+		for k, v := range override.PlatformResultCaps {
+			base.PlatformResultCaps[k] = v
+		}
+	}
 	if override.TieEpsilon > 0 {
 		base.TieEpsilon = override.TieEpsilon
 	}
+	if override.PopularityBoostEnabled {
+		base.PopularityBoostEnabled = true
+	}
 	if override.PopularityBoostMultiplier > 0 {
 		base.PopularityBoostMultiplier = override.PopularityBoostMultiplier
 	}
+	if override.ContextMultiplier > 0 {
+		base.ContextMultiplier = override.ContextMultiplier
+	}
+	if override.TextMatchMultiplier > 0 {
+		base.TextMatchMultiplier = override.TextMatchMultiplier
+	}
 	if override.PopularityPlatformWeights != nil {
 		if base.PopularityPlatformWeights == nil {
 			base.PopularityPlatformWeights = map[string]float64{}
@@ -133,6 +198,27 @@ func mergeRankingConfig(base, override *RankingConfig) {
 			base.PopularityPlatformWeights[k] = v
 		}
 	}
+	if override.PopularityDecayHalfLifeYears > 0 {
+		base.PopularityDecayHalfLifeYears = override.PopularityDecayHalfLifeYears
+	}
+	if len(override.PlatformOrder) > 0 {
+		base.PlatformOrder = append([]string(nil), override.PlatformOrder...)
+	}
+	if override.LibraryBoost > 0 {
+		base.LibraryBoost = override.LibraryBoost
+	}
+	if len(override.TieBreakOrder) > 0 {
+		base.TieBreakOrder = append([]string(nil), override.TieBreakOrder...)
+	}
+}
+
+// WithOverrides returns a copy of c with override merged on top, leaving c untouched.
+// As with ranking.toml, zero values in override keep the value from c.
+func (c *RankingConfig) WithOverrides(override *RankingConfig) *RankingConfig {
+	merged := &RankingConfig{}
+	mergeRankingConfig(merged, c)
+	mergeRankingConfig(merged, override)
+	return merged
 }
 
 // candidateRankingConfigPaths returns common locations to auto-discover ranking config
@@ -206,9 +292,7 @@ func StartRankingConfigWatcher(ctx context.Context, interval time.Duration) {
 						// Merge over defaults to keep unspecified keys sane
 						newCfg := DefaultRankingConfig()
 						mergeRankingConfig(newCfg, fileCfg)
-						rankingCfgMu.Lock()
-						rankingCfg = newCfg
-						rankingCfgMu.Unlock()
+						SetRankingConfig(newCfg)
 						lastModTime = fi.ModTime()
 						slog.Info("ranking config reloaded", "path", watchPath, "mtime", lastModTime)
 					}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID in from an edge proxy and back out to the client
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key holding the request ID
+const requestIDKey = "request_id"
+
+// maxRequestIDLength bounds client-supplied request IDs so they can't bloat logs
+const maxRequestIDLength = 128
+
+// AccessLog logs one structured line per request with method, path, status,
+// latency, client IP, request ID and response size. Query strings and bodies
+// are never logged, since they carry user search terms and submitted URLs.
+// Paths in skipPaths (e.g. "/health") are not logged. A nil logger uses slog's default.
+// The request ID is also put on the request context, so platform services can
+// include it in their logs.
+func AccessLog(logger *slog.Logger, level slog.Level, skipPaths []string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		if path = strings.TrimSpace(path); path != "" {
+			skip[path] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(services.WithRequestID(c.Request.Context(), requestID))
+
+		start := time.Now()
+		c.Next()
+
+		path := c.Request.URL.Path
+		if skip[path] {
+			return
+		}
+
+		log := logger
+		if log == nil {
+			log = slog.Default()
+		}
+		log.LogAttrs(c.Request.Context(), level, "HTTP request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("request_id", requestID),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+		)
+	}
+}
+
+// ParseLogLevel parses a level name ("debug", "info", "warn", "error"),
+// falling back to info for empty or unknown names
+func ParseLogLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// validRequestID accepts client-supplied IDs made of printable ASCII within the length limit
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 16-byte hex ID
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccessLogRouter(buf *bytes.Buffer, level slog.Level) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	router := gin.New()
+	router.Use(AccessLog(logger, level, []string{"/health", "/metrics"}))
+	router.POST("/api/v1/songs/resolve", func(c *gin.Context) {
+		c.String(http.StatusCreated, "created")
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestAccessLog_LogsRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(&buf, slog.LevelInfo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/songs/resolve?q=secret-query", strings.NewReader(`{"url":"https://open.spotify.com/track/private"}`))
+	req.Header.Set(RequestIDHeader, "req-123")
+	req.RemoteAddr = "203.0.113.7:54321"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-123", w.Header().Get(RequestIDHeader))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry), buf.String())
+	assert.Equal(t, "HTTP request", entry["msg"])
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "POST", entry["method"])
+	assert.Equal(t, "/api/v1/songs/resolve", entry["path"])
+	assert.Equal(t, float64(http.StatusCreated), entry["status"])
+	assert.Contains(t, entry, "latency")
+	assert.Equal(t, "203.0.113.7", entry["client_ip"])
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, float64(len("created")), entry["bytes"])
+
+	// Neither the query string nor the body is logged
+	assert.NotContains(t, buf.String(), "secret-query")
+	assert.NotContains(t, buf.String(), "private")
+}
+
+func TestAccessLog_SkipListAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(&buf, slog.LevelInfo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Empty(t, buf.String())
+	assert.Len(t, w.Header().Get(RequestIDHeader), 32, "a request ID is generated when none is supplied")
+
+	// Below the handler's level nothing is written
+	buf.Reset()
+	router = newAccessLogRouter(&buf, slog.LevelDebug)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/songs/resolve", nil))
+	assert.Empty(t, buf.String())
+}
+
+func TestAccessLog_RequestIDOnContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AccessLog(slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)), slog.LevelInfo, nil))
+	var seen string
+	router.GET("/api/v1/search", func(c *gin.Context) {
+		seen = services.RequestIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/search", nil))
+
+	assert.NotEmpty(t, seen)
+	assert.Equal(t, w.Header().Get(RequestIDHeader), seen)
+}
+
+func TestParseLogLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, ParseLogLevel("debug"))
+	assert.Equal(t, slog.LevelWarn, ParseLogLevel("WARN"))
+	assert.Equal(t, slog.LevelInfo, ParseLogLevel(""))
+	assert.Equal(t, slog.LevelInfo, ParseLogLevel("verbose"))
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"songshare/internal/config"
 	"songshare/internal/repositories"
 )
 
@@ -17,13 +19,41 @@ import (
 type AdminHandler struct {
 	songRepository repositories.SongRepository
 	mongoClient    *mongo.Client
+	config         *config.Config
+	consistency    *consistencyChecker
+
+	// statsCollections limits the db-stats collection breakdown, so a shared
+	// database doesn't expose other applications' collections
+	statsCollections []string
 }
 
+// defaultStatsCollections are the collections this app owns
+var defaultStatsCollections = []string{"songs", "collections"}
+
 // NewAdminHandler creates a new admin handler
 func NewAdminHandler(songRepository repositories.SongRepository, mongoClient *mongo.Client) *AdminHandler {
 	return &AdminHandler{
 		songRepository: songRepository,
 		mongoClient:    mongoClient,
+		consistency:    &consistencyChecker{interval: defaultConsistencyCheckInterval},
+
+		statsCollections: defaultStatsCollections,
+	}
+}
+
+// ApplyConfig gives the handler the loaded configuration for the config endpoint
+// and applies the consistency checker settings
+func (h *AdminHandler) ApplyConfig(cfg *config.Config) {
+	h.config = cfg
+	if cfg == nil {
+		return
+	}
+	if cfg.ConsistencyCheckInterval > 0 {
+		h.consistency.interval = cfg.ConsistencyCheckInterval
+	}
+	h.consistency.autoMerge = cfg.ConsistencyAutoMerge
+	if len(cfg.AdminStatsCollections) > 0 {
+		h.statsCollections = cfg.AdminStatsCollections
 	}
 }
 
@@ -83,6 +113,25 @@ func (h *AdminHandler) GetDatabaseStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// GetConfig handles GET /api/v1/admin/config
+// Returns the effective configuration with secrets redacted.
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	if h.config == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Configuration not loaded",
+		})
+		return
+	}
+
+	enabledPlatforms := h.config.GetEnabledPlatforms()
+	sort.Strings(enabledPlatforms)
+
+	c.JSON(http.StatusOK, gin.H{
+		"config":            h.config.Redacted(),
+		"enabled_platforms": enabledPlatforms,
+	})
+}
+
 // GetDatabaseStatsPage handles GET /admin/db-stats (HTML page)
 func (h *AdminHandler) GetDatabaseStatsPage(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
@@ -271,7 +320,7 @@ func (h *AdminHandler) collectDatabaseStats(ctx context.Context) (*DatabaseStats
 
 	// Get database stats
 	var dbStats bson.M
-	err := database.RunCommand(ctx, bson.D{{"dbStats", 1}}).Decode(&dbStats)
+	err := database.RunCommand(ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&dbStats)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database stats: %w", err)
 	}
@@ -301,9 +350,9 @@ func (h *AdminHandler) collectDatabaseStats(ctx context.Context) (*DatabaseStats
 		return nil, fmt.Errorf("failed to list collections: %w", err)
 	}
 
-	for _, collName := range collections {
+	for _, collName := range allowedStatsCollections(collections, h.statsCollections) {
 		var collStats bson.M
-		err := database.RunCommand(ctx, bson.D{{"collStats", collName}}).Decode(&collStats)
+		err := database.RunCommand(ctx, bson.D{{Key: "collStats", Value: collName}}).Decode(&collStats)
 		if err != nil {
 			slog.Warn("Failed to get collection stats", "collection", collName, "error", err)
 			continue
@@ -358,6 +407,24 @@ func (h *AdminHandler) collectDatabaseStats(ctx context.Context) (*DatabaseStats
 	return stats, nil
 }
 
+// allowedStatsCollections returns the existing collections that are on the
+// allow-list, in allow-list order
+func allowedStatsCollections(existing, allowed []string) []string {
+	exists := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		exists[name] = true
+	}
+
+	names := make([]string, 0, len(allowed))
+	for _, name := range allowed {
+		if exists[name] {
+			names = append(names, name)
+			delete(exists, name) // Report each collection once
+		}
+	}
+	return names
+}
+
 // getRecentActivity retrieves recent song additions
 func (h *AdminHandler) getRecentActivity(ctx context.Context) ([]RecentSong, error) {
 	// This would need to be implemented based on your Song model
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"songshare/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Default album art proxy limits, matching the config defaults
+const (
+	defaultArtProxyTimeout  = 5 * time.Second
+	defaultArtProxyMaxBytes = 5 << 20 // 5 MiB
+)
+
+// defaultArtProxyHosts are the platform image CDNs the proxy may fetch from;
+// subdomains of each host are allowed too
+var defaultArtProxyHosts = []string{"scdn.co", "mzstatic.com", "resources.tidal.com"}
+
+// artProxyContentTypes are the image types the proxy will pass through
+var artProxyContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
+// artPlaceholderSVG is served whenever the upstream image can't be used
+const artPlaceholderSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="400" viewBox="0 0 400 400">` +
+	`<rect width="400" height="400" fill="#f7fafc"/>` +
+	`<text x="200" y="230" font-size="120" text-anchor="middle" fill="#a0aec0">&#9835;</text></svg>`
+
+// Cache lifetimes for proxied art and for the placeholder (kept short so a
+// transient upstream failure isn't cached for long)
+const (
+	artProxyCacheMaxAge  = 24 * time.Hour
+	artPlaceholderMaxAge = 5 * time.Minute
+)
+
+const (
+	artProxyMaxRedirects = 3
+	artProxyUserAgent    = "SongShare-ArtProxy/1.0"
+)
+
+// errArtRejected marks upstream responses the proxy refuses to serve
+var errArtRejected = errors.New("album art rejected")
+
+// ArtProxy serves platform album art from our own origin, so share pages don't
+// leak visitors to third-party CDNs. Fetches are bounded in time and size.
+type ArtProxy struct {
+	client       *http.Client
+	timeout      time.Duration
+	maxBytes     int64
+	allowedHosts []string
+}
+
+// NewArtProxy creates an album art proxy with the default limits
+func NewArtProxy() *ArtProxy {
+	proxy := &ArtProxy{
+		timeout:      defaultArtProxyTimeout,
+		maxBytes:     defaultArtProxyMaxBytes,
+		allowedHosts: defaultArtProxyHosts,
+	}
+	proxy.client = &http.Client{CheckRedirect: proxy.checkRedirect}
+	return proxy
+}
+
+// ApplyConfig applies the operator-tunable fetch limits
+func (p *ArtProxy) ApplyConfig(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	if cfg.ArtProxyTimeout > 0 {
+		p.timeout = cfg.ArtProxyTimeout
+	}
+	if cfg.ArtProxyMaxBytes > 0 {
+		p.maxBytes = cfg.ArtProxyMaxBytes
+	}
+}
+
+// ServeArt handles GET /api/v1/art?url=<image URL>
+// Disallowed, failed, slow, oversized or non-image fetches get the placeholder.
+func (p *ArtProxy) ServeArt(c *gin.Context) {
+	target, err := p.parseTarget(c.Query("url"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid image URL",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	data, contentType, err := p.fetch(c.Request.Context(), target)
+	if err != nil {
+		slog.Warn("Serving album art placeholder", "url", target.String(), "error", err)
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(artPlaceholderMaxAge.Seconds())))
+		c.Data(http.StatusOK, "image/svg+xml", []byte(artPlaceholderSVG))
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(artProxyCacheMaxAge.Seconds())))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// parseTarget validates the requested image URL against the host allow-list
+func (p *ArtProxy) parseTarget(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, errors.New("url parameter is required")
+	}
+	target, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+	if target.Scheme != "https" {
+		return nil, errors.New("only https image URLs are allowed")
+	}
+	if !p.isAllowedHost(target.Hostname()) {
+		return nil, fmt.Errorf("host %q is not allowed", target.Hostname())
+	}
+	return target, nil
+}
+
+// isAllowedHost matches host, or any of its parent domains, against the allow-list
+func (p *ArtProxy) isAllowedHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range p.allowedHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRedirect keeps redirects on allow-listed hosts
+func (p *ArtProxy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= artProxyMaxRedirects {
+		return fmt.Errorf("%w: too many redirects", errArtRejected)
+	}
+	if req.URL.Scheme != "https" || !p.isAllowedHost(req.URL.Hostname()) {
+		return fmt.Errorf("%w: redirect to %s", errArtRejected, req.URL.Host)
+	}
+	return nil
+}
+
+// fetch downloads the image within the configured timeout and size cap and
+// returns it with its verified content type
+func (p *ArtProxy) fetch(ctx context.Context, target *url.URL) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", artProxyUserAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%w: upstream returned status %d", errArtRejected, resp.StatusCode)
+	}
+	if resp.ContentLength > p.maxBytes {
+		return nil, "", fmt.Errorf("%w: content length %d exceeds %d bytes", errArtRejected, resp.ContentLength, p.maxBytes)
+	}
+
+	// Read one byte past the cap so an oversized body without a Content-Length is caught
+	data, err := io.ReadAll(io.LimitReader(resp.Body, p.maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > p.maxBytes {
+		return nil, "", fmt.Errorf("%w: image exceeds %d bytes", errArtRejected, p.maxBytes)
+	}
+
+	// Both the declared type and the sniffed bytes must be a known image type;
+	// the sniffed type is what gets served
+	declared := strings.ToLower(strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0]))
+	sniffed := http.DetectContentType(data)
+	if !artProxyContentTypes[declared] || !artProxyContentTypes[sniffed] {
+		return nil, "", fmt.Errorf("%w: content type %q (sniffed %q)", errArtRejected, declared, sniffed)
+	}
+
+	return data, sniffed, nil
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPNG is a PNG signature followed by filler, enough for content sniffing
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+// newTestArtProxy returns a proxy that trusts the given TLS test server
+func newTestArtProxy(server *httptest.Server) *ArtProxy {
+	proxy := NewArtProxy()
+	proxy.client = server.Client()
+	proxy.client.CheckRedirect = proxy.checkRedirect
+	proxy.allowedHosts = []string{"127.0.0.1"}
+	return proxy
+}
+
+func performArtRequest(proxy *ArtProxy, imageURL string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/art", proxy.ServeArt)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/art?url="+url.QueryEscape(imageURL), nil))
+	return w
+}
+
+func assertPlaceholder(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	assert.Equal(t, artPlaceholderSVG, w.Body.String())
+}
+
+func TestArtProxy_ServesImage(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(testPNG)
+	}))
+	defer server.Close()
+
+	w := performArtRequest(newTestArtProxy(server), server.URL+"/cover.png")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, testPNG, w.Body.Bytes())
+}
+
+func TestArtProxy_OversizedUpstream(t *testing.T) {
+	oversized := append(append([]byte{}, testPNG...), bytes.Repeat([]byte{0}, 1024)...)
+
+	t.Run("Declared length", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(oversized)
+		}))
+		defer server.Close()
+
+		proxy := newTestArtProxy(server)
+		proxy.ApplyConfig(&config.Config{ArtProxyMaxBytes: 512})
+		assertPlaceholder(t, performArtRequest(proxy, server.URL+"/huge.png"))
+	})
+
+	t.Run("Streamed without length", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			for i := 0; i < len(oversized); i += 100 {
+				end := min(i+100, len(oversized))
+				_, _ = w.Write(oversized[i:end])
+				w.(http.Flusher).Flush()
+			}
+		}))
+		defer server.Close()
+
+		proxy := newTestArtProxy(server)
+		proxy.ApplyConfig(&config.Config{ArtProxyMaxBytes: 512})
+		assertPlaceholder(t, performArtRequest(proxy, server.URL+"/huge.png"))
+	})
+}
+
+func TestArtProxy_SlowUpstream(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(testPNG)
+	}))
+	defer server.Close()
+
+	proxy := newTestArtProxy(server)
+	proxy.ApplyConfig(&config.Config{ArtProxyTimeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	assertPlaceholder(t, performArtRequest(proxy, server.URL+"/slow.png"))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestArtProxy_RejectsNonImageContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+	}{
+		{name: "HTML declared", contentType: "text/html", body: []byte("<html><script>alert(1)</script></html>")},
+		{name: "SVG declared", contentType: "image/svg+xml", body: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)},
+		{name: "Image declared but HTML body", contentType: "image/png", body: []byte("<html></html>")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			assertPlaceholder(t, performArtRequest(newTestArtProxy(server), server.URL+"/cover"))
+		})
+	}
+}
+
+func TestArtProxy_RejectsDisallowedURLs(t *testing.T) {
+	proxy := NewArtProxy()
+
+	for _, imageURL := range []string{
+		"",
+		"http://i.scdn.co/image/abc",
+		"https://evil.example.com/image.png",
+		"https://scdn.co.evil.example/image.png",
+	} {
+		w := performArtRequest(proxy, imageURL)
+		assert.Equal(t, http.StatusBadRequest, w.Code, imageURL)
+	}
+
+	assert.True(t, proxy.isAllowedHost("i.scdn.co"))
+	assert.True(t, proxy.isAllowedHost("is1-ssl.mzstatic.com"))
+	assert.True(t, proxy.isAllowedHost("resources.tidal.com"))
+}
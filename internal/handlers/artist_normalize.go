@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"sync"
+
+	"songshare/internal/models"
+)
+
+// maxArtistCacheEntries bounds the normalization cache so arbitrary search
+// input cannot grow it without limit
+const maxArtistCacheEntries = 10000
+
+// artistNormalizer memoizes normalized artist names used in hot loops
+// (grouping keys, popularity lookups)
+type artistNormalizer struct {
+	mu    sync.RWMutex
+	cache map[string]string
+}
+
+var defaultArtistNormalizer = &artistNormalizer{
+	cache: make(map[string]string),
+}
+
+// normalizeArtist returns a comparison key for an artist name: lowercased,
+// diacritics and punctuation removed, whitespace collapsed and a leading
+// "the" dropped, so "The Beatles" and "Beatles" compare equal
+func normalizeArtist(name string) string {
+	return defaultArtistNormalizer.normalize(name)
+}
+
+func (n *artistNormalizer) normalize(name string) string {
+	n.mu.RLock()
+	normalized, exists := n.cache[name]
+	n.mu.RUnlock()
+	if exists {
+		return normalized
+	}
+
+	normalized = computeArtistKey(name)
+
+	n.mu.Lock()
+	if len(n.cache) >= maxArtistCacheEntries {
+		// Reset rather than track recency; the working set rebuilds quickly
+		n.cache = make(map[string]string)
+	}
+	n.cache[name] = normalized
+	n.mu.Unlock()
+
+	return normalized
+}
+
+// computeArtistKey performs the uncached normalization
+func computeArtistKey(name string) string {
+	return models.NormalizeSearchText(name)
+}
+
+// normalizeArtistPopularity keys configured artist boosts by normalizeArtist,
+// so "The Weeknd" in the file matches "Weeknd" in results
+func normalizeArtistPopularity(overrides map[string]int) map[string]int {
+	if len(overrides) == 0 {
+		return nil
+	}
+	normalized := make(map[string]int, len(overrides))
+	for artist, score := range overrides {
+		key := normalizeArtist(artist)
+		if existing, ok := normalized[key]; !ok || score > existing {
+			normalized[key] = score
+		}
+	}
+	return normalized
+}
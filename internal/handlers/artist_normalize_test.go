@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"testing"
+
+	"songshare/internal/handlers/render"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeArtist(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Lowercases and trims", input: "  Taylor Swift ", expected: "taylor swift"},
+		{name: "Strips leading The", input: "The Beatles", expected: "beatles"},
+		{name: "Keeps The as only word", input: "The", expected: "the"},
+		{name: "Keeps inner the", input: "Florence and the Machine", expected: "florence and the machine"},
+		{name: "Folds diacritics", input: "Beyoncé", expected: "beyonce"},
+		{name: "Folds multiple diacritics", input: "Sigur Rós", expected: "sigur ros"},
+		{name: "Strips punctuation", input: "AC/DC", expected: "ac dc"},
+		{name: "Strips dots", input: "R.E.M.", expected: "r e m"},
+		{name: "Ampersand becomes and", input: "Simon & Garfunkel", expected: "simon and garfunkel"},
+		{name: "Collapses whitespace", input: "Dua   Lipa", expected: "dua lipa"},
+		{name: "Empty string", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeArtist(tt.input))
+		})
+	}
+}
+
+func TestNormalizeArtist_CacheReset(t *testing.T) {
+	n := &artistNormalizer{cache: make(map[string]string)}
+	for i := 0; i < maxArtistCacheEntries; i++ {
+		n.cache[string(rune(i))] = ""
+	}
+
+	assert.Equal(t, "beatles", n.normalize("The Beatles"))
+	assert.Len(t, n.cache, 1)
+}
+
+func TestArtistPopularityScore_NormalizesNames(t *testing.T) {
+	h := &SongHandler{}
+	assert.Equal(t, 0, h.artistPopularityScore([]string{"The Weeknd"}), "no boosts without configured overrides")
+
+	h.artistPopularity = normalizeArtistPopularity(map[string]int{"The Weeknd": 700, "Taylor Swift": 950})
+
+	assert.Equal(t, 700, h.artistPopularityScore([]string{"The Weeknd"}))
+	assert.Equal(t, 700, h.artistPopularityScore([]string{"Weeknd"}))
+	assert.Equal(t, 950, h.artistPopularityScore([]string{"TAYLOR SWIFT!"}))
+	assert.Equal(t, 0, h.artistPopularityScore([]string{"Unknown Artist"}))
+}
+
+func TestGroupSongsByISRC_GroupsNormalizedArtists(t *testing.T) {
+	h := &SongHandler{}
+	results := map[string][]render.SearchResult{
+		"spotify": {
+			{Title: "Let It Be", Artists: []string{"The Beatles"}, Platform: "spotify"},
+		},
+		"apple_music": {
+			{Title: "Let It Be", Artists: []string{"Beatles"}, Platform: "apple_music"},
+		},
+	}
+
+	grouped := h.groupSongsByISRC(results)
+
+	assert.Len(t, grouped, 1)
+	assert.Len(t, grouped[0].Platforms, 2)
+}
+
+func BenchmarkNormalizeArtist(b *testing.B) {
+	artists := []string{"The Beatles", "Beyoncé", "AC/DC", "Simon & Garfunkel"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = normalizeArtist(artists[i%len(artists)])
+	}
+}
+
+func BenchmarkComputeArtistKey(b *testing.B) {
+	artists := []string{"The Beatles", "Beyoncé", "AC/DC", "Simon & Garfunkel"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = computeArtistKey(artists[i%len(artists)])
+	}
+}
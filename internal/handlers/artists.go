@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"songshare/internal/handlers/render"
+	"songshare/internal/models"
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page size limits for the artist songs listing
+const (
+	defaultArtistSongsLimit = 20
+	maxArtistSongsLimit     = 100
+)
+
+// Artist image defaults; the TTL matches the config default
+const (
+	defaultArtistImageTTL      = 24 * time.Hour
+	maxArtistImageCacheEntries = 10000
+	artistImageLookupTimeout   = 5 * time.Second
+)
+
+// ArtistSongsResponse lists the catalog songs by one artist
+type ArtistSongsResponse struct {
+	Artist   string                       `json:"artist"`
+	ImageURL string                       `json:"image_url,omitempty"` // Representative artist image
+	Songs    []render.ResolveSongResponse `json:"songs"`
+}
+
+// GetArtistSongs handles GET /api/v1/artists/:name/songs
+// Artist names match regardless of case, diacritics and a leading "The".
+func (h *SongHandler) GetArtistSongs(c *gin.Context) {
+	name := strings.TrimSpace(c.Param("name"))
+	artistKey := normalizeArtist(name)
+	if artistKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing artist name"})
+		return
+	}
+
+	limit := defaultArtistSongsLimit
+	if parsedLimit, err := strconv.Atoi(c.Query("limit")); err == nil && parsedLimit > 0 && parsedLimit <= maxArtistSongsLimit {
+		limit = parsedLimit
+	}
+
+	// Search also matches titles and albums, so over-fetch and keep the artist's songs
+	candidates, err := h.songRepository.Search(c.Request.Context(), name, maxArtistSongsLimit)
+	if err != nil {
+		slog.Error("Failed to load artist songs", "artist", name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load artist songs"})
+		return
+	}
+
+	var songs []*models.Song
+	for _, song := range candidates {
+		if normalizeArtist(song.Artist) == artistKey {
+			songs = append(songs, song)
+			if len(songs) == limit {
+				break
+			}
+		}
+	}
+
+	response := ArtistSongsResponse{
+		Artist:   name,
+		ImageURL: h.artistImage(c.Request.Context(), name, songs),
+		Songs:    make([]render.ResolveSongResponse, 0, len(songs)),
+	}
+	if len(songs) > 0 {
+		response.Artist = songs[0].Artist
+	}
+
+	baseURL := h.renderer.BaseURL(c)
+	for _, song := range songs {
+		response.Songs = append(response.Songs, h.buildResolveResponse(baseURL, song))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// artistImage returns a representative image for the artist: the platform
+// artist image when lookups are enabled, otherwise (or when no platform has
+// one) the album art shared by most of the artist's songs. Platform results
+// are cached per artist so every page shows the same image.
+func (h *SongHandler) artistImage(ctx context.Context, name string, songs []*models.Song) string {
+	if h.artistImages != nil {
+		key := normalizeArtist(name)
+		imageURL, found := h.artistImages.get(key, time.Now())
+		if !found {
+			imageURL = h.lookupArtistImage(ctx, name)
+			h.artistImages.set(key, imageURL, time.Now())
+		}
+		if imageURL != "" {
+			return imageURL
+		}
+	}
+	return mostCommonAlbumArt(songs)
+}
+
+// lookupArtistImage asks each platform, in preference order, for an artist
+// whose name matches and returns the first image found
+func (h *SongHandler) lookupArtistImage(ctx context.Context, name string) string {
+	key := normalizeArtist(name)
+	for _, service := range h.platformServiceList() {
+		lookupCtx, cancel := context.WithTimeout(ctx, artistImageLookupTimeout)
+		results, err := service.SearchTrack(lookupCtx, services.SearchQuery{
+			Artist:       name,
+			Query:        name,
+			Limit:        5,
+			IncludeKinds: []services.EntityKind{services.EntityArtist},
+		})
+		cancel()
+		if err != nil {
+			slog.Debug("Artist image lookup failed", "platform", service.GetPlatformName(), "artist", name, "error", err)
+			continue
+		}
+		for _, result := range results {
+			if result.Kind == services.EntityArtist && result.ImageURL != "" && normalizeArtist(result.Title) == key {
+				return result.ImageURL
+			}
+		}
+	}
+	return ""
+}
+
+// mostCommonAlbumArt returns the album art used by most songs, breaking ties
+// by URL so the choice is stable
+func mostCommonAlbumArt(songs []*models.Song) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, song := range songs {
+		imageURL := song.Metadata.ImageURL
+		if imageURL == "" {
+			continue
+		}
+		counts[imageURL]++
+		if best == "" || counts[imageURL] > counts[best] || (counts[imageURL] == counts[best] && imageURL < best) {
+			best = imageURL
+		}
+	}
+	return best
+}
+
+// artistImageEntry is a cached artist image lookup; an empty URL records a miss
+type artistImageEntry struct {
+	imageURL  string
+	fetchedAt time.Time
+}
+
+// artistImageCache holds platform artist images keyed by normalized artist name
+type artistImageCache struct {
+	mu      sync.RWMutex
+	entries map[string]artistImageEntry
+	ttl     time.Duration
+}
+
+func newArtistImageCache(ttl time.Duration) *artistImageCache {
+	return &artistImageCache{
+		entries: make(map[string]artistImageEntry),
+		ttl:     ttl,
+	}
+}
+
+func (ac *artistImageCache) get(key string, now time.Time) (string, bool) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+	entry, ok := ac.entries[key]
+	if !ok || now.Sub(entry.fetchedAt) > ac.ttl {
+		return "", false
+	}
+	return entry.imageURL, true
+}
+
+func (ac *artistImageCache) set(key, imageURL string, now time.Time) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if len(ac.entries) >= maxArtistImageCacheEntries {
+		ac.pruneLocked(now)
+	}
+	if len(ac.entries) >= maxArtistImageCacheEntries {
+		// Reset rather than track recency; the working set rebuilds quickly
+		ac.entries = make(map[string]artistImageEntry)
+	}
+	ac.entries[key] = artistImageEntry{imageURL: imageURL, fetchedAt: now}
+}
+
+// pruneExpired removes entries older than the TTL and returns how many were removed
+func (ac *artistImageCache) pruneExpired(now time.Time) int {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	return ac.pruneLocked(now)
+}
+
+func (ac *artistImageCache) pruneLocked(now time.Time) int {
+	removed := 0
+	for key, entry := range ac.entries {
+		if now.Sub(entry.fetchedAt) > ac.ttl {
+			delete(ac.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performArtistSongs(t *testing.T, handler *SongHandler, name string) ArtistSongsResponse {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/artists/:name/songs", handler.GetArtistSongs)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/artists/"+name+"/songs", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response ArtistSongsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func newArtistTestRepo() *testutil.MockSongRepository {
+	repo := &testutil.MockSongRepository{}
+	repo.On("Search", mock.Anything, "beyonce", maxArtistSongsLimit).Return([]*models.Song{
+		testutil.NewSongBuilder().WithTitle("Halo").WithArtist("Beyoncé").WithISRC(testutil.TestISRC1).
+			WithImageURL("https://example.com/sasha-fierce.jpg").Build(),
+		testutil.NewSongBuilder().WithTitle("Beyonce Tribute").WithArtist("Cover Band").WithISRC(testutil.TestISRC2).Build(),
+		testutil.NewSongBuilder().WithTitle("Single Ladies").WithArtist("Beyonce").WithISRC(testutil.TestISRC3).
+			WithImageURL("https://example.com/sasha-fierce.jpg").Build(),
+	}, nil)
+	return repo
+}
+
+func TestGetArtistSongs_PlatformImageCached(t *testing.T) {
+	repo := newArtistTestRepo()
+	spotify := testutil.NewMockPlatformService("spotify")
+	artist := testutil.NewTrackInfoBuilder().WithTitle("Beyoncé").WithImageURL("https://i.scdn.co/image/beyonce").Build()
+	artist.Kind = services.EntityArtist
+	spotify.On("SearchTrack", mock.Anything, mock.MatchedBy(func(q services.SearchQuery) bool {
+		return len(q.IncludeKinds) == 1 && q.IncludeKinds[0] == services.EntityArtist
+	})).Return([]*services.TrackInfo{artist}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.ApplyConfig(&config.Config{ArtistImagesEnabled: true})
+
+	response := performArtistSongs(t, handler, "beyonce")
+	assert.Equal(t, "Beyoncé", response.Artist)
+	assert.Equal(t, "https://i.scdn.co/image/beyonce", response.ImageURL)
+	require.Len(t, response.Songs, 2)
+	assert.Equal(t, "Halo", response.Songs[0].Song.Title)
+	assert.Equal(t, "Single Ladies", response.Songs[1].Song.Title)
+
+	// The second request is served from the artist image cache
+	response = performArtistSongs(t, handler, "beyonce")
+	assert.Equal(t, "https://i.scdn.co/image/beyonce", response.ImageURL)
+	spotify.AssertNumberOfCalls(t, "SearchTrack", 1)
+}
+
+func TestGetArtistSongs_DisabledUsesAlbumArt(t *testing.T) {
+	repo := newArtistTestRepo()
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+
+	response := performArtistSongs(t, handler, "beyonce")
+	assert.Equal(t, "https://example.com/sasha-fierce.jpg", response.ImageURL)
+	spotify.AssertNotCalled(t, "SearchTrack", mock.Anything, mock.Anything)
+}
+
+func TestMostCommonAlbumArt(t *testing.T) {
+	songs := []*models.Song{
+		testutil.NewSongBuilder().WithImageURL("https://example.com/b.jpg").Build(),
+		testutil.NewSongBuilder().WithImageURL("https://example.com/a.jpg").Build(),
+		testutil.NewSongBuilder().Build(),
+	}
+	assert.Equal(t, "https://example.com/a.jpg", mostCommonAlbumArt(songs), "ties break by URL")
+
+	songs = append(songs, testutil.NewSongBuilder().WithImageURL("https://example.com/b.jpg").Build())
+	assert.Equal(t, "https://example.com/b.jpg", mostCommonAlbumArt(songs))
+	assert.Empty(t, mostCommonAlbumArt(nil))
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"songshare/internal/models"
+)
+
+// Default album art backfill concurrency, matching the config default
+const defaultBackfillMaxConcurrent = 4
+
+// backfillTimeout bounds a background album art backfill, which outlives the
+// request that started it
+const backfillTimeout = 10 * time.Second
+
+// backfillTracker runs album art backfills in the background. A song has at
+// most one backfill in flight and at most maxConcurrent run at once; views
+// arriving while either limit is reached skip the backfill rather than wait.
+type backfillTracker struct {
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+	slots    chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newBackfillTracker creates a tracker. A non-positive limit allows one backfill at a time.
+func newBackfillTracker(maxConcurrent int) *backfillTracker {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &backfillTracker{
+		inFlight: make(map[string]struct{}),
+		slots:    make(chan struct{}, maxConcurrent),
+	}
+}
+
+// acquire claims the song's backfill and a concurrency slot, reporting
+// whether the caller should run it
+func (t *backfillTracker) acquire(songID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, running := t.inFlight[songID]; running {
+		return false
+	}
+	select {
+	case t.slots <- struct{}{}:
+	default:
+		return false
+	}
+	t.inFlight[songID] = struct{}{}
+	t.wg.Add(1)
+	return true
+}
+
+// release ends a backfill claimed with acquire
+func (t *backfillTracker) release(songID string) {
+	t.mu.Lock()
+	delete(t.inFlight, songID)
+	t.mu.Unlock()
+	<-t.slots
+	t.wg.Done()
+}
+
+// wait blocks until running backfills finish
+func (t *backfillTracker) wait() {
+	t.wg.Wait()
+}
+
+// startAlbumArtBackfill fetches the song's missing album art in the
+// background, so a page view never waits on platform APIs. Concurrent views
+// of one song share a single backfill, and the rate limit applies per backfill
+// rather than per view.
+func (h *SongHandler) startAlbumArtBackfill(song *models.Song) {
+	songID := song.ID.Hex()
+	if !h.backfills.acquire(songID) {
+		slog.Debug("Skipping album art backfill, already running or at capacity", "songID", songID)
+		return
+	}
+	if !h.backfillLimiter.Allow() {
+		h.backfills.release(songID)
+		slog.Debug("Skipping album art backfill, rate limit reached", "songID", songID)
+		return
+	}
+
+	// The request goes on rendering its song, so the backfill updates a copy
+	backfilled := *song
+	go func() {
+		defer h.backfills.release(songID)
+		ctx, cancel := context.WithTimeout(context.Background(), backfillTimeout)
+		defer cancel()
+		h.backfillAlbumArt(ctx, &backfilled)
+	}()
+}
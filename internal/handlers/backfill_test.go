@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRedirectToSong_ConcurrentViewsBackfillOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	song := testutil.NewSongBuilder().
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	song.ID = primitive.NewObjectID()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// Hold the platform call open until every view has been served
+	release := make(chan struct{})
+	spotify.On("GetTrackByID", mock.Anything, testutil.SpotifyTrackID1).
+		Run(func(mock.Arguments) { <-release }).
+		Return(testutil.NewTrackInfoBuilder().WithImageURL("https://i.scdn.co/image/cover").Build(), nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.backfillLimiter = newTokenBucket(0, 1)
+
+	router := gin.New()
+	router.GET("/s/:id", handler.RedirectToSong)
+
+	const views = 50
+	var wg sync.WaitGroup
+	codes := make([]int, views)
+	for i := 0; i < views; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/s/"+testutil.TestISRC1, nil))
+			codes[i] = w.Code
+		}()
+	}
+	wg.Wait()
+	close(release)
+	handler.backfills.wait()
+
+	for _, code := range codes {
+		require.Equal(t, http.StatusOK, code)
+	}
+	spotify.AssertNumberOfCalls(t, "GetTrackByID", 1)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+	assert.Empty(t, song.Metadata.ImageURL, "views keep serving the stored song")
+}
+
+func TestBackfillTracker_LimitsConcurrency(t *testing.T) {
+	tracker := newBackfillTracker(2)
+
+	assert.True(t, tracker.acquire("a"))
+	assert.False(t, tracker.acquire("a"), "one backfill per song")
+	assert.True(t, tracker.acquire("b"))
+	assert.False(t, tracker.acquire("c"), "at capacity")
+
+	tracker.release("a")
+	assert.True(t, tracker.acquire("c"))
+	assert.False(t, tracker.acquire("a"), "at capacity again")
+
+	tracker.release("b")
+	tracker.release("c")
+	tracker.wait()
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBotUserAgents are User-Agent substrings of search and link-preview crawlers
+var defaultBotUserAgents = []string{
+	"googlebot",
+	"bingbot",
+	"duckduckbot",
+	"yandexbot",
+	"baiduspider",
+	"applebot",
+	"facebookexternalhit",
+	"twitterbot",
+	"linkedinbot",
+	"slackbot",
+	"discordbot",
+	"telegrambot",
+	"whatsapp",
+	"pinterest",
+	"embedly",
+}
+
+// Default cache lifetimes, matching the config defaults. Crawlers re-fetch on
+// their own schedule, so they get a longer lifetime than browsers.
+const (
+	defaultBotCacheMaxAge   = time.Hour
+	defaultHumanCacheMaxAge = 5 * time.Minute
+)
+
+// isBot reports whether the request's User-Agent matches the bot allow-list
+func (h *SongHandler) isBot(c *gin.Context) bool {
+	ua := strings.ToLower(c.GetHeader("User-Agent"))
+	if ua == "" {
+		return false
+	}
+	for _, pattern := range h.botUserAgents {
+		if strings.Contains(ua, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// setCachePolicy sets Cache-Control for bot or human clients
+func (h *SongHandler) setCachePolicy(c *gin.Context, bot bool) {
+	maxAge := h.humanCacheMaxAge
+	if bot {
+		maxAge = h.botCacheMaxAge
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	c.Header("Vary", "Accept, User-Agent")
+}
+
+// wantsHTML reports whether the client should get an HTML page rather than JSON.
+// Browsers typically send text/html as the first preference. Link-preview
+// crawlers often send */*, so they get the HTML page for its OG tags.
+func (h *SongHandler) wantsHTML(c *gin.Context) bool {
+	return h.isBot(c) || strings.Contains(c.GetHeader("Accept"), "text/html")
+}
+
+// normalizeBotUserAgents lowercases patterns and drops blanks
+func normalizeBotUserAgents(patterns []string) []string {
+	normalized := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			normalized = append(normalized, pattern)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performSongPageRequest(t *testing.T, handler *SongHandler, userAgent, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/s/:id", handler.RedirectToSong)
+
+	req := httptest.NewRequest(http.MethodGet, "/s/"+testutil.TestISRC1, nil)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", accept)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func newBotTestHandler(t *testing.T) *SongHandler {
+	t.Helper()
+	repo := &testutil.MockSongRepository{}
+	song := testutil.NewSongBuilder().
+		WithTitle("Halo").
+		WithArtist("Beyoncé").
+		WithISRC(testutil.TestISRC1).
+		WithImageURL("https://example.com/halo.jpg").
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+	return NewSongHandler(repo, "https://songshare.example", nil, nil, nil)
+}
+
+func TestRedirectToSong_BotGetsBotCachePolicyAndOGTags(t *testing.T) {
+	handler := newBotTestHandler(t)
+
+	w := performSongPageRequest(t, handler, "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", "*/*")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+
+	body := w.Body.String()
+	assert.Contains(t, body, `<meta property="og:title" content="Halo - Beyoncé">`)
+	assert.Contains(t, body, `<meta property="og:image" content="https://example.com/halo.jpg">`)
+	assert.Contains(t, body, `<meta property="og:url" content="https://songshare.example/s/`+testutil.TestISRC1+`">`)
+	assert.Contains(t, body, `Listen to Halo by Beyoncé on Spotify`)
+}
+
+func TestRedirectToSong_HumanGetsHumanCachePolicy(t *testing.T) {
+	handler := newBotTestHandler(t)
+
+	w := performSongPageRequest(t, handler, "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Safari/605.1.15", "text/html")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+}
+
+func TestRedirectToSong_HumanAPIClientGetsJSON(t *testing.T) {
+	handler := newBotTestHandler(t)
+
+	w := performSongPageRequest(t, handler, "curl/8.4.0", "*/*")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+}
+
+func TestSongHandler_ApplyConfigBotPolicy(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	handler.ApplyConfig(&config.Config{
+		BotUserAgents:  []string{" MyCrawler ", ""},
+		BotCacheMaxAge: 2 * time.Hour,
+	})
+
+	assert.Equal(t, []string{"mycrawler"}, handler.botUserAgents)
+	assert.Equal(t, 2*time.Hour, handler.botCacheMaxAge)
+	assert.Equal(t, defaultHumanCacheMaxAge, handler.humanCacheMaxAge)
+}
+
+func newMissingSongHandler() *SongHandler {
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByIDPrefix", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	return NewSongHandler(repo, "https://songshare.example", nil, nil, nil)
+}
+
+func TestRedirectToSong_BrowserMissGetsHTMLNotFoundPage(t *testing.T) {
+	handler := newMissingSongHandler()
+
+	w := performSongPageRequest(t, handler, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)", "text/html,application/xhtml+xml,*/*;q=0.8")
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	body := w.Body.String()
+	assert.Contains(t, body, "Song not found")
+	assert.Contains(t, body, `<form class="search-form" action="/search" method="get">`)
+	assert.NotContains(t, body, `"error"`)
+}
+
+func TestRedirectToSong_APIClientMissGetsJSON(t *testing.T) {
+	handler := newMissingSongHandler()
+
+	w := performSongPageRequest(t, handler, "curl/8.4.0", "application/json")
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.JSONEq(t, `{"error":"Song not found"}`, w.Body.String())
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlatformCacheStats is one platform service's track and search cache use
+type PlatformCacheStats struct {
+	Hits    int     `json:"hits"`
+	Misses  int     `json:"misses"`
+	Entries int     `json:"entries"` // Values stored since startup or the last reset
+	HitRate float64 `json:"hit_rate"`
+}
+
+// GetStats handles GET /api/v1/stats
+// Reports each platform service's cache hits, misses and stored entries,
+// counted since startup or the last reset
+func (h *HealthHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"platform_caches": h.platformCacheStats()})
+}
+
+// platformCacheStats returns each counting platform service's cache use
+func (h *HealthHandler) platformCacheStats() map[string]PlatformCacheStats {
+	caches := make(map[string]PlatformCacheStats)
+	for _, reporter := range h.cacheStatsReporters() {
+		hits, misses, entries := reporter.CacheStats()
+		stats := PlatformCacheStats{Hits: hits, Misses: misses, Entries: entries}
+		if lookups := hits + misses; lookups > 0 {
+			stats.HitRate = float64(hits) / float64(lookups)
+		}
+		caches[reporter.GetPlatformName()] = stats
+	}
+	return caches
+}
+
+// ResetCacheStats handles POST /api/v1/admin/cache-stats/reset
+// Zeroes every platform service's cache counters. Admin only: register behind RequireAdmin.
+func (h *HealthHandler) ResetCacheStats(c *gin.Context) {
+	platforms := []string{}
+	for _, reporter := range h.cacheStatsReporters() {
+		reporter.ResetCacheStats()
+		platforms = append(platforms, reporter.GetPlatformName())
+	}
+	c.JSON(http.StatusOK, gin.H{"reset": platforms})
+}
+
+// cacheStatsReporter is a platform service that counts its cache use
+type cacheStatsReporter interface {
+	services.PlatformService
+	services.CacheStatsReporter
+}
+
+// cacheStatsReporters returns the platform services that count their cache use
+func (h *HealthHandler) cacheStatsReporters() []cacheStatsReporter {
+	var reporters []cacheStatsReporter
+	for _, service := range h.platformServices {
+		if reporter, ok := service.(cacheStatsReporter); ok {
+			reporters = append(reporters, reporter)
+		}
+	}
+	return reporters
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPlatformService is a platform service reporting fixed cache counters
+type countingPlatformService struct {
+	*testutil.MockPlatformService
+	hits, misses, entries int
+}
+
+func (s *countingPlatformService) CacheStats() (hits, misses, entries int) {
+	return s.hits, s.misses, s.entries
+}
+
+func (s *countingPlatformService) ResetCacheStats() {
+	s.hits, s.misses, s.entries = 0, 0, 0
+}
+
+func TestCacheStats_ReportAndReset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spotify := &countingPlatformService{MockPlatformService: testutil.NewMockPlatformService("spotify"), hits: 3, misses: 1, entries: 1}
+	tidal := testutil.NewMockPlatformService("tidal")
+	handler := NewHealthHandler(nil, nil, spotify, tidal)
+
+	router := gin.New()
+	router.GET("/api/v1/stats", handler.GetStats)
+	router.POST("/api/v1/admin/cache-stats/reset", handler.ResetCacheStats)
+
+	getStats := func() map[string]PlatformCacheStats {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			PlatformCaches map[string]PlatformCacheStats `json:"platform_caches"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.PlatformCaches
+	}
+
+	// Platforms without a counted cache are left out
+	assert.Equal(t, map[string]PlatformCacheStats{
+		"spotify": {Hits: 3, Misses: 1, Entries: 1, HitRate: 0.75},
+	}, getStats())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache-stats/reset", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"reset":["spotify"]}`, w.Body.String())
+
+	assert.Equal(t, PlatformCacheStats{}, getStats()["spotify"])
+}
+
+func TestGetHealth_IncludesCacheStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spotify := &countingPlatformService{MockPlatformService: testutil.NewMockPlatformService("spotify"), hits: 1, misses: 1, entries: 1}
+	handler := NewHealthHandler(nil, nil, spotify)
+
+	router := gin.New()
+	router.GET("/health", handler.GetHealth)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"status": "ok",
+		"platform_caches": {"spotify": {"hits": 1, "misses": 1, "entries": 1, "hit_rate": 0.5}}
+	}`, w.Body.String())
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const defaultCleanupInterval = 10 * time.Minute
+
+// cleanupTask prunes one store of expired entries and returns how many it removed
+type cleanupTask struct {
+	name  string
+	prune func(now time.Time) int
+}
+
+// cleanupTasks lists the in-memory stores the cleanup worker maintains
+func (h *SongHandler) cleanupTasks() []cleanupTask {
+	tasks := []cleanupTask{
+		{name: "search_cache", prune: h.searchCache.pruneExpired},
+	}
+	if h.artistImages != nil {
+		tasks = append(tasks, cleanupTask{name: "artist_images", prune: h.artistImages.pruneExpired})
+	}
+	return tasks
+}
+
+// StartCleanupWorker periodically removes expired in-memory data until ctx is cancelled.
+// Retention windows and the interval come from ApplyConfig.
+func (h *SongHandler) StartCleanupWorker(ctx context.Context) {
+	runCleanupWorker(ctx, h.cleanupInterval, h.cleanupTasks(), time.Now)
+}
+
+// runCleanupWorker runs tasks on every tick in a background goroutine
+func runCleanupWorker(ctx context.Context, interval time.Duration, tasks []cleanupTask, now func() time.Time) {
+	if interval <= 0 {
+		slog.Info("Cleanup worker disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Cleanup worker stopped")
+				return
+			case <-ticker.C:
+				runCleanupTasks(tasks, now())
+			}
+		}
+	}()
+}
+
+// runCleanupTasks prunes every task once and returns the total removed
+func runCleanupTasks(tasks []cleanupTask, now time.Time) int {
+	total := 0
+	for _, task := range tasks {
+		removed := task.prune(now)
+		if removed > 0 {
+			slog.Debug("Pruned expired entries", "store", task.name, "removed", removed)
+		}
+		total += removed
+	}
+	return total
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchCache_PruneExpired(t *testing.T) {
+	cache := newSearchCache()
+	now := time.Now()
+	cache.entries["stale"] = searchCacheEntry{timestamp: now.Add(-10 * time.Minute)}
+	cache.entries["fresh"] = searchCacheEntry{
+		results:   []render.SearchResult{{Title: "Fresh"}},
+		timestamp: now.Add(-time.Minute),
+	}
+
+	removed := cache.pruneExpired(now)
+
+	assert.Equal(t, 1, removed)
+	assert.NotContains(t, cache.entries, "stale")
+	assert.Contains(t, cache.entries, "fresh")
+}
+
+func TestSongHandler_ApplyConfigRetention(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	handler.ApplyConfig(&config.Config{SearchCacheTTL: 30 * time.Second, CleanupInterval: time.Minute})
+
+	now := time.Now()
+	handler.searchCache.entries["old"] = searchCacheEntry{timestamp: now.Add(-time.Minute)}
+	handler.searchCache.entries["new"] = searchCacheEntry{timestamp: now.Add(-10 * time.Second)}
+
+	assert.Equal(t, time.Minute, handler.cleanupInterval)
+	assert.Equal(t, 1, runCleanupTasks(handler.cleanupTasks(), now))
+	assert.Contains(t, handler.searchCache.entries, "new")
+}
+
+func TestRunCleanupWorker_StopsOnCancel(t *testing.T) {
+	var runs atomic.Int32
+	tasks := []cleanupTask{{name: "counter", prune: func(time.Time) int {
+		runs.Add(1)
+		return 0
+	}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runCleanupWorker(ctx, 5*time.Millisecond, tasks, time.Now)
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+	cancel()
+
+	// Allow an in-flight tick to finish, then confirm no further runs
+	time.Sleep(20 * time.Millisecond)
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultCollectionMaxTracks matches the COLLECTION_MAX_TRACKS default
+const defaultCollectionMaxTracks = 5000
+
+// ndjsonContentType is the media type for streamed collection progress
+const ndjsonContentType = "application/x-ndjson"
+
+// ResolveCollectionRequest represents the request to resolve a playlist or album
+type ResolveCollectionRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// CollectionResponse is a resolved collection with a universal link per track
+type CollectionResponse struct {
+	ID            string                    `json:"id"`
+	Name          string                    `json:"name"`
+	Kind          string                    `json:"kind"`
+	Platform      string                    `json:"platform"`
+	SourceURL     string                    `json:"source_url"`
+	ImageURL      string                    `json:"image_url,omitempty"`
+	UniversalLink string                    `json:"universal_link"`
+	Tracks        []CollectionTrackResponse `json:"tracks"`
+}
+
+// CollectionTrackResponse is one collection entry
+type CollectionTrackResponse struct {
+	SongID        string `json:"song_id"`
+	Title         string `json:"title"`
+	Artist        string `json:"artist"`
+	ISRC          string `json:"isrc,omitempty"`
+	UniversalLink string `json:"universal_link"`
+
+	// Platforms links the track on each platform; set when the collection is fetched
+	Platforms map[string]render.PlatformLink `json:"platforms,omitempty"`
+}
+
+// collectionEvent is one line of a streamed collection resolve
+type collectionEvent struct {
+	Type       string              `json:"type"` // "progress", "collection" or "error"
+	Resolved   int                 `json:"resolved,omitempty"`
+	Total      int                 `json:"total,omitempty"`
+	Collection *CollectionResponse `json:"collection,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// CollectionHandler resolves playlists and albums into shareable collections
+type CollectionHandler struct {
+	songs       *SongHandler
+	collections repositories.CollectionRepository
+	maxTracks   int
+}
+
+// NewCollectionHandler creates a collection handler that resolves tracks through songs
+func NewCollectionHandler(songs *SongHandler, collections repositories.CollectionRepository) *CollectionHandler {
+	return &CollectionHandler{
+		songs:       songs,
+		collections: collections,
+		maxTracks:   defaultCollectionMaxTracks,
+	}
+}
+
+// ApplyConfig applies the operator-tunable collection size cap
+func (h *CollectionHandler) ApplyConfig(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	if cfg.CollectionMaxTracks > 0 {
+		h.maxTracks = cfg.CollectionMaxTracks
+	}
+}
+
+// ResolveCollection handles POST /api/v1/collections/resolve
+// Clients sending Accept: application/x-ndjson get progress lines as pages
+// resolve, followed by the collection.
+func (h *CollectionHandler) ResolveCollection(c *gin.Context) {
+	var req ResolveCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ref, err := services.ParsePlaylistURL(strings.TrimSpace(req.URL))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unsupported playlist or album URL",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	source := h.playlistSource(ref.Platform)
+	if source == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Platform %s is not available", ref.Platform),
+		})
+		return
+	}
+
+	baseURL := h.songs.renderer.BaseURL(c)
+	if !strings.Contains(c.GetHeader("Accept"), ndjsonContentType) {
+		collection, err := h.resolveCollection(c.Request.Context(), source, ref, req.URL, nil)
+		if err != nil {
+			slog.Error("Failed to resolve collection", "url", req.URL, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to resolve collection",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, buildCollectionResponse(baseURL, collection, nil))
+		return
+	}
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+	emit := func(event collectionEvent) {
+		if err := encoder.Encode(event); err != nil {
+			slog.Debug("Failed to write collection progress", "error", err)
+		}
+		c.Writer.Flush()
+	}
+
+	collection, err := h.resolveCollection(c.Request.Context(), source, ref, req.URL, func(resolved, total int) {
+		emit(collectionEvent{Type: "progress", Resolved: resolved, Total: total})
+	})
+	if err != nil {
+		slog.Error("Failed to resolve collection", "url", req.URL, "error", err)
+		emit(collectionEvent{Type: "error", Error: err.Error()})
+		return
+	}
+	response := buildCollectionResponse(baseURL, collection, nil)
+	emit(collectionEvent{Type: "collection", Collection: &response})
+}
+
+// GetCollection handles GET /api/v1/collections/:id and the /c/:id universal
+// link, serving the collection page to browsers and JSON to API clients
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	id := c.Param("id")
+	if !primitive.IsValidObjectID(id) {
+		h.renderCollectionNotFound(c)
+		return
+	}
+
+	collection, err := h.collections.FindByID(c.Request.Context(), id)
+	if err != nil {
+		slog.Error("Collection lookup failed", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load collection"})
+		return
+	}
+	if collection == nil {
+		h.renderCollectionNotFound(c)
+		return
+	}
+
+	songs := h.collectionSongs(c.Request.Context(), collection)
+	h.songs.setCachePolicy(c, h.songs.isBot(c))
+
+	if h.songs.wantsHTML(c) {
+		h.songs.renderer.RenderCollectionPage(c, collection, songs, platformUIAdapter)
+		return
+	}
+	c.JSON(http.StatusOK, buildCollectionResponse(h.songs.renderer.BaseURL(c), collection, songs))
+}
+
+// renderCollectionNotFound answers a collection link miss with the HTML 404
+// page for browsers and a JSON error for API clients
+func (h *CollectionHandler) renderCollectionNotFound(c *gin.Context) {
+	if h.songs.wantsHTML(c) {
+		h.songs.renderer.RenderCollectionNotFoundPage(c)
+		return
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+}
+
+// collectionSongs loads the stored songs behind a collection's tracks for
+// their platform links. A failed lookup only costs the links, so it's logged
+// and the collection is served without them.
+func (h *CollectionHandler) collectionSongs(ctx context.Context, collection *models.Collection) map[primitive.ObjectID]*models.Song {
+	ids := make([]string, 0, len(collection.Tracks))
+	for _, track := range collection.Tracks {
+		ids = append(ids, track.SongID.Hex())
+	}
+
+	songs := make(map[primitive.ObjectID]*models.Song, len(ids))
+	if len(ids) == 0 {
+		return songs
+	}
+	found, err := h.songs.songRepository.FindMany(ctx, ids)
+	if err != nil {
+		slog.Warn("Failed to load collection songs", "id", collection.ID.Hex(), "error", err)
+		return songs
+	}
+	for _, song := range found {
+		songs[song.ID] = song
+	}
+	return songs
+}
+
+// playlistSource returns the configured platform service that can list
+// playlists for platform, or nil
+func (h *CollectionHandler) playlistSource(platform string) services.PlaylistService {
+	for _, service := range h.songs.platformServiceList() {
+		if service.GetPlatformName() != platform {
+			continue
+		}
+		if source, ok := service.(services.PlaylistService); ok {
+			return source
+		}
+	}
+	return nil
+}
+
+// resolveCollection walks every page of the playlist, resolves its tracks to
+// catalog songs and saves the collection. progress, if set, is called after
+// each page with the tracks resolved so far and the playlist's total.
+func (h *CollectionHandler) resolveCollection(ctx context.Context, source services.PlaylistService, ref services.PlaylistRef, sourceURL string, progress func(resolved, total int)) (*models.Collection, error) {
+	var collection *models.Collection
+	offset := 0
+	for {
+		page, err := source.GetPlaylistPage(ctx, ref, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch playlist page at offset %d: %w", offset, err)
+		}
+		if collection == nil {
+			collection = models.NewCollection(page.Name, ref.Kind, ref.Platform, sourceURL)
+			collection.ImageURL = page.ImageURL
+		}
+
+		tracks := page.Tracks
+		if remaining := h.maxTracks - len(collection.Tracks); len(tracks) > remaining {
+			tracks = tracks[:remaining]
+		}
+		songs, err := h.resolveTracks(ctx, tracks)
+		if err != nil {
+			return nil, err
+		}
+		for _, song := range songs {
+			collection.AddSong(song)
+		}
+
+		if progress != nil {
+			progress(len(collection.Tracks), page.Total)
+		}
+
+		// Next must move forward, so a misbehaving platform can't loop forever
+		if page.Next <= offset || len(collection.Tracks) >= h.maxTracks {
+			break
+		}
+		offset = page.Next
+	}
+
+	if err := h.collections.Save(ctx, collection); err != nil {
+		return nil, fmt.Errorf("failed to save collection: %w", err)
+	}
+	return collection, nil
+}
+
+// resolveTracks maps one page of tracks to catalog songs in order, reusing
+// songs already stored and saving the rest in one batch. Tracks whose song
+// can't be saved are left out.
+func (h *CollectionHandler) resolveTracks(ctx context.Context, tracks []*services.TrackInfo) ([]*models.Song, error) {
+	repo := h.songs.songRepository
+
+	var isrcs []string
+	for _, track := range tracks {
+		if track.ISRC != "" {
+			isrcs = append(isrcs, models.CanonicalISRC(track.ISRC))
+		}
+	}
+	existing := map[string]*models.Song{}
+	if len(isrcs) > 0 {
+		found, err := repo.FindByISRCBatch(ctx, isrcs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up songs by ISRC: %w", err)
+		}
+		existing = found
+	}
+
+	songs := make([]*models.Song, 0, len(tracks))
+	var created []*models.Song
+	for _, track := range tracks {
+		isrc := models.CanonicalISRC(track.ISRC)
+		if song := existing[isrc]; isrc != "" && song != nil {
+			songs = append(songs, song)
+			continue
+		}
+		if isrc == "" {
+			song, err := repo.FindByPlatformID(ctx, track.Platform, track.ExternalID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up song by platform ID: %w", err)
+			}
+			if song != nil {
+				songs = append(songs, song)
+				continue
+			}
+		}
+
+		song := track.ToSong()
+		song.CanonicalizeISRC()
+		if isrc != "" {
+			// The same recording can appear twice in one playlist
+			existing[isrc] = song
+		}
+		created = append(created, song)
+		songs = append(songs, song)
+	}
+
+	saved := h.songs.saveSongs(ctx, created)
+	kept := make([]*models.Song, 0, len(songs))
+	for _, song := range songs {
+		if stored, isNew := saved[song]; isNew && !stored {
+			continue
+		}
+		kept = append(kept, song)
+	}
+	for _, song := range created {
+		if saved[song] {
+			h.songs.queueEnrichment(ctx, song.ID.Hex(), song.ISRC)
+		}
+	}
+	return kept, nil
+}
+
+// buildCollectionResponse converts a collection with universal links under
+// baseURL. songs, when set, adds each track's platform links.
+func buildCollectionResponse(baseURL string, collection *models.Collection, songs map[primitive.ObjectID]*models.Song) CollectionResponse {
+	response := CollectionResponse{
+		ID:            collection.ID.Hex(),
+		Name:          collection.Name,
+		Kind:          collection.Kind,
+		Platform:      collection.Platform,
+		SourceURL:     collection.SourceURL,
+		ImageURL:      collection.ImageURL,
+		UniversalLink: render.CollectionURL(baseURL, collection),
+		Tracks:        make([]CollectionTrackResponse, 0, len(collection.Tracks)),
+	}
+	for _, track := range collection.Tracks {
+		trackResponse := CollectionTrackResponse{
+			SongID:        track.SongID.Hex(),
+			Title:         track.Title,
+			Artist:        track.Artist,
+			ISRC:          track.ISRC,
+			UniversalLink: render.CollectionTrackURL(baseURL, track),
+		}
+		if song := songs[track.SongID]; song != nil {
+			trackResponse.Platforms = make(map[string]render.PlatformLink, len(song.PlatformLinks))
+			for _, link := range song.PlatformLinks {
+				trackResponse.Platforms[link.Platform] = render.PlatformLink{
+					URL:       link.URL,
+					Available: link.Available,
+					Platform:  link.Platform,
+					Primary:   link.Primary,
+				}
+			}
+		}
+		response.Tracks = append(response.Tracks, trackResponse)
+	}
+	return response
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stubPlaylistPlatform serves fixed playlist pages keyed by offset
+type stubPlaylistPlatform struct {
+	*testutil.MockPlatformService
+	pages map[int]*services.PlaylistPage
+}
+
+func (s *stubPlaylistPlatform) GetPlaylistPage(ctx context.Context, ref services.PlaylistRef, offset int) (*services.PlaylistPage, error) {
+	return s.pages[offset], nil
+}
+
+func playlistTrack(id, isrc, title string) *services.TrackInfo {
+	return testutil.NewTrackInfoBuilder().
+		WithExternalID(id).
+		WithURL("https://open.spotify.com/track/" + id).
+		WithISRC(isrc).
+		WithTitle(title).
+		Build()
+}
+
+// newCollectionTestHandler sets up a three-page playlist where the first track
+// is already in the catalog and the last page repeats the second track
+func newCollectionTestHandler(t *testing.T) (*CollectionHandler, *testutil.MockSongRepository, *testutil.MockCollectionRepository) {
+	t.Helper()
+	existing := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60718").
+		WithISRC(testutil.TestISRC1).
+		WithTitle("Stored Song").
+		Build()
+
+	platform := &stubPlaylistPlatform{
+		MockPlatformService: testutil.NewMockPlatformService("spotify"),
+		pages: map[int]*services.PlaylistPage{
+			0: {Name: "Road Trip", Total: 4, Next: 2, Tracks: []*services.TrackInfo{
+				playlistTrack("track1", testutil.TestISRC1, "Stored Song"),
+				playlistTrack("track2", testutil.TestISRC2, "Second Song"),
+			}},
+			2: {Total: 4, Next: 3, Tracks: []*services.TrackInfo{
+				playlistTrack("track3", testutil.TestISRC3, "Third Song"),
+			}},
+			3: {Total: 4, Tracks: []*services.TrackInfo{
+				playlistTrack("track2", testutil.TestISRC2, "Second Song"),
+			}},
+		},
+	}
+
+	// Batch lookups see songs saved by earlier pages
+	stored := map[string]*models.Song{testutil.TestISRC1: existing}
+	songRepo := &testutil.MockSongRepository{}
+	songRepo.On("FindByISRCBatch", mock.Anything, mock.Anything).Return(stored, nil)
+	songRepo.On("UpsertMany", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for _, song := range args.Get(1).([]*models.Song) {
+			song.ID = primitive.NewObjectID()
+			stored[song.ISRC] = song
+		}
+	}).Return(nil)
+
+	collectionRepo := &testutil.MockCollectionRepository{}
+	collectionRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Collection).ID = primitive.NewObjectID()
+	}).Return(nil)
+
+	songs := NewSongHandler(songRepo, "http://localhost", platform, nil, nil)
+	return NewCollectionHandler(songs, collectionRepo), songRepo, collectionRepo
+}
+
+func performResolveCollection(t *testing.T, handler *CollectionHandler, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/collections/resolve", handler.ResolveCollection)
+
+	body, err := json.Marshal(ResolveCollectionRequest{URL: "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/collections/resolve", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestResolveCollection_MultiPagePlaylist(t *testing.T) {
+	handler, songRepo, collectionRepo := newCollectionTestHandler(t)
+
+	w := performResolveCollection(t, handler, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response CollectionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Road Trip", response.Name)
+	assert.Equal(t, services.PlaylistKindPlaylist, response.Kind)
+	assert.Equal(t, "http://localhost/c/"+response.ID, response.UniversalLink)
+
+	require.Len(t, response.Tracks, 4)
+	titles := make([]string, len(response.Tracks))
+	for i, track := range response.Tracks {
+		titles[i] = track.Title
+		assert.Equal(t, "http://localhost/s/"+track.ISRC, track.UniversalLink)
+	}
+	assert.Equal(t, []string{"Stored Song", "Second Song", "Third Song", "Second Song"}, titles)
+	assert.Equal(t, "64b7f0c2a1b2c3d4e5f60718", response.Tracks[0].SongID)
+	assert.Equal(t, response.Tracks[1].SongID, response.Tracks[3].SongID)
+
+	// Stored songs are reused; each page saves its new songs in one batch
+	songRepo.AssertNumberOfCalls(t, "UpsertMany", 2)
+	collectionRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestResolveCollection_StreamsNDJSONProgress(t *testing.T) {
+	handler, _, _ := newCollectionTestHandler(t)
+
+	w := performResolveCollection(t, handler, ndjsonContentType)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+
+	var events []collectionEvent
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var event collectionEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 4)
+	assert.Equal(t, collectionEvent{Type: "progress", Resolved: 2, Total: 4}, events[0])
+	assert.Equal(t, collectionEvent{Type: "progress", Resolved: 4, Total: 4}, events[2])
+	assert.Equal(t, "collection", events[3].Type)
+	require.NotNil(t, events[3].Collection)
+	assert.Len(t, events[3].Collection.Tracks, 4)
+}
+
+func TestResolveCollection_TruncatesToMaxTracks(t *testing.T) {
+	handler, _, _ := newCollectionTestHandler(t)
+	handler.maxTracks = 3
+
+	w := performResolveCollection(t, handler, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response CollectionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Tracks, 3)
+}
+
+func TestResolveCollection_RejectsTrackURL(t *testing.T) {
+	handler, _, _ := newCollectionTestHandler(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/collections/resolve", handler.ResolveCollection)
+
+	body, err := json.Marshal(ResolveCollectionRequest{URL: testutil.SpotifyURL1})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/collections/resolve", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCollection_NotFound(t *testing.T) {
+	handler, _, collectionRepo := newCollectionTestHandler(t)
+	collectionRepo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/c/:id", handler.GetCollection)
+
+	for _, id := range []string{"64b7f0c2a1b2c3d4e5f60718", "not-an-id"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/c/"+id, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, id)
+	}
+}
+
+// storedCollectionFixture stores a two-track collection whose first song has
+// Spotify and Apple Music links and whose second song has no ISRC
+func storedCollectionFixture(songRepo *testutil.MockSongRepository, collectionRepo *testutil.MockCollectionRepository) *models.Collection {
+	linked := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60718").
+		WithTitle("Linked Song").
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		WithAppleMusicLink(testutil.AppleMusicTrackID1, testutil.AppleMusicURL1).
+		Build()
+	unlinked := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60719").
+		WithTitle("No ISRC Song").
+		WithISRC("").
+		Build()
+
+	collection := models.NewCollection("Road Trip", services.PlaylistKindPlaylist, "spotify", "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M")
+	collection.ID, _ = primitive.ObjectIDFromHex("64b7f0c2a1b2c3d4e5f607aa")
+	collection.ImageURL = "https://example.com/cover.jpg"
+	collection.AddSong(linked)
+	collection.AddSong(unlinked)
+
+	collectionRepo.On("FindByID", mock.Anything, collection.ID.Hex()).Return(collection, nil)
+	songRepo.On("FindMany", mock.Anything, []string{linked.ID.Hex(), unlinked.ID.Hex()}).Return([]*models.Song{linked, unlinked}, nil)
+	return collection
+}
+
+func performGetCollection(t *testing.T, handler *CollectionHandler, id, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/c/:id", handler.GetCollection)
+
+	req := httptest.NewRequest(http.MethodGet, "/c/"+id, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetCollection_RendersHTMLPage(t *testing.T) {
+	handler, songRepo, collectionRepo := newCollectionTestHandler(t)
+	collection := storedCollectionFixture(songRepo, collectionRepo)
+
+	w := performGetCollection(t, handler, collection.ID.Hex(), "text/html")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	body := w.Body.String()
+	assert.Contains(t, body, `<meta property="og:title" content="Road Trip">`)
+	assert.Contains(t, body, `<meta property="og:image" content="https://example.com/cover.jpg">`)
+	assert.Contains(t, body, `<meta property="og:url" content="http://localhost/c/64b7f0c2a1b2c3d4e5f607aa">`)
+	assert.Contains(t, body, "Linked Song")
+	assert.Contains(t, body, `href="http://localhost/s/`+testutil.TestISRC1+`"`)
+	assert.Contains(t, body, `href="http://localhost/s/64b7f0c2"`, "songs without an ISRC link by ID prefix")
+	assert.Contains(t, body, `href="`+testutil.SpotifyURL1+`"`)
+	assert.Contains(t, body, `href="`+testutil.AppleMusicURL1+`"`)
+}
+
+func TestGetCollection_RendersJSON(t *testing.T) {
+	handler, songRepo, collectionRepo := newCollectionTestHandler(t)
+	collection := storedCollectionFixture(songRepo, collectionRepo)
+
+	w := performGetCollection(t, handler, collection.ID.Hex(), "application/json")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response CollectionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Road Trip", response.Name)
+	assert.Equal(t, "https://example.com/cover.jpg", response.ImageURL)
+	assert.Equal(t, "http://localhost/c/64b7f0c2a1b2c3d4e5f607aa", response.UniversalLink)
+	require.Len(t, response.Tracks, 2)
+	assert.Equal(t, testutil.SpotifyURL1, response.Tracks[0].Platforms["spotify"].URL)
+	assert.Contains(t, response.Tracks[0].Platforms, "apple_music")
+	assert.Empty(t, response.Tracks[1].Platforms)
+}
+
+func TestGetCollection_NotFoundHTMLPage(t *testing.T) {
+	handler, _, collectionRepo := newCollectionTestHandler(t)
+	collectionRepo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(nil, nil)
+
+	w := performGetCollection(t, handler, "64b7f0c2a1b2c3d4e5f60718", "text/html")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "Collection not found")
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"songshare/internal/models"
+)
+
+const defaultConsistencyCheckInterval = time.Hour
+
+// DuplicateISRC describes documents that share one non-empty ISRC
+type DuplicateISRC struct {
+	ISRC    string   `json:"isrc"`
+	SongIDs []string `json:"song_ids"`
+	Merged  bool     `json:"merged"`
+	KeptID  string   `json:"kept_id,omitempty"` // Surviving document when merged
+	Error   string   `json:"error,omitempty"`
+}
+
+// ConsistencyReport is the outcome of one consistency check run
+type ConsistencyReport struct {
+	CheckedAt  time.Time       `json:"checked_at"`
+	Duration   string          `json:"duration"`
+	AutoMerge  bool            `json:"auto_merge"`
+	Duplicates []DuplicateISRC `json:"duplicates"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// consistencyChecker holds the settings and latest result of the ISRC uniqueness check
+type consistencyChecker struct {
+	interval  time.Duration
+	autoMerge bool
+
+	mu     sync.RWMutex
+	report *ConsistencyReport
+}
+
+// StartConsistencyChecker periodically scans for duplicate ISRCs until ctx is cancelled
+func (h *AdminHandler) StartConsistencyChecker(ctx context.Context) {
+	interval := h.consistency.interval
+	if interval <= 0 {
+		slog.Info("Consistency checker disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Consistency checker stopped")
+				return
+			case <-ticker.C:
+				h.runConsistencyCheck(ctx)
+			}
+		}
+	}()
+}
+
+// runConsistencyCheck finds duplicate ISRCs, merges them when auto-merge is on,
+// and stores the report for the admin endpoint
+func (h *AdminHandler) runConsistencyCheck(ctx context.Context) *ConsistencyReport {
+	start := time.Now()
+	report := &ConsistencyReport{
+		CheckedAt:  start,
+		AutoMerge:  h.consistency.autoMerge,
+		Duplicates: []DuplicateISRC{},
+	}
+
+	groups, err := h.songRepository.FindDuplicateISRCs(ctx)
+	if err != nil {
+		slog.Error("Consistency check failed", "error", err)
+		report.Error = err.Error()
+	}
+
+	isrcs := make([]string, 0, len(groups))
+	for isrc := range groups {
+		isrcs = append(isrcs, isrc)
+	}
+	sort.Strings(isrcs)
+
+	for _, isrc := range isrcs {
+		songs := groups[isrc]
+		duplicate := DuplicateISRC{ISRC: isrc, SongIDs: make([]string, 0, len(songs))}
+		for _, song := range songs {
+			duplicate.SongIDs = append(duplicate.SongIDs, song.ID.Hex())
+		}
+		slog.Warn("Duplicate ISRC detected", "isrc", isrc, "song_ids", duplicate.SongIDs)
+
+		if report.AutoMerge {
+			kept, err := h.mergeDuplicateISRC(ctx, songs)
+			if err != nil {
+				slog.Error("Failed to merge duplicate ISRC", "isrc", isrc, "error", err)
+				duplicate.Error = err.Error()
+			} else {
+				duplicate.Merged = true
+				duplicate.KeptID = kept.ID.Hex()
+			}
+		}
+		report.Duplicates = append(report.Duplicates, duplicate)
+	}
+
+	report.Duration = time.Since(start).String()
+	h.consistency.mu.Lock()
+	h.consistency.report = report
+	h.consistency.mu.Unlock()
+
+	slog.Info("Consistency check completed", "duplicates", len(report.Duplicates), "auto_merge", report.AutoMerge)
+	return report
+}
+
+// mergeDuplicateISRC folds every song into the oldest one and deletes the rest
+func (h *AdminHandler) mergeDuplicateISRC(ctx context.Context, songs []*models.Song) (*models.Song, error) {
+	if len(songs) < 2 {
+		return nil, fmt.Errorf("need at least two songs to merge, got %d", len(songs))
+	}
+
+	sorted := make([]*models.Song, len(songs))
+	copy(sorted, songs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID.Hex() < sorted[j].ID.Hex()
+	})
+
+	kept := sorted[0]
+	// A leftover duplicate is reported again next run
+	if _, _, err := h.mergeSongs(ctx, kept, sorted[1:]); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Merged duplicate ISRC", "isrc", kept.ISRC, "kept_id", kept.ID.Hex(), "merged", len(sorted)-1)
+	return kept, nil
+}
+
+// mergeSongs folds the duplicates' platform links and missing metadata into
+// primary, saves it and deletes the duplicates. It returns how many links
+// primary gained and the IDs of duplicates that couldn't be deleted; primary
+// already holds their links, so deleting them can simply be retried.
+func (h *AdminHandler) mergeSongs(ctx context.Context, primary *models.Song, duplicates []*models.Song) (int, []string, error) {
+	linksBefore := len(primary.PlatformLinks)
+	for _, song := range duplicates {
+		primary.MergeFrom(song)
+	}
+	if err := h.songRepository.Update(ctx, primary); err != nil {
+		return 0, nil, fmt.Errorf("failed to update merged song: %w", err)
+	}
+
+	var undeleted []string
+	for _, song := range duplicates {
+		if err := h.songRepository.DeleteByID(ctx, song.ID.Hex()); err != nil {
+			slog.Error("Failed to delete merged duplicate", "song_id", song.ID.Hex(), "error", err)
+			undeleted = append(undeleted, song.ID.Hex())
+		}
+	}
+	return len(primary.PlatformLinks) - linksBefore, undeleted, nil
+}
+
+// GetConsistencyReport handles GET /api/v1/admin/consistency
+// Returns the latest ISRC consistency report; ?run=true runs a check first.
+func (h *AdminHandler) GetConsistencyReport(c *gin.Context) {
+	if c.Query("run") == "true" {
+		c.JSON(http.StatusOK, h.runConsistencyCheck(c.Request.Context()))
+		return
+	}
+
+	h.consistency.mu.RLock()
+	report := h.consistency.report
+	h.consistency.mu.RUnlock()
+
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No consistency check has run yet",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// seedDuplicateISRC returns two documents for TestISRC1, the older carrying Spotify
+// and the newer carrying Apple Music
+func seedDuplicateISRC() (older, newer *models.Song) {
+	older = testutil.NewSongBuilder().
+		WithID(primitive.NewObjectID().Hex()).
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	older.CreatedAt = time.Now().Add(-time.Hour)
+
+	newer = testutil.NewSongBuilder().
+		WithID(primitive.NewObjectID().Hex()).
+		WithISRC(testutil.TestISRC1).
+		WithAppleMusicLink(testutil.AppleMusicTrackID1, testutil.AppleMusicURL1).
+		Build()
+	newer.CreatedAt = time.Now()
+	return older, newer
+}
+
+func TestConsistencyCheck_DetectsDuplicates(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	older, newer := seedDuplicateISRC()
+	repo.On("FindDuplicateISRCs", mock.Anything).
+		Return(map[string][]*models.Song{testutil.TestISRC1: {newer, older}}, nil)
+
+	handler := NewAdminHandler(repo, nil)
+	report := handler.runConsistencyCheck(context.Background())
+
+	require.Len(t, report.Duplicates, 1)
+	duplicate := report.Duplicates[0]
+	assert.Equal(t, testutil.TestISRC1, duplicate.ISRC)
+	assert.ElementsMatch(t, []string{older.ID.Hex(), newer.ID.Hex()}, duplicate.SongIDs)
+	assert.False(t, duplicate.Merged)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestConsistencyCheck_AutoMerge(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	older, newer := seedDuplicateISRC()
+	repo.On("FindDuplicateISRCs", mock.Anything).
+		Return(map[string][]*models.Song{testutil.TestISRC1: {newer, older}}, nil)
+	repo.On("Update", mock.Anything, older).Return(nil)
+	repo.On("DeleteByID", mock.Anything, newer.ID.Hex()).Return(nil)
+
+	handler := NewAdminHandler(repo, nil)
+	handler.ApplyConfig(&config.Config{ConsistencyAutoMerge: true})
+	report := handler.runConsistencyCheck(context.Background())
+
+	require.Len(t, report.Duplicates, 1)
+	assert.True(t, report.Duplicates[0].Merged)
+	assert.Equal(t, older.ID.Hex(), report.Duplicates[0].KeptID)
+	assert.True(t, older.HasPlatform("spotify"))
+	assert.True(t, older.HasPlatform("apple_music"))
+	repo.AssertExpectations(t)
+}
+
+func TestGetConsistencyReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &testutil.MockSongRepository{}
+	older, newer := seedDuplicateISRC()
+	repo.On("FindDuplicateISRCs", mock.Anything).
+		Return(map[string][]*models.Song{testutil.TestISRC1: {older, newer}}, nil)
+
+	handler := NewAdminHandler(repo, nil)
+	router := gin.New()
+	admin := router.Group("/api/v1/admin", RequireAdmin("admin-token"))
+	admin.GET("/consistency", handler.GetConsistencyReport)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer admin-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/admin/consistency")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = get("/api/v1/admin/consistency?run=true")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = get("/api/v1/admin/consistency")
+	require.Equal(t, http.StatusOK, w.Code)
+	var report ConsistencyReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.Len(t, report.Duplicates, 1)
+	assert.Equal(t, testutil.TestISRC1, report.Duplicates[0].ISRC)
+	repo.AssertNumberOfCalls(t, "FindDuplicateISRCs", 1)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"songshare/internal/models"
+	"songshare/internal/services"
+)
+
+// defaultCrossLinkMergeThreshold matches the CROSS_LINK_MERGE_THRESHOLD
+// default. It is deliberately high: a wrong merge shows one recording's links
+// on another's page, while a missed merge only leaves a duplicate song.
+const defaultCrossLinkMergeThreshold = 0.9
+
+// Weights of the cross-link score components; they sum to 1
+const (
+	crossLinkTitleWeight    = 0.5
+	crossLinkArtistWeight   = 0.3
+	crossLinkDurationWeight = 0.2
+)
+
+// Durations within crossLinkDurationExactMs count as a full match; the match
+// falls off linearly to nothing at crossLinkDurationMaxMs apart
+const (
+	crossLinkDurationExactMs = 2000
+	crossLinkDurationMaxMs   = 10000
+)
+
+// crossLinkUnknownDuration is the duration component when either side has no
+// duration, so title and artist alone can't clear the default threshold
+const crossLinkUnknownDuration = 0.4
+
+// crossLinkScore rates how likely track and song are the same recording, from
+// 0 to 1. Differing ISRCs mean distinct recordings and score 0; matching ISRCs
+// score 1. Otherwise title and artist similarity and duration agreement are
+// combined by weight.
+func crossLinkScore(track *services.TrackInfo, song *models.Song) float64 {
+	trackISRC := models.CanonicalISRC(track.ISRC)
+	songISRC := models.CanonicalISRC(song.ISRC)
+	if trackISRC != "" && songISRC != "" {
+		if trackISRC == songISRC {
+			return 1
+		}
+		return 0
+	}
+
+	title := textSimilarity(track.Title, song.Title)
+	artist := textSimilarity(strings.Join(track.Artists, ", "), song.Artist)
+	duration := durationAgreement(track.Duration, song.Metadata.Duration)
+
+	return crossLinkTitleWeight*title + crossLinkArtistWeight*artist + crossLinkDurationWeight*duration
+}
+
+// textSimilarity compares two strings after search normalization, returning
+// 1 minus their edit distance relative to the longer string
+func textSimilarity(a, b string) float64 {
+	ra := []rune(models.NormalizeSearchText(a))
+	rb := []rune(models.NormalizeSearchText(b))
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// durationAgreement scores how closely two millisecond durations agree
+func durationAgreement(a, b int) float64 {
+	if a <= 0 || b <= 0 {
+		return crossLinkUnknownDuration
+	}
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	switch {
+	case diff <= crossLinkDurationExactMs:
+		return 1
+	case diff >= crossLinkDurationMaxMs:
+		return 0
+	default:
+		return float64(crossLinkDurationMaxMs-diff) / float64(crossLinkDurationMaxMs-crossLinkDurationExactMs)
+	}
+}
+
+// findCrossLinkMatch looks for a stored song that track is trusted enough to
+// merge into, returning it with its score. Songs already linked on track's
+// platform are another recording on that platform and never match.
+func (h *SongHandler) findCrossLinkMatch(ctx context.Context, track *services.TrackInfo) (*models.Song, float64, error) {
+	if track.Title == "" || len(track.Artists) == 0 {
+		return nil, 0, nil
+	}
+
+	candidates, err := h.songRepository.FindByTitleArtist(ctx, track.Title, track.Artists[0])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var best *models.Song
+	bestScore := 0.0
+	for _, candidate := range candidates {
+		if candidate.HasPlatform(track.Platform) {
+			continue
+		}
+		if score := crossLinkScore(track, candidate); score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	if best == nil || bestScore < h.crossLinkThreshold {
+		return nil, bestScore, nil
+	}
+	return best, bestScore, nil
+}
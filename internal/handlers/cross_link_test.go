@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// crossLinkCandidate is a stored Spotify song without an ISRC
+func crossLinkCandidate(title, artist string, durationMs int) *models.Song {
+	song := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60718").
+		WithTitle(title).
+		WithArtist(artist).
+		WithISRC("").
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	song.Metadata.Duration = durationMs
+	return song
+}
+
+// crossLinkTrack is an Apple Music track as returned by the platform
+func crossLinkTrack(title, artist, isrc string, durationMs int) *services.TrackInfo {
+	track := testutil.NewTrackInfoBuilder().
+		WithPlatform("apple_music").
+		WithExternalID(testutil.AppleMusicTrackID1).
+		WithURL(testutil.AppleMusicURL1).
+		WithTitle(title).
+		WithISRC(isrc).
+		Build()
+	track.Artists = []string{artist}
+	track.Duration = durationMs
+	return track
+}
+
+func TestFindCrossLinkMatch_MergeDecisions(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate *models.Song
+		track     *services.TrackInfo
+		merge     bool
+	}{
+		{
+			name:      "identical title, artist and duration",
+			candidate: crossLinkCandidate("Bohemian Rhapsody", "Queen", 354000),
+			track:     crossLinkTrack("Bohemian Rhapsody", "Queen", "", 354000),
+			merge:     true,
+		},
+		{
+			name:      "case, punctuation and small duration drift",
+			candidate: crossLinkCandidate("Don't Stop Me Now", "Queen", 209000),
+			track:     crossLinkTrack("DON'T STOP ME NOW", "queen", "", 210500),
+			merge:     true,
+		},
+		{
+			name:      "track ISRC with a candidate that has none",
+			candidate: crossLinkCandidate("Bohemian Rhapsody", "Queen", 354000),
+			track:     crossLinkTrack("Bohemian Rhapsody", "Queen", "GBUM71029604", 354000),
+			merge:     true,
+		},
+		{
+			name:      "unknown duration",
+			candidate: crossLinkCandidate("Bohemian Rhapsody", "Queen", 0),
+			track:     crossLinkTrack("Bohemian Rhapsody", "Queen", "", 354000),
+			merge:     false,
+		},
+		{
+			name:      "live version runs much longer",
+			candidate: crossLinkCandidate("Bohemian Rhapsody", "Queen", 354000),
+			track:     crossLinkTrack("Bohemian Rhapsody - Live", "Queen", "", 420000),
+			merge:     false,
+		},
+		{
+			name:      "remix with a close duration",
+			candidate: crossLinkCandidate("Blinding Lights", "The Weeknd", 200000),
+			track:     crossLinkTrack("Blinding Lights (Chromatics Remix)", "The Weeknd", "", 201000),
+			merge:     false,
+		},
+		{
+			name:      "cover by another artist",
+			candidate: crossLinkCandidate("Hallelujah", "Leonard Cohen", 276000),
+			track:     crossLinkTrack("Hallelujah", "Jeff Buckley", "", 276000),
+			merge:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &testutil.MockSongRepository{}
+			testutil.ExpectSongRepositoryFindByTitleArtist(repo, tt.candidate)
+			handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+
+			match, score, err := handler.findCrossLinkMatch(context.Background(), tt.track)
+			require.NoError(t, err)
+			if tt.merge {
+				assert.Same(t, tt.candidate, match, "score %.3f", score)
+			} else {
+				assert.Nil(t, match, "score %.3f", score)
+			}
+		})
+	}
+}
+
+func TestCrossLinkScore_ISRCAgreement(t *testing.T) {
+	song := crossLinkCandidate("Bohemian Rhapsody", "Queen", 354000)
+	song.ISRC = "GBUM71029604"
+
+	assert.Equal(t, 1.0, crossLinkScore(crossLinkTrack("Bohemian Rhapsody (Remastered)", "Queen", "GBUM71029604", 0), song))
+	assert.Equal(t, 0.0, crossLinkScore(crossLinkTrack("Bohemian Rhapsody", "Queen", "GBUM71029605", 354000), song),
+		"different ISRCs are different recordings however alike the metadata")
+}
+
+func TestFindCrossLinkMatch_SkipsSongsLinkedOnSamePlatform(t *testing.T) {
+	candidate := crossLinkCandidate("Bohemian Rhapsody", "Queen", 354000)
+	track := crossLinkTrack("Bohemian Rhapsody", "Queen", "", 354000)
+	track.Platform = "spotify"
+	track.ExternalID = "anotherSpotifyTrack"
+
+	repo := &testutil.MockSongRepository{}
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo, candidate)
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+
+	match, _, err := handler.findCrossLinkMatch(context.Background(), track)
+	require.NoError(t, err)
+	assert.Nil(t, match)
+}
+
+func TestFindCrossLinkMatch_ConfiguredThreshold(t *testing.T) {
+	// Scores about 0.73: partly matching title, same artist, durations 5s apart
+	candidate := crossLinkCandidate("Bohemian Rhapsody", "Queen", 354000)
+	track := crossLinkTrack("Bohemian Rhapsody Remastered", "Queen", "", 359000)
+
+	for _, tt := range []struct {
+		threshold float64
+		merge     bool
+	}{
+		{threshold: 0.5, merge: true},
+		{threshold: 0.9, merge: false},
+		{threshold: 1.1, merge: false},
+	} {
+		repo := &testutil.MockSongRepository{}
+		testutil.ExpectSongRepositoryFindByTitleArtist(repo, candidate)
+		handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+		handler.ApplyConfig(&config.Config{CrossLinkMergeThreshold: tt.threshold})
+
+		match, _, err := handler.findCrossLinkMatch(context.Background(), track)
+		require.NoError(t, err)
+		assert.Equal(t, tt.merge, match != nil, "threshold %.1f", tt.threshold)
+	}
+}
+
+func TestResolveSong_MergesTrustedCrossLink(t *testing.T) {
+	candidate := crossLinkCandidate("Bohemian Rhapsody", "Queen", 354000)
+	track := crossLinkTrack("Bohemian Rhapsody", "Queen", "GBUM71029604", 354000)
+
+	repo := &testutil.MockSongRepository{}
+	apple := testutil.NewMockPlatformService("apple_music")
+	repo.On("FindByPlatformID", mock.Anything, "apple_music", testutil.AppleMusicTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, "GBUM71029604").Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, "GBUM71029604").Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo, candidate)
+	repo.On("Update", mock.Anything, candidate).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(apple, testutil.AppleMusicTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, apple, nil)
+	w, response := performResolveURL(t, handler, testutil.AppleMusicURL1, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, candidate.ID.Hex(), response.Song.ID)
+	assert.Contains(t, response.Platforms, "spotify")
+	assert.Contains(t, response.Platforms, "apple_music")
+	link := candidate.GetPlatformLink("apple_music")
+	require.NotNil(t, link)
+	assert.GreaterOrEqual(t, link.Confidence, defaultCrossLinkMergeThreshold)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
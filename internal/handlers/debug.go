@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"songshare/internal/handlers/render"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupingDiagnostics explains how a grouped song was formed, to help operators
+// trace grouping problems back to a platform's metadata
+type GroupingDiagnostics struct {
+	// MissingISRCPlatforms lists platforms that contributed results without an ISRC
+	MissingISRCPlatforms []string `json:"missing_isrc_platforms,omitempty"`
+	// FragmentOfISRC is set when this ISRC-less group has the same title and
+	// artist as an ISRC group, i.e. it would have merged had the ISRC been present
+	FragmentOfISRC string `json:"fragment_of_isrc,omitempty"`
+	// MetadataConflicts lists fields the grouped platforms disagree on
+	MetadataConflicts []MetadataConflict `json:"metadata_conflicts,omitempty"`
+}
+
+// MetadataConflict is a field that platforms report materially different
+// values for, keyed by platform, which usually points to bad platform data
+type MetadataConflict struct {
+	Field  string            `json:"field"`
+	Values map[string]string `json:"values"`
+}
+
+// conflictDurationThreshold is how far apart two platforms' track lengths can
+// be before they conflict; small differences come from encoding and padding
+const conflictDurationThreshold = 5 * time.Second
+
+// recordMissingISRC notes that platform contributed a result lacking an ISRC
+func (g *GroupedSong) recordMissingISRC(platform string) {
+	if g.Diagnostics == nil {
+		g.Diagnostics = &GroupingDiagnostics{}
+	}
+	for _, existing := range g.Diagnostics.MissingISRCPlatforms {
+		if existing == platform {
+			return
+		}
+	}
+	g.Diagnostics.MissingISRCPlatforms = append(g.Diagnostics.MissingISRCPlatforms, platform)
+}
+
+// linkFragmentedGroups marks title+artist groups that match an ISRC group
+func linkFragmentedGroups(isrcToSong map[string]*GroupedSong, titleArtistToSong map[string][]*GroupedSong, key func(string, []string) string) {
+	isrcByKey := make(map[string]string, len(isrcToSong))
+	for isrc, song := range isrcToSong {
+		isrcByKey[key(song.Title, song.Artists)] = isrc
+	}
+	for titleArtistKey, songs := range titleArtistToSong {
+		isrc, ok := isrcByKey[titleArtistKey]
+		if !ok {
+			continue
+		}
+		for _, song := range songs {
+			if song.Diagnostics != nil {
+				song.Diagnostics.FragmentOfISRC = isrc
+			}
+		}
+	}
+}
+
+// recordMetadataConflicts notes the titles, durations and release years the
+// group's platforms disagree on. Titles are compared ignoring case and spacing.
+func (g *GroupedSong) recordMetadataConflicts() {
+	if len(g.Platforms) < 2 {
+		return
+	}
+
+	var conflicts []MetadataConflict
+	conflict := func(field string, value func(render.SearchResult) string, differ func(a, b render.SearchResult) bool) {
+		var known []render.SearchResult
+		for _, result := range g.Platforms {
+			if value(result) != "" {
+				known = append(known, result)
+			}
+		}
+		for i := 1; i < len(known); i++ {
+			for j := 0; j < i; j++ {
+				if !differ(known[i], known[j]) {
+					continue
+				}
+				values := make(map[string]string, len(known))
+				for _, result := range known {
+					values[result.Platform] = value(result)
+				}
+				conflicts = append(conflicts, MetadataConflict{Field: field, Values: values})
+				return
+			}
+		}
+	}
+
+	conflict("title", func(r render.SearchResult) string { return r.Title },
+		func(a, b render.SearchResult) bool { return comparableTitle(a.Title) != comparableTitle(b.Title) })
+	conflict("duration_ms", func(r render.SearchResult) string {
+		if r.DurationMs <= 0 {
+			return ""
+		}
+		return strconv.Itoa(r.DurationMs)
+	}, func(a, b render.SearchResult) bool {
+		diff := time.Duration(a.DurationMs-b.DurationMs) * time.Millisecond
+		return diff > conflictDurationThreshold || diff < -conflictDurationThreshold
+	})
+	conflict("release_date", func(r render.SearchResult) string { return r.ReleaseDate },
+		func(a, b render.SearchResult) bool { return releaseYear(a.ReleaseDate) != releaseYear(b.ReleaseDate) })
+
+	if len(conflicts) == 0 {
+		return
+	}
+	if g.Diagnostics == nil {
+		g.Diagnostics = &GroupingDiagnostics{}
+	}
+	g.Diagnostics.MetadataConflicts = conflicts
+}
+
+// comparableTitle lowercases a title and collapses its whitespace
+func comparableTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// releaseYear returns the year of a release date; platforms report dates at
+// different precisions ("2008" vs "2008-11-14"), so only years are compared
+func releaseYear(date string) string {
+	if len(date) < 4 {
+		return date
+	}
+	return date[:4]
+}
+
+// DebugGroup is a grouped song as shown by the debug endpoint
+type DebugGroup struct {
+	Title       string               `json:"title"`
+	Artists     []string             `json:"artists"`
+	ISRC        string               `json:"isrc,omitempty"`
+	Platforms   []string             `json:"platforms"`
+	Diagnostics *GroupingDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// DebugSearchResponse is returned by the debug search endpoint
+type DebugSearchResponse struct {
+	Query          string            `json:"query"`
+	PlatformStatus map[string]string `json:"platform_status"`
+	Groups         []DebugGroup      `json:"groups"`
+}
+
+// DebugSearch handles GET /api/v1/debug/search
+// It runs a search and returns the grouping with per-group diagnostics.
+// Returns 404 unless debug mode is enabled.
+func (h *SongHandler) DebugSearch(c *gin.Context) {
+	if !h.debug {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		return
+	}
+
+	limit := 10
+	if parsedLimit, err := strconv.Atoi(c.Query("limit")); err == nil && parsedLimit > 0 && parsedLimit <= 50 {
+		limit = parsedLimit
+	}
+
+	searchResponse := h.performSearch(c.Request.Context(), h.renderer.BaseURL(c), SearchSongsRequest{
+		Query:    query,
+		Platform: strings.TrimSpace(c.Query("platform")),
+		Limit:    limit,
+	})
+
+	grouped := h.groupSongsByISRC(searchResponse.Results)
+	groups := make([]DebugGroup, 0, len(grouped))
+	for _, song := range grouped {
+		platforms := make([]string, 0, len(song.Platforms))
+		for _, result := range song.Platforms {
+			platforms = append(platforms, result.Platform)
+		}
+		groups = append(groups, DebugGroup{
+			Title:       song.Title,
+			Artists:     song.Artists,
+			ISRC:        song.ISRC,
+			Platforms:   platforms,
+			Diagnostics: song.Diagnostics,
+		})
+	}
+
+	c.JSON(http.StatusOK, DebugSearchResponse{
+		Query:          query,
+		PlatformStatus: searchResponse.PlatformStatus,
+		Groups:         groups,
+	})
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/handlers/render"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func fragmentedResults() map[string][]render.SearchResult {
+	return map[string][]render.SearchResult{
+		"spotify": {
+			{Title: "Halo", Artists: []string{"Beyoncé"}, Platform: "spotify", ISRC: "USSM10804557"},
+		},
+		"apple_music": {
+			{Title: "Halo", Artists: []string{"Beyonce"}, Platform: "apple_music"},
+		},
+		"tidal": {
+			{Title: "Halo", Artists: []string{"Beyoncé"}, Platform: "tidal", ISRC: "USSM10804557"},
+			{Title: "Crazy in Love", Artists: []string{"Beyoncé"}, Platform: "tidal", ISRC: "unknown"},
+		},
+	}
+}
+
+func findGroup(t *testing.T, groups []GroupedSong, title, isrc string) GroupedSong {
+	t.Helper()
+	for _, group := range groups {
+		if group.Title == title && group.ISRC == isrc {
+			return group
+		}
+	}
+	require.Failf(t, "group not found", "%s (%s)", title, isrc)
+	return GroupedSong{}
+}
+
+func TestGroupSongsByISRC_MissingISRCDiagnostics(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	handler.debug = true
+
+	groups := handler.groupSongsByISRC(fragmentedResults())
+	require.Len(t, groups, 3)
+
+	withISRC := findGroup(t, groups, "Halo", "USSM10804557")
+	assert.Nil(t, withISRC.Diagnostics)
+
+	fragment := findGroup(t, groups, "Halo", "")
+	require.NotNil(t, fragment.Diagnostics)
+	assert.Equal(t, []string{"apple_music"}, fragment.Diagnostics.MissingISRCPlatforms)
+	assert.Equal(t, "USSM10804557", fragment.Diagnostics.FragmentOfISRC)
+
+	unknown := findGroup(t, groups, "Crazy in Love", "unknown")
+	require.NotNil(t, unknown.Diagnostics)
+	assert.Equal(t, []string{"tidal"}, unknown.Diagnostics.MissingISRCPlatforms)
+	assert.Empty(t, unknown.Diagnostics.FragmentOfISRC)
+}
+
+func TestGroupSongsByISRC_NoDiagnosticsWithoutDebug(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+
+	for _, group := range handler.groupSongsByISRC(fragmentedResults()) {
+		assert.Nil(t, group.Diagnostics)
+	}
+}
+
+func performDebugSearch(t *testing.T, handler *SongHandler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/debug/search", handler.DebugSearch)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/debug/search"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDebugSearch_DisabledByDefault(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+
+	w := performDebugSearch(t, handler, "?q=halo")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDebugSearch_SurfacesDiagnostics(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+
+	repo.On("Search", mock.Anything, "halo", mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{
+		testutil.NewTrackInfoBuilder().WithPlatform("spotify").WithTitle("Halo").WithArtists("Beyoncé").WithISRC("USSM10804557").Build(),
+	}, nil)
+	appleMusic.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{
+		testutil.NewTrackInfoBuilder().WithPlatform("apple_music").WithTitle("Halo").WithArtists("Beyoncé").WithISRC("").Build(),
+	}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+	handler.debug = true
+
+	w := performDebugSearch(t, handler, "?q=halo")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response DebugSearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Groups, 2)
+
+	var fragment *DebugGroup
+	for i := range response.Groups {
+		if response.Groups[i].ISRC == "" {
+			fragment = &response.Groups[i]
+		}
+	}
+	require.NotNil(t, fragment)
+	require.NotNil(t, fragment.Diagnostics)
+	assert.Equal(t, []string{"apple_music"}, fragment.Diagnostics.MissingISRCPlatforms)
+	assert.Equal(t, "USSM10804557", fragment.Diagnostics.FragmentOfISRC)
+	assert.Equal(t, "ok", response.PlatformStatus["spotify"])
+}
+
+func TestDebugSearchScores_Breakdown(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+
+	spotifyTrack := testutil.NewTrackInfoBuilder().WithPlatform("spotify").WithTitle("Halo").WithArtists("Beyoncé").WithISRC("USSM10804557").Build()
+	spotifyTrack.Popularity = 80
+	appleTrack := testutil.NewTrackInfoBuilder().WithPlatform("apple_music").WithTitle("Halo").WithArtists("Beyoncé").WithISRC("USSM10804557").Build()
+
+	repo.On("Search", mock.Anything, "halo", mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{spotifyTrack}, nil)
+	appleMusic.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{appleTrack}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/search/debug", handler.DebugSearchScores)
+	perform := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/search/debug?q=halo", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, perform().Code)
+
+	handler.debug = true
+	w := perform()
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response ScoreDebugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Results, 1)
+
+	result := response.Results[0]
+	assert.Equal(t, 1, result.Rank)
+	assert.Equal(t, 200, result.Score.Platforms)
+	assert.Zero(t, result.Score.Popularity, "the popularity boost is opt-in")
+	assert.Equal(t, map[string]int{"spotify": 80, "apple_music": 0}, result.PlatformPopularity)
+	assert.Equal(t, "apple_music", result.RepresentativePlatform, "platforms are grouped in name order")
+	assert.NotNil(t, response.Ranking)
+}
+
+func TestGroupSongsByISRC_MetadataConflicts(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	handler.debug = true
+
+	results := map[string][]render.SearchResult{
+		"spotify": {
+			{Title: "Halo", Artists: []string{"Beyoncé"}, Platform: "spotify", ISRC: "USSM10804557", DurationMs: 261640, ReleaseDate: "2008-11-14"},
+			{Title: "Crazy in Love", Artists: []string{"Beyoncé"}, Platform: "spotify", ISRC: "USSM10300005", DurationMs: 236133},
+		},
+		"apple_music": {
+			{Title: "HALO", Artists: []string{"Beyoncé"}, Platform: "apple_music", ISRC: "USSM10804557", DurationMs: 261000, ReleaseDate: "2008"},
+			{Title: "Crazy in Love", Artists: []string{"Beyoncé"}, Platform: "apple_music", ISRC: "USSM10300005", DurationMs: 236200},
+		},
+		"tidal": {
+			{Title: "Halo (Live)", Artists: []string{"Beyoncé"}, Platform: "tidal", ISRC: "USSM10804557", DurationMs: 301000, ReleaseDate: "2008-11-14"},
+		},
+	}
+
+	// Apple Music is grouped first, so its title names the group
+	halo := findGroup(t, handler.groupSongsByISRC(results), "HALO", "USSM10804557")
+	require.NotNil(t, halo.Diagnostics)
+	assert.Equal(t, []MetadataConflict{
+		{Field: "title", Values: map[string]string{"spotify": "Halo", "apple_music": "HALO", "tidal": "Halo (Live)"}},
+		{Field: "duration_ms", Values: map[string]string{"spotify": "261640", "apple_music": "261000", "tidal": "301000"}},
+	}, halo.Diagnostics.MetadataConflicts, "release dates differing only in precision don't conflict")
+
+	// Durations within the threshold agree
+	agreeing := findGroup(t, handler.groupSongsByISRC(results), "Crazy in Love", "USSM10300005")
+	assert.Nil(t, agreeing.Diagnostics)
+
+	handler.debug = false
+	assert.Nil(t, findGroup(t, handler.groupSongsByISRC(results), "HALO", "USSM10804557").Diagnostics)
+}
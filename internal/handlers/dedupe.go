@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"songshare/internal/handlers/render"
+)
+
+// dedupePlatformResults drops repeated tracks from one platform's results, keyed by
+// external ID (or URL when the ID is unknown). The first occurrence keeps its
+// position; a later duplicate replaces it only if it is more canonical.
+func dedupePlatformResults(results []render.SearchResult) []render.SearchResult {
+	if len(results) < 2 {
+		return results
+	}
+
+	deduped := make([]render.SearchResult, 0, len(results))
+	index := make(map[string]int, len(results))
+	for _, result := range results {
+		key := resultIdentity(result)
+		if key == "" {
+			deduped = append(deduped, result)
+			continue
+		}
+		if i, seen := index[key]; seen {
+			if moreCanonical(result, deduped[i]) {
+				deduped[i] = result
+			}
+			continue
+		}
+		index[key] = len(deduped)
+		deduped = append(deduped, result)
+	}
+	return deduped
+}
+
+// resultIdentity identifies a track within its platform
+func resultIdentity(result render.SearchResult) string {
+	if result.ExternalID != "" {
+		return result.Platform + ":" + result.ExternalID
+	}
+	if result.URL != "" {
+		return result.Platform + ":" + result.URL
+	}
+	return ""
+}
+
+// addPlatformResult adds result to the group, collapsing multiple links from the
+// same platform (e.g. album and single versions of one recording) to the canonical
+// one. A local catalog result marks the group as in the library instead.
+func (g *GroupedSong) addPlatformResult(result render.SearchResult) {
+	if result.IsLocal() {
+		g.InLibrary = true
+		g.LibraryURL = result.URL
+		return
+	}
+	for i, existing := range g.Platforms {
+		if existing.Platform == result.Platform {
+			if moreCanonical(result, existing) {
+				g.Platforms[i] = result
+			}
+			return
+		}
+	}
+	g.Platforms = append(g.Platforms, result)
+}
+
+// moreCanonical reports whether candidate is a better link than current for the
+// same recording: available first, then with an ISRC, then more popular, then the
+// earlier (original) release. Full ties keep current.
+func moreCanonical(candidate, current render.SearchResult) bool {
+	if candidate.Available != current.Available {
+		return candidate.Available
+	}
+	if (candidate.ISRC != "") != (current.ISRC != "") {
+		return candidate.ISRC != ""
+	}
+	if candidate.Popularity != current.Popularity {
+		return candidate.Popularity > current.Popularity
+	}
+	if candidate.ReleaseDate != "" && current.ReleaseDate != "" && candidate.ReleaseDate != current.ReleaseDate {
+		return candidate.ReleaseDate < current.ReleaseDate
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch_DuplicateSpotifyResultsCollapseToOneLink(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	albumVersion := testutil.NewTrackInfoBuilder().
+		WithExternalID("album-version").
+		WithURL("https://open.spotify.com/track/album-version").
+		WithISRC(testutil.TestISRC1).
+		WithPopularity(70).
+		Build()
+	singleVersion := testutil.NewTrackInfoBuilder().
+		WithExternalID("single-version").
+		WithURL("https://open.spotify.com/track/single-version").
+		WithISRC(testutil.TestISRC1).
+		WithPopularity(40).
+		Build()
+
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).
+		Return([]*services.TrackInfo{singleVersion, albumVersion, albumVersion}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "test song", Limit: 10})
+
+	// The repeated album entry is dropped within the platform
+	require.Len(t, response.Results["spotify"], 2)
+
+	// The album and single versions collapse within the group, keeping the canonical link
+	groups := handler.groupSongsByISRC(response.Results)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Platforms, 1)
+	assert.Equal(t, "album-version", groups[0].Platforms[0].ExternalID)
+}
+
+func TestDedupePlatformResults(t *testing.T) {
+	results := []render.SearchResult{
+		{Platform: "spotify", ExternalID: "a", Title: "First"},
+		{Platform: "spotify", ExternalID: "b", Title: "Other"},
+		{Platform: "spotify", ExternalID: "a", Title: "Repeat", Popularity: 50},
+		{Platform: "local", URL: "http://localhost/s/X"},
+		{Platform: "local", URL: "http://localhost/s/X"},
+	}
+
+	deduped := dedupePlatformResults(results)
+
+	require.Len(t, deduped, 3)
+	assert.Equal(t, "Repeat", deduped[0].Title, "more popular duplicate replaces the first in place")
+	assert.Equal(t, "Other", deduped[1].Title)
+	assert.Equal(t, "local", deduped[2].Platform)
+}
+
+func TestMoreCanonical(t *testing.T) {
+	base := render.SearchResult{Platform: "spotify", Available: true, ISRC: testutil.TestISRC1, Popularity: 50, ReleaseDate: "2020-01-01"}
+
+	unavailable := base
+	unavailable.Available = false
+	assert.False(t, moreCanonical(unavailable, base))
+	assert.True(t, moreCanonical(base, unavailable))
+
+	noISRC := base
+	noISRC.ISRC = ""
+	assert.True(t, moreCanonical(base, noISRC))
+
+	reissue := base
+	reissue.ReleaseDate = "2023-05-05"
+	assert.True(t, moreCanonical(base, reissue), "original release beats reissue")
+
+	assert.False(t, moreCanonical(base, base), "ties keep the current link")
+}
+
+func TestSearch_LocalResultIsASourceNotAPlatform(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	stored := testutil.NewSongBuilder().WithISRC(testutil.TestISRC1).WithTitle("Test Song").Build()
+	libraryOnly := testutil.NewSongBuilder().WithISRC(testutil.TestISRC2).WithTitle("Library Only").Build()
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{stored, libraryOnly}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{
+		testutil.NewTrackInfoBuilder().WithISRC(testutil.TestISRC1).Build(),
+	}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "test song", Limit: 10})
+
+	// Local results keep their own key but aren't a platform that was searched
+	require.Len(t, response.Results[render.LocalSource], 2)
+	assert.True(t, response.Results[render.LocalSource][0].IsLocal())
+	assert.NotContains(t, response.PlatformStatus, render.LocalSource)
+
+	groups := make(map[string]GroupedSong)
+	for _, group := range handler.groupSongsByISRC(response.Results) {
+		groups[group.ISRC] = group
+	}
+	require.Len(t, groups, 2)
+
+	shared := groups[testutil.TestISRC1]
+	require.Len(t, shared.Platforms, 1)
+	assert.Equal(t, "spotify", shared.Platforms[0].Platform)
+	assert.True(t, shared.InLibrary)
+	assert.Equal(t, "http://localhost/s/"+testutil.TestISRC1, shared.LibraryURL)
+
+	only := groups[testutil.TestISRC2]
+	assert.Empty(t, only.Platforms)
+	assert.True(t, only.InLibrary)
+	assert.Equal(t, 0, countStreamingPlatforms(only))
+
+	// The library boosts the score on its own line, not as a platform
+	breakdown := handler.relevanceBreakdown(shared, config.DefaultRankingConfig(), "", 0, time.Now())
+	assert.Equal(t, 100, breakdown.Platforms)
+	assert.Equal(t, 100, breakdown.Library)
+
+	html := handler.renderSearchResultsHTML([]GroupedSong{only})
+	assert.Contains(t, html, `class="platform-badge source-local">In library</a>`)
+	assert.Contains(t, html, `href="http://localhost/s/`+testutil.TestISRC2+`"`)
+	assert.NotContains(t, html, "createShareLink")
+}
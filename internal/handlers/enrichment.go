@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"songshare/internal/models"
+	"songshare/internal/services"
+)
+
+// Enrichment queue behaviour when full
+const (
+	enrichmentQueueDrop  = "drop"  // discard the job and log
+	enrichmentQueueBlock = "block" // wait for space, bounded by the caller's context
+)
+
+// Default enrichment pool sizing, matching the config defaults
+const (
+	defaultEnrichmentWorkers   = 4
+	defaultEnrichmentQueueSize = 100
+)
+
+// enrichmentMetrics publishes pool counters at /debug/vars
+var enrichmentMetrics = expvar.NewMap("enrichment")
+
+// enrichmentJob identifies a stored song to look up on other platforms
+type enrichmentJob struct {
+	songID string
+	isrc   string
+}
+
+// enrichmentPool runs enrichment jobs on a fixed number of workers fed from a
+// bounded queue, so a burst of resolves cannot fan out unbounded platform calls
+type enrichmentPool struct {
+	jobs    chan enrichmentJob
+	block   bool
+	handle  func(ctx context.Context, job enrichmentJob)
+	depth   atomic.Int64
+	ctx     context.Context
+	wg      sync.WaitGroup
+	workers int
+}
+
+// newEnrichmentPool starts workers that process jobs until ctx is cancelled
+func newEnrichmentPool(ctx context.Context, workers, queueSize int, mode string, handle func(context.Context, enrichmentJob)) *enrichmentPool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	p := &enrichmentPool{
+		jobs:    make(chan enrichmentJob, queueSize),
+		block:   mode == enrichmentQueueBlock,
+		handle:  handle,
+		ctx:     ctx,
+		workers: workers,
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *enrichmentPool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case job := <-p.jobs:
+			p.depth.Add(-1)
+			enrichmentMetrics.Add("queue_depth", -1)
+			p.handle(p.ctx, job)
+			enrichmentMetrics.Add("processed", 1)
+		}
+	}
+}
+
+// submit queues a job. In drop mode a full queue rejects the job immediately;
+// in block mode submit waits until space frees up or ctx is done.
+// It reports whether the job was queued.
+func (p *enrichmentPool) submit(ctx context.Context, job enrichmentJob) bool {
+	// Count before sending so a fast worker never drives depth negative
+	p.depth.Add(1)
+	enrichmentMetrics.Add("queue_depth", 1)
+
+	if p.block {
+		select {
+		case p.jobs <- job:
+			return true
+		case <-ctx.Done():
+		case <-p.ctx.Done():
+		}
+	} else {
+		select {
+		case p.jobs <- job:
+			return true
+		default:
+		}
+	}
+
+	p.depth.Add(-1)
+	enrichmentMetrics.Add("queue_depth", -1)
+	enrichmentMetrics.Add("dropped", 1)
+	return false
+}
+
+// queueDepth returns the number of jobs waiting for a worker
+func (p *enrichmentPool) queueDepth() int {
+	return int(p.depth.Load())
+}
+
+// wait blocks until all workers have exited after the pool's context is cancelled
+func (p *enrichmentPool) wait() {
+	p.wg.Wait()
+}
+
+// StartEnrichmentWorkers starts the pool that adds links from other platforms to
+// newly resolved songs. Until it is started, resolves skip enrichment.
+func (h *SongHandler) StartEnrichmentWorkers(ctx context.Context) {
+	h.enrichment = newEnrichmentPool(ctx, h.enrichmentWorkers, h.enrichmentQueueSize, h.enrichmentQueueMode, h.enrichSong)
+	slog.Info("Enrichment workers started", "workers", h.enrichmentWorkers, "queue_size", h.enrichmentQueueSize, "mode", h.enrichmentQueueMode)
+}
+
+// queueEnrichment schedules a stored song for cross-platform enrichment
+func (h *SongHandler) queueEnrichment(ctx context.Context, songID, isrc string) {
+	if h.enrichment == nil || songID == "" || isrc == "" {
+		return
+	}
+	if !h.enrichment.submit(ctx, enrichmentJob{songID: songID, isrc: isrc}) {
+		slog.Warn("Enrichment queue full, skipping song", "song_id", songID, "isrc", isrc)
+	}
+}
+
+// enrichSong looks the song's ISRC up on platforms it has no link for yet and
+// saves any links found, along with a third-party lyrics link if configured. Platforms are queried one at a time so each worker
+// holds at most one outstanding platform request.
+func (h *SongHandler) enrichSong(ctx context.Context, job enrichmentJob) {
+	song, err := h.songRepository.FindByID(ctx, job.songID)
+	if err != nil || song == nil {
+		slog.Warn("Enrichment skipped, song not found", "song_id", job.songID, "error", err)
+		return
+	}
+
+	added := 0
+	for _, service := range h.platformServiceList() {
+		platform := service.GetPlatformName()
+		if song.HasPlatform(platform) {
+			continue
+		}
+
+		// The hint lets platforms that miss the ISRC search the song instead
+		lookupCtx, cancel := context.WithTimeout(services.WithISRCHint(ctx, song.Title, song.Artist), 10*time.Second)
+		track, err := service.GetTrackByISRC(lookupCtx, job.isrc)
+		cancel()
+		if err != nil || track == nil {
+			slog.Debug("Enrichment lookup failed", "platform", platform, "isrc", job.isrc, "error", err)
+			continue
+		}
+
+		if err := song.AddPlatformLink(platform, track.ExternalID, track.URL, track.LinkConfidence()); err != nil {
+			slog.Warn("Rejected platform link", "platform", platform, "track_id", track.ExternalID, "error", err)
+			continue
+		}
+		added++
+	}
+
+	if h.addLyricsURL(ctx, song) {
+		added++
+	}
+
+	if added == 0 {
+		return
+	}
+	song.RecomputePrimary(primaryPreferences())
+	if err := h.songRepository.Update(ctx, song); err != nil {
+		slog.Error("Failed to save enriched song", "song_id", job.songID, "error", err)
+	}
+}
+
+// addLyricsURL looks up a third-party lyrics link for song and reports whether
+// one was set. No match and provider errors both leave the song untouched.
+func (h *SongHandler) addLyricsURL(ctx context.Context, song *models.Song) bool {
+	if h.lyricsProvider == nil || song.Metadata.LyricsURL != "" {
+		return false
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	lyricsURL, err := h.lyricsProvider.FindLyricsURL(lookupCtx, song.ISRC, song.Title, song.Artist)
+	if err != nil {
+		slog.Debug("Lyrics lookup failed", "isrc", song.ISRC, "error", err)
+		return false
+	}
+	if lyricsURL == "" {
+		return false
+	}
+	song.Metadata.LyricsURL = lyricsURL
+	return true
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichmentPool_ConcurrencyBoundedUnderBurst(t *testing.T) {
+	const workers = 3
+	const jobs = 50
+
+	var active, maxActive, processed atomic.Int32
+	handle := func(ctx context.Context, job enrichmentJob) {
+		current := active.Add(1)
+		for {
+			prev := maxActive.Load()
+			if current <= prev || maxActive.CompareAndSwap(prev, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		active.Add(-1)
+		processed.Add(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := newEnrichmentPool(ctx, workers, 10, enrichmentQueueBlock, handle)
+
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.True(t, pool.submit(context.Background(), enrichmentJob{songID: "id", isrc: "isrc"}))
+		}()
+	}
+	wg.Wait()
+
+	require.Eventually(t, func() bool { return processed.Load() == jobs }, 5*time.Second, time.Millisecond)
+	assert.LessOrEqual(t, maxActive.Load(), int32(workers))
+	assert.Equal(t, 0, pool.queueDepth())
+
+	cancel()
+	pool.wait()
+}
+
+func TestEnrichmentPool_DropsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	var started atomic.Int32
+	handle := func(ctx context.Context, job enrichmentJob) {
+		started.Add(1)
+		<-release
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := newEnrichmentPool(ctx, 1, 1, enrichmentQueueDrop, handle)
+
+	require.True(t, pool.submit(context.Background(), enrichmentJob{songID: "1"}))
+	require.Eventually(t, func() bool { return started.Load() == 1 }, time.Second, time.Millisecond)
+
+	assert.True(t, pool.submit(context.Background(), enrichmentJob{songID: "2"}))
+	assert.False(t, pool.submit(context.Background(), enrichmentJob{songID: "3"}))
+	assert.Equal(t, 1, pool.queueDepth())
+
+	close(release)
+	cancel()
+	pool.wait()
+}
+
+func TestEnrichmentPool_BlockRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	poolCtx, cancel := context.WithCancel(context.Background())
+	pool := newEnrichmentPool(poolCtx, 1, 0, enrichmentQueueBlock, func(ctx context.Context, job enrichmentJob) {
+		<-release
+	})
+
+	require.True(t, pool.submit(context.Background(), enrichmentJob{songID: "1"}))
+
+	submitCtx, submitCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer submitCancel()
+	assert.False(t, pool.submit(submitCtx, enrichmentJob{songID: "2"}))
+
+	close(release)
+	cancel()
+	pool.wait()
+}
+
+func TestEnrichSong_AddsMissingPlatformLinks(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	song := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60718").
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	track := testutil.NewTrackInfoBuilder().
+		WithPlatform("apple_music").
+		WithExternalID(testutil.AppleMusicTrackID1).
+		WithURL(testutil.AppleMusicURL1).
+		Build()
+
+	repo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(song, nil)
+	repo.On("Update", mock.Anything, song).Return(nil)
+	appleMusic.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).Return(track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+	handler.enrichSong(context.Background(), enrichmentJob{songID: "64b7f0c2a1b2c3d4e5f60718", isrc: testutil.TestISRC1})
+
+	assert.True(t, song.HasPlatform("apple_music"))
+	spotify.AssertNotCalled(t, "GetTrackByISRC", mock.Anything, mock.Anything)
+	repo.AssertCalled(t, "Update", mock.Anything, song)
+}
+
+func TestEnrichSong_NoUpdateWhenNothingFound(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	song := testutil.NewSongBuilder().WithID("64b7f0c2a1b2c3d4e5f60718").WithISRC(testutil.TestISRC1).Build()
+
+	repo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(song, nil)
+	appleMusic.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).Return(nil, &services.PlatformError{Platform: "apple_music", Category: services.ErrorCategoryNoResults})
+
+	handler := NewSongHandler(repo, "http://localhost", nil, appleMusic, nil)
+	handler.enrichSong(context.Background(), enrichmentJob{songID: "64b7f0c2a1b2c3d4e5f60718", isrc: testutil.TestISRC1})
+
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// stubLyricsProvider returns a fixed lyrics URL (or none)
+type stubLyricsProvider struct {
+	url   string
+	calls int
+}
+
+func (s *stubLyricsProvider) FindLyricsURL(ctx context.Context, isrc, title, artist string) (string, error) {
+	s.calls++
+	return s.url, nil
+}
+
+func TestEnrichSong_SetsLyricsURL(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	song := testutil.NewSongBuilder().WithID("64b7f0c2a1b2c3d4e5f60718").WithISRC(testutil.TestISRC1).Build()
+
+	repo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(song, nil)
+	repo.On("Update", mock.Anything, song).Return(nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	lyrics := &stubLyricsProvider{url: "https://www.musixmatch.com/lyrics/Test-Artist/Test-Song"}
+	handler.lyricsProvider = lyrics
+	handler.enrichSong(context.Background(), enrichmentJob{songID: "64b7f0c2a1b2c3d4e5f60718", isrc: testutil.TestISRC1})
+
+	assert.Equal(t, "https://www.musixmatch.com/lyrics/Test-Artist/Test-Song", song.Metadata.LyricsURL)
+	repo.AssertCalled(t, "Update", mock.Anything, song)
+	assert.Equal(t, lyrics.url, handler.buildResolveResponse("http://localhost", song).Song.LyricsURL)
+
+	// Already linked songs aren't looked up again
+	handler.enrichSong(context.Background(), enrichmentJob{songID: "64b7f0c2a1b2c3d4e5f60718", isrc: testutil.TestISRC1})
+	assert.Equal(t, 1, lyrics.calls)
+}
+
+func TestEnrichSong_LyricsNoMatch(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	song := testutil.NewSongBuilder().WithID("64b7f0c2a1b2c3d4e5f60718").WithISRC(testutil.TestISRC1).Build()
+
+	repo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	handler.lyricsProvider = &stubLyricsProvider{}
+	handler.enrichSong(context.Background(), enrichmentJob{songID: "64b7f0c2a1b2c3d4e5f60718", isrc: testutil.TestISRC1})
+
+	assert.Empty(t, song.Metadata.LyricsURL)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestEnrichSong_RecomputesMissingPrimary(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	song := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60718").
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	// Stored before primaries existed
+	song.PlatformLinks[0].Primary = false
+	track := testutil.NewTrackInfoBuilder().
+		WithPlatform("apple_music").
+		WithExternalID(testutil.AppleMusicTrackID1).
+		WithURL(testutil.AppleMusicURL1).
+		Build()
+
+	repo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(song, nil)
+	repo.On("Update", mock.Anything, song).Return(nil)
+	appleMusic.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).Return(track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+	handler.enrichSong(context.Background(), enrichmentJob{songID: "64b7f0c2a1b2c3d4e5f60718", isrc: testutil.TestISRC1})
+
+	primary := song.PrimaryPlatformLink()
+	require.NotNil(t, primary)
+	assert.Equal(t, "apple_music", primary.Platform)
+	assert.False(t, song.GetPlatformLink("spotify").Primary)
+}
+
+func TestEnrichSong_KeepsExistingPrimary(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	song := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60718").
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	track := testutil.NewTrackInfoBuilder().
+		WithPlatform("apple_music").
+		WithExternalID(testutil.AppleMusicTrackID1).
+		WithURL(testutil.AppleMusicURL1).
+		Build()
+
+	repo.On("FindByID", mock.Anything, "64b7f0c2a1b2c3d4e5f60718").Return(song, nil)
+	repo.On("Update", mock.Anything, song).Return(nil)
+	appleMusic.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).Return(track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+	handler.enrichSong(context.Background(), enrichmentJob{songID: "64b7f0c2a1b2c3d4e5f60718", isrc: testutil.TestISRC1})
+
+	assert.Equal(t, "spotify", song.PrimaryPlatformLink().Platform)
+	assert.False(t, song.GetPlatformLink("apple_music").Primary)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"songshare/internal/config"
+)
+
+// SearchExperimentRequest runs a search ranked with a one-off ranking config
+type SearchExperimentRequest struct {
+	Query    string `json:"query" binding:"required"`
+	Platform string `json:"platform,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+
+	// DurationMs ranks results near this track length higher, as in search
+	DurationMs int `json:"duration_ms,omitempty"`
+
+	// Ranking overrides the global ranking config for this request only;
+	// omitted or zero fields keep the global values
+	Ranking *config.RankingConfig `json:"ranking,omitempty"`
+}
+
+// ExperimentResult is one ranked song with the score breakdown behind its position
+type ExperimentResult struct {
+	Rank      int                `json:"rank"`
+	Title     string             `json:"title"`
+	Artists   []string           `json:"artists"`
+	ISRC      string             `json:"isrc,omitempty"`
+	Platforms []string           `json:"platforms"`
+	InLibrary bool               `json:"in_library"` // Already in the local catalog
+	Score     RelevanceBreakdown `json:"score"`
+}
+
+// SearchExperimentResponse is returned by the search experiment endpoint
+type SearchExperimentResponse struct {
+	Query          string                `json:"query"`
+	Ranking        *config.RankingConfig `json:"ranking"` // Effective config after overrides
+	PlatformStatus map[string]string     `json:"platform_status"`
+	Results        []ExperimentResult    `json:"results"`
+}
+
+// SearchExperiment handles POST /api/v1/search/experiment
+// It runs a search and ranks the grouped results with the request's ranking
+// overrides, without touching the global ranking config.
+func (h *SongHandler) SearchExperiment(c *gin.Context) {
+	var req SearchExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query is required"})
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+
+	searchResponse := h.performSearch(c.Request.Context(), h.renderer.BaseURL(c), SearchSongsRequest{
+		Query:      req.Query,
+		Platform:   req.Platform,
+		Limit:      req.Limit,
+		DurationMs: req.DurationMs,
+	})
+
+	ranking := config.GetRankingConfig().WithOverrides(req.Ranking)
+	c.JSON(http.StatusOK, SearchExperimentResponse{
+		Query:          req.Query,
+		Ranking:        ranking,
+		PlatformStatus: searchResponse.PlatformStatus,
+		Results:        h.rankExperiment(searchResponse, ranking, time.Now()),
+	})
+}
+
+// rankExperiment groups and ranks search results with ranking, keeping the
+// breakdowns; the query and searched duration, if any, count as in search
+func (h *SongHandler) rankExperiment(searchResponse SearchSongsResponse, ranking *config.RankingConfig, now time.Time) []ExperimentResult {
+	query := searchResponse.Query.Query
+	targetDurationMs := searchResponse.Query.DurationMs
+	grouped := h.groupSongsForDuration(searchResponse.Results, ranking, query, targetDurationMs)
+
+	results := make([]ExperimentResult, 0, len(grouped))
+	for i, song := range grouped {
+		results = append(results, h.experimentResult(i+1, song, ranking, query, targetDurationMs, now))
+	}
+	return results
+}
+
+// experimentResult describes song at rank with its score breakdown under ranking
+func (h *SongHandler) experimentResult(rank int, song GroupedSong, ranking *config.RankingConfig, query string, targetDurationMs int, now time.Time) ExperimentResult {
+	platforms := make([]string, 0, len(song.Platforms))
+	for _, result := range song.Platforms {
+		platforms = append(platforms, result.Platform)
+	}
+	return ExperimentResult{
+		Rank:      rank,
+		Title:     song.Title,
+		Artists:   song.Artists,
+		ISRC:      song.ISRC,
+		Platforms: platforms,
+		InLibrary: song.InLibrary,
+		Score:     h.relevanceBreakdown(song, ranking, query, targetDurationMs, now),
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newExperimentHandler returns a handler whose Spotify search finds a popular
+// 1975 classic and a less popular 2021 release
+func newExperimentHandler() *SongHandler {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	tidal := testutil.NewMockPlatformService("tidal")
+
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{
+		testutil.NewTrackInfoBuilder().WithExternalID("classic").WithTitle("Classic").WithISRC(testutil.TestISRC3).
+			WithPopularity(85).WithReleaseDate("1975-10-31").Build(),
+		testutil.NewTrackInfoBuilder().WithExternalID("recent").WithTitle("Recent").WithISRC(testutil.TestISRC1).
+			WithPopularity(65).WithReleaseDate("2021-06-01").Build(),
+	}, nil)
+	tidal.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{
+		testutil.NewTrackInfoBuilder().WithPlatform("tidal").WithTitle("Recent").WithISRC(testutil.TestISRC1).
+			WithReleaseDate("2021-06-01").Build(),
+	}, nil)
+
+	return NewSongHandler(repo, "http://localhost", spotify, nil, tidal)
+}
+
+func performExperiment(t *testing.T, handler *SongHandler, req SearchExperimentRequest) SearchExperimentResponse {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/search/experiment", handler.SearchExperiment)
+
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/search/experiment", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response SearchExperimentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func experimentTitles(results []ExperimentResult) []string {
+	titles := make([]string, 0, len(results))
+	for _, result := range results {
+		titles = append(titles, result.Title)
+	}
+	return titles
+}
+
+func TestSearchExperiment_OverridesChangeOrdering(t *testing.T) {
+	handler := newExperimentHandler()
+
+	// The popularity boost is off by default, so both songs score the same
+	plain := performExperiment(t, handler, SearchExperimentRequest{Query: "song", Platform: "spotify"})
+	assert.Zero(t, plain.Results[0].Score.Popularity)
+	assert.Equal(t, plain.Results[0].Score.Total, plain.Results[1].Score.Total)
+
+	// Spotify-only so both songs are on one platform and popularity decides
+	baseline := performExperiment(t, handler, SearchExperimentRequest{
+		Query:    "song",
+		Platform: "spotify",
+		Ranking:  &config.RankingConfig{PopularityBoostEnabled: true},
+	})
+	assert.Equal(t, []string{"Classic", "Recent"}, experimentTitles(baseline.Results))
+	assert.Equal(t, 35, baseline.Results[0].Score.Popularity)
+	assert.Equal(t, baseline.Results[0].Score.Platforms+baseline.Results[0].Score.Popularity, baseline.Results[0].Score.Total)
+
+	// Decaying old releases drops the classic below the recent track
+	decayed := performExperiment(t, handler, SearchExperimentRequest{
+		Query:    "song",
+		Platform: "spotify",
+		Ranking:  &config.RankingConfig{PopularityBoostEnabled: true, PopularityDecayHalfLifeYears: 20},
+	})
+	assert.Equal(t, []string{"Recent", "Classic"}, experimentTitles(decayed.Results))
+	assert.Equal(t, 20.0, decayed.Ranking.PopularityDecayHalfLifeYears)
+	assert.Equal(t, 0, decayed.Results[1].Score.Popularity)
+
+	// The override applied to this request only
+	assert.Zero(t, config.GetRankingConfig().PopularityDecayHalfLifeYears)
+	assert.False(t, config.GetRankingConfig().PopularityBoostEnabled)
+}
+
+func TestSearchExperiment_TieEpsilon(t *testing.T) {
+	handler := newExperimentHandler()
+
+	// With a wide epsilon the popularity gap is a tie, broken by raw popularity
+	// rather than by the score from the platform count
+	wide := performExperiment(t, handler, SearchExperimentRequest{
+		Query:   "song",
+		Ranking: &config.RankingConfig{TieEpsilon: 100},
+	})
+	assert.Equal(t, []string{"Classic", "Recent"}, experimentTitles(wide.Results))
+
+	narrow := performExperiment(t, handler, SearchExperimentRequest{Query: "song"})
+	assert.Equal(t, []string{"Recent", "Classic"}, experimentTitles(narrow.Results), "two platforms outscore popularity")
+}
+
+func TestSearchExperiment_PlatformOrder(t *testing.T) {
+	handler := newExperimentHandler()
+
+	defaultOrder := performExperiment(t, handler, SearchExperimentRequest{Query: "song"})
+	require.Equal(t, "Recent", defaultOrder.Results[0].Title)
+	assert.Equal(t, []string{"spotify", "tidal"}, defaultOrder.Results[0].Platforms)
+
+	tidalFirst := performExperiment(t, handler, SearchExperimentRequest{
+		Query:   "song",
+		Ranking: &config.RankingConfig{PlatformOrder: []string{"tidal", "spotify"}},
+	})
+	require.Equal(t, "Recent", tidalFirst.Results[0].Title)
+	assert.Equal(t, []string{"tidal", "spotify"}, tidalFirst.Results[0].Platforms)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Dependency states reported by /healthz
+const (
+	healthStatusOK       = "ok"
+	healthStatusDegraded = "degraded" // platform down; cached songs still serve
+	healthStatusDown     = "down"     // core dependency down; the app can't serve
+)
+
+// HealthChecker is a dependency that can report its health, such as the
+// database or the cache
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// HealthHandler reports the health of the app's dependencies
+type HealthHandler struct {
+	database         HealthChecker
+	cache            HealthChecker
+	platformServices []services.PlatformService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(database, cache HealthChecker, platformServices ...services.PlatformService) *HealthHandler {
+	return &HealthHandler{
+		database:         database,
+		cache:            cache,
+		platformServices: platformServices,
+	}
+}
+
+// GetHealth handles GET /health
+// A liveness check that calls no dependency (see GetHealthz for those). The
+// response summarizes each platform's cache use, as GET /api/v1/stats does.
+func (h *HealthHandler) GetHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":          healthStatusOK,
+		"platform_caches": h.platformCacheStats(),
+	})
+}
+
+// GetHealthz handles GET /healthz
+// Checks MongoDB, the cache and every configured platform concurrently and
+// returns each one's status. Responds 503 only when MongoDB or the cache is
+// down; a failing platform is reported as degraded.
+func (h *HealthHandler) GetHealthz(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	statuses := make(map[string]string)
+	healthy := true
+
+	check := func(name string, checker HealthChecker, core bool) {
+		defer wg.Done()
+		checkCtx, cancel := context.WithTimeout(ctx, platformHealthCheckTimeout)
+		defer cancel()
+
+		status := healthStatusOK
+		if err := checker.Health(checkCtx); err != nil {
+			status = healthStatusDegraded
+			if core {
+				status = healthStatusDown
+			}
+			slog.Warn("Health check failed", "dependency", name, "error", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		statuses[name] = status
+		if status == healthStatusDown {
+			healthy = false
+		}
+	}
+
+	wg.Add(2)
+	go check("mongodb", h.database, true)
+	go check("cache", h.cache, true)
+	for _, service := range h.platformServices {
+		if service == nil || !services.IsConfigured(service) {
+			continue
+		}
+		wg.Add(1)
+		go check(service.GetPlatformName(), service, false)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, statuses)
+}
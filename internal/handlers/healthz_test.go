@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// healthCheckerFunc adapts a function to HealthChecker
+type healthCheckerFunc func(ctx context.Context) error
+
+func (f healthCheckerFunc) Health(ctx context.Context) error {
+	return f(ctx)
+}
+
+func performHealthz(t *testing.T, handler *HealthHandler) (int, map[string]string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/healthz", handler.GetHealthz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var statuses map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+	return w.Code, statuses
+}
+
+func TestGetHealthz_PlatformFailureIsDegraded(t *testing.T) {
+	up := healthCheckerFunc(func(ctx context.Context) error { return nil })
+	spotify := testutil.NewMockPlatformService("spotify")
+	spotify.On("Health", mock.Anything).Return(nil)
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	appleMusic.On("Health", mock.Anything).Return(errors.New("timeout"))
+
+	handler := NewHealthHandler(up, up, spotify, appleMusic, nil)
+	code, statuses := performHealthz(t, handler)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]string{
+		"mongodb":     "ok",
+		"cache":       "ok",
+		"spotify":     "ok",
+		"apple_music": "degraded",
+	}, statuses)
+}
+
+func TestGetHealthz_CoreDependencyDown(t *testing.T) {
+	up := healthCheckerFunc(func(ctx context.Context) error { return nil })
+	down := healthCheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+
+	code, statuses := performHealthz(t, NewHealthHandler(down, up))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "down", statuses["mongodb"])
+	assert.Equal(t, "ok", statuses["cache"])
+
+	code, statuses = performHealthz(t, NewHealthHandler(up, down))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "down", statuses["cache"])
+}
+
+func TestGetHealthz_SkipsUnconfiguredPlatforms(t *testing.T) {
+	up := healthCheckerFunc(func(ctx context.Context) error { return nil })
+	unconfigured := services.NewSpotifyService("", "", nil)
+
+	code, statuses := performHealthz(t, NewHealthHandler(up, up, unconfigured))
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotContains(t, statuses, "spotify")
+}
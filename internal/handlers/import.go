@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importMaxItems is how many ISRCs and URLs one import request accepts
+const importMaxItems = 500
+
+// Per-item import outcomes
+const (
+	importCreated   = "created"
+	importExisting  = "existing"
+	importDuplicate = "duplicate"
+	importError     = "error"
+)
+
+// ImportRequest lists ISRCs and platform URLs to seed the catalog with
+type ImportRequest struct {
+	Items []string `json:"items"`
+}
+
+// UnmarshalJSON accepts either {"items": [...]} or a bare JSON array of items
+func (r *ImportRequest) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &r.Items)
+	}
+	type plain ImportRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// ImportItemResult is the outcome of importing one requested item
+type ImportItemResult struct {
+	Index       int    `json:"index"` // Position in the request
+	Item        string `json:"item"`
+	Status      string `json:"status"` // "created", "existing", "duplicate" or "error"
+	SongID      string `json:"song_id,omitempty"`
+	ISRC        string `json:"isrc,omitempty"`
+	Title       string `json:"title,omitempty"`
+	DuplicateOf string `json:"duplicate_of,omitempty"` // Earlier item resolving to the same song
+	Error       string `json:"error,omitempty"`
+	Details     string `json:"details,omitempty"`
+}
+
+// ImportSummary counts an import's outcomes
+type ImportSummary struct {
+	Total      int `json:"total"`
+	Created    int `json:"created"`
+	Existing   int `json:"existing"`
+	Duplicates int `json:"duplicates"`
+	Failed     int `json:"failed"`
+}
+
+// importEvent is one line of a streamed import
+type importEvent struct {
+	Type     string            `json:"type"` // "progress", "item" or "summary"
+	Resolved int               `json:"resolved,omitempty"`
+	Total    int               `json:"total,omitempty"`
+	Item     *ImportItemResult `json:"item,omitempty"`
+	Summary  *ImportSummary    `json:"summary,omitempty"`
+}
+
+// importResolution is a resolved import item before it is saved
+type importResolution struct {
+	song    *models.Song
+	stored  bool // song was already in the catalog
+	err     string
+	details string
+}
+
+// ImportCatalog handles POST /api/v1/admin/import
+// Seeds the catalog from a list of ISRCs and platform URLs, resolving them
+// concurrently and saving new songs in one batch. Progress streams as NDJSON:
+// a progress line per resolved item, then one line per item in request order
+// and a summary. Items resolving to the same song are reported as duplicates
+// of the first. Admin only: register behind RequireAdmin.
+func (h *SongHandler) ImportCatalog(c *gin.Context) {
+	var req ImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(req.Items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No items to import"})
+		return
+	}
+	if len(req.Items) > importMaxItems {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Too many items",
+			"details": fmt.Sprintf("at most %d items can be imported per request", importMaxItems),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+	emit := func(event importEvent) {
+		if err := encoder.Encode(event); err != nil {
+			slog.Debug("Failed to write import progress", "error", err)
+		}
+		c.Writer.Flush()
+	}
+
+	// Resolve each distinct item once; repeats are reported as duplicates
+	unique := make([]string, 0, len(req.Items))
+	seen := make(map[string]int, len(req.Items))
+	for _, item := range req.Items {
+		key := importItemKey(item)
+		if _, ok := seen[key]; !ok {
+			seen[key] = len(unique)
+			unique = append(unique, key)
+		}
+	}
+
+	resolutions := make([]importResolution, len(unique))
+	jobs := make(chan int)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < min(resolveBatchWorkers, len(unique)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				resolutions[j] = h.resolveImportItem(ctx, unique[j])
+				done <- struct{}{}
+			}
+		}()
+	}
+	go func() {
+		for j := range unique {
+			jobs <- j
+		}
+		close(jobs)
+	}()
+	for resolved := 1; resolved <= len(unique); resolved++ {
+		<-done
+		emit(importEvent{Type: "progress", Resolved: resolved, Total: len(unique)})
+	}
+	wg.Wait()
+
+	results, songs := collectImportResults(req.Items, seen, resolutions)
+
+	var created []*models.Song
+	for i, result := range results {
+		if result.Status == importCreated {
+			created = append(created, songs[i])
+		}
+	}
+	saved := h.saveSongs(ctx, created)
+
+	summary := ImportSummary{Total: len(results)}
+	for i := range results {
+		result := &results[i]
+		song := songs[i]
+		if result.Status == importCreated {
+			if !saved[song] {
+				result.Status = importError
+				result.Error = "Failed to save song"
+			} else {
+				h.queueEnrichment(ctx, song.ID.Hex(), song.ISRC)
+			}
+		}
+
+		switch result.Status {
+		case importCreated:
+			summary.Created++
+		case importExisting:
+			summary.Existing++
+		case importDuplicate:
+			summary.Duplicates++
+		default:
+			summary.Failed++
+		}
+		if result.Status != importError {
+			if !song.ID.IsZero() {
+				result.SongID = song.ID.Hex()
+			}
+			result.ISRC = song.ISRC
+			result.Title = song.Title
+		}
+		emit(importEvent{Type: "item", Item: result})
+	}
+	emit(importEvent{Type: "summary", Summary: &summary})
+}
+
+// collectImportResults maps each requested item to its resolution in request
+// order. The first item resolving to a song owns it; later items resolving to
+// the same song are duplicates, and their platform links join the owner when
+// the song is new. Returns the results and each result's song.
+func collectImportResults(items []string, seen map[string]int, resolutions []importResolution) ([]ImportItemResult, []*models.Song) {
+	results := make([]ImportItemResult, len(items))
+	songs := make([]*models.Song, len(items))
+	owners := make(map[string]int)
+	for i, item := range items {
+		resolution := resolutions[seen[importItemKey(item)]]
+		results[i] = ImportItemResult{Index: i, Item: item}
+		if resolution.song == nil {
+			results[i].Status = importError
+			results[i].Error = resolution.err
+			results[i].Details = resolution.details
+			continue
+		}
+
+		key := saveRetryKey(resolution.song)
+		if resolution.stored && resolution.song.ISRC == "" {
+			key = "id:" + resolution.song.ID.Hex()
+		}
+		if owner, ok := owners[key]; ok && key != "" {
+			owned := songs[owner]
+			if results[owner].Status == importCreated && owned != resolution.song {
+				for _, link := range resolution.song.PlatformLinks {
+					if owned.HasPlatform(link.Platform) {
+						continue
+					}
+					if err := owned.AddPlatformLink(link.Platform, link.ExternalID, link.URL, link.Confidence); err != nil {
+						slog.Warn("Rejected platform link", "platform", link.Platform, "isrc", owned.ISRC, "error", err)
+					}
+				}
+			}
+			results[i].Status = importDuplicate
+			results[i].DuplicateOf = items[owner]
+			songs[i] = owned
+			continue
+		}
+
+		owners[key] = i
+		songs[i] = resolution.song
+		results[i].Status = importCreated
+		if resolution.stored {
+			results[i].Status = importExisting
+		}
+	}
+	return results, songs
+}
+
+// importItemKey identifies an import item: the normalized code for an ISRC,
+// otherwise the trimmed URL
+func importItemKey(item string) string {
+	if isrc, err := models.NormalizeISRC(item); err == nil {
+		return isrc
+	}
+	return strings.TrimSpace(item)
+}
+
+// resolveImportItem resolves one ISRC or platform URL without saving it
+func (h *SongHandler) resolveImportItem(ctx context.Context, item string) importResolution {
+	if isrc, err := models.NormalizeISRC(item); err == nil {
+		song, err := h.songRepository.FindByISRC(ctx, isrc)
+		if err != nil {
+			return importResolution{err: "Failed to look up ISRC", details: err.Error()}
+		}
+		if song != nil {
+			return importResolution{song: song, stored: true}
+		}
+		if song = h.lookupISRCSong(ctx, isrc); song == nil {
+			return importResolution{err: "Song not found", details: "no platform recognizes this ISRC"}
+		}
+		return importResolution{song: song}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.batchURLTimeout)
+	defer cancel()
+
+	platformService, trackID, urlErr := h.parseResolveURL(ctx, item)
+	if urlErr != nil {
+		return importResolution{err: urlErr.message, details: urlErr.details}
+	}
+
+	song, status, err := h.resolveSongFromPlatform(ctx, platformService, trackID, false)
+	if err != nil {
+		slog.Error("Failed to resolve import item", "item", item, "error", err)
+		return importResolution{err: "Failed to resolve song from URL", details: h.batchErrorDetails(err)}
+	}
+	if song == nil {
+		return importResolution{err: "Song not found"}
+	}
+	return importResolution{song: song, stored: status == resolveStored}
+}
+
+// saveSongs stores new songs in one bulk upsert, so a song a concurrent resolve
+// inserted first is replaced rather than failing the batch. It falls back to
+// one save per song when the batch fails.
+// It reports for each song whether it ended up stored.
+func (h *SongHandler) saveSongs(ctx context.Context, songs []*models.Song) map[*models.Song]bool {
+	saved := make(map[*models.Song]bool, len(songs))
+	if len(songs) == 0 {
+		return saved
+	}
+
+	err := h.songRepository.UpsertMany(ctx, songs)
+	for _, song := range songs {
+		saved[song] = err == nil
+	}
+	if err == nil {
+		return saved
+	}
+	slog.Warn("Batch save failed, saving songs individually", "count", len(songs), "error", err)
+
+	for _, song := range songs {
+		err := h.songRepository.Save(ctx, song)
+		if err == nil {
+			saved[song] = true
+			continue
+		}
+
+		var dupErr *repositories.DuplicateSongError
+		if errors.As(err, &dupErr) && dupErr.ISRC != "" {
+			if existing, findErr := h.songRepository.FindByISRC(ctx, dupErr.ISRC); findErr == nil && existing != nil {
+				*song = *existing
+				saved[song] = true
+				continue
+			}
+		}
+		slog.Error("Failed to save song", "isrc", song.ISRC, "title", song.Title, "error", err)
+	}
+	return saved
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/models"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func performImport(t *testing.T, handler *SongHandler, body string) (*httptest.ResponseRecorder, []importEvent) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/admin/import", handler.ImportCatalog)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/import", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var events []importEvent
+	scanner := bufio.NewScanner(bytes.NewReader(w.Body.Bytes()))
+	for w.Code == http.StatusOK && scanner.Scan() {
+		var event importEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	return w, events
+}
+
+func TestImportCatalog_MixedList(t *testing.T) {
+	stored := testutil.NewSongBuilder().WithISRC(testutil.TestISRC1).Build()
+	stored.ID = primitive.NewObjectID()
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(stored, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC2).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC2).Return(nil, nil)
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByTitleArtist", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	var savedBatch []*models.Song
+	repo.On("UpsertMany", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		savedBatch = args.Get(1).([]*models.Song)
+		for _, song := range savedBatch {
+			song.ID = primitive.NewObjectID()
+		}
+	}).Return(nil)
+
+	spotify := testutil.NewMockPlatformService("spotify")
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1,
+		testutil.NewTrackInfoBuilder().
+			WithExternalID(testutil.SpotifyTrackID1).
+			WithURL(testutil.SpotifyURL1).
+			WithISRC(testutil.TestISRC2).
+			Build(), nil)
+	spotify.On("GetTrackByISRC", mock.Anything, testutil.TestISRC2).Return(nil, assert.AnError)
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	appleMusic.On("GetTrackByISRC", mock.Anything, testutil.TestISRC2).Return(
+		testutil.NewTrackInfoBuilder().
+			WithPlatform("apple_music").
+			WithExternalID(testutil.AppleMusicTrackID1).
+			WithURL(testutil.AppleMusicURL1).
+			WithISRC(testutil.TestISRC2).
+			Build(), nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+	items := []string{
+		testutil.TestISRC1,
+		testutil.SpotifyURL1,
+		testutil.TestISRC2,
+		"us-um7-17-03861",
+		"https://example.com/not-a-track",
+	}
+	body, err := json.Marshal(items)
+	require.NoError(t, err)
+	w, events := performImport(t, handler, string(body))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+
+	// One progress line per distinct item, then the items in order and a summary
+	require.Len(t, events, 4+len(items)+1)
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, "progress", events[i].Type)
+		assert.Equal(t, i+1, events[i].Resolved)
+		assert.Equal(t, 4, events[i].Total)
+	}
+	results := make([]ImportItemResult, len(items))
+	for i := range items {
+		event := events[4+i]
+		require.Equal(t, "item", event.Type)
+		require.NotNil(t, event.Item)
+		assert.Equal(t, i, event.Item.Index)
+		assert.Equal(t, items[i], event.Item.Item)
+		results[i] = *event.Item
+	}
+
+	assert.Equal(t, importExisting, results[0].Status)
+	assert.Equal(t, stored.ID.Hex(), results[0].SongID)
+
+	assert.Equal(t, importCreated, results[1].Status)
+	assert.Equal(t, testutil.TestISRC2, results[1].ISRC)
+	assert.NotEmpty(t, results[1].SongID)
+
+	assert.Equal(t, importDuplicate, results[2].Status)
+	assert.Equal(t, testutil.SpotifyURL1, results[2].DuplicateOf)
+	assert.Equal(t, results[1].SongID, results[2].SongID)
+
+	assert.Equal(t, importDuplicate, results[3].Status)
+	assert.Equal(t, testutil.TestISRC1, results[3].DuplicateOf)
+	assert.Equal(t, stored.ID.Hex(), results[3].SongID)
+
+	assert.Equal(t, importError, results[4].Status)
+	assert.Equal(t, "Invalid platform URL", results[4].Error)
+
+	summary := events[len(events)-1]
+	require.Equal(t, "summary", summary.Type)
+	assert.Equal(t, ImportSummary{Total: 5, Created: 1, Existing: 1, Duplicates: 2, Failed: 1}, *summary.Summary)
+
+	// The new song is saved once, with the links from both items that found it
+	require.Len(t, savedBatch, 1)
+	assert.True(t, savedBatch[0].HasPlatform("spotify"))
+	assert.True(t, savedBatch[0].HasPlatform("apple_music"))
+	repo.AssertNumberOfCalls(t, "UpsertMany", 1)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	repo.AssertNumberOfCalls(t, "FindByISRC", 3)
+}
+
+func TestImportCatalog_RejectsOversizedBatch(t *testing.T) {
+	handler := NewSongHandler(&testutil.MockSongRepository{}, "http://localhost", nil, nil, nil)
+	items := make([]string, importMaxItems+1)
+	for i := range items {
+		items[i] = testutil.TestISRC1
+	}
+	body, err := json.Marshal(ImportRequest{Items: items})
+	require.NoError(t, err)
+
+	w, _ := performImport(t, handler, string(body))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// isrcLookupTimeout bounds the live cross-platform ISRC lookup
+const isrcLookupTimeout = 10 * time.Second
+
+// songLinkLiveResolveTimeout bounds a universal link's live resolution, which
+// a visitor waits on
+const songLinkLiveResolveTimeout = 5 * time.Second
+
+// Default pacing of universal link live resolution, matching the config defaults
+const (
+	defaultLiveResolveRatePerSecond = 1.0
+	defaultLiveResolveBurst         = 5
+)
+
+// ResolveISRC handles GET /api/v1/isrc/:isrc
+// Returns the song for an ISRC, resolving it live across platforms if it isn't in the catalog yet.
+func (h *SongHandler) ResolveISRC(c *gin.Context) {
+	isrc, err := models.NormalizeISRC(c.Param("isrc"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Invalid ISRC",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	song, err := h.songRepository.FindByISRC(ctx, isrc)
+	if err != nil {
+		slog.Error("ISRC lookup failed", "isrc", isrc, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to look up ISRC",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if song == nil {
+		song, err = h.resolveISRCLive(ctx, isrc)
+		if err != nil {
+			slog.Error("Failed to resolve ISRC", "isrc", isrc, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to resolve ISRC",
+				"details": err.Error(),
+			})
+			return
+		}
+		if song == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "No platform recognizes ISRC " + isrc,
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, h.buildResolveResponse(h.renderer.BaseURL(c), song))
+}
+
+// resolveISRCLive looks the ISRC up on every platform and saves a song linking
+// all platforms that know it. Returns nil if no platform recognizes the ISRC.
+func (h *SongHandler) resolveISRCLive(ctx context.Context, isrc string) (*models.Song, error) {
+	song := h.lookupISRCSong(ctx, isrc)
+	if song == nil {
+		return nil, nil
+	}
+
+	if err := h.songRepository.Save(ctx, song); err != nil {
+		// Another request may have created the song while we were looking it up
+		var dupErr *repositories.DuplicateSongError
+		if errors.As(err, &dupErr) {
+			return h.songRepository.FindByISRC(ctx, isrc)
+		}
+		return nil, fmt.Errorf("failed to save song: %w", err)
+	}
+
+	return song, nil
+}
+
+// resolveSongLinkLive resolves a universal link whose ISRC isn't in the
+// catalog yet, saving the song. Returns nil when live resolution is off, the
+// identifier isn't an ISRC, the rate limit is reached or no platform knows it.
+func (h *SongHandler) resolveSongLinkLive(ctx context.Context, identifier string) *models.Song {
+	if !h.liveResolveLinks {
+		return nil
+	}
+	isrc, err := models.NormalizeISRC(strings.TrimRight(identifier, "/"))
+	if err != nil {
+		return nil
+	}
+	if !h.liveResolveLimiter.Allow() {
+		slog.Debug("Skipping live song link resolution, rate limit reached", "isrc", isrc)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, songLinkLiveResolveTimeout)
+	defer cancel()
+
+	song, err := h.resolveISRCLive(ctx, isrc)
+	if err != nil {
+		slog.Error("Failed to resolve song link live", "isrc", isrc, "error", err)
+		return nil
+	}
+	if song != nil {
+		slog.Info("Resolved song link live", "isrc", isrc, "songID", song.ID.Hex())
+	}
+	return song
+}
+
+// lookupISRCSong builds an unsaved song linking every platform that knows the
+// ISRC. Returns nil if no platform recognizes it.
+func (h *SongHandler) lookupISRCSong(ctx context.Context, isrc string) *models.Song {
+	lookupCtx, cancel := context.WithTimeout(ctx, isrcLookupTimeout)
+	defer cancel()
+
+	platforms := h.platformServiceList()
+	tracks := services.LookupISRCAllPlatforms(lookupCtx, isrc, platforms)
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	// Metadata comes from the most preferred platform that found the track
+	var song *models.Song
+	for _, platform := range platforms {
+		track, found := tracks[platform.GetPlatformName()]
+		if !found {
+			continue
+		}
+		if song == nil {
+			song = track.ToSong()
+			song.ISRC = isrc
+			continue
+		}
+		if err := song.AddPlatformLink(track.Platform, track.ExternalID, track.URL, track.LinkConfidence()); err != nil {
+			slog.Warn("Rejected platform link", "platform", track.Platform, "isrc", isrc, "error", err)
+		}
+		if song.Metadata.ImageURL == "" {
+			song.Metadata.ImageURL = track.ImageURL
+		}
+	}
+
+	return song
+}
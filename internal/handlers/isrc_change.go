@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"songshare/internal/models"
+	"songshare/internal/services"
+)
+
+// defaultLinkRefreshAge is how old a stored platform link can get before a
+// resolve re-fetches the track to pick up upstream corrections
+const defaultLinkRefreshAge = 7 * 24 * time.Hour
+
+// refreshStoredSong re-fetches a song matched by platform ID when its link is
+// stale, recording the verification and reconciling any ISRC change. A track
+// the platform no longer has marks the link unavailable; other failures are
+// logged and the stored song is returned unchanged. Links matched at reduced
+// confidence, such as Tidal's ISRC search fallback, never rekey the song: the
+// linked track's ISRC is expected to differ from the stored one.
+func (h *SongHandler) refreshStoredSong(ctx context.Context, platformService services.PlatformService, trackID string, song *models.Song) *models.Song {
+	platform := platformService.GetPlatformName()
+	link := song.GetPlatformLink(platform)
+	if h.linkRefreshAge <= 0 || link == nil || time.Since(link.LastVerified) < h.linkRefreshAge {
+		return song
+	}
+
+	trackInfo, err := platformService.GetTrackByID(ctx, trackID)
+	if services.ClassifyError(err) == services.ErrorCategoryNoResults {
+		return h.markLinkUnavailable(ctx, song, platform)
+	}
+	if err != nil {
+		slog.Warn("Failed to refresh stored track", "platform", platform, "track_id", trackID, "error", err)
+		return song
+	}
+
+	linkURL := trackInfo.URL
+	if linkURL == "" {
+		linkURL = link.URL
+	}
+	if err := song.AddPlatformLink(platform, trackID, linkURL, link.Confidence); err != nil {
+		slog.Warn("Rejected platform link", "platform", platform, "track_id", trackID, "error", err)
+	}
+
+	if link.Confidence < 1.0 || trackInfo.LinkConfidence() < 1.0 {
+		if err := h.songRepository.Update(ctx, song); err != nil {
+			slog.Error("Failed to update refreshed song", "song_id", song.ID.Hex(), "error", err)
+		}
+		return song
+	}
+
+	reconciled, changed, err := h.reconcileISRC(ctx, song, trackInfo.ISRC, platform)
+	if err != nil {
+		slog.Error("Failed to reconcile ISRC change", "song_id", song.ID.Hex(), "error", err)
+		return song
+	}
+	if !changed {
+		if err := h.songRepository.Update(ctx, song); err != nil {
+			slog.Error("Failed to update refreshed song", "song_id", song.ID.Hex(), "error", err)
+		}
+	}
+	return reconciled
+}
+
+// reconcileISRC applies a platform-reported ISRC that differs from the stored one.
+// If another song already holds the new ISRC, this song is merged into it and
+// deleted, and the surviving song is returned. Either way the replaced ISRC
+// stays on the surviving song as an alias and the change is added to its
+// ISRC history. It reports whether anything changed.
+func (h *SongHandler) reconcileISRC(ctx context.Context, song *models.Song, freshISRC, platform string) (*models.Song, bool, error) {
+	freshISRC, err := models.NormalizeISRC(freshISRC)
+	if err != nil || freshISRC == song.ISRC {
+		return song, false, nil
+	}
+
+	oldISRC := song.ISRC
+	existing, err := h.songRepository.FindByISRC(ctx, freshISRC)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check ISRC collision: %w", err)
+	}
+
+	change := models.ISRCChange{From: oldISRC, To: freshISRC, Platform: platform, ChangedAt: time.Now()}
+	if existing == nil || existing.ID == song.ID {
+		song.ReplaceISRC(freshISRC)
+		song.RecordISRCChange(change)
+		if err := h.songRepository.Update(ctx, song); err != nil {
+			return nil, false, fmt.Errorf("failed to update song ISRC: %w", err)
+		}
+		slog.Info("Song ISRC changed",
+			"song_id", song.ID.Hex(),
+			"platform", platform,
+			"old_isrc", oldISRC,
+			"new_isrc", freshISRC)
+		return song, true, nil
+	}
+
+	existing.MergeFrom(song)
+	change.MergedFrom = song.ID.Hex()
+	existing.RecordISRCChange(change)
+	// MergeFrom keeps the survivor's own link for a platform both songs have,
+	// but the one just refreshed is the platform's current answer
+	if refreshed := song.GetPlatformLink(platform); refreshed != nil {
+		if err := existing.AddPlatformLink(platform, refreshed.ExternalID, refreshed.URL, refreshed.Confidence); err != nil {
+			slog.Warn("Rejected platform link", "platform", platform, "track_id", refreshed.ExternalID, "error", err)
+		}
+	}
+	if err := h.songRepository.Update(ctx, existing); err != nil {
+		return nil, false, fmt.Errorf("failed to update merged song: %w", err)
+	}
+	if err := h.songRepository.DeleteByID(ctx, song.ID.Hex()); err != nil {
+		// The merged song is already complete; the stale duplicate is only clutter
+		slog.Error("Failed to delete song merged after ISRC change", "song_id", song.ID.Hex(), "error", err)
+	}
+
+	slog.Info("Song ISRC changed, merged into existing song",
+		"song_id", song.ID.Hex(),
+		"merged_into", existing.ID.Hex(),
+		"platform", platform,
+		"old_isrc", oldISRC,
+		"new_isrc", freshISRC)
+	return existing, true, nil
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"songshare/internal/models"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	staleSongID    = "64b7f0c2a1b2c3d4e5f60001"
+	existingSongID = "64b7f0c2a1b2c3d4e5f60002"
+	correctedISRC  = "USUM72000001"
+)
+
+// staleSpotifySong returns a stored song whose Spotify link was last verified long ago
+func staleSpotifySong() *models.Song {
+	song := testutil.NewSongBuilder().
+		WithID(staleSongID).
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	song.PlatformLinks[0].LastVerified = time.Now().Add(-30 * 24 * time.Hour)
+	return song
+}
+
+func TestResolveSong_ISRCChangeMergesIntoCollidingSong(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	stale := staleSpotifySong()
+	existing := testutil.NewSongBuilder().
+		WithID(existingSongID).
+		WithISRC(correctedISRC).
+		WithAppleMusicLink(testutil.AppleMusicTrackID1, testutil.AppleMusicURL1).
+		Build()
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithURL(testutil.SpotifyURL1).
+		WithISRC(correctedISRC).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stale, nil)
+	repo.On("FindByISRC", mock.Anything, correctedISRC).Return(existing, nil)
+	repo.On("Update", mock.Anything, existing).Return(nil)
+	repo.On("DeleteByID", mock.Anything, staleSongID).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, existingSongID, response.Song.ID)
+	assert.True(t, existing.HasPlatform("spotify"))
+	assert.True(t, existing.HasPlatform("apple_music"))
+	assert.Equal(t, correctedISRC, existing.ISRC)
+	assert.Equal(t, []string{testutil.TestISRC1}, existing.PreviousISRCs)
+	require.Len(t, existing.ISRCHistory, 1)
+	assert.Equal(t, testutil.TestISRC1, existing.ISRCHistory[0].From)
+	assert.Equal(t, correctedISRC, existing.ISRCHistory[0].To)
+	assert.Equal(t, "spotify", existing.ISRCHistory[0].Platform)
+	assert.Equal(t, staleSongID, existing.ISRCHistory[0].MergedFrom)
+	repo.AssertCalled(t, "DeleteByID", mock.Anything, staleSongID)
+	repo.AssertNotCalled(t, "Update", mock.Anything, stale)
+}
+
+func TestResolveSong_ISRCChangeMergeKeepsRefreshedLink(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	stale := staleSpotifySong()
+	// The surviving song has an outdated link for the same platform
+	existing := testutil.NewSongBuilder().
+		WithID(existingSongID).
+		WithISRC(correctedISRC).
+		WithSpotifyLink(testutil.SpotifyTrackID2, testutil.SpotifyURL2).
+		Build()
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithURL(testutil.SpotifyURL1).
+		WithISRC(correctedISRC).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stale, nil)
+	repo.On("FindByISRC", mock.Anything, correctedISRC).Return(existing, nil)
+	repo.On("Update", mock.Anything, existing).Return(nil)
+	repo.On("DeleteByID", mock.Anything, staleSongID).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, _ := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, existing.PlatformLinks, 1)
+	assert.Equal(t, testutil.SpotifyTrackID1, existing.PlatformLinks[0].ExternalID)
+	assert.Equal(t, testutil.SpotifyURL1, existing.PlatformLinks[0].URL)
+	repo.AssertCalled(t, "DeleteByID", mock.Anything, staleSongID)
+}
+
+func TestResolveSong_ISRCChangeWithoutCollisionUpdatesSong(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	stale := staleSpotifySong()
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithURL(testutil.SpotifyURL1).
+		WithISRC(correctedISRC).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stale, nil)
+	repo.On("FindByISRC", mock.Anything, correctedISRC).Return(nil, nil)
+	repo.On("Update", mock.Anything, stale).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, staleSongID, response.Song.ID)
+	assert.Equal(t, correctedISRC, stale.ISRC)
+	assert.Equal(t, []string{testutil.TestISRC1}, stale.PreviousISRCs)
+	require.Len(t, stale.ISRCHistory, 1)
+	assert.Equal(t, testutil.TestISRC1, stale.ISRCHistory[0].From)
+	assert.Equal(t, correctedISRC, stale.ISRCHistory[0].To)
+	assert.NotZero(t, stale.ISRCHistory[0].ChangedAt)
+	assert.Empty(t, stale.ISRCHistory[0].MergedFrom)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+	repo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestResolveSong_FreshLinkSkipsRefresh(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	song := testutil.NewSongBuilder().
+		WithID(staleSongID).
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, _ := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	spotify.AssertNotCalled(t, "GetTrackByID", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestResolveSong_LowConfidenceLinkKeepsISRC(t *testing.T) {
+	tests := []struct {
+		name            string
+		linkConfidence  float64
+		matchConfidence float64
+	}{
+		{"Stored link matched at reduced confidence", 0.85, 0},
+		{"Refetched track found by a fallback", 1.0, 0.85},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &testutil.MockSongRepository{}
+			spotify := testutil.NewMockPlatformService("spotify")
+			stale := staleSpotifySong()
+			stale.PlatformLinks[0].Confidence = tt.linkConfidence
+			track := testutil.NewTrackInfoBuilder().
+				WithExternalID(testutil.SpotifyTrackID1).
+				WithURL(testutil.SpotifyURL1).
+				WithISRC(correctedISRC).
+				Build()
+			track.MatchConfidence = tt.matchConfidence
+
+			repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stale, nil)
+			repo.On("Update", mock.Anything, stale).Return(nil)
+			testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+			handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+			w, response := performResolve(t, handler, "")
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, staleSongID, response.Song.ID)
+			assert.Equal(t, testutil.TestISRC1, stale.ISRC)
+			repo.AssertNotCalled(t, "FindByISRC", mock.Anything, mock.Anything)
+			repo.AssertNumberOfCalls(t, "Update", 1)
+		})
+	}
+}
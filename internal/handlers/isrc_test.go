@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/handlers/render"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performISRCLookup(handler *SongHandler, isrc string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/isrc/:isrc", handler.ResolveISRC)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/isrc/"+isrc, nil))
+	return w
+}
+
+func TestResolveISRC_KnownLocal(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	song := testutil.CreateTestSong()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w := performISRCLookup(handler, "us-um7-17-03861")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response render.ResolveSongResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "http://localhost/s/"+testutil.TestISRC1, response.UniversalLink)
+	spotify.AssertNotCalled(t, "GetTrackByISRC", mock.Anything, mock.Anything)
+}
+
+func TestResolveISRC_ResolvesLive(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).Return(nil)
+	spotify.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).Return(
+		testutil.NewTrackInfoBuilder().
+			WithExternalID(testutil.SpotifyTrackID1).
+			WithURL(testutil.SpotifyURL1).
+			WithISRC(testutil.TestISRC1).
+			Build(), nil)
+	appleMusic.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).Return(
+		testutil.NewTrackInfoBuilder().
+			WithPlatform("apple_music").
+			WithExternalID(testutil.AppleMusicTrackID1).
+			WithURL(testutil.AppleMusicURL1).
+			WithISRC(testutil.TestISRC1).
+			WithImageURL("https://example.com/art.jpg").
+			Build(), nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+	w := performISRCLookup(handler, testutil.TestISRC1)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response render.ResolveSongResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Platforms, "spotify")
+	assert.Contains(t, response.Platforms, "apple_music")
+	assert.Equal(t, "https://example.com/art.jpg", response.Song.ImageURL)
+	assert.Equal(t, testutil.TestISRC1, response.Song.ISRC)
+	repo.AssertCalled(t, "Save", mock.Anything, mock.AnythingOfType("*models.Song"))
+}
+
+func TestResolveISRC_UnknownToAllPlatforms(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	spotify.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).
+		Return(nil, &services.PlatformError{Platform: "spotify", Operation: "get_by_isrc", Message: "not found"})
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w := performISRCLookup(handler, testutil.TestISRC1)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "No platform recognizes ISRC")
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestResolveISRC_Invalid(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+
+	for _, isrc := range []string{"not-an-isrc", "USUM7170386", "12UM71703861"} {
+		w := performISRCLookup(handler, isrc)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, isrc)
+	}
+	repo.AssertNotCalled(t, "FindByISRC", mock.Anything, mock.Anything)
+}
+
+func TestRedirectToSong_NormalizesISRC(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	song := testutil.CreateTestSong()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/s/:id", handler.RedirectToSong)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/s/us-um7-17-03861", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertCalled(t, "FindByISRC", mock.Anything, testutil.TestISRC1)
+}
+
+func TestRedirectToSong_CaseAndTrailingSlashVariants(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	song := testutil.CreateTestSong()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+	repo.On("FindByISRC", mock.Anything, "6543210ABCDE").Return(nil, nil)
+	repo.On("FindByIDPrefix", mock.Anything, "6543210abcde").Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/s/:id", handler.RedirectToSong)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Lowercase ISRCs and ID prefixes typed in uppercase both find the song
+	assert.Equal(t, http.StatusOK, get("/s/usum71703861").Code)
+	assert.Equal(t, http.StatusOK, get("/s/6543210ABCDE").Code)
+
+	// A trailing slash redirects to the canonical link
+	w := get("/s/usum71703861/")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/s/usum71703861", w.Header().Get("Location"))
+
+	// Identifiers taken from other paths, such as oEmbed URLs, may keep the slash
+	found, err := handler.findSongByISRC(t.Context(), "usum71703861/")
+	require.NoError(t, err)
+	assert.Same(t, song, found)
+}
+
+func performSongLink(handler *SongHandler, identifier string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/s/:id", handler.RedirectToSong)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/s/"+identifier, nil)
+	req.Header.Set("Accept", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRedirectToSong_MissResolvesLive(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByIDPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).Return(nil)
+	spotify.On("GetTrackByISRC", mock.Anything, testutil.TestISRC1).Return(
+		testutil.NewTrackInfoBuilder().
+			WithExternalID(testutil.SpotifyTrackID1).
+			WithURL(testutil.SpotifyURL1).
+			WithISRC(testutil.TestISRC1).
+			WithImageURL("https://example.com/art.jpg").
+			Build(), nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+
+	// Off by default: the miss stays a 404
+	assert.Equal(t, http.StatusNotFound, performSongLink(handler, testutil.TestISRC1).Code)
+	spotify.AssertNotCalled(t, "GetTrackByISRC", mock.Anything, mock.Anything)
+
+	handler.liveResolveLinks = true
+	w := performSongLink(handler, "us-um7-17-03861")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), testutil.SpotifyURL1)
+	repo.AssertCalled(t, "Save", mock.Anything, mock.AnythingOfType("*models.Song"))
+
+	// Beyond the rate limit a miss is a 404 without platform calls
+	handler.liveResolveLimiter = newTokenBucket(0.001, 1)
+	handler.liveResolveLimiter.Allow()
+	assert.Equal(t, http.StatusNotFound, performSongLink(handler, testutil.TestISRC1).Code)
+	spotify.AssertNumberOfCalls(t, "GetTrackByISRC", 1)
+}
+
+func TestRedirectToSong_InvalidISRCMissIsNotResolvedLive(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	repo.On("FindByISRC", mock.Anything, "not-an-isrc").Return(nil, nil)
+	repo.On("FindByIDPrefix", mock.Anything, "not-an-isrc").Return(nil, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.liveResolveLinks = true
+
+	assert.Equal(t, http.StatusNotFound, performSongLink(handler, "not-an-isrc").Code)
+	spotify.AssertNotCalled(t, "GetTrackByISRC", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestFindSongByISRC_FallsBackToPreviousISRC(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	song := testutil.NewSongBuilder().WithISRC("USUM72000001").Build()
+	song.PreviousISRCs = []string{testutil.TestISRC1}
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	found, err := handler.findSongByISRC(t.Context(), "usum71703861")
+
+	require.NoError(t, err)
+	assert.Same(t, song, found)
+	repo.AssertNotCalled(t, "FindByIDPrefix", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"songshare/internal/handlers/render"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SongLinksResponse is returned by the song links endpoint
+type SongLinksResponse struct {
+	ISRC  string                `json:"isrc"`
+	Links []render.PlatformLink `json:"links"`
+	// Best is the link to open for the ?prefer= order, falling back to any
+	// available link and then to any link
+	Best *render.PlatformLink `json:"best,omitempty"`
+}
+
+// GetSongLinks handles GET /api/v1/s/:id/links
+// The optional ?prefer= parameter is a comma-separated platform preference
+// chain (e.g. "tidal,apple_music") used to pick the best link.
+func (h *SongHandler) GetSongLinks(c *gin.Context) {
+	song, err := h.findSongByISRC(c.Request.Context(), c.Param("id"))
+	if err != nil || song == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+		return
+	}
+
+	response := SongLinksResponse{
+		ISRC:  song.ISRC,
+		Links: make([]render.PlatformLink, 0, len(song.PlatformLinks)),
+	}
+	for _, link := range song.PlatformLinks {
+		response.Links = append(response.Links, render.PlatformLink{
+			URL:       link.URL,
+			Available: link.Available,
+			Platform:  link.Platform,
+			Primary:   link.Primary,
+		})
+	}
+
+	if best := song.BestPlatformLink(parsePreferChain(c.Query("prefer"))); best != nil {
+		response.Best = &render.PlatformLink{
+			URL:       best.URL,
+			Available: best.Available,
+			Platform:  best.Platform,
+			Primary:   best.Primary,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parsePreferChain splits a comma-separated ?prefer= value into platform names
+func parsePreferChain(prefer string) []string {
+	var preferences []string
+	for _, platform := range strings.Split(prefer, ",") {
+		if platform = strings.ToLower(strings.TrimSpace(platform)); platform != "" {
+			preferences = append(preferences, platform)
+		}
+	}
+	return preferences
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performLinksLookup(handler *SongHandler, target string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/s/:id/links", handler.GetSongLinks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestGetSongLinks_PreferChain(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	song := testutil.CreateTestSongWithPlatforms()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC2).Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	w := performLinksLookup(handler, "/api/v1/s/"+testutil.TestISRC2+"/links?prefer=tidal,%20Apple_Music,spotify")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response SongLinksResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Links, 2)
+	require.NotNil(t, response.Best)
+	assert.Equal(t, "apple_music", response.Best.Platform)
+	assert.Equal(t, testutil.AppleMusicURL1, response.Best.URL)
+}
+
+func TestGetSongLinks_NoPreference(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	song := testutil.CreateTestSongWithPlatforms()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC2).Return(song, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	w := performLinksLookup(handler, "/api/v1/s/"+testutil.TestISRC2+"/links")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response SongLinksResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Best)
+	assert.Equal(t, "spotify", response.Best.Platform)
+}
+
+func TestGetSongLinks_NotFound(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByISRC", mock.Anything, "missing").Return(nil, nil)
+	repo.On("FindByIDPrefix", mock.Anything, "missing").Return(nil, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	w := performLinksLookup(handler, "/api/v1/s/missing/links?prefer=spotify")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"songshare/internal/models"
+)
+
+// MergeSongsRequest names the song to keep and the duplicates to fold into it
+type MergeSongsRequest struct {
+	PrimaryID    string   `json:"primary_id" binding:"required"`
+	DuplicateIDs []string `json:"duplicate_ids" binding:"required,min=1"`
+	// Force merges songs whose ISRCs differ
+	Force bool `json:"force"`
+}
+
+// MergeSongsResponse is the merged song, for the operator to verify
+type MergeSongsResponse struct {
+	Song       *models.Song `json:"song"`
+	LinksAdded int          `json:"links_added"`
+	// UndeletedIDs are merged duplicates whose deletion failed; retrying the merge removes them
+	UndeletedIDs []string `json:"undeleted_ids,omitempty"`
+}
+
+// MergeSongs handles POST /api/v1/admin/songs/merge
+// Folds the duplicates' platform links into the primary song and deletes the
+// duplicates. Songs with different ISRCs are only merged with "force": true.
+func (h *AdminHandler) MergeSongs(c *gin.Context) {
+	var req MergeSongsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	seen := map[string]bool{req.PrimaryID: true}
+	for _, id := range append([]string{req.PrimaryID}, req.DuplicateIDs...) {
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid song ID",
+				"details": fmt.Sprintf("%q is not a song ID", id),
+			})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	primary, err := h.songRepository.FindByID(ctx, req.PrimaryID)
+	if err != nil {
+		slog.Error("Failed to find song", "song_id", req.PrimaryID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find song"})
+		return
+	}
+	if primary == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Song not found", "details": req.PrimaryID})
+		return
+	}
+
+	duplicates := make([]*models.Song, 0, len(req.DuplicateIDs))
+	for _, id := range req.DuplicateIDs {
+		if seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid duplicate IDs",
+				"details": fmt.Sprintf("%s is listed twice or is the primary song", id),
+			})
+			return
+		}
+		seen[id] = true
+
+		song, err := h.songRepository.FindByID(ctx, id)
+		if err != nil {
+			slog.Error("Failed to find song", "song_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find song"})
+			return
+		}
+		if song == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Song not found", "details": id})
+			return
+		}
+		if !req.Force && models.CanonicalISRC(song.ISRC) != models.CanonicalISRC(primary.ISRC) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "ISRCs differ",
+				"details": fmt.Sprintf("song %s has ISRC %q, the primary song %q; pass \"force\": true to merge anyway", id, song.ISRC, primary.ISRC),
+			})
+			return
+		}
+		duplicates = append(duplicates, song)
+	}
+
+	linksAdded, undeleted, err := h.mergeSongs(ctx, primary, duplicates)
+	if err != nil {
+		slog.Error("Failed to merge songs", "primary_id", req.PrimaryID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to merge songs",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	slog.Info("Merged songs", "primary_id", req.PrimaryID, "duplicates", len(duplicates), "links_added", linksAdded, "forced", req.Force)
+	c.JSON(http.StatusOK, MergeSongsResponse{Song: primary, LinksAdded: linksAdded, UndeletedIDs: undeleted})
+}
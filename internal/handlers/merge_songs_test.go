@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func performMergeSongs(t *testing.T, handler *AdminHandler, req MergeSongsRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/admin/songs/merge", handler.MergeSongs)
+
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/admin/songs/merge", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+	return w
+}
+
+func TestMergeSongs_MergesLinksAndDeletesDuplicates(t *testing.T) {
+	primary, duplicate := seedDuplicateISRC()
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByID", mock.Anything, primary.ID.Hex()).Return(primary, nil)
+	repo.On("FindByID", mock.Anything, duplicate.ID.Hex()).Return(duplicate, nil)
+	repo.On("Update", mock.Anything, primary).Return(nil)
+	repo.On("DeleteByID", mock.Anything, duplicate.ID.Hex()).Return(nil)
+
+	handler := NewAdminHandler(repo, nil)
+	w := performMergeSongs(t, handler, MergeSongsRequest{PrimaryID: primary.ID.Hex(), DuplicateIDs: []string{duplicate.ID.Hex()}})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Song struct {
+			ID            string `json:"id"`
+			PlatformLinks []struct {
+				Platform string `json:"platform"`
+			} `json:"platform_links"`
+		} `json:"song"`
+		LinksAdded int `json:"links_added"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, primary.ID.Hex(), response.Song.ID)
+	assert.Len(t, response.Song.PlatformLinks, 2)
+	assert.Equal(t, 1, response.LinksAdded)
+	repo.AssertCalled(t, "DeleteByID", mock.Anything, duplicate.ID.Hex())
+}
+
+func TestMergeSongs_DifferentISRCsNeedForce(t *testing.T) {
+	primary, duplicate := seedDuplicateISRC()
+	duplicate.ISRC = testutil.TestISRC2
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByID", mock.Anything, primary.ID.Hex()).Return(primary, nil)
+	repo.On("FindByID", mock.Anything, duplicate.ID.Hex()).Return(duplicate, nil)
+	repo.On("Update", mock.Anything, primary).Return(nil)
+	repo.On("DeleteByID", mock.Anything, duplicate.ID.Hex()).Return(nil)
+	handler := NewAdminHandler(repo, nil)
+
+	req := MergeSongsRequest{PrimaryID: primary.ID.Hex(), DuplicateIDs: []string{duplicate.ID.Hex()}}
+	w := performMergeSongs(t, handler, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ISRCs differ")
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+
+	req.Force = true
+	w = performMergeSongs(t, handler, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertCalled(t, "DeleteByID", mock.Anything, duplicate.ID.Hex())
+}
+
+func TestMergeSongs_RejectsInvalidIDs(t *testing.T) {
+	primaryID := primitive.NewObjectID().Hex()
+	handler := NewAdminHandler(&testutil.MockSongRepository{}, nil)
+
+	tests := []struct {
+		name string
+		req  MergeSongsRequest
+	}{
+		{"no duplicates", MergeSongsRequest{PrimaryID: primaryID}},
+		{"malformed ID", MergeSongsRequest{PrimaryID: primaryID, DuplicateIDs: []string{"abc"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, performMergeSongs(t, handler, tt.req).Code)
+		})
+	}
+}
+
+func TestMergeSongs_RejectsPrimaryAsDuplicate(t *testing.T) {
+	primary, _ := seedDuplicateISRC()
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByID", mock.Anything, primary.ID.Hex()).Return(primary, nil)
+	handler := NewAdminHandler(repo, nil)
+
+	w := performMergeSongs(t, handler, MergeSongsRequest{PrimaryID: primary.ID.Hex(), DuplicateIDs: []string{primary.ID.Hex()}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
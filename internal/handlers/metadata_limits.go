@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"log/slog"
+	"unicode/utf8"
+
+	"songshare/internal/services"
+)
+
+// Limits beyond which resolved track metadata is logged as suspicious. The
+// values are stored whole; pages cap them for display (see render.SetDisplayLimits).
+const (
+	saneTitleLength = 200
+	saneArtistCount = 10
+)
+
+// logOversizedTrack warns when a platform returns a title or artist list past
+// the sane limits, so operators can spot bad catalog data
+func logOversizedTrack(platform, trackID string, track *services.TrackInfo) {
+	if track == nil {
+		return
+	}
+	titleLength := utf8.RuneCountInString(track.Title)
+	if titleLength <= saneTitleLength && len(track.Artists) <= saneArtistCount {
+		return
+	}
+	slog.Warn("Resolved track metadata exceeds sane limits",
+		"platform", platform, "track_id", trackID,
+		"title_length", titleLength, "artists", len(track.Artists))
+}
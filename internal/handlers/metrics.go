@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"songshare/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics handles GET /metrics
+// Serves Prometheus metrics: platform API requests and latency, cache
+// operations, and Go runtime and process stats.
+func Metrics() gin.HandlerFunc {
+	return gin.WrapH(metrics.Handler())
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_ExposesPlatformAndCacheMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/metrics", Metrics())
+
+	metrics.ObservePlatformRequest("apple_music", "search", "429", 2*time.Second)
+	metrics.RecordCacheOperation(metrics.CacheSourceSongRepository, "get", metrics.CacheMiss)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `songshare_platform_requests_total{operation="search",platform="apple_music",status="429"} 1`)
+	assert.Contains(t, body, `songshare_platform_request_duration_seconds_bucket{operation="search",platform="apple_music",le="2.5"} 1`)
+	assert.Contains(t, body, `songshare_cache_operations_total{operation="get",result="miss",source="song_repository"}`)
+	assert.Contains(t, body, "go_goroutines")
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page size limits for the missing-platform listing
+const (
+	defaultMissingPlatformLimit = 100
+	maxMissingPlatformLimit     = 500
+)
+
+// MissingPlatformSong is a song lacking a link on the requested platform
+type MissingPlatformSong struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Artist    string   `json:"artist"`
+	ISRC      string   `json:"isrc"`
+	Platforms []string `json:"platforms"` // Platforms the song is already linked on
+}
+
+// MissingPlatformResponse is one page of songs missing a platform link
+type MissingPlatformResponse struct {
+	Platform string                `json:"platform"`
+	Songs    []MissingPlatformSong `json:"songs"`
+	// NextAfter fetches the following page as ?after=; empty on the last page
+	NextAfter string `json:"next_after,omitempty"`
+}
+
+// GetSongsMissingPlatform handles GET /api/v1/admin/songs/missing-platform/:platform
+// Lists songs that have an ISRC but no link on the platform, in catalog order,
+// so enrichment can be targeted at them. Page with ?limit= and ?after= set to
+// the previous page's next_after; songs linked in between don't shift pages.
+func (h *AdminHandler) GetSongsMissingPlatform(c *gin.Context) {
+	platform := c.Param("platform")
+
+	after := c.Query("after")
+	if after != "" && !primitive.IsValidObjectID(after) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid after",
+			"details": "after must be a song ID",
+		})
+		return
+	}
+
+	limit := defaultMissingPlatformLimit
+	if parsedLimit, err := strconv.Atoi(c.Query("limit")); err == nil && parsedLimit > 0 && parsedLimit <= maxMissingPlatformLimit {
+		limit = parsedLimit
+	}
+
+	// Fetch one extra song to learn whether another page follows
+	songs, err := h.songRepository.FindMissingPlatform(c.Request.Context(), platform, after, limit+1)
+	if err != nil {
+		slog.Error("Failed to find songs missing platform", "platform", platform, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find songs missing platform"})
+		return
+	}
+
+	response := MissingPlatformResponse{Platform: platform, Songs: make([]MissingPlatformSong, 0, len(songs))}
+	if len(songs) > limit {
+		songs = songs[:limit]
+		response.NextAfter = songs[limit-1].ID.Hex()
+	}
+
+	for _, song := range songs {
+		platforms := make([]string, 0, len(song.PlatformLinks))
+		for _, link := range song.PlatformLinks {
+			platforms = append(platforms, link.Platform)
+		}
+		response.Songs = append(response.Songs, MissingPlatformSong{
+			ID:        song.ID.Hex(),
+			Title:     song.Title,
+			Artist:    song.Artist,
+			ISRC:      song.ISRC,
+			Platforms: platforms,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
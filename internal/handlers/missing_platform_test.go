@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/models"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performMissingPlatformRequest(t *testing.T, repo *testutil.MockSongRepository, target, authorization string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAdminHandler(repo, nil)
+	router := gin.New()
+	admin := router.Group("/api/v1/admin", RequireAdmin("admin-token"))
+	admin.GET("/songs/missing-platform/:platform", handler.GetSongsMissingPlatform)
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetSongsMissingPlatform_Pages(t *testing.T) {
+	songs := []*models.Song{
+		testutil.NewSongBuilder().WithID("64b7f0c2a1b2c3d4e5f60702").WithISRC(testutil.TestISRC1).WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).Build(),
+		testutil.NewSongBuilder().WithID("64b7f0c2a1b2c3d4e5f60703").WithISRC(testutil.TestISRC2).Build(),
+		testutil.NewSongBuilder().WithID("64b7f0c2a1b2c3d4e5f60704").WithISRC(testutil.TestISRC3).Build(),
+	}
+	repo := &testutil.MockSongRepository{}
+	after := "64b7f0c2a1b2c3d4e5f60701"
+	repo.On("FindMissingPlatform", mock.Anything, "tidal", after, 3).Return(songs, nil)
+
+	w := performMissingPlatformRequest(t, repo, "/api/v1/admin/songs/missing-platform/tidal?after="+after+"&limit=2", "Bearer admin-token")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response MissingPlatformResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "tidal", response.Platform)
+	require.Len(t, response.Songs, 2)
+	assert.Equal(t, testutil.TestISRC1, response.Songs[0].ISRC)
+	assert.Equal(t, []string{"spotify"}, response.Songs[0].Platforms)
+	assert.Equal(t, "64b7f0c2a1b2c3d4e5f60703", response.NextAfter)
+}
+
+func TestGetSongsMissingPlatform_LastPage(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindMissingPlatform", mock.Anything, "deezer", "", defaultMissingPlatformLimit+1).Return([]*models.Song{}, nil)
+
+	w := performMissingPlatformRequest(t, repo, "/api/v1/admin/songs/missing-platform/deezer", "Bearer admin-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "next_after")
+}
+
+func TestGetSongsMissingPlatform_RejectsBadCursorAndUnauthorized(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+
+	w := performMissingPlatformRequest(t, repo, "/api/v1/admin/songs/missing-platform/tidal?after=12", "Bearer admin-token")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performMissingPlatformRequest(t, repo, "/api/v1/admin/songs/missing-platform/tidal", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	repo.AssertNotCalled(t, "FindMissingPlatform", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
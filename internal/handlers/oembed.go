@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Embedded song page size when the consumer sets no maxwidth/maxheight
+const (
+	defaultOEmbedWidth  = 400
+	defaultOEmbedHeight = 600
+)
+
+// OEmbedResponse is an oEmbed "rich" response embedding a song page
+type OEmbedResponse struct {
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+// GetOEmbed handles GET /api/v1/oembed
+// Returns an oEmbed response for one of our universal links (?url=), whose HTML
+// is an iframe of the song page sized within ?maxwidth= and ?maxheight=.
+func (h *SongHandler) GetOEmbed(c *gin.Context) {
+	if format := c.DefaultQuery("format", "json"); format != "json" {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error":   "Unsupported format",
+			"details": "only json is supported",
+		})
+		return
+	}
+
+	rawURL := c.Query("url")
+	if rawURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing url parameter",
+		})
+		return
+	}
+
+	identifier, ok := h.songLinkIdentifier(c, rawURL)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Not a song link",
+		})
+		return
+	}
+
+	song, err := h.findSongByISRC(c.Request.Context(), identifier)
+	if err != nil {
+		slog.Error("Song lookup failed", "identifier", identifier, "error", err)
+	}
+	if err != nil || song == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Song not found",
+		})
+		return
+	}
+
+	width := clampOEmbedDimension(c.Query("maxwidth"), defaultOEmbedWidth)
+	height := clampOEmbedDimension(c.Query("maxheight"), defaultOEmbedHeight)
+
+	baseURL := h.renderer.BaseURL(c)
+	songURL := fmt.Sprintf("%s/s/%s", baseURL, url.PathEscape(identifier))
+	title := fmt.Sprintf("%s - %s", song.Title, song.Artist)
+
+	c.JSON(http.StatusOK, OEmbedResponse{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        song.Title,
+		AuthorName:   song.Artist,
+		ProviderName: h.renderer.SiteName(),
+		ProviderURL:  baseURL,
+		ThumbnailURL: song.Metadata.ImageURL,
+		HTML: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" frameborder="0" loading="lazy"></iframe>`,
+			html.EscapeString(songURL), width, height, html.EscapeString(title)),
+		Width:  width,
+		Height: height,
+	})
+}
+
+// songLinkIdentifier extracts the ISRC or ID from one of our universal links,
+// /s/:id or /api/v1/s/:id on a host we serve
+func (h *SongHandler) songLinkIdentifier(c *gin.Context, rawURL string) (string, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || !h.renderer.IsOwnHost(c, parsed.Host) {
+		return "", false
+	}
+
+	path := strings.TrimSuffix(strings.TrimPrefix(parsed.Path, "/api/v1"), "/")
+	identifier, ok := strings.CutPrefix(path, "/s/")
+	if !ok || identifier == "" || strings.Contains(identifier, "/") {
+		return "", false
+	}
+	return identifier, true
+}
+
+// clampOEmbedDimension limits a default dimension to the consumer's maximum,
+// ignoring maximums that aren't positive integers
+func clampOEmbedDimension(maximum string, dimension int) int {
+	if parsed, err := strconv.Atoi(maximum); err == nil && parsed > 0 && parsed < dimension {
+		return parsed
+	}
+	return dimension
+}
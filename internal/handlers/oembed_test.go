@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performOEmbed(t *testing.T, handler *SongHandler, query url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/oembed", handler.GetOEmbed)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/oembed?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetOEmbed_RichResponse(t *testing.T) {
+	song := testutil.NewSongBuilder().
+		WithTitle("Bohemian Rhapsody").
+		WithArtist("Queen").
+		WithISRC(testutil.TestISRC1).
+		WithImageURL("https://example.com/art.jpg").
+		Build()
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+	handler := NewSongHandler(repo, "https://songshare.example", nil, nil, nil)
+
+	w := performOEmbed(t, handler, url.Values{
+		"url":       {"https://songshare.example/s/" + testutil.TestISRC1},
+		"format":    {"json"},
+		"maxwidth":  {"320"},
+		"maxheight": {"9999"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response OEmbedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "rich", response.Type)
+	assert.Equal(t, "1.0", response.Version)
+	assert.Equal(t, "Bohemian Rhapsody", response.Title)
+	assert.Equal(t, "Queen", response.AuthorName)
+	assert.Equal(t, "https://example.com/art.jpg", response.ThumbnailURL)
+	assert.Equal(t, 320, response.Width)
+	assert.Equal(t, defaultOEmbedHeight, response.Height)
+	assert.Contains(t, response.HTML, `src="https://songshare.example/s/`+testutil.TestISRC1+`"`)
+	assert.Contains(t, response.HTML, `width="320"`)
+}
+
+func TestGetOEmbed_Errors(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByISRC", mock.Anything, "UNKNOWN00001").Return(nil, nil)
+	repo.On("FindByIDPrefix", mock.Anything, "UNKNOWN00001").Return(nil, nil)
+	handler := NewSongHandler(repo, "https://songshare.example", nil, nil, nil)
+
+	tests := []struct {
+		name   string
+		query  url.Values
+		status int
+	}{
+		{"missing url", url.Values{}, http.StatusBadRequest},
+		{"xml format", url.Values{"url": {"https://songshare.example/s/" + testutil.TestISRC1}, "format": {"xml"}}, http.StatusNotImplemented},
+		{"foreign host", url.Values{"url": {"https://evil.example/s/" + testutil.TestISRC1}}, http.StatusNotFound},
+		{"not a song path", url.Values{"url": {"https://songshare.example/c/64b7f0c2a1b2c3d4e5f60718"}}, http.StatusNotFound},
+		{"unknown song", url.Values{"url": {"https://songshare.example/s/UNKNOWN00001"}}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performOEmbed(t, handler, tt.query)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	repo.AssertNotCalled(t, "FindByISRC", mock.Anything, testutil.TestISRC1)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"songshare/internal/services"
+)
+
+// Platform health defaults; the TTL matches the config default
+const (
+	defaultPlatformHealthTTL   = 30 * time.Second
+	platformHealthCheckTimeout = 5 * time.Second
+)
+
+// platformHealthResult is the outcome of one Health call
+type platformHealthResult struct {
+	err       error
+	checkedAt time.Time
+}
+
+// platformHealth caches the latest Health result per platform so searches can
+// skip a platform that is known to be down instead of waiting out its timeout.
+// It complements per-request error handling: results older than the TTL are
+// ignored, so a stopped checker never keeps a platform switched off.
+type platformHealth struct {
+	mu      sync.RWMutex
+	results map[string]platformHealthResult
+	ttl     time.Duration
+}
+
+func newPlatformHealth(ttl time.Duration) *platformHealth {
+	return &platformHealth{
+		results: make(map[string]platformHealthResult),
+		ttl:     ttl,
+	}
+}
+
+// record stores the result of a health check
+func (p *platformHealth) record(platform string, err error, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.results[platform] = platformHealthResult{err: err, checkedAt: now}
+}
+
+// unavailable returns the error from the platform's last health check when that
+// check failed within the TTL, and nil otherwise
+func (p *platformHealth) unavailable(platform string, now time.Time) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	result, ok := p.results[platform]
+	if !ok || result.err == nil || now.Sub(result.checkedAt) > p.ttl {
+		return nil
+	}
+	return result.err
+}
+
+func (p *platformHealth) setTTL(ttl time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ttl = ttl
+}
+
+// checkPlatformHealth runs Health on every configured platform concurrently and
+// records the results
+func (h *SongHandler) checkPlatformHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, service := range h.platformServiceList() {
+		wg.Add(1)
+		go func(service services.PlatformService) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, platformHealthCheckTimeout)
+			defer cancel()
+
+			platform := service.GetPlatformName()
+			err := service.Health(checkCtx)
+			if err != nil && h.platformHealth.unavailable(platform, time.Now()) == nil {
+				slog.Warn("Platform health check failed; skipping it in searches", "platform", platform, "error", err)
+			}
+			h.platformHealth.record(platform, err, time.Now())
+		}(service)
+	}
+	wg.Wait()
+}
+
+// StartPlatformHealthChecker re-checks platform health twice per TTL, so fresh
+// results are always available, until ctx is cancelled
+func (h *SongHandler) StartPlatformHealthChecker(ctx context.Context) {
+	h.platformHealth.mu.RLock()
+	interval := h.platformHealth.ttl / 2
+	h.platformHealth.mu.RUnlock()
+	if interval <= 0 {
+		slog.Info("Platform health checker disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		h.checkPlatformHealth(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Platform health checker stopped")
+				return
+			case <-ticker.C:
+				h.checkPlatformHealth(ctx)
+			}
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformHealth_ExpiresAfterTTL(t *testing.T) {
+	health := newPlatformHealth(30 * time.Second)
+	now := time.Now()
+
+	assert.NoError(t, health.unavailable("spotify", now), "unknown platforms are available")
+
+	health.record("spotify", errors.New("token endpoint down"), now)
+	assert.Error(t, health.unavailable("spotify", now.Add(10*time.Second)))
+	assert.NoError(t, health.unavailable("spotify", now.Add(time.Minute)), "stale failures are ignored")
+
+	health.record("spotify", nil, now.Add(20*time.Second))
+	assert.NoError(t, health.unavailable("spotify", now.Add(25*time.Second)))
+}
+
+func TestSearch_SkipsUnhealthyPlatform(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	tidal := testutil.NewMockPlatformService("tidal")
+
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("Health", mock.Anything).Return(errors.New("failed to get access token"))
+	// A dispatched Spotify search would hang for the full search timeout
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).After(10*time.Second).Return([]*services.TrackInfo{}, nil)
+	tidal.On("Health", mock.Anything).Return(nil)
+	tidal.On("SearchTrack", mock.Anything, mock.Anything).
+		Return([]*services.TrackInfo{testutil.NewTrackInfoBuilder().WithPlatform("tidal").Build()}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, tidal)
+	handler.checkPlatformHealth(context.Background())
+
+	start := time.Now()
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "test song", Limit: 10})
+	assert.Less(t, time.Since(start), time.Second)
+
+	assert.Equal(t, services.ErrorCategoryUnavailable, response.PlatformStatus["spotify"])
+	assert.Empty(t, response.Results["spotify"])
+	require.Len(t, response.Results["tidal"], 1)
+	spotify.AssertNotCalled(t, "SearchTrack", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"sync"
+
+	"songshare/internal/services"
+)
+
+// builtinPlatforms are the platforms passed to NewSongHandler, in metadata
+// preference order. A URL on one of them is recognized even when its service
+// is missing, so it can be answered as "not configured" rather than unsupported.
+var builtinPlatforms = []string{"spotify", "apple_music", "tidal"}
+
+// platformRegistry holds the handler's platform services by platform name.
+// It is built once by NewSongHandler and safe for concurrent use, so platforms
+// can be registered or removed while searches and resolves are in flight.
+type platformRegistry struct {
+	mu       sync.RWMutex
+	services map[string]services.PlatformService
+	order    []string // Registration order, which is metadata preference order
+}
+
+func newPlatformRegistry() *platformRegistry {
+	return &platformRegistry{services: make(map[string]services.PlatformService)}
+}
+
+// newBuiltinPlatformRegistry registers the built-in platforms' services, nil
+// ones included, in builtinPlatforms order
+func newBuiltinPlatformRegistry(spotifyService, appleMusicService, tidalService services.PlatformService) *platformRegistry {
+	registry := newPlatformRegistry()
+	for i, service := range []services.PlatformService{spotifyService, appleMusicService, tidalService} {
+		registry.register(builtinPlatforms[i], service)
+	}
+	return registry
+}
+
+// register adds service under platform, replacing any service already
+// registered there without changing its place in the order
+func (r *platformRegistry) register(platform string, service services.PlatformService) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.services[platform]; !exists {
+		r.order = append(r.order, platform)
+	}
+	r.services[platform] = service
+}
+
+// deregister removes platform, reporting whether it was registered
+func (r *platformRegistry) deregister(platform string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.services[platform]; !exists {
+		return false
+	}
+	delete(r.services, platform)
+	for i, name := range r.order {
+		if name == platform {
+			r.order = append(r.order[:i:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// get returns the service registered for platform; it may be nil for a
+// built-in platform constructed without a service
+func (r *platformRegistry) get(platform string) (services.PlatformService, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	service, ok := r.services[platform]
+	return service, ok
+}
+
+// snapshot returns a copy of the registry, so callers can fan out over it
+// without holding the lock
+func (r *platformRegistry) snapshot() map[string]services.PlatformService {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snapshot := make(map[string]services.PlatformService, len(r.services))
+	for platform, service := range r.services {
+		snapshot[platform] = service
+	}
+	return snapshot
+}
+
+// ordered returns the registered services in registration order
+func (r *platformRegistry) ordered() []services.PlatformService {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]services.PlatformService, 0, len(r.order))
+	for _, platform := range r.order {
+		list = append(list, r.services[platform])
+	}
+	return list
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSongHandler_RuntimePlatformRegistration(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	deezer := testutil.NewMockPlatformService("deezer")
+
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{}, nil)
+	deezer.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	search := func(query string) SearchSongsResponse {
+		return handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: query, Limit: 10})
+	}
+
+	response := search("first song")
+	assert.Contains(t, response.Results, "spotify")
+	assert.NotContains(t, response.Results, "deezer")
+
+	// A platform registered at runtime joins the next search's fan-out
+	handler.RegisterPlatformService(deezer)
+	response = search("second song")
+	assert.Contains(t, response.Results, "spotify")
+	assert.Contains(t, response.Results, "deezer")
+	deezer.AssertNumberOfCalls(t, "SearchTrack", 1)
+
+	// ...and leaves it again once removed
+	assert.True(t, handler.DeregisterPlatformService("deezer"))
+	assert.False(t, handler.DeregisterPlatformService("deezer"))
+	response = search("third song")
+	assert.Contains(t, response.Results, "spotify")
+	assert.NotContains(t, response.Results, "deezer")
+	deezer.AssertNumberOfCalls(t, "SearchTrack", 1)
+
+	// A removed built-in platform is still recognized, just not reachable
+	assert.True(t, handler.DeregisterPlatformService("spotify"))
+	assert.Empty(t, search("fourth song").Results["spotify"])
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	w, _ := performResolve(t, handler, "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	spotify.AssertNumberOfCalls(t, "SearchTrack", 3)
+	spotify.AssertNotCalled(t, "GetTrackByID", mock.Anything, mock.Anything)
+}
+
+func TestPlatformRegistry_ConcurrentUse(t *testing.T) {
+	registry := newBuiltinPlatformRegistry(nil, nil, nil)
+	deezer := testutil.NewMockPlatformService("deezer")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registry.register("deezer", deezer)
+			registry.deregister("deezer")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = registry.snapshot()
+			_ = registry.ordered()
+		}()
+	}
+	wg.Wait()
+
+	registry.register("deezer", deezer)
+	require.Len(t, registry.ordered(), 4)
+	assert.Equal(t, deezer, registry.ordered()[3], "new platforms come after the built-in ones")
+}
+
+func TestSongHandler_ApplyConfigRoutesBuiltinPlatformsThroughProxy(t *testing.T) {
+	var mu sync.Mutex
+	var tunnels []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tunnels = append(tunnels, r.Method+" "+r.Host)
+		mu.Unlock()
+		http.Error(w, "no upstream in tests", http.StatusForbidden)
+	}))
+	t.Cleanup(proxy.Close)
+
+	spotify := services.NewSpotifyService("id", "secret", nil)
+	handler := NewSongHandler(nil, "http://localhost", spotify, nil, nil)
+	handler.ApplyConfig(&config.Config{Platforms: map[string]*config.PlatformConfig{
+		"spotify": {Name: "spotify", ProxyURL: proxy.URL},
+	}})
+
+	require.Error(t, spotify.Health(context.Background()))
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, tunnels, "token exchange did not go through the proxy")
+	assert.Equal(t, "CONNECT accounts.spotify.com:443", tunnels[0])
+}
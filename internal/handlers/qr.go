@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR code image sizes in pixels
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 2048
+)
+
+// qrMaxAge bounds how long clients and CDNs keep a QR code. The encoded link
+// follows the song's current ISRC and the request's host and scheme, so it
+// can change and must not be cached as immutable.
+const qrMaxAge = 24 * time.Hour
+
+// GetSongQRCode handles GET /api/v1/s/:id/qr
+// Returns a QR code of the song's universal link for printing on posters and
+// stickers: a PNG of ?size= pixels (default 256), or an SVG with ?format=svg.
+func (h *SongHandler) GetSongQRCode(c *gin.Context) {
+	songID := c.Param("id")
+
+	size := defaultQRSize
+	if raw := c.Query("size"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < minQRSize || parsed > maxQRSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid size",
+				"details": fmt.Sprintf("size must be between %d and %d pixels", minQRSize, maxQRSize),
+			})
+			return
+		}
+		size = parsed
+	}
+
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "svg" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unsupported format",
+			"details": "format must be png or svg",
+		})
+		return
+	}
+
+	song, err := h.findSongByISRC(c.Request.Context(), songID)
+	if err != nil {
+		slog.Error("Song lookup failed", "identifier", songID, "error", err)
+	}
+	if err != nil || song == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Song not found",
+		})
+		return
+	}
+
+	identifier := song.ISRC
+	if identifier == "" {
+		identifier = songID
+	}
+	link := fmt.Sprintf("%s/s/%s", h.renderer.BaseURL(c), url.PathEscape(identifier))
+
+	code, err := qrcode.New(link, qrcode.Medium)
+	if err != nil {
+		slog.Error("Failed to encode QR code", "link", link, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate QR code",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(qrMaxAge.Seconds())))
+	c.Header("Vary", "Host, X-Forwarded-Proto")
+	if format == "svg" {
+		c.Data(http.StatusOK, "image/svg+xml", qrSVG(code.Bitmap(), size))
+		return
+	}
+
+	png, err := code.PNG(size)
+	if err != nil {
+		slog.Error("Failed to render QR code", "link", link, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate QR code",
+		})
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// qrSVG draws a QR bitmap (including its quiet zone) as an SVG of size
+// pixels, one unit per module, so it scales without blurring
+func qrSVG(bitmap [][]bool, size int) []byte {
+	modules := len(bitmap)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, modules, modules)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, modules, modules)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return []byte(b.String())
+}
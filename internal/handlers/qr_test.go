@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/models"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performQRCode(t *testing.T, handler *SongHandler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/s/:id/qr", handler.GetSongQRCode)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func qrTestHandler() *SongHandler {
+	song := testutil.NewSongBuilder().WithISRC(testutil.TestISRC1).Build()
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+	repo.On("FindByISRC", mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, mock.Anything).Return((*models.Song)(nil), nil)
+	repo.On("FindByIDPrefix", mock.Anything, mock.Anything).Return((*models.Song)(nil), nil)
+	return NewSongHandler(repo, "https://songshare.example", nil, nil, nil)
+}
+
+func TestGetSongQRCode_PNG(t *testing.T) {
+	w := performQRCode(t, qrTestHandler(), "/api/v1/s/"+testutil.TestISRC1+"/qr")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=86400", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Host, X-Forwarded-Proto", w.Header().Get("Vary"))
+
+	img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, defaultQRSize, img.Bounds().Dx())
+
+	expected, err := qrcode.Encode("https://songshare.example/s/"+testutil.TestISRC1, qrcode.Medium, defaultQRSize)
+	require.NoError(t, err)
+	assert.Equal(t, expected, w.Body.Bytes(), "the code encodes the universal link")
+
+	w = performQRCode(t, qrTestHandler(), "/api/v1/s/"+testutil.TestISRC1+"/qr?size=512")
+	require.Equal(t, http.StatusOK, w.Code)
+	img, err = png.Decode(bytes.NewReader(w.Body.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 512, img.Bounds().Dx())
+}
+
+func TestGetSongQRCode_SVG(t *testing.T) {
+	w := performQRCode(t, qrTestHandler(), "/api/v1/s/"+testutil.TestISRC1+"/qr?format=svg&size=300")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `width="300" height="300"`)
+	assert.Contains(t, w.Body.String(), `shape-rendering="crispEdges"`)
+}
+
+func TestGetSongQRCode_Errors(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, performQRCode(t, qrTestHandler(), "/api/v1/s/missing/qr").Code)
+	assert.Equal(t, http.StatusBadRequest, performQRCode(t, qrTestHandler(), "/api/v1/s/"+testutil.TestISRC1+"/qr?size=big").Code)
+	assert.Equal(t, http.StatusBadRequest, performQRCode(t, qrTestHandler(), "/api/v1/s/"+testutil.TestISRC1+"/qr?size=10").Code)
+	assert.Equal(t, http.StatusBadRequest, performQRCode(t, qrTestHandler(), "/api/v1/s/"+testutil.TestISRC1+"/qr?format=gif").Code)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket is a non-blocking token-bucket limiter: callers either get a
+// token immediately or are told to skip the work
+type tokenBucket struct {
+	mu         sync.Mutex
+	rate       float64 // tokens added per second
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+	now        func() time.Time
+}
+
+// newTokenBucket creates a full bucket. A non-positive rate disables limiting.
+func newTokenBucket(ratePerSecond float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:       ratePerSecond,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+		now:        time.Now,
+	}
+}
+
+// Allow consumes a token if one is available
+func (b *tokenBucket) Allow() bool {
+	if b == nil || b.rate <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.lastRefill = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Allow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(1, 2)
+	bucket.now = func() time.Time { return now }
+	bucket.lastRefill = now
+
+	assert.True(t, bucket.Allow())
+	assert.True(t, bucket.Allow())
+	assert.False(t, bucket.Allow(), "burst exhausted")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.False(t, bucket.Allow(), "half a token is not enough")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, bucket.Allow(), "refilled one token")
+
+	now = now.Add(time.Hour)
+	assert.True(t, bucket.Allow())
+	assert.True(t, bucket.Allow())
+	assert.False(t, bucket.Allow(), "refill capped at burst")
+}
+
+func TestTokenBucket_Disabled(t *testing.T) {
+	bucket := newTokenBucket(0, 1)
+	for i := 0; i < 10; i++ {
+		assert.True(t, bucket.Allow())
+	}
+
+	var nilBucket *tokenBucket
+	assert.True(t, nilBucket.Allow())
+}
+
+func TestRedirectToSong_SkipsBackfillBeyondRate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	song := testutil.NewSongBuilder().
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+	// Backfill finds no art, so the song stays eligible on every request
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, testutil.CreateTestTrackInfo(), nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.backfillLimiter = newTokenBucket(0.001, 1)
+
+	router := gin.New()
+	router.GET("/s/:id", handler.RedirectToSong)
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/s/"+testutil.TestISRC1, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		handler.backfills.wait()
+	}
+
+	spotify.AssertNumberOfCalls(t, "GetTrackByID", 1)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RawResponseDebugHeader asks for every platform API response made while
+// serving the request to be logged raw
+const RawResponseDebugHeader = "X-Debug-Raw-Responses"
+
+// RawResponseDebug honours RawResponseDebugHeader on requests carrying the
+// admin token, when enabled (RAW_RESPONSE_DEBUG_HEADER). Other requests only
+// have their platform responses logged at the configured sample rate.
+func RawResponseDebug(enabled bool, adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enabled && adminToken != "" && c.GetHeader(RawResponseDebugHeader) == "1" && hasAdminToken(c, adminToken) {
+			c.Request = c.Request.WithContext(services.WithRawResponseDebug(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
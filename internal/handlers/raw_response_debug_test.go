@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawResponseDebug(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		adminToken    string
+		authorization string
+		header        string
+		want          bool
+	}{
+		{"Admin request with header", true, "admin-token", "Bearer admin-token", "1", true},
+		{"Header disabled", false, "admin-token", "Bearer admin-token", "1", false},
+		{"Not an admin", true, "admin-token", "Bearer wrong", "1", false},
+		{"No admin token configured", true, "", "Bearer ", "1", false},
+		{"No header", true, "admin-token", "Bearer admin-token", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var forced bool
+			router := gin.New()
+			router.Use(RawResponseDebug(tt.enabled, tt.adminToken))
+			router.GET("/api/v1/search", func(c *gin.Context) {
+				forced = services.RawResponseDebugFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/search", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			if tt.header != "" {
+				req.Header.Set(RawResponseDebugHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, forced)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"songshare/internal/handlers/render"
+	"songshare/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page size limits for the recent songs feed
+const (
+	defaultRecentLimit = 20
+	maxRecentLimit     = 100
+)
+
+// RecentSongsResponse is one page of the recent songs feed
+type RecentSongsResponse struct {
+	Songs []render.ResolveSongResponse `json:"songs"`
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// GetRecentSongs handles GET /api/v1/recent
+// Songs are returned newest first. Pass the previous page's next_cursor as
+// ?cursor= to continue; songs added meanwhile don't cause duplicates or skips.
+func (h *SongHandler) GetRecentSongs(c *gin.Context) {
+	limit := defaultRecentLimit
+	if parsedLimit, err := strconv.Atoi(c.Query("limit")); err == nil && parsedLimit > 0 && parsedLimit <= maxRecentLimit {
+		limit = parsedLimit
+	}
+
+	var cursor *repositories.RecentCursor
+	if token := c.Query("cursor"); token != "" {
+		decoded, err := repositories.DecodeRecentCursor(token)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid cursor",
+				"details": err.Error(),
+			})
+			return
+		}
+		cursor = decoded
+	}
+
+	// Fetch one extra song to learn whether another page follows
+	songs, err := h.songRepository.FindRecentAfter(c.Request.Context(), cursor, limit+1)
+	if err != nil {
+		slog.Error("Failed to load recent songs", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load recent songs"})
+		return
+	}
+
+	response := RecentSongsResponse{Songs: make([]render.ResolveSongResponse, 0, len(songs))}
+	if len(songs) > limit {
+		songs = songs[:limit]
+		response.NextCursor = repositories.RecentCursorFor(songs[len(songs)-1]).Encode()
+	}
+
+	baseURL := h.renderer.BaseURL(c)
+	for _, song := range songs {
+		response.Songs = append(response.Songs, h.buildResolveResponse(baseURL, song))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func performRecentRequest(handler *SongHandler, target string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/recent", handler.GetRecentSongs)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func recentTestSongs(n int) []*models.Song {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	songs := make([]*models.Song, n)
+	for i := range songs {
+		songs[i] = testutil.NewSongBuilder().WithID(primitive.NewObjectID().Hex()).WithISRC(testutil.TestISRC1).Build()
+		songs[i].CreatedAt = base.Add(-time.Duration(i) * time.Minute)
+	}
+	return songs
+}
+
+func TestGetRecentSongs_NextCursor(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	songs := recentTestSongs(3)
+	repo.On("FindRecentAfter", mock.Anything, (*repositories.RecentCursor)(nil), 3).Return(songs, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	w := performRecentRequest(handler, "/api/v1/recent?limit=2")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response RecentSongsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Songs, 2)
+	require.NotEmpty(t, response.NextCursor)
+
+	// The cursor points at the last song returned, and is passed back decoded
+	next := recentTestSongs(1)
+	repo.On("FindRecentAfter", mock.Anything, mock.MatchedBy(func(cursor *repositories.RecentCursor) bool {
+		return cursor != nil && cursor.ID == songs[1].ID && cursor.CreatedAt.Equal(songs[1].CreatedAt)
+	}), 3).Return(next, nil)
+
+	w = performRecentRequest(handler, "/api/v1/recent?limit=2&cursor="+response.NextCursor)
+	require.Equal(t, http.StatusOK, w.Code)
+	response = RecentSongsResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Songs, 1)
+	assert.Empty(t, response.NextCursor)
+	repo.AssertExpectations(t)
+}
+
+func TestGetRecentSongs_InvalidCursor(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+
+	w := performRecentRequest(handler, "/api/v1/recent?cursor=garbage")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "FindRecentAfter", mock.Anything, mock.Anything, mock.Anything)
+}
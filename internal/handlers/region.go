@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegionHeader lets clients (or an edge proxy) pick the catalog region
+const RegionHeader = "X-Region"
+
+// ResolveRegion reads the listener's catalog region from ?region= or the
+// X-Region header and attaches it to the request context, so platform services
+// query that country's catalog. Malformed values are ignored and the
+// platform's configured default applies, as it does on platforms that don't
+// serve the requested region.
+func ResolveRegion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, candidate := range []string{c.Query("region"), c.GetHeader(RegionHeader)} {
+			if region, ok := services.NormalizeRegion(candidate); ok {
+				c.Request = c.Request.WithContext(services.WithRegion(c.Request.Context(), region))
+				break
+			}
+		}
+		c.Next()
+	}
+}
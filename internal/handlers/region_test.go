@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRegion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ResolveRegion())
+	router.GET("/region", func(c *gin.Context) {
+		c.String(http.StatusOK, services.RegionFromContext(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name     string
+		target   string
+		header   string
+		expected string
+	}{
+		{name: "None", target: "/region", expected: ""},
+		{name: "Query param", target: "/region?region=gb", expected: "GB"},
+		{name: "Header", target: "/region", header: "de", expected: "DE"},
+		{name: "Query param wins", target: "/region?region=FR", header: "DE", expected: "FR"},
+		{name: "Malformed query falls back to header", target: "/region?region=france", header: "DE", expected: "DE"},
+		{name: "Malformed ignored", target: "/region?region=123", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(RegionHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
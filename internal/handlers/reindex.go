@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reindexBatchSize is how many songs each reindex bulk update covers
+const reindexBatchSize = 500
+
+// reindexEvent is one line of a streamed reindex
+type reindexEvent struct {
+	Type      string `json:"type"` // "progress" or "summary"
+	Processed int    `json:"processed"`
+	Updated   int64  `json:"updated"`
+	// Cursor is the last song ID reindexed; pass it as ?after= to resume
+	Cursor    string `json:"cursor,omitempty"`
+	Complete  bool   `json:"complete,omitempty"`
+	Cancelled bool   `json:"cancelled,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ReindexSearch handles POST /api/v1/admin/reindex
+// Ensures the songs text index exists, then recomputes every song's
+// denormalized search fields in batches, streaming NDJSON progress after each
+// batch and a final summary. ?after=<song ID> resumes after the cursor of an
+// earlier run; disconnecting cancels after the current batch.
+// Admin only: register behind RequireAdmin.
+func (h *AdminHandler) ReindexSearch(c *gin.Context) {
+	cursor := c.Query("after")
+	if cursor != "" {
+		if _, err := primitive.ObjectIDFromHex(cursor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid cursor",
+				"details": "after must be a song ID from an earlier reindex",
+			})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	if err := h.songRepository.EnsureTextIndex(ctx); err != nil {
+		slog.Error("Failed to ensure text index", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to ensure text index",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+	emit := func(event reindexEvent) {
+		if err := encoder.Encode(event); err != nil {
+			slog.Debug("Failed to write reindex progress", "error", err)
+		}
+		c.Writer.Flush()
+	}
+
+	summary := reindexEvent{Type: "summary", Cursor: cursor}
+	fail := func(err error) {
+		if ctx.Err() != nil {
+			summary.Cancelled = true
+			return
+		}
+		summary.Error = err.Error()
+	}
+	for {
+		if ctx.Err() != nil {
+			summary.Cancelled = true
+			break
+		}
+		songs, err := h.songRepository.FindAfterID(ctx, summary.Cursor, reindexBatchSize)
+		if err != nil {
+			fail(err)
+			break
+		}
+		if len(songs) == 0 {
+			summary.Complete = true
+			break
+		}
+		for _, song := range songs {
+			song.UpdateSearchText()
+		}
+		updated, err := h.songRepository.UpdateSearchText(ctx, songs)
+		if err != nil {
+			fail(err)
+			break
+		}
+
+		summary.Processed += len(songs)
+		summary.Updated += updated
+		summary.Cursor = songs[len(songs)-1].ID.Hex()
+		emit(reindexEvent{Type: "progress", Processed: summary.Processed, Updated: summary.Updated, Cursor: summary.Cursor})
+	}
+
+	// The cursor is logged too, since a cancelled client won't read the summary
+	slog.Info("Search reindex finished", "processed", summary.Processed, "updated", summary.Updated,
+		"cursor", summary.Cursor, "complete", summary.Complete, "error", summary.Error)
+	emit(summary)
+}
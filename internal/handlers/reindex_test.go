@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"songshare/internal/models"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func performReindex(t *testing.T, repo *testutil.MockSongRepository, query string) (*httptest.ResponseRecorder, []reindexEvent) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/admin/reindex", NewAdminHandler(repo, nil).ReindexSearch)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/reindex"+query, nil))
+
+	var events []reindexEvent
+	if w.Code == http.StatusOK {
+		scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+		for scanner.Scan() {
+			var event reindexEvent
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			events = append(events, event)
+		}
+	}
+	return w, events
+}
+
+func unindexedSong(title, artist string) *models.Song {
+	song := testutil.NewSongBuilder().WithTitle(title).WithArtist(artist).Build()
+	song.ID = primitive.NewObjectID()
+	song.SearchText = ""
+	return song
+}
+
+func TestReindexSearch_PopulatesSearchText(t *testing.T) {
+	first, second := unindexedSong("Halo", "Beyoncé"), unindexedSong("Hey Jude", "The Beatles")
+
+	repo := &testutil.MockSongRepository{}
+	repo.On("EnsureTextIndex", mock.Anything).Return(nil)
+	repo.On("FindAfterID", mock.Anything, "", reindexBatchSize).Return([]*models.Song{first, second}, nil)
+	repo.On("FindAfterID", mock.Anything, second.ID.Hex(), reindexBatchSize).Return([]*models.Song{}, nil)
+	repo.On("UpdateSearchText", mock.Anything, mock.Anything).Return(int64(2), nil)
+
+	w, events := performReindex(t, repo, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+
+	// Diacritics are folded so "Beyonce" finds the song
+	assert.True(t, strings.HasPrefix(first.SearchText, "halo beyonce"), first.SearchText)
+	assert.True(t, strings.HasPrefix(second.SearchText, "hey jude"), second.SearchText)
+	repo.AssertCalled(t, "UpdateSearchText", mock.Anything, []*models.Song{first, second})
+
+	require.Len(t, events, 2)
+	assert.Equal(t, reindexEvent{Type: "progress", Processed: 2, Updated: 2, Cursor: second.ID.Hex()}, events[0])
+	assert.Equal(t, reindexEvent{Type: "summary", Processed: 2, Updated: 2, Cursor: second.ID.Hex(), Complete: true}, events[1])
+}
+
+func TestReindexSearch_ResumesAfterCursor(t *testing.T) {
+	cursor := primitive.NewObjectID().Hex()
+	repo := &testutil.MockSongRepository{}
+	repo.On("EnsureTextIndex", mock.Anything).Return(nil)
+	repo.On("FindAfterID", mock.Anything, cursor, reindexBatchSize).Return([]*models.Song{}, nil)
+
+	w, events := performReindex(t, repo, "?after="+cursor)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, events, 1)
+	assert.Equal(t, reindexEvent{Type: "summary", Cursor: cursor, Complete: true}, events[0])
+	repo.AssertNotCalled(t, "UpdateSearchText", mock.Anything, mock.Anything)
+}
+
+func TestReindexSearch_ReportsFailedBatch(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	repo.On("EnsureTextIndex", mock.Anything).Return(nil)
+	repo.On("FindAfterID", mock.Anything, "", reindexBatchSize).Return(nil, assert.AnError)
+
+	w, events := performReindex(t, repo, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, events, 1)
+	assert.Equal(t, assert.AnError.Error(), events[0].Error)
+	assert.False(t, events[0].Complete)
+}
+
+func TestReindexSearch_InvalidCursorAndIndexFailure(t *testing.T) {
+	w, _ := performReindex(t, &testutil.MockSongRepository{}, "?after=nope")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	repo := &testutil.MockSongRepository{}
+	repo.On("EnsureTextIndex", mock.Anything).Return(assert.AnError)
+	w, _ = performReindex(t, repo, "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	repo.AssertNotCalled(t, "FindAfterID", mock.Anything, mock.Anything, mock.Anything)
+}
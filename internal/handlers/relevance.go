@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+	"songshare/internal/models"
+)
+
+// Popularity boost buckets (points before the configured multiplier)
+const (
+	popularityBoostHigh   = 35.0 // popularity >= 80
+	popularityBoostMedium = 20.0 // popularity >= 60
+	popularityBoostLow    = 10.0 // popularity >= 40
+)
+
+// Text match points: title tiers plus an artist bonus, capped at textMatchMax
+const (
+	textMatchExactTitle    = 50.0 // title is the query
+	textMatchTitlePrefix   = 40.0 // title starts with the query
+	textMatchTitleContains = 30.0 // title contains the query
+	textMatchFuzzyTitle    = 25.0 // title is within a few typos of the query
+	textMatchExactArtist   = 10.0 // an artist is the query
+	textMatchPartialArtist = 5.0  // an artist and the query contain one another
+	textMatchMax           = 60.0
+)
+
+// Fuzzy matching bounds: queries shorter than fuzzyMinQueryLength only match
+// by containment, since a typo there can't be told from another word; longer
+// ones allow one edit per fuzzyCharsPerEdit characters, up to fuzzyMaxEdits
+const (
+	fuzzyMinQueryLength = 4
+	fuzzyCharsPerEdit   = 4
+	fuzzyMaxEdits       = 3
+)
+
+// calculateTextMatchScore scores how well song's title and artists match the
+// search query, both normalized as for catalog search. An empty query scores 0.
+func calculateTextMatchScore(song GroupedSong, query string) float64 {
+	query = models.NormalizeSearchText(query)
+	if query == "" {
+		return 0
+	}
+
+	var score float64
+	title := models.NormalizeSearchText(song.Title)
+	switch {
+	case title == query:
+		score += textMatchExactTitle
+	case strings.HasPrefix(title, query):
+		score += textMatchTitlePrefix
+	case strings.Contains(title, query):
+		score += textMatchTitleContains
+	case fuzzyMatch(title, query):
+		score += textMatchFuzzyTitle
+	}
+
+	for _, artist := range song.Artists {
+		artist = models.NormalizeSearchText(artist)
+		if artist == "" {
+			continue
+		}
+		if artist == query {
+			score += textMatchExactArtist
+			break
+		}
+		if strings.Contains(artist, query) || strings.Contains(query, artist) {
+			score += textMatchPartialArtist
+			break
+		}
+	}
+
+	return min(score, textMatchMax)
+}
+
+// fuzzyMatch reports whether title is a near miss for query (both
+// normalized): containment once spaces are removed, which is cheap, or a
+// bounded edit distance that catches typos, transpositions and missing letters
+func fuzzyMatch(title, query string) bool {
+	compactTitle := strings.ReplaceAll(title, " ", "")
+	compactQuery := strings.ReplaceAll(query, " ", "")
+	if compactQuery == "" {
+		return false
+	}
+	if strings.Contains(compactTitle, compactQuery) {
+		return true
+	}
+
+	t, q := []rune(title), []rune(query)
+	if len(q) < fuzzyMinQueryLength {
+		return false
+	}
+	maxEdits := min(len(q)/fuzzyCharsPerEdit, fuzzyMaxEdits)
+	if diff := len(t) - len(q); diff > maxEdits || -diff > maxEdits {
+		// The lengths alone rule it out; skip the edit distance table
+		return false
+	}
+	return levenshtein(t, q) <= maxEdits
+}
+
+// getPopularityWithFallbacks returns a single 0-100 popularity for a grouped song.
+// Platforms are combined using PopularityPlatformWeights; if no weighted platform
+// reports popularity, the highest unweighted value is used instead. When
+// PopularityDecayHalfLifeYears is set, the result is decayed by the release's
+// age, so the boost and popularity tie-breaks see the same value.
+func getPopularityWithFallbacks(song GroupedSong, cfg *config.RankingConfig, now time.Time) float64 {
+	var weightedSum, weightTotal float64
+	maxPopularity := 0
+
+	for _, result := range song.Platforms {
+		if result.Popularity <= 0 {
+			continue
+		}
+		if result.Popularity > maxPopularity {
+			maxPopularity = result.Popularity
+		}
+		if cfg == nil {
+			continue
+		}
+		if weight := cfg.PopularityPlatformWeights[result.Platform]; weight > 0 {
+			weightedSum += float64(result.Popularity) * weight
+			weightTotal += weight
+		}
+	}
+
+	popularity := float64(maxPopularity)
+	if weightTotal > 0 {
+		popularity = weightedSum / weightTotal
+	}
+	if cfg != nil && cfg.PopularityDecayHalfLifeYears > 0 {
+		popularity = decayPopularity(popularity, song.ReleaseDate, cfg.PopularityDecayHalfLifeYears, now)
+	}
+	return popularity
+}
+
+// calculatePopularityBoost converts popularity into thresholded relevance points.
+// Popularity is decayed per getPopularityWithFallbacks, so formerly popular old
+// tracks don't crowd out current matches.
+func calculatePopularityBoost(song GroupedSong, cfg *config.RankingConfig, now time.Time) float64 {
+	popularity := getPopularityWithFallbacks(song, cfg, now)
+	if popularity <= 0 {
+		return 0
+	}
+
+	multiplier := 1.0
+	if cfg != nil && cfg.PopularityBoostMultiplier > 0 {
+		multiplier = cfg.PopularityBoostMultiplier
+	}
+
+	var boost float64
+	switch {
+	case popularity >= 80:
+		boost = popularityBoostHigh
+	case popularity >= 60:
+		boost = popularityBoostMedium
+	case popularity >= 40:
+		boost = popularityBoostLow
+	}
+	return boost * multiplier
+}
+
+// contextMultiplier returns the configured weight for the contextual signals
+// (recency and album art), defaulting to 1.0
+func contextMultiplier(cfg *config.RankingConfig) float64 {
+	if cfg != nil && cfg.ContextMultiplier > 0 {
+		return cfg.ContextMultiplier
+	}
+	return 1.0
+}
+
+// textMatchMultiplier returns the configured weight for the text match points,
+// defaulting to 1.0
+func textMatchMultiplier(cfg *config.RankingConfig) float64 {
+	if cfg != nil && cfg.TextMatchMultiplier > 0 {
+		return cfg.TextMatchMultiplier
+	}
+	return 1.0
+}
+
+// decayPopularity halves popularity every halfLifeYears since release.
+// Unknown or future release dates leave popularity unchanged.
+func decayPopularity(popularity float64, releaseDate string, halfLifeYears float64, now time.Time) float64 {
+	released, ok := parseReleaseDate(releaseDate)
+	if !ok || !released.Before(now) {
+		return popularity
+	}
+
+	ageYears := now.Sub(released).Hours() / (24 * 365.25)
+	return popularity * math.Pow(0.5, ageYears/halfLifeYears)
+}
+
+// parseReleaseDate accepts the full, year-month and year-only forms platforms return
+func parseReleaseDate(releaseDate string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, releaseDate); err == nil {
+			// Zero-value dates formatted from unset metadata are not real releases
+			if t.Year() < 1900 {
+				return time.Time{}, false
+			}
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// filterByMinPlatforms drops grouped songs available on fewer than minPlatforms
+// streaming platforms. Songs whose title exactly matches the query are always
+// kept so a precise search is never hidden.
+func filterByMinPlatforms(songs []GroupedSong, minPlatforms int, query string) []GroupedSong {
+	if minPlatforms <= 1 {
+		return songs
+	}
+
+	filtered := make([]GroupedSong, 0, len(songs))
+	for _, song := range songs {
+		if countStreamingPlatforms(song) >= minPlatforms || isExactTitleMatch(song, query) {
+			filtered = append(filtered, song)
+		}
+	}
+	return filtered
+}
+
+// countStreamingPlatforms counts distinct platforms, excluding the local catalog
+func countStreamingPlatforms(song GroupedSong) int {
+	seen := make(map[string]bool, len(song.Platforms))
+	for _, result := range song.Platforms {
+		if !result.IsLocal() {
+			seen[result.Platform] = true
+		}
+	}
+	return len(seen)
+}
+
+// isExactTitleMatch reports whether the query is the song's title, optionally
+// followed by its primary artist
+func isExactTitleMatch(song GroupedSong, query string) bool {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return false
+	}
+	title := strings.TrimSpace(song.Title)
+	if strings.EqualFold(query, title) {
+		return true
+	}
+	return len(song.Artists) > 0 && strings.EqualFold(query, title+" "+strings.TrimSpace(song.Artists[0]))
+}
+
+// Duration matching against a searched-for track length. A match outweighs
+// one extra platform, so the right recording beats a better-covered namesake.
+const (
+	durationMatchTolerance = 3 * time.Second  // within this counts as the same track
+	durationOutlierMargin  = 15 * time.Second // beyond this is a different track
+	durationMatchBoost     = 150
+	durationOutlierPenalty = -100
+)
+
+// durationMatchScore boosts a song within the tolerance of the target duration
+// and penalizes one beyond the outlier margin. Songs or searches without a
+// duration score 0.
+func durationMatchScore(durationMs, targetDurationMs int) int {
+	if durationMs <= 0 || targetDurationMs <= 0 {
+		return 0
+	}
+	diff := time.Duration(durationMs-targetDurationMs) * time.Millisecond
+	if diff < 0 {
+		diff = -diff
+	}
+	switch {
+	case diff <= durationMatchTolerance:
+		return durationMatchBoost
+	case diff > durationOutlierMargin:
+		return durationOutlierPenalty
+	}
+	return 0
+}
+
+// parseDurationMs parses a duration_ms query parameter, ignoring values that
+// aren't positive integers
+func parseDurationMs(raw string) int {
+	if durationMs, err := strconv.Atoi(raw); err == nil && durationMs > 0 {
+		return durationMs
+	}
+	return 0
+}
+
+// orderResultsByRank reorders each platform's results to follow the rank of
+// the grouped song they belong to. Results outside any group, such as albums
+// and local catalog matches, keep their order after the ranked ones.
+func orderResultsByRank(results map[string][]render.SearchResult, grouped []GroupedSong) map[string][]render.SearchResult {
+	type resultKey struct{ platform, externalID, url string }
+	keyOf := func(result render.SearchResult) resultKey {
+		return resultKey{result.Platform, result.ExternalID, result.URL}
+	}
+
+	rank := make(map[resultKey]int)
+	for i, song := range grouped {
+		for _, result := range song.Platforms {
+			if _, seen := rank[keyOf(result)]; !seen {
+				rank[keyOf(result)] = i
+			}
+		}
+	}
+
+	ordered := make(map[string][]render.SearchResult, len(results))
+	for platform, platformResults := range results {
+		sorted := append([]render.SearchResult(nil), platformResults...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			rankI, rankedI := rank[keyOf(sorted[i])]
+			rankJ, rankedJ := rank[keyOf(sorted[j])]
+			if rankedI != rankedJ {
+				return rankedI
+			}
+			return rankI < rankJ
+		})
+		ordered[platform] = sorted
+	}
+	return ordered
+}
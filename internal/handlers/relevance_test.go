@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+	"songshare/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func groupedWithPopularity(releaseDate string, popularity int) GroupedSong {
+	return GroupedSong{
+		Title:       "Test Song",
+		ReleaseDate: releaseDate,
+		Platforms: []render.SearchResult{
+			{Platform: "spotify", Popularity: popularity},
+		},
+	}
+}
+
+func TestGetPopularityWithFallbacks(t *testing.T) {
+	cfg := config.DefaultRankingConfig()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Weighted across platforms", func(t *testing.T) {
+		song := GroupedSong{Platforms: []render.SearchResult{
+			{Platform: "spotify", Popularity: 90},
+			{Platform: "tidal", Popularity: 45},
+		}}
+		assert.InDelta(t, 70.0, getPopularityWithFallbacks(song, cfg, now), 0.01)
+	})
+
+	t.Run("Falls back to unweighted maximum", func(t *testing.T) {
+		song := GroupedSong{Platforms: []render.SearchResult{
+			{Platform: "apple_music", Popularity: 55},
+		}}
+		assert.Equal(t, 55.0, getPopularityWithFallbacks(song, cfg, now))
+	})
+
+	t.Run("No popularity", func(t *testing.T) {
+		song := GroupedSong{Platforms: []render.SearchResult{{Platform: "spotify"}}}
+		assert.Equal(t, 0.0, getPopularityWithFallbacks(song, cfg, now))
+	})
+
+	t.Run("Decayed by release age", func(t *testing.T) {
+		decayCfg := config.DefaultRankingConfig()
+		decayCfg.PopularityDecayHalfLifeYears = 20
+
+		song := groupedWithPopularity("2005-06-01", 80)
+		assert.InDelta(t, 40.0, getPopularityWithFallbacks(song, decayCfg, now), 0.1)
+	})
+}
+
+func TestRankedBefore_DecayWithoutBoost(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	oldHit := groupedWithPopularity("1975-10-31", 90)
+	newTrack := groupedWithPopularity("2025-03-14", 65)
+
+	cfg := config.DefaultRankingConfig()
+	assert.False(t, cfg.PopularityBoostEnabled)
+	assert.True(t, rankedBefore(oldHit, newTrack, 100, 100, cfg, now), "undecayed popularity breaks the tie")
+
+	cfg.PopularityDecayHalfLifeYears = 20
+	assert.True(t, rankedBefore(newTrack, oldHit, 100, 100, cfg, now), "decay applies to tie-breaks without the boost")
+}
+
+func TestCalculatePopularityBoost_Decay(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	oldHit := groupedWithPopularity("1975-10-31", 90)
+	newTrack := groupedWithPopularity("2025-03-14", 65)
+
+	t.Run("Decay disabled by default", func(t *testing.T) {
+		cfg := config.DefaultRankingConfig()
+		assert.Equal(t, 0.0, cfg.PopularityDecayHalfLifeYears)
+
+		assert.Greater(t, calculatePopularityBoost(oldHit, cfg, now), calculatePopularityBoost(newTrack, cfg, now))
+	})
+
+	t.Run("Decay favors the new relevant track", func(t *testing.T) {
+		cfg := config.DefaultRankingConfig()
+		cfg.PopularityDecayHalfLifeYears = 20
+
+		assert.Greater(t, calculatePopularityBoost(newTrack, cfg, now), calculatePopularityBoost(oldHit, cfg, now))
+	})
+
+	t.Run("Unknown release date is not decayed", func(t *testing.T) {
+		cfg := config.DefaultRankingConfig()
+		cfg.PopularityDecayHalfLifeYears = 20
+
+		undated := groupedWithPopularity("", 90)
+		assert.Equal(t, popularityBoostHigh, calculatePopularityBoost(undated, cfg, now))
+	})
+}
+
+func TestDecayPopularity(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.InDelta(t, 40.0, decayPopularity(80, "2005-01-01", 20, now), 0.1)
+	assert.InDelta(t, 80.0, decayPopularity(80, "2025", 20, now), 0.1)
+	assert.Equal(t, 80.0, decayPopularity(80, "0001-01-01", 20, now))
+	assert.Equal(t, 80.0, decayPopularity(80, "2030-01-01", 20, now))
+}
+
+func TestFilterByMinPlatforms(t *testing.T) {
+	songs := []GroupedSong{
+		{Title: "Everywhere", Artists: []string{"A"}, Platforms: []render.SearchResult{
+			{Platform: "spotify"}, {Platform: "apple_music"}, {Platform: "tidal"},
+		}},
+		{Title: "Two Places", Artists: []string{"B"}, Platforms: []render.SearchResult{
+			{Platform: "spotify"}, {Platform: "local"}, {Platform: "tidal"},
+		}},
+		{Title: "Spotify Only", Artists: []string{"C"}, Platforms: []render.SearchResult{
+			{Platform: "spotify"}, {Platform: "local"},
+		}},
+	}
+
+	titles := func(grouped []GroupedSong) []string {
+		var out []string
+		for _, song := range grouped {
+			out = append(out, song.Title)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name         string
+		minPlatforms int
+		query        string
+		expected     []string
+	}{
+		{name: "Default keeps everything", minPlatforms: 1, query: "song", expected: []string{"Everywhere", "Two Places", "Spotify Only"}},
+		{name: "Zero keeps everything", minPlatforms: 0, query: "song", expected: []string{"Everywhere", "Two Places", "Spotify Only"}},
+		{name: "Two platforms ignores local", minPlatforms: 2, query: "song", expected: []string{"Everywhere", "Two Places"}},
+		{name: "Three platforms", minPlatforms: 3, query: "song", expected: []string{"Everywhere"}},
+		{name: "Above all", minPlatforms: 4, query: "song", expected: nil},
+		{name: "Exact title is never hidden", minPlatforms: 3, query: "spotify only", expected: []string{"Everywhere", "Spotify Only"}},
+		{name: "Exact title with artist", minPlatforms: 3, query: "Spotify Only C", expected: []string{"Everywhere", "Spotify Only"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, titles(filterByMinPlatforms(songs, tt.minPlatforms, tt.query)))
+		})
+	}
+}
+
+func TestDurationMatchScore(t *testing.T) {
+	assert.Equal(t, durationMatchBoost, durationMatchScore(354000, 355500))
+	assert.Equal(t, 0, durationMatchScore(354000, 364000))
+	assert.Equal(t, durationOutlierPenalty, durationMatchScore(240000, 354000))
+	assert.Equal(t, 0, durationMatchScore(0, 354000))
+	assert.Equal(t, 0, durationMatchScore(354000, 0))
+}
+
+func TestGroupSongsForDuration_MatchOutranksSameTitle(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	// A radio edit on two platforms and the album version on one
+	results := map[string][]render.SearchResult{
+		"spotify": {
+			{Platform: "spotify", Title: "Song", Artists: []string{"Artist"}, ISRC: "USAAA0000001", DurationMs: 210000},
+			{Platform: "spotify", Title: "Song", Artists: []string{"Artist"}, ISRC: "USAAA0000002", DurationMs: 354000},
+		},
+		"tidal": {
+			{Platform: "tidal", Title: "Song", Artists: []string{"Artist"}, ISRC: "USAAA0000001", DurationMs: 210000},
+		},
+	}
+	cfg := config.DefaultRankingConfig()
+
+	unranked := handler.groupSongsForDuration(results, cfg, "", 0)
+	assert.Equal(t, "USAAA0000001", unranked[0].ISRC)
+
+	// A local file's length picks out the album version
+	ranked := handler.groupSongsForDuration(results, cfg, "", 354700)
+	assert.Equal(t, "USAAA0000002", ranked[0].ISRC)
+	assert.Equal(t, durationMatchBoost, handler.relevanceBreakdown(ranked[0], cfg, "", 354700, time.Now()).Duration)
+	assert.Equal(t, durationOutlierPenalty, handler.relevanceBreakdown(ranked[1], cfg, "", 354700, time.Now()).Duration)
+}
+
+func TestRelevanceBreakdown_ContextMultiplier(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	song := GroupedSong{
+		Title:       "Song",
+		ReleaseDate: "2024-05-01",
+		ImageURL:    "https://example.com/art.jpg",
+		Platforms:   []render.SearchResult{{Platform: "spotify"}},
+	}
+
+	defaults := handler.relevanceBreakdown(song, config.DefaultRankingConfig(), "", 0, time.Now())
+	assert.Equal(t, 50, defaults.Recency)
+	assert.Equal(t, 25, defaults.AlbumArt)
+
+	cfg := config.DefaultRankingConfig()
+	cfg.ContextMultiplier = 0.5
+	halved := handler.relevanceBreakdown(song, cfg, "", 0, time.Now())
+	assert.Equal(t, 25, halved.Recency)
+	assert.Equal(t, 12, halved.AlbumArt)
+	assert.Equal(t, defaults.Platforms, halved.Platforms, "other signals are unaffected")
+	assert.Equal(t, defaults.Total-38, halved.Total)
+}
+
+func TestRelevanceBreakdown_TextMatchMultiplier(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	song := GroupedSong{Title: "Bohemian Rhapsody", Artists: []string{"Queen"}}
+
+	defaults := handler.relevanceBreakdown(song, config.DefaultRankingConfig(), "bohemian rhapsody", 0, time.Now())
+	assert.Equal(t, 50, defaults.TextMatch)
+	assert.Equal(t, 2.5, defaults.TieEpsilon)
+
+	cfg := config.DefaultRankingConfig()
+	cfg.TextMatchMultiplier = 2
+	cfg.TieEpsilon = 5
+	doubled := handler.relevanceBreakdown(song, cfg, "bohemian rhapsody", 0, time.Now())
+	assert.Equal(t, 100, doubled.TextMatch)
+	assert.Equal(t, defaults.Total+50, doubled.Total)
+	assert.Equal(t, 5.0, doubled.TieEpsilon, "the breakdown reports the epsilon it was ranked with")
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		query string
+		want  bool
+	}{
+		{"Missing letter", "Bohemian Rhapsody", "bohemian rapsody", true},
+		{"Transposition", "Bohemian Rhapsody", "bohemain rhapsody", true},
+		{"Typo", "Stairway to Heaven", "stairway to heavan", true},
+		{"Missing space", "Stairway to Heaven", "stairwayto heaven", true},
+		{"Containment fast path", "Bohemian Rhapsody", "rhapsody", true},
+		{"Too many edits for a short query", "Hello", "halo", false},
+		{"Lengths too far apart", "Today", "yesterday", false},
+		{"Different song", "Bohemian Like You", "bohemian rhapsody", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title := models.NormalizeSearchText(tt.title)
+			query := models.NormalizeSearchText(tt.query)
+			assert.Equal(t, tt.want, fuzzyMatch(title, query))
+		})
+	}
+}
+
+func TestCalculateTextMatchScore(t *testing.T) {
+	song := GroupedSong{Title: "Bohemian Rhapsody", Artists: []string{"Queen"}}
+
+	tests := []struct {
+		query string
+		want  float64
+	}{
+		{"Bohemian Rhapsody", textMatchExactTitle},
+		{"bohemian", textMatchTitlePrefix},
+		{"rhapsody", textMatchTitleContains},
+		{"bohemian rapsody", textMatchFuzzyTitle},
+		{"queen", textMatchExactArtist},
+		{"bohemian rhapsody queen", textMatchPartialArtist},
+		{"halo", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, calculateTextMatchScore(song, tt.query))
+		})
+	}
+
+	selfTitled := GroupedSong{Title: "Queen", Artists: []string{"Queen"}}
+	assert.Equal(t, textMatchMax, calculateTextMatchScore(selfTitled, "queen"), "capped")
+}
+
+func TestGroupSongsForDuration_TypoStillRanksIntendedSong(t *testing.T) {
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+	results := map[string][]render.SearchResult{
+		"spotify": {
+			{Platform: "spotify", Title: "Bohemian Like You", Artists: []string{"The Dandy Warhols"}, ISRC: "USAAA0000001"},
+			{Platform: "spotify", Title: "Bohemian Rhapsody", Artists: []string{"Queen"}, ISRC: "USAAA0000002"},
+		},
+	}
+
+	ranked := handler.groupSongsForDuration(results, config.DefaultRankingConfig(), "bohemian rapsody", 0)
+
+	assert.Equal(t, "Bohemian Rhapsody", ranked[0].Title)
+	assert.Equal(t, int(textMatchFuzzyTitle), handler.relevanceBreakdown(ranked[0], config.DefaultRankingConfig(), "bohemian rapsody", 0, time.Now()).TextMatch)
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetAllowedHosts configures which request Host headers may be used to build
+// self-referential links. Requests for any other host fall back to the static
+// base URL, so a spoofed Host header cannot inject foreign links.
+func (r *SongRenderer) SetAllowedHosts(hosts []string) {
+	allowed := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		if host = normalizeHost(host); host != "" {
+			allowed[host] = true
+		}
+	}
+	r.allowedHosts = allowed
+}
+
+// BaseURL returns the base URL for links generated while serving this request
+func (r *SongRenderer) BaseURL(c *gin.Context) string {
+	if c == nil || c.Request == nil || len(r.allowedHosts) == 0 {
+		return r.baseURL
+	}
+
+	host := normalizeHost(c.Request.Host)
+	if !r.isAllowedHost(host) {
+		return r.baseURL
+	}
+
+	return r.requestScheme(c) + "://" + host
+}
+
+// IsOwnHost reports whether host serves this site's links: the host of the
+// request's base URL, the static base URL or any allowed host
+func (r *SongRenderer) IsOwnHost(c *gin.Context, host string) bool {
+	host = normalizeHost(host)
+	for _, base := range []string{r.BaseURL(c), r.baseURL} {
+		if parsed, err := url.Parse(base); err == nil && normalizeHost(parsed.Host) == host {
+			return true
+		}
+	}
+	return r.isAllowedHost(host)
+}
+
+// isAllowedHost matches the host with or without its port against the allow-list
+func (r *SongRenderer) isAllowedHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	if r.allowedHosts[host] {
+		return true
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return r.allowedHosts[hostname]
+	}
+	return false
+}
+
+// requestScheme determines the scheme the client used, defaulting to the static base URL's
+func (r *SongRenderer) requestScheme(c *gin.Context) string {
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	switch proto := strings.ToLower(c.GetHeader("X-Forwarded-Proto")); proto {
+	case "http", "https":
+		return proto
+	}
+	if parsed, err := url.Parse(r.baseURL); err == nil && parsed.Scheme != "" {
+		return parsed.Scheme
+	}
+	return "https"
+}
+
+// normalizeHost lowercases a host and strips any trailing dot
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
@@ -0,0 +1,118 @@
+package render
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHostContext(host string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/s/GBUM71505078", nil)
+	c.Request.Host = host
+	return c
+}
+
+func TestSongRenderer_BaseURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetAllowedHosts([]string{"share.brandA.com", " Share.BrandB.com ", "localhost"})
+
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{name: "Allowed host", host: "share.branda.com", expected: "https://share.branda.com"},
+		{name: "Second allowed host", host: "share.brandb.com", expected: "https://share.brandb.com"},
+		{name: "Case-insensitive match", host: "SHARE.BRANDA.COM", expected: "https://share.branda.com"},
+		{name: "Allowed host with port", host: "localhost:8080", expected: "https://localhost:8080"},
+		{name: "Unlisted host falls back", host: "evil.example", expected: "https://songshare.example"},
+		{name: "Lookalike host falls back", host: "share.branda.com.evil.example", expected: "https://songshare.example"},
+		{name: "Empty host falls back", host: "", expected: "https://songshare.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderer.BaseURL(newHostContext(tt.host)))
+		})
+	}
+}
+
+func TestSongRenderer_BaseURL_NoAllowList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	renderer := NewSongRenderer("http://localhost:8080")
+
+	assert.Equal(t, "http://localhost:8080", renderer.BaseURL(newHostContext("share.branda.com")))
+	assert.Equal(t, "http://localhost:8080", renderer.BaseURL(nil))
+}
+
+func TestSongRenderer_BaseURL_Scheme(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	renderer := NewSongRenderer("http://localhost:8080")
+	renderer.SetAllowedHosts([]string{"share.branda.com"})
+
+	c := newHostContext("share.branda.com")
+	assert.Equal(t, "http://share.branda.com", renderer.BaseURL(c), "defaults to the static base URL's scheme")
+
+	c.Request.Header.Set("X-Forwarded-Proto", "https")
+	assert.Equal(t, "https://share.branda.com", renderer.BaseURL(c))
+
+	c = newHostContext("share.branda.com")
+	c.Request.TLS = &tls.ConnectionState{}
+	assert.Equal(t, "https://share.branda.com", renderer.BaseURL(c))
+}
+
+func TestRenderSongJSON_MultiHostUniversalLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.ISRC = "GBUM71505078"
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetAllowedHosts([]string{"share.branda.com", "share.brandb.com"})
+
+	for host, expected := range map[string]string{
+		"share.branda.com": "https://share.branda.com/s/GBUM71505078",
+		"share.brandb.com": "https://share.brandb.com/s/GBUM71505078",
+		"attacker.example": "https://songshare.example/s/GBUM71505078",
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/s/GBUM71505078", nil)
+		c.Request.Host = host
+
+		renderer.RenderSongJSON(c, song)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response ResolveSongResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, expected, response.UniversalLink, host)
+	}
+}
+
+func TestRenderSongPage_CanonicalUsesRequestHost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.ISRC = "GBUM71505078"
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetAllowedHosts([]string{"share.brandb.com"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/s/GBUM71505078", nil)
+	c.Request.Host = "share.brandb.com"
+
+	renderer.RenderSongPage(c, song, func(string) *PlatformUIConfig { return &PlatformUIConfig{} })
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `<link rel="canonical" href="https://share.brandb.com/s/GBUM71505078">`)
+	assert.Contains(t, body, `<meta property="og:url" content="https://share.brandb.com/s/GBUM71505078">`)
+}
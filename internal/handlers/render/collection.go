@@ -0,0 +1,87 @@
+package render
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"songshare/internal/models"
+	"songshare/internal/templates"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CollectionPageTrack is one track row on the collection page
+type CollectionPageTrack struct {
+	Title     string
+	Artist    string
+	ShareURL  string
+	Platforms []PlatformDisplayData
+}
+
+// CollectionURL builds the universal link for a collection
+func CollectionURL(baseURL string, collection *models.Collection) string {
+	return fmt.Sprintf("%s/c/%s", baseURL, collection.ID.Hex())
+}
+
+// CollectionTrackURL builds the universal link for one collection track,
+// falling back to the song ID prefix for songs without an ISRC
+func CollectionTrackURL(baseURL string, track models.CollectionTrack) string {
+	if track.ISRC == "" {
+		return fmt.Sprintf("%s/s/%s", baseURL, track.SongID.Hex()[:8])
+	}
+	return fmt.Sprintf("%s/s/%s", baseURL, track.ISRC)
+}
+
+// RenderCollectionPage renders a collection as an HTML page. songs holds the
+// collection's stored songs by ID; tracks whose song is missing are listed
+// without platform badges.
+func (r *SongRenderer) RenderCollectionPage(c *gin.Context, collection *models.Collection, songs map[primitive.ObjectID]*models.Song, getPlatformUIConfig func(string) *PlatformUIConfig) {
+	baseURL := r.BaseURL(c)
+	data := struct {
+		Collection  *models.Collection
+		Tracks      []CollectionPageTrack
+		CoverArt    string
+		OGImage     string
+		ShareURL    string
+		Description string
+		Theme       themeData
+	}{
+		Collection: collection,
+		Tracks:     make([]CollectionPageTrack, 0, len(collection.Tracks)),
+		CoverArt:   collection.ImageURL,
+		OGImage:    r.theme.previewImage(collection.ImageURL),
+		ShareURL:   CollectionURL(baseURL, collection),
+		Theme:      r.theme,
+	}
+
+	for _, track := range collection.Tracks {
+		row := CollectionPageTrack{
+			Title:    track.Title,
+			Artist:   track.Artist,
+			ShareURL: CollectionTrackURL(baseURL, track),
+		}
+		if song := songs[track.SongID]; song != nil {
+			row.Platforms = platformDisplayData(song, getPlatformUIConfig)
+		}
+		data.Tracks = append(data.Tracks, row)
+	}
+
+	// Preview text for link unfurls (og:description)
+	data.Description = fmt.Sprintf("%d songs, shareable on any platform", len(collection.Tracks))
+	if len(collection.Tracks) == 1 {
+		data.Description = "1 song, shareable on any platform"
+	}
+
+	tmpl, err := templates.GetTemplate("collection_page")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template error"})
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(c.Writer, data); err != nil {
+		slog.Error("Failed to render collection page", "id", collection.ID.Hex(), "error", err)
+	}
+}
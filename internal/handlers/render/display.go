@@ -0,0 +1,67 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"songshare/internal/models"
+)
+
+// Display caps matching the DISPLAY_MAX_TITLE_LENGTH and DISPLAY_MAX_ARTISTS
+// config defaults
+const (
+	DefaultMaxTitleLength = 100 // Characters
+	DefaultMaxArtists     = 3
+)
+
+// SetDisplayLimits sets how many characters of a title and how many artists
+// pages show; values of 0 or less keep the current limit. Stored and JSON
+// values are never shortened.
+func (r *SongRenderer) SetDisplayLimits(maxTitleLength, maxArtists int) {
+	if maxTitleLength > 0 {
+		r.maxTitleLength = maxTitleLength
+	}
+	if maxArtists > 0 {
+		r.maxArtists = maxArtists
+	}
+}
+
+// DisplayTitle shortens title to the configured length for display
+func (r *SongRenderer) DisplayTitle(title string) string {
+	return TruncateDisplay(title, r.maxTitleLength)
+}
+
+// DisplayArtists lists artists for display, up to the configured count
+func (r *SongRenderer) DisplayArtists(artists []string) string {
+	return JoinArtistsDisplay(artists, r.maxArtists)
+}
+
+// displaySongArtist lists a song's artists for display. Only the credited
+// artist list is capped: the joined Artist can't be split back into names,
+// since a name may itself contain ", ".
+func (r *SongRenderer) displaySongArtist(song *models.Song) string {
+	if len(song.Artists) == 0 {
+		return song.Artist
+	}
+	return r.DisplayArtists(song.Artists)
+}
+
+// TruncateDisplay shortens s to at most maxLength characters, ending it with
+// an ellipsis when anything was cut. maxLength of 0 or less leaves s whole.
+func TruncateDisplay(s string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(s) <= maxLength {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimRight(string(runes[:maxLength-1]), " ") + "…"
+}
+
+// JoinArtistsDisplay joins the first maxArtists artists with commas and
+// counts the rest as "+N more". maxArtists of 0 or less lists them all.
+func JoinArtistsDisplay(artists []string, maxArtists int) string {
+	if maxArtists <= 0 || len(artists) <= maxArtists {
+		return strings.Join(artists, ", ")
+	}
+	return fmt.Sprintf("%s +%d more", strings.Join(artists[:maxArtists], ", "), len(artists)-maxArtists)
+}
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"songshare/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderSongPage(t *testing.T, renderer *SongRenderer, song *models.Song) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/s/"+song.ISRC, nil)
+	renderer.RenderSongPage(c, song, func(string) *PlatformUIConfig { return &PlatformUIConfig{} })
+
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestTruncateDisplay(t *testing.T) {
+	assert.Equal(t, "Halo", TruncateDisplay("Halo", 10))
+	assert.Equal(t, "Exactly10!", TruncateDisplay("Exactly10!", 10))
+	assert.Equal(t, "A very…", TruncateDisplay("A very long title", 8), "trailing spaces go before the ellipsis")
+	assert.Equal(t, "Déjà vu…", TruncateDisplay("Déjà vu (Remix)", 8), "counts characters, not bytes")
+	assert.Equal(t, "A very long title", TruncateDisplay("A very long title", 0))
+}
+
+func TestJoinArtistsDisplay(t *testing.T) {
+	assert.Equal(t, "Queen", JoinArtistsDisplay([]string{"Queen"}, 3))
+	assert.Equal(t, "A, B, C", JoinArtistsDisplay([]string{"A", "B", "C"}, 3))
+	assert.Equal(t, "A, B, C +2 more", JoinArtistsDisplay([]string{"A", "B", "C", "D", "E"}, 3))
+	assert.Equal(t, "A, B, C, D, E", JoinArtistsDisplay([]string{"A", "B", "C", "D", "E"}, 0))
+	assert.Empty(t, JoinArtistsDisplay(nil, 3))
+}
+
+func TestRenderSongPage_CapsLongTitleAndArtists(t *testing.T) {
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetDisplayLimits(20, 2)
+
+	title := "An Absurdly Long Title That Goes On And On"
+	song := models.NewSong(title, "Lead, Feature One, Feature Two, Feature Three")
+	song.Artists = []string{"Lead", "Feature One", "Feature Two", "Feature Three"}
+	song.ISRC = "GBUM71505078"
+	page := renderSongPage(t, renderer, song)
+
+	assert.Contains(t, page, `<div class="song-title" title="`+title+`">An Absurdly Long Ti…`)
+	assert.Contains(t, page, `>Lead, Feature One &#43;2 more</div>`)
+	// The full values stay in the page metadata
+	assert.Contains(t, page, "<title>"+title+" - Lead, Feature One, Feature Two, Feature Three</title>")
+	assert.Equal(t, title, song.Title)
+}
+
+func TestRenderSongPage_ArtistNamesWithCommas(t *testing.T) {
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetDisplayLimits(0, 1)
+
+	// One artist whose name contains the join separator is never split
+	song := models.NewSong("See You Again", "Tyler, The Creator")
+	song.ISRC = "USQX91700278"
+	assert.Contains(t, renderSongPage(t, renderer, song), `>Tyler, The Creator</div>`)
+
+	// The credited list caps by artist, not by comma
+	song = models.NewSong("Earfquake", "Tyler, The Creator, Playboi Carti")
+	song.Artists = []string{"Tyler, The Creator", "Playboi Carti"}
+	song.ISRC = "USQX91900758"
+	assert.Contains(t, renderSongPage(t, renderer, song), `>Tyler, The Creator &#43;1 more</div>`)
+}
+
+func TestSetDisplayLimits_KeepsDefaultsForUnsetValues(t *testing.T) {
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetDisplayLimits(0, -1)
+
+	long := strings.Repeat("a", DefaultMaxTitleLength+1)
+	assert.Equal(t, []rune(renderer.DisplayTitle(long))[DefaultMaxTitleLength-1], '…')
+	assert.Equal(t, "A, B, C +1 more", renderer.DisplayArtists([]string{"A", "B", "C", "D"}))
+}
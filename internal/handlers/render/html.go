@@ -2,7 +2,10 @@ package render
 
 import (
 	"fmt"
+	"html"
 	"strings"
+
+	"songshare/internal/models"
 )
 
 // SearchResultWithSource pairs a search result with its source information
@@ -48,15 +51,10 @@ func RenderSearchResultsHTML(results []SearchResultWithSource) string {
 		// Platform badge
 		html.WriteString(`<div class="result-platforms">`)
 		platformClass := fmt.Sprintf("platform-%s", result.Platform)
-		platformName := result.Platform
-		if result.Platform == "apple_music" {
-			platformName = "Apple Music"
-		} else if result.Platform == "spotify" {
-			platformName = "Spotify"
-		} else if result.Platform == "local" {
+		platformName := NormalizePlatformName(result.Platform)
+		if result.IsLocal() {
+			platformClass = "source-local"
 			platformName = "SongShare"
-		} else if result.Platform == "tidal" {
-			platformName = "Tidal"
 		}
 		html.WriteString(fmt.Sprintf(`<span class="platform-badge %s">%s</span>`, platformClass, platformName))
 		html.WriteString(`</div>`)
@@ -66,7 +64,7 @@ func RenderSearchResultsHTML(results []SearchResultWithSource) string {
 		// Actions
 		html.WriteString(`<div class="result-actions">`)
 
-		if result.Platform == "local" {
+		if result.IsLocal() {
 			html.WriteString(fmt.Sprintf(`<a href="%s" class="action-btn action-primary">View Song</a>`, result.URL))
 		} else {
 			html.WriteString(fmt.Sprintf(`
@@ -201,6 +199,32 @@ func renderPlatformBadge(badge PlatformBadge) string {
 	return badgeHTML
 }
 
+// RenderOOBBadges generates hx-swap-oob fragments that replace the platform badges of
+// each displayed search result (identified by its result-item ID) with the song's links
+func RenderOOBBadges(song *models.Song, resultIDs []string) string {
+	if song == nil || len(resultIDs) == 0 {
+		return ""
+	}
+
+	var badges strings.Builder
+	for _, link := range song.PlatformLinks {
+		if !link.Available {
+			continue
+		}
+		badgeClass := fmt.Sprintf("platform-badge platform-%s", strings.ReplaceAll(link.Platform, "_", "-"))
+		badges.WriteString(fmt.Sprintf(`<a href="%s" target="_blank" class="%s">%s</a>`,
+			html.EscapeString(link.URL), badgeClass, NormalizePlatformName(link.Platform)))
+	}
+
+	var out strings.Builder
+	for _, resultID := range resultIDs {
+		out.WriteString(fmt.Sprintf(`<div class="result-platforms" id="%s-platforms" hx-swap-oob="true">`, html.EscapeString(resultID)))
+		out.WriteString(badges.String())
+		out.WriteString(`</div>`)
+	}
+	return out.String()
+}
+
 // NormalizePlatformName converts platform identifiers to display names
 func NormalizePlatformName(platform string) string {
 	switch platform {
@@ -212,8 +236,8 @@ func NormalizePlatformName(platform string) string {
 		return "Tidal"
 	case "youtube_music":
 		return "YouTube Music"
-	case "local":
-		return "SongShare"
+	case "deezer":
+		return "Deezer"
 	default:
 		return platform
 	}
@@ -0,0 +1,43 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"songshare/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderOOBBadges(t *testing.T) {
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.ISRC = "GBUM71505078"
+	song.AddPlatformLink("spotify", "sp1", "https://open.spotify.com/track/sp1", 1.0)
+	song.AddPlatformLink("apple_music", "am1", "https://music.apple.com/us/song/am1", 1.0)
+
+	oob := RenderOOBBadges(song, []string{"result-0", "result-2"})
+
+	assert.Equal(t, 2, strings.Count(oob, `hx-swap-oob="true"`))
+	assert.Contains(t, oob, `<div class="result-platforms" id="result-0-platforms" hx-swap-oob="true">`)
+	assert.Contains(t, oob, `<div class="result-platforms" id="result-2-platforms" hx-swap-oob="true">`)
+	assert.Contains(t, oob, `<a href="https://open.spotify.com/track/sp1" target="_blank" class="platform-badge platform-spotify">Spotify</a>`)
+	assert.Contains(t, oob, `class="platform-badge platform-apple-music">Apple Music</a>`)
+}
+
+func TestRenderOOBBadges_SkipsUnavailableLinks(t *testing.T) {
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.AddPlatformLink("spotify", "sp1", "https://open.spotify.com/track/sp1", 1.0)
+	song.AddPlatformLink("tidal", "td1", "https://tidal.com/browse/track/td1", 1.0)
+	song.PlatformLinks[1].Available = false
+
+	oob := RenderOOBBadges(song, []string{"result-0"})
+
+	assert.Contains(t, oob, "platform-spotify")
+	assert.NotContains(t, oob, "platform-tidal")
+}
+
+func TestRenderOOBBadges_NoResults(t *testing.T) {
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	assert.Empty(t, RenderOOBBadges(song, nil))
+	assert.Empty(t, RenderOOBBadges(nil, []string{"result-0"}))
+}
@@ -0,0 +1,131 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseFields splits a comma-separated fields parameter, ignoring blanks
+func ParseFields(raw string) []string {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// RespondJSON writes v as JSON, projected to the fields named in the request's
+// "fields" query parameter when present
+func RespondJSON(c *gin.Context, status int, v interface{}) {
+	if fields := ParseFields(c.Query("fields")); len(fields) > 0 {
+		c.JSON(status, ProjectFields(v, fields))
+		return
+	}
+	c.JSON(status, v)
+}
+
+// ProjectFields reduces v to the requested JSON fields. Each field is a top-level
+// JSON key or a dotted path to a nested one, such as "song.title"; a "*" segment
+// matches every key of an object, such as "results.*.title". Arrays are
+// projected element by element, so empty arrays and elements missing the field
+// are kept. A selected field is kept whole. v is encoded with encoding/json
+// first, so field names, omitempty and embedded structs follow its rules. An
+// empty field list, or a v that can't be encoded, returns v unchanged.
+func ProjectFields(v interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return v
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return v
+	}
+
+	root := &fieldSelection{}
+	for _, field := range fields {
+		root.add(strings.Split(field, "."))
+	}
+
+	projected, ok := projectValue(decoded, root)
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return projected
+}
+
+// fieldSelection is a node of the requested field paths
+type fieldSelection struct {
+	whole    bool
+	children map[string]*fieldSelection
+}
+
+// add marks path as selected below s
+func (s *fieldSelection) add(path []string) {
+	if len(path) == 0 {
+		s.whole = true
+		return
+	}
+	if s.children == nil {
+		s.children = make(map[string]*fieldSelection)
+	}
+	child, ok := s.children[path[0]]
+	if !ok {
+		child = &fieldSelection{}
+		s.children[path[0]] = child
+	}
+	child.add(path[1:])
+}
+
+// child returns the selection for key, falling back to a "*" wildcard
+func (s *fieldSelection) child(key string) *fieldSelection {
+	if child, ok := s.children[key]; ok {
+		return child
+	}
+	return s.children["*"]
+}
+
+// projectValue returns the projection of a decoded JSON value and whether
+// anything of it is kept. Objects keep only selected keys, arrays project each
+// element, and scalars can't be projected into.
+func projectValue(v interface{}, sel *fieldSelection) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for key, value := range v {
+			child := sel.child(key)
+			if child == nil {
+				continue
+			}
+			if child.whole {
+				out[key] = value
+				continue
+			}
+			if nested, ok := projectValue(value, child); ok {
+				out[key] = nested
+			}
+		}
+		return out, true
+
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, element := range v {
+			if nested, ok := projectValue(element, sel); ok {
+				out = append(out, nested)
+			}
+		}
+		return out, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,142 @@
+package render
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResolveResponse() ResolveSongResponse {
+	return ResolveSongResponse{
+		Song: SongMetadata{
+			ID:      "abc123",
+			Title:   "Bohemian Rhapsody",
+			Artists: []string{"Queen"},
+			Album:   "A Night at the Opera",
+		},
+		Platforms: map[string]PlatformLink{
+			"spotify": {URL: "https://open.spotify.com/track/1", Available: true, Platform: "spotify"},
+		},
+		UniversalLink: "http://localhost/s/GBUM71505078",
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	assert.Equal(t, []string{"title", "artists"}, ParseFields(" title, ,artists,"))
+	assert.Nil(t, ParseFields(""))
+}
+
+func TestProjectFields_Projected(t *testing.T) {
+	projected := ProjectFields(testResolveResponse(), []string{"song.title", "song.artists", "platforms"})
+
+	data, err := json.Marshal(projected)
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &body))
+
+	assert.Equal(t, map[string]interface{}{
+		"title":   "Bohemian Rhapsody",
+		"artists": []interface{}{"Queen"},
+	}, body["song"])
+	assert.Contains(t, body, "platforms")
+	assert.NotContains(t, body, "universal_link")
+}
+
+func TestProjectFields_SearchResults(t *testing.T) {
+	results := map[string][]SearchResult{
+		"spotify": {{Title: "Song A", Artists: []string{"Artist"}, URL: "https://x", Popularity: 80}},
+	}
+
+	data, err := json.Marshal(ProjectFields(results, []string{"*.title", "*.url"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"spotify":[{"title":"Song A","url":"https://x"}]}`, string(data))
+}
+
+func TestProjectFields_TopLevelOnly(t *testing.T) {
+	data, err := json.Marshal(ProjectFields(testResolveResponse(), []string{"title", "universal_link"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"universal_link":"http://localhost/s/GBUM71505078"}`, string(data), "nested fields need a dotted path")
+}
+
+func TestProjectFields_KeepsArrays(t *testing.T) {
+	type response struct {
+		Results []SearchResult `json:"results"`
+		Counts  []int          `json:"counts"`
+	}
+
+	data, err := json.Marshal(ProjectFields(response{Results: []SearchResult{}}, []string{"results.title"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"results":[]}`, string(data))
+
+	// Elements without the field stay, empty, rather than dropping the array
+	data, err = json.Marshal(ProjectFields(response{Results: []SearchResult{{Title: "Song A"}}}, []string{"results.missing"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"results":[{}]}`, string(data))
+}
+
+func TestProjectFields_EncodingJSONRules(t *testing.T) {
+	type Embedded struct {
+		Genre string `json:"genre"`
+	}
+	type response struct {
+		Embedded
+		Title    string `json:"title"`
+		ISRC     string `json:"isrc,omitempty"`
+		Internal string `json:"-"`
+	}
+	value := response{Embedded: Embedded{Genre: "Rock"}, Title: "Song A", Internal: "secret"}
+
+	data, err := json.Marshal(ProjectFields(value, []string{"genre", "title", "isrc", "Internal", "Embedded"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"genre":"Rock","title":"Song A"}`, string(data), "embedded fields are flattened and empty omitempty fields left out")
+}
+
+func TestProjectFields_Full(t *testing.T) {
+	response := testResolveResponse()
+	assert.Equal(t, response, ProjectFields(response, nil))
+}
+
+func TestRenderSongJSON_FieldsParam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.ISRC = "GBUM71505078"
+	renderer := NewSongRenderer("http://localhost")
+
+	tests := []struct {
+		name       string
+		query      string
+		expectKeys []string
+		absentKeys []string
+	}{
+		{name: "Full response", query: "", expectKeys: []string{"song", "platforms", "universal_link"}},
+		{name: "Projected response", query: "?fields=universal_link", expectKeys: []string{"universal_link"}, absentKeys: []string{"song", "platforms"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/s/GBUM71505078"+tt.query, nil)
+
+			renderer.RenderSongJSON(c, song)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			for _, key := range tt.expectKeys {
+				assert.Contains(t, body, key)
+			}
+			for _, key := range tt.absentKeys {
+				assert.NotContains(t, body, key)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"net/http"
 	"sort"
+	"strings"
+	"time"
 
 	"songshare/internal/models"
 	"songshare/internal/templates"
@@ -22,6 +24,8 @@ type SongMetadata struct {
 	ReleaseDate string   `json:"release_date"`
 	ISRC        string   `json:"isrc,omitempty"`
 	ImageURL    string   `json:"image_url,omitempty"`
+	LyricsURL   string   `json:"lyrics_url,omitempty"` // Third-party lyrics page
+	Views       int64    `json:"views,omitempty"`      // Human visits to the universal link
 }
 
 // PlatformLink represents a link to a song on a specific platform
@@ -29,6 +33,7 @@ type PlatformLink struct {
 	URL       string `json:"url"`
 	Available bool   `json:"available"`
 	Platform  string `json:"platform"`
+	Primary   bool   `json:"primary,omitempty"` // Default link for the share page's main action
 }
 
 // ResolveSongResponse represents the response with song metadata and platform links
@@ -36,6 +41,10 @@ type ResolveSongResponse struct {
 	Song          SongMetadata            `json:"song"`
 	Platforms     map[string]PlatformLink `json:"platforms"`
 	UniversalLink string                  `json:"universal_link"`
+	Ephemeral     bool                    `json:"ephemeral,omitempty"` // Resolved for preview only, not saved
+	Saving        bool                    `json:"saving,omitempty"`    // Save failed transiently and is being retried
+	Verified      bool                    `json:"verified"`            // All links are certain matches verified recently
+	Retired       bool                    `json:"retired,omitempty"`   // No longer available on any platform
 }
 
 // PlatformDisplayData contains platform information for templates
@@ -48,10 +57,13 @@ type PlatformDisplayData struct {
 	Description string
 	Color       string
 	CSSClass    string
+	Primary     bool
 }
 
 // SearchResult represents a single search result for rendering
 type SearchResult struct {
+	Kind        string   `json:"kind,omitempty"`        // Empty for tracks; "album" or "artist" when enabled
+	ExternalID  string   `json:"external_id,omitempty"` // Platform track ID
 	Title       string   `json:"title"`
 	Artists     []string `json:"artists"`
 	Album       string   `json:"album"`
@@ -69,27 +81,40 @@ type SearchResult struct {
 
 // SongRenderer handles rendering song responses in different formats
 type SongRenderer struct {
-	baseURL string
+	baseURL      string
+	allowedHosts map[string]bool // Request hosts that may override baseURL
+	theme        themeData
+
+	// verifiedMaxAge is how recently every link must have been verified for a song to show as verified
+	verifiedMaxAge time.Duration
+
+	// Display caps for long titles and artist lists (see SetDisplayLimits)
+	maxTitleLength int
+	maxArtists     int
 }
 
 // NewSongRenderer creates a new song renderer
 func NewSongRenderer(baseURL string) *SongRenderer {
 	return &SongRenderer{
-		baseURL: baseURL,
+		baseURL:        baseURL,
+		theme:          newThemeData(Theme{}),
+		verifiedMaxAge: DefaultVerifiedMaxAge,
+		maxTitleLength: DefaultMaxTitleLength,
+		maxArtists:     DefaultMaxArtists,
 	}
 }
 
 // buildUniversalLink builds the universal link for a song
-func (r *SongRenderer) buildUniversalLink(song *models.Song) string {
+func buildUniversalLink(baseURL string, song *models.Song) string {
 	if song.ISRC == "" {
 		slog.Warn("Song missing ISRC", "songID", song.ID.Hex(), "title", song.Title)
 		// This shouldn't happen with properly indexed songs
-		return fmt.Sprintf("%s/s/unknown", r.baseURL)
+		return fmt.Sprintf("%s/s/unknown", baseURL)
 	}
-	return fmt.Sprintf("%s/s/%s", r.baseURL, song.ISRC)
+	return fmt.Sprintf("%s/s/%s", baseURL, song.ISRC)
 }
 
-// RenderSongJSON renders a song as JSON response
+// RenderSongJSON renders a song as JSON response, honoring the ?fields= projection
 func (r *SongRenderer) RenderSongJSON(c *gin.Context, song *models.Song) {
 	response := ResolveSongResponse{
 		Song: SongMetadata{
@@ -101,9 +126,13 @@ func (r *SongRenderer) RenderSongJSON(c *gin.Context, song *models.Song) {
 			ReleaseDate: song.Metadata.ReleaseDate.Format("2006-01-02"),
 			ISRC:        song.ISRC,
 			ImageURL:    song.Metadata.ImageURL,
+			LyricsURL:   song.Metadata.LyricsURL,
+			Views:       song.Views,
 		},
 		Platforms:     make(map[string]PlatformLink),
-		UniversalLink: r.buildUniversalLink(song),
+		UniversalLink: buildUniversalLink(r.BaseURL(c), song),
+		Verified:      r.IsVerified(song),
+		Retired:       song.Retired,
 	}
 
 	// Add platform links
@@ -112,10 +141,11 @@ func (r *SongRenderer) RenderSongJSON(c *gin.Context, song *models.Song) {
 			URL:       link.URL,
 			Available: link.Available,
 			Platform:  link.Platform,
+			Primary:   link.Primary,
 		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	RespondJSON(c, http.StatusOK, response)
 }
 
 // PlatformUIConfig represents the configuration struct from handlers package
@@ -136,34 +166,86 @@ func (r *SongRenderer) RenderSongPage(c *gin.Context, song *models.Song, getPlat
 		PlatformURLs map[string]string
 		Platforms    []PlatformDisplayData
 		AlbumArt     string
+		OGImage      string
+		ShareURL     string
+		Description  string
+		Verified     bool
+		Theme        themeData
+
+		// Title and artists as shown on the page, capped for layout
+		DisplayTitle  string
+		DisplayArtist string
 	}{
 		Song:         song,
 		PlatformURLs: make(map[string]string),
 		Platforms:    []PlatformDisplayData{},
 		AlbumArt:     song.Metadata.ImageURL,
+		OGImage:      r.theme.previewImage(song.Metadata.ImageURL),
+		ShareURL:     buildUniversalLink(r.BaseURL(c), song),
+		Verified:     r.IsVerified(song),
+		Theme:        r.theme,
+
+		DisplayTitle:  r.DisplayTitle(song.Title),
+		DisplayArtist: r.displaySongArtist(song),
 	}
 
 	// Extract platform URLs and create platform display data
 	for _, link := range song.PlatformLinks {
 		if link.Available {
 			data.PlatformURLs[link.Platform] = link.URL
+		}
+	}
+	data.Platforms = platformDisplayData(song, getPlatformUIConfig)
 
-			// Get UI configuration for this platform
-			uiConfig := getPlatformUIConfig(link.Platform)
-			data.Platforms = append(data.Platforms, PlatformDisplayData{
-				Platform:    link.Platform,
-				URL:         link.URL,
-				Name:        uiConfig.Name,
-				IconURL:     uiConfig.IconURL,
-				ButtonText:  uiConfig.ButtonText,
-				Description: uiConfig.Description,
-				Color:       uiConfig.Color,
-				CSSClass:    uiConfig.BadgeClass,
-			})
+	// Preview text for link unfurls (og:description)
+	platformNames := make([]string, 0, len(data.Platforms))
+	for _, platform := range data.Platforms {
+		platformNames = append(platformNames, platform.Name)
+	}
+	data.Description = fmt.Sprintf("Listen to %s by %s", song.Title, song.Artist)
+	if len(platformNames) > 0 {
+		data.Description += " on " + strings.Join(platformNames, ", ")
+	}
+	if song.Retired {
+		data.Description = fmt.Sprintf("%s by %s is no longer available on supported platforms", song.Title, song.Artist)
+	}
+
+	tmpl, err := templates.GetTemplate("song_page")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template error"})
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(c.Writer, data); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Render error"})
+	}
+}
+
+// platformDisplayData builds the display data for a song's available links in
+// a deterministic order: Apple Music, Spotify, TIDAL, then others by name
+func platformDisplayData(song *models.Song, getPlatformUIConfig func(string) *PlatformUIConfig) []PlatformDisplayData {
+	platforms := []PlatformDisplayData{}
+	for _, link := range song.PlatformLinks {
+		if !link.Available {
+			continue
 		}
+
+		// Get UI configuration for this platform
+		uiConfig := getPlatformUIConfig(link.Platform)
+		platforms = append(platforms, PlatformDisplayData{
+			Platform:    link.Platform,
+			URL:         link.URL,
+			Name:        uiConfig.Name,
+			IconURL:     uiConfig.IconURL,
+			ButtonText:  uiConfig.ButtonText,
+			Description: uiConfig.Description,
+			Color:       uiConfig.Color,
+			CSSClass:    uiConfig.BadgeClass,
+			Primary:     link.Primary,
+		})
 	}
 
-	// Ensure deterministic platform order for display: Apple Music, Spotify, TIDAL, then others
 	platformPriority := func(platform string) int {
 		switch platform {
 		case "apple_music":
@@ -177,17 +259,29 @@ func (r *SongRenderer) RenderSongPage(c *gin.Context, song *models.Song, getPlat
 		}
 	}
 
-	sort.SliceStable(data.Platforms, func(i, j int) bool {
-		pi := platformPriority(data.Platforms[i].Platform)
-		pj := platformPriority(data.Platforms[j].Platform)
+	sort.SliceStable(platforms, func(i, j int) bool {
+		pi := platformPriority(platforms[i].Platform)
+		pj := platformPriority(platforms[j].Platform)
 		if pi != pj {
 			return pi < pj
 		}
 		// Fallback: sort by name to keep stable across runs
-		return data.Platforms[i].Name < data.Platforms[j].Name
+		return platforms[i].Name < platforms[j].Name
 	})
+	return platforms
+}
 
-	tmpl, err := templates.GetTemplate("song_page")
+// RenderSearchPage renders the search page
+func (r *SongRenderer) RenderSearchPage(c *gin.Context, query string) {
+	data := struct {
+		Query string
+		Theme themeData
+	}{
+		Query: query,
+		Theme: r.theme,
+	}
+
+	tmpl, err := templates.GetTemplate("search_page")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template error"})
 		return
@@ -199,22 +293,38 @@ func (r *SongRenderer) RenderSongPage(c *gin.Context, song *models.Song, getPlat
 	}
 }
 
-// RenderSearchPage renders the search page
-func (r *SongRenderer) RenderSearchPage(c *gin.Context, query string) {
+// RenderNotFoundPage renders the HTML 404 page for song links that don't resolve
+func (r *SongRenderer) RenderNotFoundPage(c *gin.Context) {
+	r.renderNotFound(c, "Song", "song")
+}
+
+// RenderCollectionNotFoundPage renders the HTML 404 page for collection links that don't resolve
+func (r *SongRenderer) RenderCollectionNotFoundPage(c *gin.Context) {
+	r.renderNotFound(c, "Collection", "playlist or album")
+}
+
+// renderNotFound renders the HTML 404 page; subject titles the page and noun
+// names what the link was expected to point at
+func (r *SongRenderer) renderNotFound(c *gin.Context, subject, noun string) {
 	data := struct {
-		Query string
+		Subject string
+		Noun    string
+		Theme   themeData
 	}{
-		Query: query,
+		Subject: subject,
+		Noun:    noun,
+		Theme:   r.theme,
 	}
 
-	tmpl, err := templates.GetTemplate("search_page")
+	tmpl, err := templates.GetTemplate("not_found_page")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template error"})
 		return
 	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusNotFound)
 	if err := tmpl.Execute(c.Writer, data); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Render error"})
+		slog.Error("Failed to render not found page", "error", err)
 	}
 }
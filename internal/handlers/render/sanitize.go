@@ -0,0 +1,109 @@
+package render
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// allowedTags maps the inline tags SanitizeHTML keeps to their permitted attributes
+var allowedTags = map[string]map[string]bool{
+	"a":      {"href": true, "title": true, "class": true},
+	"b":      {"class": true},
+	"br":     {},
+	"em":     {"class": true},
+	"i":      {"class": true},
+	"p":      {"class": true},
+	"small":  {"class": true},
+	"span":   {"class": true},
+	"strong": {"class": true},
+}
+
+// droppedContentTags have their content removed along with the tag
+var droppedContentTags = map[string]bool{"script": true, "style": true, "iframe": true, "object": true}
+
+// SanitizeHTML keeps a small set of inline tags and safe link targets from
+// operator-supplied markup. Everything else is dropped, and text is escaped.
+func SanitizeHTML(input string) string {
+	var out strings.Builder
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(input))
+	skipDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				return ""
+			}
+			return out.String()
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedContentTags[token.Data] {
+				if tokenType == nethtml.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+			if attrs, ok := allowedTags[token.Data]; ok {
+				writeStartTag(&out, token, attrs)
+			}
+		case nethtml.EndTagToken:
+			if droppedContentTags[token.Data] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+			if _, ok := allowedTags[token.Data]; ok && token.Data != "br" {
+				out.WriteString("</" + token.Data + ">")
+			}
+		case nethtml.TextToken:
+			if skipDepth == 0 {
+				out.WriteString(html.EscapeString(token.Data))
+			}
+		}
+	}
+}
+
+// writeStartTag writes a tag with only its permitted, safe attributes
+func writeStartTag(out *strings.Builder, token nethtml.Token, allowed map[string]bool) {
+	out.WriteString("<" + token.Data)
+	for _, attr := range token.Attr {
+		if !allowed[attr.Key] {
+			continue
+		}
+		if attr.Key == "href" && !isSafeURL(attr.Val) {
+			continue
+		}
+		out.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+	if token.Data == "a" {
+		out.WriteString(` rel="noopener"`)
+	}
+	out.WriteString(">")
+}
+
+// isSafeURL accepts http(s), mailto and site-relative URLs
+func isSafeURL(raw string) bool {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch {
+	case value == "":
+		return false
+	case strings.HasPrefix(value, "https://"), strings.HasPrefix(value, "http://"), strings.HasPrefix(value, "mailto:"):
+		return true
+	case strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "//"):
+		return true
+	}
+	return false
+}
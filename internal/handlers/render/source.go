@@ -0,0 +1,13 @@
+package render
+
+// LocalSource marks search results from SongShare's own catalog. It is a result
+// source, not a streaming platform: a local result's URL is the song's universal
+// link, so it is listed under its own key in search results but never counted,
+// ordered or badged as a platform.
+const LocalSource = "local"
+
+// IsLocal reports whether the result came from the local catalog rather than a
+// streaming platform
+func (r SearchResult) IsLocal() bool {
+	return r.Platform == LocalSource
+}
@@ -0,0 +1,77 @@
+package render
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Theme holds operator branding for the song and search pages
+type Theme struct {
+	SiteName     string // Shown in titles, headers and og:site_name
+	PrimaryColor string // CSS color for accents; empty keeps each page's built-in colors
+	LogoURL      string // Optional logo shown above the page content
+	FooterHTML   string // Replaces the default footer; sanitized to basic inline markup
+	OGImageURL   string // Link preview image for pages without album art
+}
+
+// DefaultSiteName is used when no site name is configured
+const DefaultSiteName = "SongShare"
+
+// themeData is the sanitized theme passed to templates
+type themeData struct {
+	SiteName     string
+	PrimaryColor template.CSS // Validated by cssColorPattern, so safe to emit in a style block
+	LogoURL      string
+	FooterHTML   template.HTML
+	OGImageURL   string
+}
+
+// cssColorPattern accepts hex, rgb()/rgba()/hsl()/hsla() and named colors.
+// It admits no quotes, semicolons or braces, so a match can't leave the
+// declaration it is rendered into.
+var cssColorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|(rgb|rgba|hsl|hsla)\([0-9.,%\s]+\)|[a-zA-Z]+)$`)
+
+// newThemeData validates and sanitizes a theme, filling in defaults
+func newThemeData(theme Theme) themeData {
+	data := themeData{
+		SiteName:   strings.TrimSpace(theme.SiteName),
+		LogoURL:    strings.TrimSpace(theme.LogoURL),
+		OGImageURL: strings.TrimSpace(theme.OGImageURL),
+	}
+	if data.SiteName == "" {
+		data.SiteName = DefaultSiteName
+	}
+	if color := strings.TrimSpace(theme.PrimaryColor); cssColorPattern.MatchString(color) {
+		data.PrimaryColor = template.CSS(color)
+	}
+	if !isSafeURL(data.LogoURL) {
+		data.LogoURL = ""
+	}
+	if !isSafeURL(data.OGImageURL) {
+		data.OGImageURL = ""
+	}
+	if footer := strings.TrimSpace(theme.FooterHTML); footer != "" {
+		data.FooterHTML = template.HTML(SanitizeHTML(footer))
+	}
+	return data
+}
+
+// SetTheme applies operator branding to rendered pages
+func (r *SongRenderer) SetTheme(theme Theme) {
+	r.theme = newThemeData(theme)
+}
+
+// previewImage returns the link preview image for a page with the given art,
+// falling back to the configured default
+func (t themeData) previewImage(art string) string {
+	if art != "" {
+		return art
+	}
+	return t.OGImageURL
+}
+
+// SiteName returns the configured site name, or DefaultSiteName
+func (r *SongRenderer) SiteName() string {
+	return r.theme.SiteName
+}
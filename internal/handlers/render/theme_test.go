@@ -0,0 +1,163 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func renderThemedSongPage(t *testing.T, renderer *SongRenderer) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.ISRC = "GBUM71505078"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/s/GBUM71505078", nil)
+	renderer.RenderSongPage(c, song, func(string) *PlatformUIConfig { return &PlatformUIConfig{} })
+
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func renderThemedSearchPage(t *testing.T, renderer *SongRenderer) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/search", nil)
+	renderer.RenderSearchPage(c, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestRenderPages_ConfiguredBranding(t *testing.T) {
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetTheme(Theme{
+		SiteName:     "BrandA Music",
+		PrimaryColor: "#ff5500",
+		LogoURL:      "https://cdn.branda.example/logo.svg",
+		FooterHTML:   `<p>© BrandA <a href="https://branda.example/privacy" onclick="steal()">Privacy</a></p><script>alert(1)</script>`,
+	})
+
+	songPage := renderThemedSongPage(t, renderer)
+	assert.Contains(t, songPage, `<meta property="og:site_name" content="BrandA Music">`)
+	assert.Contains(t, songPage, `--primary-color: #ff5500;`)
+	assert.Contains(t, songPage, `<img src="https://cdn.branda.example/logo.svg" alt="BrandA Music" class="site-logo">`)
+	assert.Contains(t, songPage, `<p>© BrandA <a href="https://branda.example/privacy" rel="noopener">Privacy</a></p>`)
+	assert.NotContains(t, songPage, "Powered by SongShare")
+	assert.NotContains(t, songPage, "alert(1)")
+	assert.NotContains(t, songPage, "steal()")
+
+	searchPage := renderThemedSearchPage(t, renderer)
+	assert.Contains(t, searchPage, `<title>Search Songs - BrandA Music</title>`)
+	assert.Contains(t, searchPage, `BrandA Music Search</h1>`)
+	assert.Contains(t, searchPage, `--primary-color: #ff5500;`)
+	assert.Contains(t, searchPage, `class="site-logo"`)
+	assert.Contains(t, searchPage, `© BrandA`)
+}
+
+func TestRenderPages_FunctionalColor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetTheme(Theme{PrimaryColor: "rgb(255, 85, 0)"})
+
+	newContext := func(path string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+		return c, w
+	}
+	c, notFound := newContext("/s/missing")
+	renderer.RenderNotFoundPage(c)
+	c, collection := newContext("/c/abc")
+	renderer.RenderCollectionPage(c, &models.Collection{ID: primitive.NewObjectID(), Name: "Mix", Kind: "playlist"}, nil,
+		func(string) *PlatformUIConfig { return &PlatformUIConfig{} })
+
+	for name, page := range map[string]string{
+		"song":       renderThemedSongPage(t, renderer),
+		"search":     renderThemedSearchPage(t, renderer),
+		"not found":  notFound.Body.String(),
+		"collection": collection.Body.String(),
+	} {
+		assert.Contains(t, page, `--primary-color: rgb(255, 85, 0);`, name)
+		assert.NotContains(t, page, "ZgotmplZ", name)
+	}
+
+	renderer.SetTheme(Theme{PrimaryColor: "hsla(20, 100%, 50%, 0.8)"})
+	assert.Contains(t, renderThemedSongPage(t, renderer), `--primary-color: hsla(20, 100%, 50%, 0.8);`)
+}
+
+func TestRenderPages_DefaultBranding(t *testing.T) {
+	renderer := NewSongRenderer("https://songshare.example")
+
+	songPage := renderThemedSongPage(t, renderer)
+	assert.Contains(t, songPage, `<meta property="og:site_name" content="SongShare">`)
+	assert.Contains(t, songPage, "Powered by SongShare")
+	assert.NotContains(t, songPage, "--primary-color:")
+	assert.NotContains(t, songPage, `class="site-logo"`)
+
+	searchPage := renderThemedSearchPage(t, renderer)
+	assert.Contains(t, searchPage, "SongShare Search</h1>")
+}
+
+func TestSetTheme_RejectsUnsafeValues(t *testing.T) {
+	renderer := NewSongRenderer("https://songshare.example")
+	renderer.SetTheme(Theme{
+		SiteName:     `<b>Brand</b>`,
+		PrimaryColor: "red; background: url(https://evil.example)",
+		LogoURL:      "javascript:alert(1)",
+	})
+
+	songPage := renderThemedSongPage(t, renderer)
+	assert.Contains(t, songPage, "&lt;b&gt;Brand&lt;/b&gt;")
+	assert.NotContains(t, songPage, "evil.example")
+	assert.NotContains(t, songPage, "javascript:")
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Allowed inline markup", input: `<strong class="x">Hi</strong> <em>there</em><br>`, expected: `<strong class="x">Hi</strong> <em>there</em><br>`},
+		{name: "Disallowed tags keep text", input: `<div><h1>Title</h1></div>`, expected: `Title`},
+		{name: "Script content removed", input: `a<script>alert(1)</script>b`, expected: `ab`},
+		{name: "Event handlers stripped", input: `<span onmouseover="x()">t</span>`, expected: `<span>t</span>`},
+		{name: "Unsafe link target dropped", input: `<a href="javascript:x()">l</a>`, expected: `<a rel="noopener">l</a>`},
+		{name: "Relative link kept", input: `<a href="/about">l</a>`, expected: `<a href="/about" rel="noopener">l</a>`},
+		{name: "Text escaped", input: `1 < 2 & "q"`, expected: `1 &lt; 2 &amp; &#34;q&#34;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeHTML(tt.input))
+		})
+	}
+}
+
+func TestRenderSongPage_PreviewImage(t *testing.T) {
+	renderer := NewSongRenderer("https://songshare.example")
+
+	songPage := renderThemedSongPage(t, renderer)
+	assert.NotContains(t, songPage, `property="og:image"`)
+	assert.Contains(t, songPage, `<meta name="twitter:card" content="summary">`)
+
+	renderer.SetTheme(Theme{OGImageURL: "https://cdn.example.com/og-default.png"})
+	songPage = renderThemedSongPage(t, renderer)
+	assert.Contains(t, songPage, `<meta property="og:image" content="https://cdn.example.com/og-default.png">`)
+	assert.Contains(t, songPage, `<meta name="twitter:card" content="summary_large_image">`)
+
+	renderer.SetTheme(Theme{OGImageURL: "javascript:alert(1)"})
+	assert.NotContains(t, renderThemedSongPage(t, renderer), "javascript:")
+}
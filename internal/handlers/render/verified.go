@@ -0,0 +1,21 @@
+package render
+
+import (
+	"time"
+
+	"songshare/internal/models"
+)
+
+// DefaultVerifiedMaxAge matches the VERIFIED_MAX_AGE config default
+const DefaultVerifiedMaxAge = 30 * 24 * time.Hour
+
+// SetVerifiedMaxAge sets how recently every link must have been verified for a
+// song to carry the verified badge
+func (r *SongRenderer) SetVerifiedMaxAge(maxAge time.Duration) {
+	r.verifiedMaxAge = maxAge
+}
+
+// IsVerified reports whether song currently qualifies for the verified badge
+func (r *SongRenderer) IsVerified(song *models.Song) bool {
+	return song.IsVerified(r.verifiedMaxAge, time.Now())
+}
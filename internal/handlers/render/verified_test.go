@@ -0,0 +1,63 @@
+package render
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func verifiedTestSong(confidence float64, lastVerified time.Time) *models.Song {
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.ISRC = "GBUM71505078"
+	song.PlatformLinks = []models.PlatformLink{{
+		Platform:     "spotify",
+		URL:          "https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv",
+		Available:    true,
+		Confidence:   confidence,
+		LastVerified: lastVerified,
+	}}
+	return song
+}
+
+func TestRenderSongPage_VerifiedBadge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	renderer := NewSongRenderer("https://songshare.example")
+	uiConfig := func(string) *PlatformUIConfig { return &PlatformUIConfig{Name: "Spotify"} }
+
+	render := func(song *models.Song) string {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/s/GBUM71505078", nil)
+		renderer.RenderSongPage(c, song, uiConfig)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	assert.Contains(t, render(verifiedTestSong(1.0, time.Now().Add(-time.Hour))), `class="verified-badge"`)
+	assert.NotContains(t, render(verifiedTestSong(0.9, time.Now().Add(-time.Hour))), `class="verified-badge"`)
+
+	renderer.SetVerifiedMaxAge(time.Minute)
+	assert.NotContains(t, render(verifiedTestSong(1.0, time.Now().Add(-time.Hour))), `class="verified-badge"`)
+}
+
+func TestRenderSongJSON_Verified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	renderer := NewSongRenderer("https://songshare.example")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/s/GBUM71505078", nil)
+	renderer.RenderSongJSON(c, verifiedTestSong(1.0, time.Now()))
+
+	var response ResolveSongResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Verified)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"songshare/internal/handlers/render"
+	"songshare/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batch resolve limits
+const (
+	resolveBatchMaxURLs           = 50               // URLs accepted per request
+	resolveBatchWorkers           = 8                // URLs resolved concurrently
+	defaultResolveBatchURLTimeout = 15 * time.Second // time allowed to resolve each URL
+)
+
+// Per-URL batch resolve outcomes
+const (
+	resolveBatchOK    = "ok"
+	resolveBatchError = "error"
+)
+
+// ResolveBatchRequest represents the request to resolve several platform URLs at once
+type ResolveBatchRequest struct {
+	URLs []string `json:"urls" binding:"required,min=1"`
+}
+
+// ResolveBatchResult is the outcome of resolving one URL in a batch
+type ResolveBatchResult struct {
+	URL     string                      `json:"url"`
+	Status  string                      `json:"status"`            // "ok" or "error"
+	Result  *render.ResolveSongResponse `json:"result,omitempty"`  // Set when Status is "ok"
+	Error   string                      `json:"error,omitempty"`   // Set when Status is "error"
+	Details string                      `json:"details,omitempty"` // Optional detail for Error
+}
+
+// ResolveBatchResponse lists one result per requested URL, in request order
+type ResolveBatchResponse struct {
+	Results []ResolveBatchResult `json:"results"`
+}
+
+// ResolveSongBatch handles POST /api/v1/songs/resolve-batch
+// Each URL is resolved as by ResolveSong, including ?persist=false; a URL that
+// fails is reported in its own result without failing the batch.
+func (h *SongHandler) ResolveSongBatch(c *gin.Context) {
+	var req ResolveBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(req.URLs) > resolveBatchMaxURLs {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Too many URLs",
+			"details": fmt.Sprintf("at most %d URLs can be resolved per request", resolveBatchMaxURLs),
+		})
+		return
+	}
+
+	persist := c.DefaultQuery("persist", "true") != "false"
+	baseURL := h.renderer.BaseURL(c)
+
+	// Resolve each distinct URL once; repeats share the first one's result
+	unique := make([]string, 0, len(req.URLs))
+	seen := make(map[string]int, len(req.URLs))
+	for _, rawURL := range req.URLs {
+		rawURL = strings.TrimSpace(rawURL)
+		if _, ok := seen[rawURL]; !ok {
+			seen[rawURL] = len(unique)
+			unique = append(unique, rawURL)
+		}
+	}
+
+	resolved := make([]ResolveBatchResult, len(unique))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(resolveBatchWorkers, len(unique)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				resolved[j] = h.resolveBatchURL(c.Request.Context(), baseURL, unique[j], persist)
+			}
+		}()
+	}
+	for j := range unique {
+		jobs <- j
+	}
+	close(jobs)
+	wg.Wait()
+
+	response := ResolveBatchResponse{Results: make([]ResolveBatchResult, len(req.URLs))}
+	for i, rawURL := range req.URLs {
+		result := resolved[seen[strings.TrimSpace(rawURL)]]
+		result.URL = rawURL
+		response.Results[i] = result
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// resolveBatchURL resolves one batch URL within the handler's batch URL timeout
+func (h *SongHandler) resolveBatchURL(ctx context.Context, baseURL, rawURL string, persist bool) ResolveBatchResult {
+	ctx, cancel := context.WithTimeout(ctx, h.batchURLTimeout)
+	defer cancel()
+
+	platformService, trackID, urlErr := h.parseResolveURL(ctx, rawURL)
+	if urlErr != nil {
+		if song := h.storedSongForUnavailablePlatform(ctx, urlErr); song != nil {
+			response := h.buildResolvedSongResponse(baseURL, song, resolveStored)
+			return ResolveBatchResult{Status: resolveBatchOK, Result: &response}
+		}
+		return ResolveBatchResult{Status: resolveBatchError, Error: urlErr.message, Details: urlErr.details}
+	}
+
+	song, status, err := h.resolveSongFromPlatform(ctx, platformService, trackID, persist)
+	if err != nil {
+		slog.Error("Failed to resolve song", "url", rawURL, "error", err)
+		return ResolveBatchResult{
+			Status:  resolveBatchError,
+			Error:   "Failed to resolve song from URL",
+			Details: h.batchErrorDetails(err),
+		}
+	}
+	if song == nil {
+		return ResolveBatchResult{Status: resolveBatchError, Error: "Song not found"}
+	}
+
+	response := h.buildResolvedSongResponse(baseURL, song, status)
+	return ResolveBatchResult{Status: resolveBatchOK, Result: &response}
+}
+
+// batchErrorDetails describes a resolve failure, calling out platform timeouts
+func (h *SongHandler) batchErrorDetails(err error) string {
+	if services.ClassifyError(err) == services.ErrorCategoryTimeout {
+		return fmt.Sprintf("timed out after %s: %v", h.batchURLTimeout, err)
+	}
+	return err.Error()
+}
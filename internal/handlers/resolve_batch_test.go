@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performResolveBatch(t *testing.T, handler *SongHandler, urls []string) (*httptest.ResponseRecorder, ResolveBatchResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/songs/resolve-batch", handler.ResolveSongBatch)
+
+	body, err := json.Marshal(ResolveBatchRequest{URLs: urls})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/songs/resolve-batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response ResolveBatchResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w, response
+}
+
+func TestResolveSongBatch_PreservesOrderAndDeduplicates(t *testing.T) {
+	stored := testutil.NewSongBuilder().WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).Build()
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stored, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil).Maybe()
+	spotify := testutil.NewMockPlatformService("spotify")
+	spotify.On("GetTrackByID", mock.Anything, mock.Anything).Return(nil, assert.AnError).Maybe()
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	urls := []string{
+		testutil.SpotifyURL1,
+		"https://example.com/not-a-track",
+		"https://open.spotify.com/album/6i6folBtxKV28WX3msQ4FE",
+		testutil.SpotifyURL1,
+	}
+	w, response := performResolveBatch(t, handler, urls)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, response.Results, len(urls))
+	for i, result := range response.Results {
+		assert.Equal(t, urls[i], result.URL)
+	}
+
+	assert.Equal(t, resolveBatchOK, response.Results[0].Status)
+	require.NotNil(t, response.Results[0].Result)
+	assert.Equal(t, stored.ID.Hex(), response.Results[0].Result.Song.ID)
+
+	assert.Equal(t, resolveBatchError, response.Results[1].Status)
+	assert.Equal(t, "Invalid platform URL", response.Results[1].Error)
+	assert.Nil(t, response.Results[1].Result)
+
+	assert.Equal(t, resolveBatchError, response.Results[2].Status)
+	assert.Equal(t, "Album/playlist resolution not yet supported", response.Results[2].Error)
+
+	assert.Equal(t, response.Results[0].Status, response.Results[3].Status)
+	assert.Equal(t, response.Results[0].Result, response.Results[3].Result)
+	repo.AssertNumberOfCalls(t, "FindByPlatformID", 1)
+}
+
+func TestResolveSongBatch_TimesOutSlowURLs(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	spotify := testutil.NewMockPlatformService("spotify")
+	spotify.On("GetTrackByID", mock.Anything, testutil.SpotifyTrackID1).
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return(nil, context.DeadlineExceeded)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.batchURLTimeout = 50 * time.Millisecond
+	w, response := performResolveBatch(t, handler, []string{testutil.SpotifyURL1})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, resolveBatchError, response.Results[0].Status)
+	assert.Contains(t, response.Results[0].Details, "timed out")
+}
+
+func TestResolveSongBatch_ExpandsShortLinks(t *testing.T) {
+	handler := NewSongHandler(&testutil.MockSongRepository{}, "http://localhost", nil, nil, nil)
+
+	// An already expired timeout fails the expansion without any network access
+	handler.batchURLTimeout = time.Nanosecond
+	w, response := performResolveBatch(t, handler, []string{"https://spotify.link/abc123"})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, resolveBatchError, response.Results[0].Status)
+	assert.Equal(t, "Failed to expand short link", response.Results[0].Error)
+}
+
+func TestResolveSongBatch_RejectsInvalidRequests(t *testing.T) {
+	handler := NewSongHandler(&testutil.MockSongRepository{}, "http://localhost", nil, nil, nil)
+
+	w, _ := performResolveBatch(t, handler, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	tooMany := make([]string, resolveBatchMaxURLs+1)
+	for i := range tooMany {
+		tooMany[i] = testutil.SpotifyURL1
+	}
+	w, _ = performResolveBatch(t, handler, tooMany)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Too many URLs")
+}
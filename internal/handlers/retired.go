@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"songshare/internal/models"
+)
+
+// markLinkUnavailable records that a re-fetch found the song's link for
+// platform delisted and, under the retire policy, retires the song once none of
+// its links are available. The song keeps its metadata for the share page.
+func (h *SongHandler) markLinkUnavailable(ctx context.Context, song *models.Song, platform string) *models.Song {
+	if !song.MarkPlatformUnavailable(platform) {
+		return song
+	}
+	song.RecomputePrimary(primaryPreferences())
+
+	if h.retireUnavailable && !song.Retired && len(song.GetAvailablePlatforms()) == 0 {
+		song.Retired = true
+		slog.Info("Song retired, no platform links available", "song_id", song.ID.Hex(), "isrc", song.ISRC)
+	}
+
+	if err := h.songRepository.Update(ctx, song); err != nil {
+		slog.Error("Failed to update song with unavailable link", "song_id", song.ID.Hex(), "platform", platform, "error", err)
+	}
+	return song
+}
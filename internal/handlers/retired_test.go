@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// delistedError is what a platform returns for a track it no longer has
+var delistedError = &services.PlatformError{
+	Platform:  "spotify",
+	Operation: "get_track",
+	Message:   "track not found",
+	Category:  services.ErrorCategoryNoResults,
+}
+
+func TestResolveSong_DelistedEverywhereRetiresSong(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	stale := staleSpotifySong()
+	stale.Metadata.ImageURL = "https://example.com/art.jpg"
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stale, nil)
+	repo.On("Update", mock.Anything, stale).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, nil, delistedError)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, response.Retired)
+	assert.False(t, response.Platforms["spotify"].Available)
+	assert.Equal(t, "https://example.com/art.jpg", response.Song.ImageURL, "last-known metadata is kept")
+	assert.True(t, stale.Retired)
+	repo.AssertCalled(t, "Update", mock.Anything, stale)
+
+	// The share page says the song is gone instead of offering dead links
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(stale, nil)
+	page := performSongPageRequest(t, handler, "Mozilla/5.0", "text/html")
+	require.Equal(t, http.StatusOK, page.Code)
+	assert.Contains(t, page.Body.String(), "no longer available on any supported platform")
+	assert.Contains(t, page.Body.String(), `<meta name="robots" content="noindex">`)
+	assert.NotContains(t, page.Body.String(), testutil.SpotifyURL1)
+}
+
+func TestResolveSong_DelistedWithRetirePolicyOff(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	stale := staleSpotifySong()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stale, nil)
+	repo.On("Update", mock.Anything, stale).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, nil, delistedError)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.ApplyConfig(&config.Config{RetireUnavailableSongs: false})
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, response.Retired)
+	assert.False(t, stale.Retired)
+	assert.False(t, stale.PlatformLinks[0].Available)
+}
+
+func TestResolveSong_RefreshFailureKeepsLinkAvailable(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	stale := staleSpotifySong()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stale, nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, nil, &services.PlatformError{
+		Platform: "spotify", Operation: "get_track", Category: services.ErrorCategoryTimeout,
+	})
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, response.Retired)
+	assert.True(t, stale.PlatformLinks[0].Available)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestSearch_ExcludesRetiredSongsWhenConfigured(t *testing.T) {
+	retired := testutil.NewSongBuilder().
+		WithTitle("Delisted").
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	retired.Retired = true
+	repo := &testutil.MockSongRepository{}
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{retired}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "delisted", Limit: 10})
+	require.Len(t, response.Results["local"], 1)
+	assert.False(t, response.Results["local"][0].Available)
+
+	handler.ApplyConfig(&config.Config{RetireUnavailableSongs: true, SearchExcludeRetired: true})
+	response = handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "delisted", Limit: 10})
+	assert.Empty(t, response.Results["local"])
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"songshare/internal/cache"
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+)
+
+// Save retry defaults, matching the config defaults
+const (
+	defaultSaveRetryQueueSize   = 1000
+	defaultSaveRetryMaxAttempts = 10
+	saveRetryBaseDelay          = time.Second
+	saveRetryMaxDelay           = 5 * time.Minute
+	saveRetryPollInterval       = time.Second
+
+	// saveRetryStoreKey holds the pending saves so they outlive a restart
+	saveRetryStoreKey = "save_retry:pending"
+)
+
+// pendingSave is a resolved song waiting to be persisted
+type pendingSave struct {
+	song        *models.Song
+	attempts    int
+	nextAttempt time.Time
+}
+
+// storedSave is the persisted form of a pendingSave
+type storedSave struct {
+	Song        *models.Song `json:"song"`
+	Attempts    int          `json:"attempts"`
+	NextAttempt time.Time    `json:"next_attempt"`
+}
+
+// saveRetryQueue holds songs whose save failed during resolution and retries
+// them with exponential backoff. Pending saves are worked from memory, so the
+// queue keeps working while the database is the thing that's down, and are
+// mirrored to store when one is set so they survive a restart.
+type saveRetryQueue struct {
+	mu          sync.Mutex
+	pending     map[string]*pendingSave
+	maxSize     int
+	maxAttempts int
+	save        func(ctx context.Context, song *models.Song) error
+	onSaved     func(ctx context.Context, song *models.Song)
+
+	// store persists the pending saves; nil keeps them in memory only
+	store   cache.Cache
+	storeMu sync.Mutex
+}
+
+func newSaveRetryQueue(maxSize, maxAttempts int, save func(context.Context, *models.Song) error) *saveRetryQueue {
+	return &saveRetryQueue{
+		pending:     make(map[string]*pendingSave),
+		maxSize:     maxSize,
+		maxAttempts: maxAttempts,
+		save:        save,
+	}
+}
+
+// saveRetryKey identifies a song before it has a database ID
+func saveRetryKey(song *models.Song) string {
+	if song.ISRC != "" {
+		return "isrc:" + song.ISRC
+	}
+	if len(song.PlatformLinks) > 0 {
+		return song.PlatformLinks[0].Platform + ":" + song.PlatformLinks[0].ExternalID
+	}
+	return ""
+}
+
+// enqueue schedules a copy of song for retry. It reports false when the song
+// can't be identified or the queue is full.
+func (q *saveRetryQueue) enqueue(ctx context.Context, song *models.Song, now time.Time) bool {
+	key := saveRetryKey(song)
+	if key == "" {
+		return false
+	}
+
+	q.mu.Lock()
+	if _, exists := q.pending[key]; exists {
+		q.mu.Unlock()
+		return true
+	}
+	if len(q.pending) >= q.maxSize {
+		q.mu.Unlock()
+		return false
+	}
+
+	// Copy so the retry worker never races with the request rendering the song
+	songCopy := *song
+	songCopy.PlatformLinks = append([]models.PlatformLink(nil), song.PlatformLinks...)
+	q.pending[key] = &pendingSave{song: &songCopy, nextAttempt: now.Add(saveRetryBaseDelay)}
+	q.mu.Unlock()
+
+	q.persist(ctx)
+	return true
+}
+
+// persist writes the pending saves to the store. A failed write is logged and
+// the saves stay queued in memory.
+func (q *saveRetryQueue) persist(ctx context.Context) {
+	if q.store == nil {
+		return
+	}
+
+	// Serialized so an older snapshot never overwrites a newer one
+	q.storeMu.Lock()
+	defer q.storeMu.Unlock()
+
+	q.mu.Lock()
+	stored := make([]storedSave, 0, len(q.pending))
+	for _, item := range q.pending {
+		stored = append(stored, storedSave{Song: item.song, Attempts: item.attempts, NextAttempt: item.nextAttempt})
+	}
+	q.mu.Unlock()
+
+	if len(stored) == 0 {
+		if err := q.store.Delete(ctx, saveRetryStoreKey); err != nil {
+			slog.Warn("Failed to clear persisted save retries", "error", err)
+		}
+		return
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		slog.Error("Failed to encode save retries", "error", err)
+		return
+	}
+	if err := q.store.Set(ctx, saveRetryStoreKey, data, 0); err != nil {
+		slog.Warn("Failed to persist save retries", "pending", len(stored), "error", err)
+	}
+}
+
+// load restores the saves persisted by a previous process. They are due
+// immediately so the worker drains them on its first pass.
+func (q *saveRetryQueue) load(ctx context.Context, now time.Time) int {
+	if q.store == nil {
+		return 0
+	}
+
+	data, err := q.store.Get(ctx, saveRetryStoreKey)
+	if err != nil {
+		slog.Warn("Failed to load persisted save retries", "error", err)
+		return 0
+	}
+	if data == nil {
+		return 0
+	}
+
+	var stored []storedSave
+	if err := json.Unmarshal(data, &stored); err != nil {
+		slog.Error("Failed to decode persisted save retries", "error", err)
+		return 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	loaded := 0
+	for _, item := range stored {
+		if item.Song == nil || len(q.pending) >= q.maxSize {
+			continue
+		}
+		key := saveRetryKey(item.Song)
+		if _, exists := q.pending[key]; key == "" || exists {
+			continue
+		}
+		q.pending[key] = &pendingSave{song: item.Song, attempts: item.Attempts, nextAttempt: now}
+		loaded++
+	}
+	return loaded
+}
+
+// size returns the number of songs waiting to be saved
+func (q *saveRetryQueue) size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// processDue attempts every save whose backoff has elapsed and returns how many succeeded
+func (q *saveRetryQueue) processDue(ctx context.Context, now time.Time) int {
+	q.mu.Lock()
+	due := make(map[string]*pendingSave)
+	for key, item := range q.pending {
+		if !now.Before(item.nextAttempt) {
+			due[key] = item
+		}
+	}
+	q.mu.Unlock()
+
+	saved := 0
+	for key, item := range due {
+		err := q.save(ctx, item.song)
+
+		// Another request may have saved the same song in the meantime
+		if errors.Is(err, repositories.ErrDuplicateSong) {
+			slog.Info("Pending song already saved elsewhere", "key", key)
+			q.remove(key)
+			continue
+		}
+
+		if err == nil {
+			slog.Info("Saved song after retry", "key", key, "attempts", item.attempts+1)
+			q.remove(key)
+			if q.onSaved != nil {
+				q.onSaved(ctx, item.song)
+			}
+			saved++
+			continue
+		}
+
+		q.mu.Lock()
+		item.attempts++
+		if item.attempts >= q.maxAttempts {
+			delete(q.pending, key)
+			slog.Error("Giving up on saving song", "key", key, "attempts", item.attempts, "error", err)
+		} else {
+			item.nextAttempt = now.Add(saveRetryBackoff(item.attempts))
+			slog.Warn("Song save retry failed", "key", key, "attempts", item.attempts, "next_attempt", item.nextAttempt, "error", err)
+		}
+		q.mu.Unlock()
+	}
+
+	if len(due) > 0 {
+		q.persist(ctx)
+	}
+	return saved
+}
+
+func (q *saveRetryQueue) remove(key string) {
+	q.mu.Lock()
+	delete(q.pending, key)
+	q.mu.Unlock()
+}
+
+// saveRetryBackoff doubles the delay per failed attempt, capped at saveRetryMaxDelay
+func saveRetryBackoff(attempts int) time.Duration {
+	delay := saveRetryBaseDelay
+	for i := 0; i < attempts && delay < saveRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > saveRetryMaxDelay {
+		delay = saveRetryMaxDelay
+	}
+	return delay
+}
+
+// StartSaveRetryWorker enables queueing songs whose save fails during resolution
+// and retries them in the background until ctx is cancelled. Pending saves are
+// persisted to store, typically Valkey, and the ones left by a previous process
+// are drained at startup; a nil store keeps them in memory only.
+func (h *SongHandler) StartSaveRetryWorker(ctx context.Context, store cache.Cache) {
+	queue := newSaveRetryQueue(h.saveRetryQueueSize, h.saveRetryMaxAttempts, h.songRepository.Save)
+	queue.onSaved = func(ctx context.Context, song *models.Song) {
+		h.queueEnrichment(ctx, song.ID.Hex(), song.ISRC)
+	}
+	queue.store = store
+
+	// Restored before any request can enqueue and overwrite the stored saves
+	loaded := queue.load(ctx, time.Now())
+	h.saveRetries = queue
+
+	ticker := time.NewTicker(saveRetryPollInterval)
+	go func() {
+		defer ticker.Stop()
+
+		// Drain what a previous process left before waiting for new failures
+		if loaded > 0 {
+			slog.Info("Restored pending song saves", "pending", loaded)
+			queue.processDue(ctx, time.Now())
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				if pending := queue.size(); pending > 0 && store == nil {
+					slog.Warn("Save retry worker stopped, dropping unsaved songs", "pending", pending)
+				} else if pending > 0 {
+					slog.Info("Save retry worker stopped, unsaved songs persisted", "pending", pending)
+				} else {
+					slog.Info("Save retry worker stopped")
+				}
+				return
+			case now := <-ticker.C:
+				queue.processDue(ctx, now)
+			}
+		}
+	}()
+}
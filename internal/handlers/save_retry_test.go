@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSong_TransientSaveFailureIsRetried(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).
+		Return(errors.New("server selection timeout")).Once()
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler.StartSaveRetryWorker(ctx, nil)
+
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, response.Saving)
+	assert.False(t, response.Ephemeral)
+	assert.Empty(t, response.Song.ID)
+	assert.Equal(t, "Test Song", response.Song.Title)
+	assert.Equal(t, 1, handler.saveRetries.size())
+
+	// Not due yet: nothing is attempted before the backoff elapses
+	assert.Equal(t, 0, handler.saveRetries.processDue(ctx, time.Now()))
+
+	// Database recovered
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).Return(nil).Once()
+	assert.Equal(t, 1, handler.saveRetries.processDue(ctx, time.Now().Add(saveRetryBaseDelay)))
+	assert.Equal(t, 0, handler.saveRetries.size())
+	repo.AssertNumberOfCalls(t, "Save", 2)
+}
+
+func TestResolveSong_SaveFailureWithoutRetryWorkerErrors(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).Return(errors.New("server selection timeout"))
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, _ := performResolve(t, handler, "")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSaveRetryQueue_BackoffAndGiveUp(t *testing.T) {
+	attempts := 0
+	queue := newSaveRetryQueue(10, 3, func(ctx context.Context, song *models.Song) error {
+		attempts++
+		return errors.New("still down")
+	})
+
+	song := testutil.NewSongBuilder().WithISRC(testutil.TestISRC1).Build()
+	now := time.Now()
+	require.True(t, queue.enqueue(context.Background(), song, now))
+	require.True(t, queue.enqueue(context.Background(), song, now), "duplicates are accepted without re-queueing")
+	assert.Equal(t, 1, queue.size())
+
+	now = now.Add(saveRetryBaseDelay)
+	queue.processDue(context.Background(), now)
+	assert.Equal(t, 1, attempts)
+
+	// Second attempt waits for the doubled delay
+	queue.processDue(context.Background(), now.Add(saveRetryBackoff(1)-time.Millisecond))
+	assert.Equal(t, 1, attempts)
+	now = now.Add(saveRetryBackoff(1))
+	queue.processDue(context.Background(), now)
+	assert.Equal(t, 2, attempts)
+
+	now = now.Add(saveRetryBackoff(2))
+	queue.processDue(context.Background(), now)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 0, queue.size(), "dropped after max attempts")
+}
+
+func TestSaveRetryQueue_DuplicateCountsAsSaved(t *testing.T) {
+	queue := newSaveRetryQueue(10, 3, func(ctx context.Context, song *models.Song) error {
+		return &repositories.DuplicateSongError{ISRC: song.ISRC}
+	})
+	song := testutil.NewSongBuilder().WithISRC(testutil.TestISRC1).Build()
+
+	require.True(t, queue.enqueue(context.Background(), song, time.Now()))
+	queue.processDue(context.Background(), time.Now().Add(saveRetryBaseDelay))
+	assert.Equal(t, 0, queue.size())
+}
+
+func TestSaveRetryQueue_Full(t *testing.T) {
+	queue := newSaveRetryQueue(1, 3, func(ctx context.Context, song *models.Song) error { return nil })
+
+	assert.True(t, queue.enqueue(context.Background(), testutil.NewSongBuilder().WithISRC("USUM71703861").Build(), time.Now()))
+	assert.False(t, queue.enqueue(context.Background(), testutil.NewSongBuilder().WithISRC("USUM71703862").Build(), time.Now()))
+}
+
+func TestSaveRetryBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, saveRetryBackoff(1))
+	assert.Equal(t, 4*time.Second, saveRetryBackoff(2))
+	assert.Equal(t, saveRetryMaxDelay, saveRetryBackoff(20))
+}
+
+// memoryStore is an in-memory cache.Cache standing in for Valkey
+type memoryStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (m *memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data[key], nil
+}
+
+func (m *memoryStore) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		m.data = map[string][]byte{}
+	}
+	m.data[key] = value
+	return nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+func (m *memoryStore) Exists(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[key]
+	return ok, nil
+}
+
+func (m *memoryStore) Close() error                     { return nil }
+func (m *memoryStore) Health(ctx context.Context) error { return nil }
+
+func TestSaveRetryQueue_SurvivesRestart(t *testing.T) {
+	store := &memoryStore{}
+	failing := newSaveRetryQueue(10, 5, func(ctx context.Context, song *models.Song) error {
+		return errors.New("still down")
+	})
+	failing.store = store
+
+	now := time.Now()
+	song := testutil.NewSongBuilder().WithISRC(testutil.TestISRC1).WithTitle("Persisted Song").Build()
+	require.True(t, failing.enqueue(context.Background(), song, now))
+	failing.processDue(context.Background(), now.Add(saveRetryBaseDelay))
+
+	// A new process picks up the pending save, attempts included, due immediately
+	var saved []*models.Song
+	restarted := newSaveRetryQueue(10, 5, func(ctx context.Context, song *models.Song) error {
+		saved = append(saved, song)
+		return nil
+	})
+	restarted.store = store
+
+	later := now.Add(time.Minute)
+	require.Equal(t, 1, restarted.load(context.Background(), later))
+	assert.Equal(t, 1, restarted.pending["isrc:"+testutil.TestISRC1].attempts)
+	assert.Equal(t, 1, restarted.processDue(context.Background(), later))
+	require.Len(t, saved, 1)
+	assert.Equal(t, "Persisted Song", saved[0].Title)
+
+	data, _ := store.Get(context.Background(), saveRetryStoreKey)
+	assert.Nil(t, data, "drained saves are cleared from the store")
+}
+
+func TestStartSaveRetryWorker_DrainsPersistedSaves(t *testing.T) {
+	store := &memoryStore{}
+	previous := newSaveRetryQueue(10, 5, nil)
+	previous.store = store
+	require.True(t, previous.enqueue(context.Background(), testutil.NewSongBuilder().WithISRC(testutil.TestISRC1).Build(), time.Now()))
+
+	repo := &testutil.MockSongRepository{}
+	savedCh := make(chan struct{})
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).Return(nil).Run(func(mock.Arguments) { close(savedCh) }).Once()
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler.StartSaveRetryWorker(ctx, store)
+
+	select {
+	case <-savedCh:
+	case <-time.After(time.Second):
+		t.Fatal("persisted save was not drained at startup")
+	}
+}
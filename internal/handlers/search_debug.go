@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"songshare/internal/config"
+)
+
+// ScoreDebugResult is one ranked song with everything that went into its score
+type ScoreDebugResult struct {
+	ExperimentResult
+
+	// PlatformPopularity is each contributing platform's reported popularity
+	PlatformPopularity map[string]int `json:"platform_popularity"`
+	// RepresentativePlatform supplied the group's title, artists and metadata;
+	// "local" when the song came from the catalog
+	RepresentativePlatform string `json:"representative_platform,omitempty"`
+}
+
+// ScoreDebugResponse is returned by the search score debug endpoint
+type ScoreDebugResponse struct {
+	Query          string                `json:"query"`
+	Ranking        *config.RankingConfig `json:"ranking"`
+	PlatformStatus map[string]string     `json:"platform_status"`
+	Results        []ScoreDebugResult    `json:"results"`
+}
+
+// DebugSearchScores handles GET /api/v1/search/debug
+// It runs a search and returns every grouped result in rank order with its full
+// relevance breakdown, per-platform popularity and representative platform.
+// Returns 404 unless debug mode is enabled; register behind RequireAdmin.
+func (h *SongHandler) DebugSearchScores(c *gin.Context) {
+	if !h.debug {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		return
+	}
+
+	limit := 10
+	if parsedLimit, err := strconv.Atoi(c.Query("limit")); err == nil && parsedLimit > 0 && parsedLimit <= 50 {
+		limit = parsedLimit
+	}
+
+	searchResponse := h.performSearch(c.Request.Context(), h.renderer.BaseURL(c), SearchSongsRequest{
+		Query:    query,
+		Platform: strings.TrimSpace(c.Query("platform")),
+		Limit:    limit,
+	})
+
+	ranking := config.GetRankingConfig()
+	now := time.Now()
+	grouped := h.groupSongsForDuration(searchResponse.Results, ranking, query, 0)
+
+	results := make([]ScoreDebugResult, 0, len(grouped))
+	for i, song := range grouped {
+		popularity := make(map[string]int, len(song.Platforms))
+		for _, result := range song.Platforms {
+			popularity[result.Platform] = result.Popularity
+		}
+		results = append(results, ScoreDebugResult{
+			ExperimentResult:       h.experimentResult(i+1, song, ranking, query, 0, now),
+			PlatformPopularity:     popularity,
+			RepresentativePlatform: song.Source,
+		})
+	}
+
+	c.JSON(http.StatusOK, ScoreDebugResponse{
+		Query:          query,
+		Ranking:        ranking,
+		PlatformStatus: searchResponse.PlatformStatus,
+		Results:        results,
+	})
+}
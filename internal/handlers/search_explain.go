@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"songshare/internal/config"
+	"songshare/internal/models"
+)
+
+// SearchExplainResponse explains one result's position in a search: its score
+// breakdown and rank, with the results either side of it for comparison
+type SearchExplainResponse struct {
+	Query  string           `json:"query"`
+	Result ExperimentResult `json:"result"`
+	// Above and Below are the neighbouring results; nil at either end
+	Above *ExperimentResult `json:"above,omitempty"`
+	Below *ExperimentResult `json:"below,omitempty"`
+	Total int               `json:"total"` // Number of ranked results
+}
+
+// ExplainSearchResult handles GET /api/v1/search/explain
+// Runs the search for ?q= with the global ranking config and explains where
+// the grouped result with ?isrc= ranked and why, alongside its neighbours.
+// An optional ?duration_ms= ranks as it does in search.
+func (h *SongHandler) ExplainSearchResult(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	isrc := models.CanonicalISRC(c.Query("isrc"))
+	if query == "" || isrc == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameters 'q' and 'isrc' are required"})
+		return
+	}
+
+	limit := 10
+	if parsedLimit, err := strconv.Atoi(c.Query("limit")); err == nil && parsedLimit > 0 && parsedLimit <= 50 {
+		limit = parsedLimit
+	}
+
+	searchResponse := h.performSearch(c.Request.Context(), h.renderer.BaseURL(c), SearchSongsRequest{
+		Query:      query,
+		Platform:   strings.TrimSpace(c.Query("platform")),
+		Limit:      limit,
+		DurationMs: parseDurationMs(c.Query("duration_ms")),
+	})
+	results := h.rankExperiment(searchResponse, config.GetRankingConfig(), time.Now())
+
+	for i, result := range results {
+		if models.CanonicalISRC(result.ISRC) != isrc {
+			continue
+		}
+		response := SearchExplainResponse{Query: query, Result: result, Total: len(results)}
+		if i > 0 {
+			response.Above = &results[i-1]
+		}
+		if i < len(results)-1 {
+			response.Below = &results[i+1]
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{
+		"error":   "Result not found",
+		"details": "no result with this ISRC in the search results",
+	})
+}
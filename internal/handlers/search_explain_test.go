@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func performSearchExplain(t *testing.T, handler *SongHandler, query url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/search/explain", handler.ExplainSearchResult)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/search/explain?"+query.Encode(), nil))
+	return w
+}
+
+func TestExplainSearchResult_BreakdownAndNeighbours(t *testing.T) {
+	original := config.GetRankingConfig()
+	t.Cleanup(func() { config.SetRankingConfig(original) })
+	config.SetRankingConfig(original.WithOverrides(&config.RankingConfig{PopularityBoostEnabled: true}))
+
+	handler := newExperimentHandler()
+
+	// "Recent" is on two platforms and outranks the more popular "Classic"
+	w := performSearchExplain(t, handler, url.Values{"q": {"song"}, "isrc": {testutil.TestISRC3}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response SearchExplainResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Total)
+	assert.Equal(t, 2, response.Result.Rank)
+	assert.Equal(t, "Classic", response.Result.Title)
+	assert.Equal(t, 100, response.Result.Score.Platforms)
+	assert.Equal(t, 35, response.Result.Score.Popularity)
+	assert.Equal(t, 135, response.Result.Score.Total)
+
+	require.NotNil(t, response.Above)
+	assert.Equal(t, 1, response.Above.Rank)
+	assert.Equal(t, "Recent", response.Above.Title)
+	assert.Equal(t, 200, response.Above.Score.Platforms)
+	assert.Greater(t, response.Above.Score.Total, response.Result.Score.Total)
+	assert.Nil(t, response.Below)
+}
+
+func TestExplainSearchResult_Errors(t *testing.T) {
+	handler := newExperimentHandler()
+
+	w := performSearchExplain(t, handler, url.Values{"q": {"song"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performSearchExplain(t, handler, url.Values{"q": {"song"}, "isrc": {testutil.TestISRC2}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -2,15 +2,22 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
+	"songshare/internal/config"
 	"songshare/internal/handlers/render"
 	"songshare/internal/models"
 	"songshare/internal/repositories"
@@ -22,6 +29,10 @@ import (
 // ResolveSongRequest represents the request to resolve a song from a platform URL
 type ResolveSongRequest struct {
 	URL string `json:"url" binding:"required"`
+
+	// Results lists the search results on screen (result-item ID -> ISRC) so an
+	// HTMX resolve can update the badges of every result for the same song
+	Results map[string]string `json:"results,omitempty"`
 }
 
 // SearchSongsRequest represents the request to search for songs
@@ -32,21 +43,39 @@ type SearchSongsRequest struct {
 	Query    string `json:"query,omitempty"`    // Free-form search query
 	Platform string `json:"platform,omitempty"` // Optional: "spotify", "apple_music", or empty for both
 	Limit    int    `json:"limit,omitempty"`    // Max results per platform (default: 10)
+	Explicit string `json:"explicit,omitempty"` // Optional: "include" (default), "exclude" or "only"
+
+	// DurationMs is an optional known track length, e.g. from a local file;
+	// results close to it rank higher and far-off ones lower
+	DurationMs int `json:"duration_ms,omitempty"`
 }
 
 // SearchSongsResponse represents the response for search results
 type SearchSongsResponse struct {
-	Results map[string][]render.SearchResult `json:"results"` // platform -> results
-	Query   SearchSongsRequest               `json:"query"`   // Echo back the query for reference
+	Results        map[string][]render.SearchResult `json:"results"`                   // platform -> results
+	Query          SearchSongsRequest               `json:"query"`                     // Echo back the query for reference
+	PlatformStatus map[string]string                `json:"platform_status,omitempty"` // platform -> "ok" or an error category
+	Message        string                           `json:"message,omitempty"`         // Set when the query was not searched
 }
 
+// defaultSearchMinQueryLength matches the SEARCH_MIN_QUERY_LENGTH default
+const defaultSearchMinQueryLength = 2
+
+// queryTooShortMessage is the empty state for queries below the minimum length
+const queryTooShortMessage = "Keep typing to search for songs."
+
+// platformStatusOK marks a platform that returned results
+const platformStatusOK = "ok"
+
 // Simple search cache entry
 type searchCacheEntry struct {
 	results   []render.SearchResult
 	timestamp time.Time
 }
 
-// Simple search cache (5-minute TTL)
+// Simple search cache (5-minute TTL by default)
+// Entries hold unranked per-platform results; grouping and ranking run on every
+// read, so a cached hit always reflects the current ranking config.
 type searchCache struct {
 	entries map[string]searchCacheEntry
 	mu      sync.RWMutex
@@ -82,93 +111,453 @@ func (sc *searchCache) set(key string, results []render.SearchResult) {
 	
 	// Clean up old entries periodically (simple cleanup)
 	if len(sc.entries) > 1000 {
-		for k, v := range sc.entries {
-			if time.Since(v.timestamp) > sc.ttl {
-				delete(sc.entries, k)
-			}
+		sc.pruneLocked(time.Now())
+	}
+}
+
+// pruneExpired removes entries older than the TTL and returns how many were removed
+func (sc *searchCache) pruneExpired(now time.Time) int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.pruneLocked(now)
+}
+
+// pruneLocked removes expired entries; callers must hold sc.mu
+func (sc *searchCache) pruneLocked(now time.Time) int {
+	removed := 0
+	for k, v := range sc.entries {
+		if now.Sub(v.timestamp) > sc.ttl {
+			delete(sc.entries, k)
+			removed++
 		}
 	}
+	return removed
+}
+
+// setTTL changes how long search results are retained
+func (sc *searchCache) setTTL(ttl time.Duration) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.ttl = ttl
 }
 
 // SongHandler handles song-related requests
 type SongHandler struct {
 	songRepository    repositories.SongRepository
-	baseURL           string
 	renderer          *render.SongRenderer
-	spotifyService    services.PlatformService
-	appleMusicService services.PlatformService
-	tidalService      services.PlatformService
+	platforms         *platformRegistry
 	searchCache       *searchCache
+	platformHealth    *platformHealth
+	backfillLimiter   *tokenBucket
+	backfills         *backfillTracker
+	minPlatforms      int
+	minQueryLength    int
+	cleanupInterval   time.Duration
+
+	// Platform search fan-out: each platform's time budget and how many are
+	// searched at once
+	searchPlatformTimeout time.Duration
+	searchMaxConcurrency  int
+
+	// crossLinkThreshold is the title+artist match score needed to merge a
+	// resolved track into an existing song it shares no ISRC with
+	crossLinkThreshold float64
+
+	// searchIncludeKinds are the extra entity kinds platform searches return
+	searchIncludeKinds []services.EntityKind
+
+	// Cross-platform enrichment of newly resolved songs
+	enrichment          *enrichmentPool
+	enrichmentWorkers   int
+	enrichmentQueueSize int
+	enrichmentQueueMode string
+
+	// debug enables grouping diagnostics and the debug endpoints
+	debug bool
+
+	// artistImages caches platform artist images; nil when lookups are disabled
+	artistImages *artistImageCache
+
+	// lyricsProvider links songs to third-party lyrics during enrichment; nil disables it
+	lyricsProvider services.LyricsProvider
+
+	// Crawler detection and per-audience cache lifetimes
+	botUserAgents    []string
+	botCacheMaxAge   time.Duration
+	humanCacheMaxAge time.Duration
+
+	// linkRefreshAge is how stale a stored link may be before resolve re-fetches it
+	linkRefreshAge time.Duration
+
+	// Songs left with no available link after a re-fetch: retireUnavailable
+	// marks them retired, excludeRetired leaves retired songs out of search
+	retireUnavailable bool
+	excludeRetired    bool
+
+	// Universal links to ISRCs missing from the catalog are resolved live
+	// when liveResolveLinks is set, at the pace liveResolveLimiter allows
+	liveResolveLinks   bool
+	liveResolveLimiter *tokenBucket
+
+	// artistPopularity boosts songs by operator-curated artists, keyed by
+	// normalizeArtist output; empty unless ARTIST_POPULARITY_FILE is set
+	artistPopularity map[string]int
+
+	// trackingParams are stripped from resolve URLs before the track ID is extracted
+	trackingParams []string
+
+	// batchURLTimeout bounds the time spent resolving each URL of a batch
+	batchURLTimeout time.Duration
+
+	// Songs whose save failed during resolution, retried in the background
+	saveRetries          *saveRetryQueue
+	saveRetryQueueSize   int
+	saveRetryMaxAttempts int
+
+	// Universal link views, counted in memory and flushed every
+	// viewFlushInterval; nil until the view count worker starts
+	views             *viewCounter
+	viewFlushInterval time.Duration
 }
 
 // NewSongHandler creates a new song handler
 func NewSongHandler(songRepository repositories.SongRepository, baseURL string, spotifyService, appleMusicService, tidalService services.PlatformService) *SongHandler {
 	return &SongHandler{
 		songRepository:    songRepository,
-		baseURL:           baseURL,
 		renderer:          render.NewSongRenderer(baseURL),
-		spotifyService:    spotifyService,
-		appleMusicService: appleMusicService,
-		tidalService:      tidalService,
+		platforms:         newBuiltinPlatformRegistry(spotifyService, appleMusicService, tidalService),
 		searchCache:       newSearchCache(),
+		platformHealth:    newPlatformHealth(defaultPlatformHealthTTL),
+		backfillLimiter:   newTokenBucket(defaultBackfillRatePerSecond, defaultBackfillBurst),
+		backfills:         newBackfillTracker(defaultBackfillMaxConcurrent),
+		minPlatforms:      1,
+		minQueryLength:    defaultSearchMinQueryLength,
+
+		searchPlatformTimeout: defaultSearchPlatformTimeout,
+		searchMaxConcurrency:  defaultSearchMaxConcurrency,
+
+		crossLinkThreshold: defaultCrossLinkMergeThreshold,
+		cleanupInterval:   defaultCleanupInterval,
+
+		enrichmentWorkers:   defaultEnrichmentWorkers,
+		enrichmentQueueSize: defaultEnrichmentQueueSize,
+		enrichmentQueueMode: enrichmentQueueDrop,
+
+		botUserAgents:    defaultBotUserAgents,
+		botCacheMaxAge:   defaultBotCacheMaxAge,
+		humanCacheMaxAge: defaultHumanCacheMaxAge,
+
+		linkRefreshAge: defaultLinkRefreshAge,
+		trackingParams: services.DefaultTrackingParams,
+
+		retireUnavailable: true,
+
+		liveResolveLimiter: newTokenBucket(defaultLiveResolveRatePerSecond, defaultLiveResolveBurst),
+
+		batchURLTimeout: defaultResolveBatchURLTimeout,
+
+		saveRetryQueueSize:   defaultSaveRetryQueueSize,
+		saveRetryMaxAttempts: defaultSaveRetryMaxAttempts,
+
+		viewFlushInterval: defaultViewFlushInterval,
 	}
 }
 
-// ResolveSong handles POST /api/v1/songs/resolve
-func (h *SongHandler) ResolveSong(c *gin.Context) {
-	var req ResolveSongRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+// Default platform search fan-out limits, matching the config defaults
+const (
+	defaultSearchPlatformTimeout = 10 * time.Second
+	defaultSearchMaxConcurrency  = 0 // Unbounded
+)
+
+// Default album art backfill pacing, matching the config defaults
+const (
+	defaultBackfillRatePerSecond = 2.0
+	defaultBackfillBurst         = 5
+)
+
+// ApplyConfig applies operator-tunable settings from the application config
+func (h *SongHandler) ApplyConfig(cfg *config.Config) {
+	if cfg == nil {
 		return
 	}
+	h.backfillLimiter = newTokenBucket(cfg.BackfillRatePerSecond, cfg.BackfillBurst)
+	if cfg.BackfillMaxConcurrent > 0 {
+		h.backfills = newBackfillTracker(cfg.BackfillMaxConcurrent)
+	}
+	h.renderer.SetAllowedHosts(cfg.AllowedHosts)
+	h.renderer.SetTheme(render.Theme{
+		SiteName:     cfg.ThemeSiteName,
+		PrimaryColor: cfg.ThemePrimaryColor,
+		LogoURL:      cfg.ThemeLogoURL,
+		FooterHTML:   cfg.ThemeFooterHTML,
+		OGImageURL:   cfg.ThemeOGImageURL,
+	})
+	if cfg.SearchMinPlatforms > 0 {
+		h.minPlatforms = cfg.SearchMinPlatforms
+	}
+	if cfg.SearchMinQueryLength > 0 {
+		h.minQueryLength = cfg.SearchMinQueryLength
+	}
+	if cfg.PerPlatformTimeout > 0 {
+		h.searchPlatformTimeout = cfg.PerPlatformTimeout
+	}
+	if cfg.MaxConcurrentPlatforms > 0 {
+		h.searchMaxConcurrency = cfg.MaxConcurrentPlatforms
+	}
+	if cfg.CrossLinkMergeThreshold > 0 {
+		h.crossLinkThreshold = cfg.CrossLinkMergeThreshold
+	}
+	if kinds, err := services.ParseEntityKinds(cfg.SearchIncludeKinds); err != nil {
+		slog.Warn("Ignoring invalid search include kinds", "kinds", cfg.SearchIncludeKinds, "error", err)
+	} else {
+		h.searchIncludeKinds = kinds
+	}
+	if err := services.ConfigureSearchQueryStrategies(cfg.SearchQueryStrategies); err != nil {
+		slog.Warn("Ignoring invalid search query strategies", "strategies", cfg.SearchQueryStrategies, "error", err)
+	}
+	if cfg.SearchCacheTTL > 0 {
+		h.searchCache.setTTL(cfg.SearchCacheTTL)
+	}
+	if cfg.PlatformHealthTTL > 0 {
+		h.platformHealth.setTTL(cfg.PlatformHealthTTL)
+	}
+	if cfg.CleanupInterval > 0 {
+		h.cleanupInterval = cfg.CleanupInterval
+	}
+	if cfg.EnrichmentWorkers > 0 {
+		h.enrichmentWorkers = cfg.EnrichmentWorkers
+	}
+	if cfg.EnrichmentQueueSize > 0 {
+		h.enrichmentQueueSize = cfg.EnrichmentQueueSize
+	}
+	h.debug = cfg.DebugEnabled
+	services.SetRawResponseSampleRate(cfg.RawResponseSampleRate)
+	if cfg.ArtistImagesEnabled {
+		ttl := cfg.ArtistImageTTL
+		if ttl <= 0 {
+			ttl = defaultArtistImageTTL
+		}
+		h.artistImages = newArtistImageCache(ttl)
+	} else {
+		h.artistImages = nil
+	}
+	if provider, err := services.NewLyricsProvider(cfg.LyricsProvider, cfg.LyricsAPIKey); err != nil {
+		slog.Warn("Ignoring lyrics provider", "provider", cfg.LyricsProvider, "error", err)
+	} else {
+		h.lyricsProvider = provider
+	}
+	if patterns := normalizeBotUserAgents(cfg.BotUserAgents); len(patterns) > 0 {
+		h.botUserAgents = patterns
+	}
+	if cfg.BotCacheMaxAge > 0 {
+		h.botCacheMaxAge = cfg.BotCacheMaxAge
+	}
+	if cfg.HumanCacheMaxAge > 0 {
+		h.humanCacheMaxAge = cfg.HumanCacheMaxAge
+	}
+	if cfg.LinkRefreshAge > 0 {
+		h.linkRefreshAge = cfg.LinkRefreshAge
+	}
+	h.retireUnavailable = cfg.RetireUnavailableSongs
+	h.artistPopularity = normalizeArtistPopularity(cfg.ArtistPopularityOverrides)
+	h.liveResolveLinks = cfg.SongLinkLiveResolve
+	h.liveResolveLimiter = newTokenBucket(cfg.SongLinkLiveResolveRatePerSecond, cfg.SongLinkLiveResolveBurst)
+	h.excludeRetired = cfg.SearchExcludeRetired
+	if len(cfg.ResolveTrackingParams) > 0 {
+		h.trackingParams = cfg.ResolveTrackingParams
+	}
+	if cfg.VerifiedMaxAge > 0 {
+		h.renderer.SetVerifiedMaxAge(cfg.VerifiedMaxAge)
+	}
+	h.renderer.SetDisplayLimits(cfg.DisplayMaxTitleLength, cfg.DisplayMaxArtists)
+	if cfg.SaveRetryQueueSize > 0 {
+		h.saveRetryQueueSize = cfg.SaveRetryQueueSize
+	}
+	if cfg.SaveRetryMaxAttempts > 0 {
+		h.saveRetryMaxAttempts = cfg.SaveRetryMaxAttempts
+	}
+	if cfg.ViewFlushInterval > 0 {
+		h.viewFlushInterval = cfg.ViewFlushInterval
+	}
+	if cfg.EnrichmentQueueMode == enrichmentQueueDrop || cfg.EnrichmentQueueMode == enrichmentQueueBlock {
+		h.enrichmentQueueMode = cfg.EnrichmentQueueMode
+	}
+	for _, platform := range credentialPlatforms {
+		service, ok := h.platforms.get(platform)
+		platformConfig, _ := cfg.GetPlatformConfig(platform)
+		if !ok {
+			continue
+		}
+		if err := services.ConfigurePlatformHTTP(service, platformConfig); err != nil {
+			slog.Warn("Ignoring platform HTTP configuration", "platform", platform, "error", err)
+		}
+		services.ConfigureNegativeCache(service, platformConfig)
+	}
+	for _, platform := range configurablePlatforms {
+		platformConfig, ok := cfg.GetPlatformConfig(platform.name)
+		if !ok || !platformConfig.Enabled {
+			continue
+		}
+		service, err := platform.newService(platformConfig)
+		if err != nil {
+			slog.Warn("Ignoring platform configuration", "platform", platform.name, "error", err)
+			continue
+		}
+		h.RegisterPlatformService(service)
+	}
+}
 
-	// Parse the platform URL
-	platform, trackID, err := services.ParsePlatformURL(req.URL)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid platform URL",
-			"details": err.Error(),
-		})
-		return
+// credentialPlatforms are the built-in platforms constructed from credentials
+// alone; ApplyConfig applies their proxy, extra headers and negative cache TTL
+// afterwards
+var credentialPlatforms = []string{"spotify", "apple_music"}
+
+// configurablePlatforms are the platforms ApplyConfig registers when their
+// PLATFORM_<NAME>_ENABLED flag is set
+var configurablePlatforms = []struct {
+	name       string
+	newService func(cfg *config.PlatformConfig) (services.PlatformService, error)
+}{
+	{"youtube_music", func(cfg *config.PlatformConfig) (services.PlatformService, error) {
+		return services.NewYouTubeMusicService(cfg)
+	}},
+	{"deezer", func(cfg *config.PlatformConfig) (services.PlatformService, error) {
+		return services.NewDeezerService(cfg)
+	}},
+}
+
+// RegisterPlatformService adds a platform to resolve, search and enrichment,
+// replacing any service already registered under the same platform name.
+// It is safe to call while requests are being served.
+func (h *SongHandler) RegisterPlatformService(service services.PlatformService) {
+	h.platforms.register(service.GetPlatformName(), service)
+}
+
+// DeregisterPlatformService removes a platform from resolve, search and
+// enrichment until it is registered again, reporting whether it was registered.
+// Links to a removed built-in platform are answered as not configured.
+func (h *SongHandler) DeregisterPlatformService(platform string) bool {
+	return h.platforms.deregister(platform)
+}
+
+// resolveURLError explains why a URL can't be resolved as a song
+type resolveURLError struct {
+	message string
+	details string
+
+	// status is the HTTP status to answer with; 0 means 400, the URL's fault
+	status int
+	// platform and trackID are set when the URL names a track on a platform
+	// this server recognizes but can't call, so a stored copy can be served
+	platform string
+	trackID  string
+}
+
+// httpStatus returns the HTTP status the error should be answered with
+func (e *resolveURLError) httpStatus() int {
+	if e.status == 0 {
+		return http.StatusBadRequest
 	}
+	return e.status
+}
 
-	// Get the platform service
-	var platformService services.PlatformService
-	switch platform {
-	case "spotify":
-		platformService = h.spotifyService
-	case "apple_music":
-		platformService = h.appleMusicService
-	case "tidal":
-		platformService = h.tidalService
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Unsupported platform: " + platform,
-		})
-		return
+// storedSongForUnavailablePlatform looks up the stored song for a URL whose
+// platform is recognized but not configured, without calling the platform.
+// It returns nil when there is no stored song or the error is about the URL.
+func (h *SongHandler) storedSongForUnavailablePlatform(ctx context.Context, urlErr *resolveURLError) *models.Song {
+	if urlErr.platform == "" {
+		return nil
 	}
+	song, err := h.songRepository.FindByPlatformID(ctx, urlErr.platform, urlErr.trackID)
+	if err != nil {
+		slog.Error("Failed to look up stored song", "platform", urlErr.platform, "track_id", urlErr.trackID, "error", err)
+		return nil
+	}
+	return song
+}
 
-	if platformService == nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Platform service not available: " + platform,
-		})
-		return
+// parseResolveURL finds the platform service and track ID a resolve URL names.
+// Share-sheet short links (spotify.link, apple.co) are expanded and share-link
+// tracking params stripped first, so every variant of a link looks up the
+// stored song by the same track ID.
+func (h *SongHandler) parseResolveURL(ctx context.Context, rawURL string) (services.PlatformService, string, *resolveURLError) {
+	expandedURL, err := services.ResolveShortURL(ctx, rawURL)
+	if err != nil {
+		return nil, "", &resolveURLError{message: "Failed to expand short link", details: err.Error()}
 	}
 
-	// Resolve the song
-	song, err := h.resolveSongFromPlatform(c.Request.Context(), platformService, trackID)
+	platform, resourceType, trackID, err := services.ParsePlatformResourceURL(services.StripTrackingParams(expandedURL, h.trackingParams))
 	if err != nil {
-		slog.Error("Failed to resolve song", "url", req.URL, "error", err)
+		return nil, "", &resolveURLError{message: "Invalid platform URL", details: err.Error()}
+	}
+	if resourceType != services.ResourceTypeTrack {
+		return nil, "", &resolveURLError{
+			message: "Album/playlist resolution not yet supported",
+			details: fmt.Sprintf("%s URLs can be resolved as collections with POST /api/v1/collections/resolve", resourceType),
+		}
+	}
+
+	platformService, ok := h.platforms.get(platform)
+	if !ok && !slices.Contains(builtinPlatforms, platform) {
+		return nil, "", &resolveURLError{message: "Unsupported platform: " + platform}
+	}
+
+	// A platform we know but can't call is our problem, not the user's
+	if platformService == nil || !services.IsConfigured(platformService) {
+		return nil, "", &resolveURLError{
+			message:  "Platform not configured: " + platform,
+			details:  "this server can't reach " + platform + " right now; try a link from another platform",
+			status:   http.StatusServiceUnavailable,
+			platform: platform,
+			trackID:  trackID,
+		}
+	}
+	return platformService, trackID, nil
+}
+
+// ResolveSong handles POST /api/v1/songs/resolve
+// With ?persist=false the song is resolved for preview without being saved.
+func (h *SongHandler) ResolveSong(c *gin.Context) {
+	var req ResolveSongRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to resolve song from URL",
+			"error":   "Invalid request body",
 			"details": err.Error(),
 		})
 		return
 	}
 
+	// persist=false resolves for preview only, without touching the catalog
+	persist := c.DefaultQuery("persist", "true") != "false"
+
+	var song *models.Song
+	status := resolveStored
+	platformService, trackID, urlErr := h.parseResolveURL(c.Request.Context(), req.URL)
+	if urlErr != nil {
+		// Songs already in the catalog still resolve while their platform is down
+		song = h.storedSongForUnavailablePlatform(c.Request.Context(), urlErr)
+		if song == nil {
+			body := gin.H{"error": urlErr.message}
+			if urlErr.details != "" {
+				body["details"] = urlErr.details
+			}
+			c.JSON(urlErr.httpStatus(), body)
+			return
+		}
+	} else {
+		// Resolve the song
+		var err error
+		song, status, err = h.resolveSongFromPlatform(c.Request.Context(), platformService, trackID, persist)
+		if err != nil {
+			slog.Error("Failed to resolve song", "url", req.URL, "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Failed to resolve song from URL",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
 	if song == nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Song not found",
@@ -177,6 +566,67 @@ func (h *SongHandler) ResolveSong(c *gin.Context) {
 	}
 
 	// Convert to response format
+	response := h.buildResolvedSongResponse(h.renderer.BaseURL(c), song, status)
+
+	// Check if this is an HTMX request (for search page integration)
+	if c.GetHeader("HX-Request") == "true" {
+		// Return redirect URL with out-of-band badge updates
+		c.Header("HX-Redirect", response.UniversalLink)
+		
+		// Generate OOB updates for all search results with the same ISRC
+		oobHTML := render.RenderOOBBadges(song, matchingResultIDs(req.Results, song.ISRC))
+		
+		// Return JSON response with redirect and OOB HTML
+		responseHTML := fmt.Sprintf(`
+			<div id="resolve-result">{"redirect": "%s"}</div>
+			%s
+		`, response.UniversalLink, oobHTML)
+		
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, responseHTML)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// resultIDPattern matches the result-item IDs generated by renderSearchResultsHTML
+var resultIDPattern = regexp.MustCompile(`^result-[0-9]+$`)
+
+// matchingResultIDs returns the displayed result IDs whose ISRC matches, in a stable order
+func matchingResultIDs(results map[string]string, isrc string) []string {
+	if isrc == "" {
+		return nil
+	}
+
+	var ids []string
+	for id, resultISRC := range results {
+		if resultISRC == isrc && resultIDPattern.MatchString(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// buildResolvedSongResponse builds the resolve API response for a song in the
+// given resolve status. Unsaved songs have no catalog ID and their universal
+// link won't resolve yet.
+func (h *SongHandler) buildResolvedSongResponse(baseURL string, song *models.Song, status resolveStatus) render.ResolveSongResponse {
+	response := h.buildResolveResponse(baseURL, song)
+	switch status {
+	case resolveEphemeral:
+		response.Song.ID = ""
+		response.Ephemeral = true
+	case resolveSaving:
+		response.Song.ID = ""
+		response.Saving = true
+	}
+	return response
+}
+
+// buildResolveResponse converts a song into the resolve API response, linking under baseURL
+func (h *SongHandler) buildResolveResponse(baseURL string, song *models.Song) render.ResolveSongResponse {
 	response := render.ResolveSongResponse{
 		Song: render.SongMetadata{
 			ID:          song.ID.Hex(),
@@ -187,9 +637,12 @@ func (h *SongHandler) ResolveSong(c *gin.Context) {
 			ReleaseDate: song.Metadata.ReleaseDate.Format("2006-01-02"),
 			ISRC:        song.ISRC,
 			ImageURL:    song.Metadata.ImageURL,
+			LyricsURL:   song.Metadata.LyricsURL,
 		},
 		Platforms:     make(map[string]render.PlatformLink),
-		UniversalLink: fmt.Sprintf("%s/s/%s", h.baseURL, song.ISRC), // ISRC-based universal links
+		UniversalLink: fmt.Sprintf("%s/s/%s", baseURL, song.ISRC), // ISRC-based universal links
+		Verified:      h.renderer.IsVerified(song),
+		Retired:       song.Retired,
 	}
 
 	// Add platform links
@@ -198,32 +651,27 @@ func (h *SongHandler) ResolveSong(c *gin.Context) {
 			URL:       link.URL,
 			Available: link.Available,
 			Platform:  link.Platform,
+			Primary:   link.Primary,
 		}
 	}
 
-	// Check if this is an HTMX request (for search page integration)
-	if c.GetHeader("HX-Request") == "true" {
-		// Return redirect URL with out-of-band badge updates
-		c.Header("HX-Redirect", response.UniversalLink)
-		
-		// Generate OOB updates for all search results with the same ISRC
-		oobHTML := "" // Simplified: no out-of-band badge updates
-		
-		// Return JSON response with redirect and OOB HTML
-		responseHTML := fmt.Sprintf(`
-			<div id="resolve-result">{"redirect": "%s"}</div>
-			%s
-		`, response.UniversalLink, oobHTML)
-		
-		c.Header("Content-Type", "text/html")
-		c.String(http.StatusOK, responseHTML)
-		return
-	}
+	return response
+}
 
-	c.JSON(http.StatusOK, response)
+// platformServiceList returns the configured platform services in metadata preference order
+func (h *SongHandler) platformServiceList() []services.PlatformService {
+	var list []services.PlatformService
+	for _, service := range h.platforms.ordered() {
+		if service != nil && services.IsConfigured(service) {
+			list = append(list, service)
+		}
+	}
+	return list
 }
 
 // SearchSongs handles POST /api/v1/songs/search
+// An optional ?fields=results.*.title,results.*.url,... query param projects the
+// response (see render.ProjectFields).
 func (h *SongHandler) SearchSongs(c *gin.Context) {
 	var req SearchSongsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -241,6 +689,13 @@ func (h *SongHandler) SearchSongs(c *gin.Context) {
 		})
 		return
 	}
+	if _, err := services.ParseExplicitFilter(req.Explicit); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid explicit filter",
+			"details": err.Error(),
+		})
+		return
+	}
 
 	// Set default limit
 	if req.Limit <= 0 {
@@ -250,153 +705,16 @@ func (h *SongHandler) SearchSongs(c *gin.Context) {
 		req.Limit = 50
 	}
 
-	// Build search query string (use Query first, then combine Title + Artist)
-	var searchTerm string
-	if req.Query != "" {
-		searchTerm = req.Query
-	} else if req.Title != "" && req.Artist != "" {
-		searchTerm = req.Title + " " + req.Artist
-		if req.Album != "" {
-			searchTerm += " " + req.Album
-		}
-	} else if req.Title != "" {
-		searchTerm = req.Title
-	} else {
-		searchTerm = req.Artist
-	}
-
-	response := SearchSongsResponse{
-		Results: make(map[string][]render.SearchResult),
-		Query:   req,
-	}
-
-	// Search local database first
-	localSongs, err := h.songRepository.Search(c.Request.Context(), searchTerm, req.Limit)
-	if err != nil {
-		slog.Error("Local search failed", "error", err)
-	} else {
-		localResults := make([]render.SearchResult, 0, len(localSongs))
-		for _, song := range localSongs {
-			// Create universal link for local songs
-			universalLink := fmt.Sprintf("%s/s/%s", h.baseURL, song.ISRC)
-			if song.ISRC == "" {
-				universalLink = fmt.Sprintf("%s/s/%s", h.baseURL, song.ID.Hex()[:8])
-			}
-			
-			localResults = append(localResults, render.SearchResult{
-				Title:       song.Title,
-				Artists:     []string{song.Artist},
-				Album:       song.Album,
-				URL:         universalLink,
-				Platform:    "local",
-				ISRC:        song.ISRC,
-				DurationMs:  song.Metadata.Duration,
-				ReleaseDate: song.Metadata.ReleaseDate.Format("2006-01-02"),
-				ImageURL:    song.Metadata.ImageURL,
-				Available:   true,
-			})
-		}
-		response.Results["local"] = localResults
-	}
-
-	// Search platform services concurrently with caching
-	platformServices := map[string]services.PlatformService{
-		"spotify":     h.spotifyService,
-		"apple_music": h.appleMusicService,
-		"tidal":       h.tidalService,
-	}
-
-	// Use channels and goroutines for concurrent search
-	type platformResult struct {
-		platform string
-		results  []render.SearchResult
-		err      error
-	}
-
-	resultsChan := make(chan platformResult, 3)
-	var wg sync.WaitGroup
-
-	for platform, service := range platformServices {
-		// Skip if platform filter specified and doesn't match
-		if req.Platform != "" && req.Platform != platform {
-			continue
-		}
-		
-		if service == nil {
-			continue
-		}
-
-		wg.Add(1)
-		go func(platform string, service services.PlatformService) {
-			defer wg.Done()
-
-			// Check cache first
-			cacheKey := fmt.Sprintf("%s:%s:%d", platform, searchTerm, req.Limit)
-			if cached, found := h.searchCache.get(cacheKey); found {
-				resultsChan <- platformResult{platform: platform, results: cached}
-				return
-			}
-
-			// Create platform search query
-			searchQuery := services.SearchQuery{
-				Title:  req.Title,
-				Artist: req.Artist,
-				Album:  req.Album,
-				Query:  searchTerm,
-				Limit:  req.Limit,
-			}
-
-			// Search with timeout
-			ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-			defer cancel()
-
-			tracks, err := service.SearchTrack(ctx, searchQuery)
-			if err != nil {
-				resultsChan <- platformResult{platform: platform, err: err}
-				return
-			}
+	response := h.performSearch(c.Request.Context(), h.renderer.BaseURL(c), req)
 
-			// Convert to render format
-			results := make([]render.SearchResult, 0, len(tracks))
-			for _, track := range tracks {
-				results = append(results, render.SearchResult{
-					Title:       track.Title,
-					Artists:     track.Artists,
-					Album:       track.Album,
-					URL:         track.URL,
-					Platform:    platform,
-					ISRC:        track.ISRC,
-					DurationMs:  track.Duration,
-					ReleaseDate: track.ReleaseDate,
-					ImageURL:    track.ImageURL,
-					Explicit:    track.Explicit,
-					Available:   track.Available,
-				})
-			}
-
-			// Cache the results
-			h.searchCache.set(cacheKey, results)
-			resultsChan <- platformResult{platform: platform, results: results}
-		}(platform, service)
+	// Each platform's results come back in its own order; with a target
+	// duration, rank them as grouped search does so close lengths lead
+	if req.DurationMs > 0 {
+		grouped := h.groupSongsForDuration(response.Results, config.GetRankingConfig(), buildSearchTerm(req), req.DurationMs)
+		response.Results = orderResultsByRank(response.Results, grouped)
 	}
 
-	// Close channel when all goroutines complete
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
-
-	// Collect results
-	for result := range resultsChan {
-		if result.err != nil {
-			slog.Error("Platform search failed", "platform", result.platform, "error", result.err)
-			response.Results[result.platform] = []render.SearchResult{}
-		} else {
-			response.Results[result.platform] = result.results
-		}
-	}
-
-	c.JSON(http.StatusOK, response)
+	render.RespondJSON(c, http.StatusOK, response)
 }
 
 // renderSongJSON returns JSON response for the song
@@ -406,19 +724,20 @@ func (h *SongHandler) renderSongJSON(c *gin.Context, song *models.Song) {
 
 // renderSongPage returns HTML page with HTMX support
 func (h *SongHandler) renderSongPage(c *gin.Context, song *models.Song) {
-	// Adapter function to convert PlatformUIConfig types
-	adapter := func(platform string) *render.PlatformUIConfig {
-		config := GetPlatformUIConfig(platform)
-		return &render.PlatformUIConfig{
-			Name:        config.Name,
-			IconURL:     config.IconURL,
-			ButtonText:  config.ButtonText,
-			Description: config.Description,
-			Color:       config.Color,
-			BadgeClass:  config.BadgeClass,
-		}
+	h.renderer.RenderSongPage(c, song, platformUIAdapter)
+}
+
+// platformUIAdapter converts a platform's UI config to the renderer's type
+func platformUIAdapter(platform string) *render.PlatformUIConfig {
+	config := GetPlatformUIConfig(platform)
+	return &render.PlatformUIConfig{
+		Name:        config.Name,
+		IconURL:     config.IconURL,
+		ButtonText:  config.ButtonText,
+		Description: config.Description,
+		Color:       config.Color,
+		BadgeClass:  config.BadgeClass,
 	}
-	h.renderer.RenderSongPage(c, song, adapter)
 }
 
 // RedirectToSong handles GET /api/v1/s/:id - universal link redirects with dual-mode support
@@ -435,41 +754,54 @@ func (h *SongHandler) RedirectToSong(c *gin.Context) {
 	song, err := h.findSongByISRC(c.Request.Context(), songID)
 	if err != nil {
 		slog.Error("Song lookup failed", "identifier", songID, "error", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Song not found",
-		})
+		h.renderSongNotFound(c)
 		return
 	}
 
 	if song == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Song not found",
-		})
+		song = h.resolveSongLinkLive(c.Request.Context(), songID)
+	}
+	if song == nil {
+		h.renderSongNotFound(c)
 		return
 	}
 
-	// Check if song needs album art backfill
+	// Songs without album art are served as they are while the art is
+	// backfilled in the background for later views
 	if h.needsAlbumArtBackfill(song) {
-		updatedSong := h.backfillAlbumArt(c.Request.Context(), song)
-		if updatedSong != nil {
-			song = updatedSong
-		}
+		h.startAlbumArtBackfill(song)
 	}
 
-	// Check Accept header for content negotiation
-	accept := c.GetHeader("Accept")
-	slog.Info("Accept header", "accept", accept) // Debug log
+	bot := h.isBot(c)
+	h.setCachePolicy(c, bot)
+	h.recordView(bot, song.ID)
 
-	// Browsers typically send text/html as the first preference
-	if strings.Contains(accept, "text/html") {
+	if h.wantsHTML(c) {
 		// Return HTML page with HTMX support
 		h.renderSongPage(c, song)
 	} else {
-		// Return JSON response
+		// Return JSON response, counting views not yet flushed to the database
+		if h.views != nil {
+			counted := *song
+			counted.Views += h.views.unflushed(song.ID)
+			song = &counted
+		}
 		h.renderSongJSON(c, song)
 	}
 }
 
+// renderSongNotFound answers a song link miss with the HTML 404 page for
+// browsers and a JSON error for API clients
+func (h *SongHandler) renderSongNotFound(c *gin.Context) {
+	if h.wantsHTML(c) {
+		h.renderer.RenderNotFoundPage(c)
+		return
+	}
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": "Song not found",
+	})
+}
+
 // needsAlbumArtBackfill checks if a song needs album art to be backfilled
 func (h *SongHandler) needsAlbumArtBackfill(song *models.Song) bool {
 	// Song needs backfill if it has no album art but has platform links
@@ -485,15 +817,10 @@ func (h *SongHandler) backfillAlbumArt(ctx context.Context, song *models.Song) *
 		}
 
 		// Get the platform service
-		var platformService services.PlatformService
-		switch link.Platform {
-		case "spotify":
-			platformService = h.spotifyService
-		case "apple_music":
-			platformService = h.appleMusicService
-		default:
+		if link.Platform != "spotify" && link.Platform != "apple_music" {
 			continue
 		}
+		platformService, _ := h.platforms.get(link.Platform)
 
 		if platformService == nil {
 			continue
@@ -548,10 +875,12 @@ func (h *SongHandler) SearchPage(c *gin.Context) {
 		}
 	}
 
+	h.setCachePolicy(c, h.isBot(c))
 	h.renderer.RenderSearchPage(c, query)
 }
 
 // SearchResults handles GET /api/v1/search/results and returns simple HTML fragments
+// An optional min_platforms param hides songs found on fewer platforms.
 func (h *SongHandler) SearchResults(c *gin.Context) {
 	query := strings.TrimSpace(c.Query("q"))
 	platform := strings.TrimSpace(c.Query("platform"))
@@ -569,6 +898,10 @@ func (h *SongHandler) SearchResults(c *gin.Context) {
 		c.String(http.StatusOK, `<div class="empty-state"><p>Enter a search term to find songs.</p></div>`)
 		return
 	}
+	if h.queryTooShort(query) {
+		c.String(http.StatusOK, `<div class="empty-state"><p>%s</p></div>`, queryTooShortMessage)
+		return
+	}
 
 	// Use the same search logic as SearchSongs but return HTML
 	req := SearchSongsRequest{
@@ -576,9 +909,13 @@ func (h *SongHandler) SearchResults(c *gin.Context) {
 		Platform: platform,
 		Limit:    limit,
 	}
+	if explicit, err := services.ParseExplicitFilter(c.Query("explicit")); err == nil {
+		req.Explicit = string(explicit)
+	}
+	req.DurationMs = parseDurationMs(c.Query("duration_ms"))
 
 	// Perform the search using our simplified search logic
-	searchResponse := h.performSearch(c.Request.Context(), req)
+	searchResponse := h.performSearch(c.Request.Context(), h.renderer.BaseURL(c), req)
 
 	// Convert to HTML
 	if len(searchResponse.Results) == 0 {
@@ -586,39 +923,81 @@ func (h *SongHandler) SearchResults(c *gin.Context) {
 		return
 	}
 
-	html := h.renderSearchResultsHTML(searchResponse.Results)
+	// Optionally hide songs available on too few platforms
+	minPlatforms := h.minPlatforms
+	if minStr := c.Query("min_platforms"); minStr != "" {
+		if parsedMin, err := strconv.Atoi(minStr); err == nil && parsedMin > 0 {
+			minPlatforms = parsedMin
+		}
+	}
+
+	groupedSongs := h.groupSongsForDuration(searchResponse.Results, config.GetRankingConfig(), query, req.DurationMs)
+	groupedSongs = filterByMinPlatforms(groupedSongs, minPlatforms, query)
+
+	html := h.renderSearchResultsHTML(groupedSongs)
 	c.String(http.StatusOK, html)
 }
 
-// performSearch extracts the search logic from SearchSongs for reuse
-func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest) SearchSongsResponse {
-	// Build search term
-	var searchTerm string
+// queryTooShort reports whether a search term is too short to be worth a
+// platform fan-out, counting runes of its normalized form so punctuation-only
+// queries are short and multibyte characters count once
+func (h *SongHandler) queryTooShort(term string) bool {
+	return utf8.RuneCountInString(models.NormalizeSearchText(term)) < h.minQueryLength
+}
+
+// buildSearchTerm combines the request fields into one search term
+func buildSearchTerm(req SearchSongsRequest) string {
 	if req.Query != "" {
-		searchTerm = req.Query
-	} else if req.Title != "" && req.Artist != "" {
-		searchTerm = req.Title + " " + req.Artist
+		return req.Query
+	}
+	if req.Title != "" && req.Artist != "" {
+		term := req.Title + " " + req.Artist
 		if req.Album != "" {
-			searchTerm += " " + req.Album
+			term += " " + req.Album
 		}
-	} else if req.Title != "" {
-		searchTerm = req.Title
-	} else {
-		searchTerm = req.Artist
+		return term
+	}
+	if req.Title != "" {
+		return req.Title
 	}
+	return req.Artist
+}
+
+// performSearch searches the local catalog and all platforms concurrently;
+// local results link under baseURL. Queries below the minimum length return
+// an empty response with a message instead.
+func (h *SongHandler) performSearch(ctx context.Context, baseURL string, req SearchSongsRequest) SearchSongsResponse {
+	searchTerm := buildSearchTerm(req)
 
 	response := SearchSongsResponse{
-		Results: make(map[string][]render.SearchResult),
-		Query:   req,
+		Results:        make(map[string][]render.SearchResult),
+		Query:          req,
+		PlatformStatus: make(map[string]string),
+	}
+
+	if h.queryTooShort(searchTerm) {
+		response.Message = queryTooShortMessage
+		return response
+	}
+
+	// Invalid values are rejected by the handlers; anything left over means include
+	explicitFilter, err := services.ParseExplicitFilter(req.Explicit)
+	if err != nil {
+		explicitFilter = services.ExplicitInclude
 	}
 
 	// Search local database first
-	if localSongs, err := h.songRepository.Search(ctx, searchTerm, req.Limit); err == nil {
+	if localSongs, err := h.songRepository.Search(ctx, searchTerm, req.Limit); err != nil {
+		slog.Error("Local search failed", "error", err)
+	} else {
 		localResults := make([]render.SearchResult, 0, len(localSongs))
 		for _, song := range localSongs {
-			universalLink := fmt.Sprintf("%s/s/%s", h.baseURL, song.ISRC)
+			if !explicitFilter.Matches(song.Metadata.Explicit) || (song.Retired && h.excludeRetired) {
+				continue
+			}
+			universalLink := fmt.Sprintf("%s/s/%s", baseURL, song.ISRC)
 			if song.ISRC == "" {
-				universalLink = fmt.Sprintf("%s/s/%s", h.baseURL, song.ID.Hex()[:8])
+				universalLink = fmt.Sprintf("%s/s/%s", baseURL, song.ID.Hex()[:8])
 			}
 			
 			localResults = append(localResults, render.SearchResult{
@@ -626,23 +1005,20 @@ func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest)
 				Artists:     []string{song.Artist},
 				Album:       song.Album,
 				URL:         universalLink,
-				Platform:    "local",
+				Platform:    render.LocalSource,
 				ISRC:        song.ISRC,
 				DurationMs:  song.Metadata.Duration,
 				ReleaseDate: song.Metadata.ReleaseDate.Format("2006-01-02"),
 				ImageURL:    song.Metadata.ImageURL,
-				Available:   true,
+				Popularity:  song.Metadata.Popularity,
+				Available:   !song.Retired,
 			})
 		}
-		response.Results["local"] = localResults
+		response.Results[render.LocalSource] = localResults
 	}
 
-	// Search platforms concurrently (same logic as SearchSongs)
-	platformServices := map[string]services.PlatformService{
-		"spotify":     h.spotifyService,
-		"apple_music": h.appleMusicService,
-		"tidal":       h.tidalService,
-	}
+	// Search platforms concurrently
+	platformServices := h.platforms.snapshot()
 
 	type platformResult struct {
 		platform string
@@ -650,14 +1026,27 @@ func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest)
 		err      error
 	}
 
-	resultsChan := make(chan platformResult, 3)
+	resultsChan := make(chan platformResult, len(platformServices))
 	var wg sync.WaitGroup
 
+	// At most searchMaxConcurrency platforms are searched at once, or all of
+	// them when it's 0; cached results don't take a slot
+	var slots chan struct{}
+	if h.searchMaxConcurrency > 0 {
+		slots = make(chan struct{}, h.searchMaxConcurrency)
+	}
+
 	for platform, service := range platformServices {
 		if req.Platform != "" && req.Platform != platform {
 			continue
 		}
-		if service == nil {
+		if service == nil || !services.IsConfigured(service) {
+			continue
+		}
+		// Fail fast on a platform whose recent health check failed
+		if err := h.platformHealth.unavailable(platform, time.Now()); err != nil {
+			response.Results[platform] = []render.SearchResult{}
+			response.PlatformStatus[platform] = services.ErrorCategoryUnavailable
 			continue
 		}
 
@@ -665,7 +1054,7 @@ func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest)
 		go func(platform string, service services.PlatformService) {
 			defer wg.Done()
 
-			cacheKey := fmt.Sprintf("%s:%s:%d", platform, searchTerm, req.Limit)
+			cacheKey := fmt.Sprintf("%s:%s:%d:%s:%v:%s", platform, searchTerm, req.Limit, explicitFilter, h.searchIncludeKinds, services.RegionFromContext(ctx))
 			if cached, found := h.searchCache.get(cacheKey); found {
 				resultsChan <- platformResult{platform: platform, results: cached}
 				return
@@ -677,9 +1066,22 @@ func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest)
 				Album:  req.Album,
 				Query:  searchTerm,
 				Limit:  req.Limit,
+
+				Explicit:     explicitFilter,
+				IncludeKinds: h.searchIncludeKinds,
+			}
+
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					resultsChan <- platformResult{platform: platform, err: ctx.Err()}
+					return
+				}
 			}
 
-			searchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			searchCtx, cancel := context.WithTimeout(ctx, h.searchPlatformTimeout)
 			defer cancel()
 
 			tracks, err := service.SearchTrack(searchCtx, searchQuery)
@@ -691,6 +1093,8 @@ func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest)
 			results := make([]render.SearchResult, 0, len(tracks))
 			for _, track := range tracks {
 				results = append(results, render.SearchResult{
+					Kind:        string(track.Kind),
+					ExternalID:  track.ExternalID,
 					Title:       track.Title,
 					Artists:     track.Artists,
 					Album:       track.Album,
@@ -700,11 +1104,13 @@ func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest)
 					DurationMs:  track.Duration,
 					ReleaseDate: track.ReleaseDate,
 					ImageURL:    track.ImageURL,
+					Popularity:  track.Popularity,
 					Explicit:    track.Explicit,
 					Available:   track.Available,
 				})
 			}
 
+			results = dedupePlatformResults(results)
 			h.searchCache.set(cacheKey, results)
 			resultsChan <- platformResult{platform: platform, results: results}
 		}(platform, service)
@@ -715,17 +1121,36 @@ func (h *SongHandler) performSearch(ctx context.Context, req SearchSongsRequest)
 		close(resultsChan)
 	}()
 
+	// Collect results, recording why a platform came back empty
+	resultCaps := config.GetRankingConfig().PlatformResultCaps
 	for result := range resultsChan {
-		if result.err != nil {
+		switch {
+		case result.err != nil:
+			category := services.ClassifyError(result.err)
+			slog.Error("Platform search failed", "platform", result.platform, "category", category, "error", result.err)
 			response.Results[result.platform] = []render.SearchResult{}
-		} else {
+			response.PlatformStatus[result.platform] = category
+		case len(result.results) == 0:
 			response.Results[result.platform] = result.results
+			response.PlatformStatus[result.platform] = services.ErrorCategoryNoResults
+		default:
+			response.Results[result.platform] = capSourceResults(result.results, resultCaps[result.platform])
+			response.PlatformStatus[result.platform] = platformStatusOK
 		}
 	}
 
 	return response
 }
 
+// capSourceResults keeps a source's first limit results, in the order the
+// source ranked them; a limit of 0 or less keeps them all
+func capSourceResults(results []render.SearchResult, limit int) []render.SearchResult {
+	if limit <= 0 || len(results) <= limit {
+		return results
+	}
+	return results[:limit]
+}
+
 // GroupedSong represents a song with multiple platform links
 type GroupedSong struct {
 	Title       string
@@ -737,16 +1162,25 @@ type GroupedSong struct {
 	ImageURL    string
 	Explicit    bool
 	Platforms   []render.SearchResult // All platform results for this song
+
+	// Source is the platform (or local catalog) whose result created the
+	// group and supplied its title, artists and other metadata
+	Source string
+
+	// InLibrary is set when the song is already in the local catalog, whose
+	// universal link is LibraryURL. The local result is not one of Platforms.
+	InLibrary  bool
+	LibraryURL string
+
+	// Diagnostics is populated only when debug mode is enabled
+	Diagnostics *GroupingDiagnostics
 }
 
 // renderSearchResultsHTML generates HTML for search results grouped by ISRC
-func (h *SongHandler) renderSearchResultsHTML(results map[string][]render.SearchResult) string {
+func (h *SongHandler) renderSearchResultsHTML(groupedSongs []GroupedSong) string {
 	var html strings.Builder
 	html.WriteString(`<div class="search-results">`)
 	
-	// Group results by ISRC
-	groupedSongs := h.groupSongsByISRC(results)
-	
 	if len(groupedSongs) == 0 {
 		html.WriteString(`<div class="no-results"><p>No results found.</p></div>`)
 		html.WriteString(`</div>`)
@@ -755,7 +1189,7 @@ func (h *SongHandler) renderSearchResultsHTML(results map[string][]render.Search
 	
 	// Render grouped songs
 	for i, song := range groupedSongs {
-		html.WriteString(fmt.Sprintf(`<div class="result-item" id="result-%d">`, i))
+		html.WriteString(fmt.Sprintf(`<div class="result-item" id="result-%d" data-isrc="%s">`, i, song.ISRC))
 		
 		// Album art (prefer image from first platform that has one)
 		imageURL := song.ImageURL
@@ -767,21 +1201,21 @@ func (h *SongHandler) renderSearchResultsHTML(results map[string][]render.Search
 		
 		// Song info
 		html.WriteString(`<div class="song-info">`)
-		html.WriteString(fmt.Sprintf(`<h2 class="title">%s`, song.Title))
+		html.WriteString(fmt.Sprintf(`<h2 class="title">%s`, h.renderer.DisplayTitle(song.Title)))
 		if song.Explicit {
 			html.WriteString(`<span class="explicit-indicator">E</span>`)
 		}
 		html.WriteString(`</h2>`)
 		
 		if len(song.Artists) > 0 {
-			html.WriteString(fmt.Sprintf(`<h3 class="artist">%s</h3>`, strings.Join(song.Artists, ", ")))
+			html.WriteString(fmt.Sprintf(`<h3 class="artist">%s</h3>`, h.renderer.DisplayArtists(song.Artists)))
 		}
 		if song.Album != "" {
 			html.WriteString(fmt.Sprintf(`<h4 class="album">%s</h4>`, song.Album))
 		}
 		
 		// Platform badges
-		html.WriteString(`<div class="result-platforms">`)
+		html.WriteString(fmt.Sprintf(`<div class="result-platforms" id="result-%d-platforms">`, i))
 		for _, platform := range song.Platforms {
 			badgeClass := fmt.Sprintf("platform-badge platform-%s", strings.ReplaceAll(platform.Platform, "_", "-"))
 			html.WriteString(fmt.Sprintf(`<a href="%s" target="_blank" class="%s">`, platform.URL, badgeClass))
@@ -794,6 +1228,9 @@ func (h *SongHandler) renderSearchResultsHTML(results map[string][]render.Search
 			html.WriteString(h.getPlatformDisplayName(platform.Platform))
 			html.WriteString(`</a>`)
 		}
+		if song.InLibrary {
+			html.WriteString(fmt.Sprintf(`<a href="%s" class="platform-badge source-local">In library</a>`, song.LibraryURL))
+		}
 		html.WriteString(`</div>`)
 		
 		html.WriteString(`</div>`) // Close song-info
@@ -805,6 +1242,9 @@ func (h *SongHandler) renderSearchResultsHTML(results map[string][]render.Search
 		if len(song.Platforms) > 0 {
 			firstPlatformURL := song.Platforms[0].URL
 			html.WriteString(fmt.Sprintf(`<button class="action-btn action-secondary action-small" onclick="createShareLink('%s', this)">Share</button>`, firstPlatformURL))
+		} else if song.InLibrary {
+			// Already shareable: the library URL is the universal link
+			html.WriteString(fmt.Sprintf(`<a href="%s" class="action-btn action-secondary action-small">View Song</a>`, song.LibraryURL))
 		}
 		
 		html.WriteString(`</div>`) // Close result-actions
@@ -815,50 +1255,70 @@ func (h *SongHandler) renderSearchResultsHTML(results map[string][]render.Search
 	return html.String()
 }
 
-// groupSongsByISRC groups search results by ISRC, with fallback grouping by title+artist
+// groupSongsByISRC groups search results by ISRC, with fallback grouping by title+artist,
+// ranked with the global ranking config
 func (h *SongHandler) groupSongsByISRC(results map[string][]render.SearchResult) []GroupedSong {
+	return h.groupSongsWithRanking(results, config.GetRankingConfig())
+}
+
+// groupSongsWithRanking groups search results like groupSongsByISRC, ranking with cfg
+func (h *SongHandler) groupSongsWithRanking(results map[string][]render.SearchResult, cfg *config.RankingConfig) []GroupedSong {
+	return h.groupSongsForDuration(results, cfg, "", 0)
+}
+
+// groupSongsForDuration groups search results like groupSongsWithRanking,
+// favouring songs whose title matches query and songs near targetDurationMs
+// when they are set
+func (h *SongHandler) groupSongsForDuration(results map[string][]render.SearchResult, cfg *config.RankingConfig, query string, targetDurationMs int) []GroupedSong {
+	if cfg == nil {
+		cfg = config.DefaultRankingConfig()
+	}
+
 	// Map ISRC to grouped song
 	isrcToSong := make(map[string]*GroupedSong)
-	// Map title+artist combo to grouped song (for songs without ISRC)
-	titleArtistToSong := make(map[string]*GroupedSong)
+	// Map title+artist combo to the grouped songs sharing it (for songs without
+	// ISRC); a combo holds several songs when their durations don't match
+	titleArtistToSong := make(map[string][]*GroupedSong)
 	
 	// Helper function to create a normalized title+artist key
 	normalizeKey := func(title string, artists []string) string {
-		titleLower := strings.ToLower(strings.TrimSpace(title))
+		titleLower := NormalizeTitleForGrouping(title)
 		artistLower := ""
 		if len(artists) > 0 {
-			artistLower = strings.ToLower(strings.TrimSpace(artists[0]))
+			artistLower = normalizeArtist(artists[0])
 		}
 		return titleLower + "|" + artistLower
 	}
 	
-	// Process all results from all platforms
-	for _, platformResults := range results {
-		for _, result := range platformResults {
+	// Process all results from all platforms, in a fixed platform order so the
+	// first result seen for a group (which sets its metadata) never varies
+	platforms := make([]string, 0, len(results))
+	for platform := range results {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	for _, platform := range platforms {
+		for _, result := range dedupePlatformResults(results[platform]) {
+			// Albums and artists are listed in the JSON results but never grouped as songs
+			if result.Kind != "" && result.Kind != string(services.EntityTrack) {
+				continue
+			}
 			if result.ISRC != "" && result.ISRC != "unknown" {
 				// Group by ISRC
 				if existing, exists := isrcToSong[result.ISRC]; exists {
-					// Check if this platform already exists for this song
-					platformExists := false
-					for _, existingPlatform := range existing.Platforms {
-						if existingPlatform.Platform == result.Platform {
-							platformExists = true
-							break
-						}
-					}
-					
-					// Only add platform if it doesn't already exist
-					if !platformExists {
-						existing.Platforms = append(existing.Platforms, result)
-					}
+					// One link per platform, keeping the canonical one
+					existing.addPlatformResult(result)
 					
 					// Update song metadata if this result has better data
 					if existing.ImageURL == "" && result.ImageURL != "" {
 						existing.ImageURL = result.ImageURL
 					}
+					if existing.DurationMs == 0 && result.DurationMs > 0 {
+						existing.DurationMs = result.DurationMs
+					}
 				} else {
 					// Create new grouped song
-					isrcToSong[result.ISRC] = &GroupedSong{
+					song := &GroupedSong{
 						Title:       result.Title,
 						Artists:     result.Artists,
 						Album:       result.Album,
@@ -867,35 +1327,41 @@ func (h *SongHandler) groupSongsByISRC(results map[string][]render.SearchResult)
 						ReleaseDate: result.ReleaseDate,
 						ImageURL:    result.ImageURL,
 						Explicit:    result.Explicit,
-						Platforms:   []render.SearchResult{result},
+						Source:      result.Platform,
 					}
+					song.addPlatformResult(result)
+					isrcToSong[result.ISRC] = song
 				}
 			} else {
-				// Group songs without ISRC by title+artist
+				// Group songs without ISRC by title+artist, keeping songs of
+				// clearly different lengths (e.g. a remix) apart
 				titleArtistKey := normalizeKey(result.Title, result.Artists)
-				
-				if existing, exists := titleArtistToSong[titleArtistKey]; exists {
-					// Check if this platform already exists for this song
-					platformExists := false
-					for _, existingPlatform := range existing.Platforms {
-						if existingPlatform.Platform == result.Platform {
-							platformExists = true
-							break
-						}
+				var existing *GroupedSong
+				for _, candidate := range titleArtistToSong[titleArtistKey] {
+					if durationsMatch(candidate.DurationMs, result.DurationMs) {
+						existing = candidate
+						break
 					}
-					
-					// Only add platform if it doesn't already exist
-					if !platformExists {
-						existing.Platforms = append(existing.Platforms, result)
+				}
+				
+				if existing != nil {
+					if h.debug {
+						existing.recordMissingISRC(result.Platform)
 					}
+
+					// One link per platform, keeping the canonical one
+					existing.addPlatformResult(result)
 					
 					// Update song metadata if this result has better data
 					if existing.ImageURL == "" && result.ImageURL != "" {
 						existing.ImageURL = result.ImageURL
 					}
+					if existing.DurationMs == 0 && result.DurationMs > 0 {
+						existing.DurationMs = result.DurationMs
+					}
 				} else {
 					// Create new grouped song for title+artist combo
-					titleArtistToSong[titleArtistKey] = &GroupedSong{
+					song := &GroupedSong{
 						Title:       result.Title,
 						Artists:     result.Artists,
 						Album:       result.Album,
@@ -904,13 +1370,25 @@ func (h *SongHandler) groupSongsByISRC(results map[string][]render.SearchResult)
 						ReleaseDate: result.ReleaseDate,
 						ImageURL:    result.ImageURL,
 						Explicit:    result.Explicit,
-						Platforms:   []render.SearchResult{result},
+						Source:      result.Platform,
 					}
+					song.addPlatformResult(result)
+					if h.debug {
+						song.recordMissingISRC(result.Platform)
+					}
+					titleArtistToSong[titleArtistKey] = append(titleArtistToSong[titleArtistKey], song)
 				}
 			}
 		}
 	}
 	
+	if h.debug {
+		linkFragmentedGroups(isrcToSong, titleArtistToSong, normalizeKey)
+		for _, song := range isrcToSong {
+			song.recordMetadataConflicts()
+		}
+	}
+
 	// Convert maps to slice with deterministic ordering
 	var groupedSongs []GroupedSong
 	
@@ -932,7 +1410,7 @@ func (h *SongHandler) groupSongsByISRC(results map[string][]render.SearchResult)
 	// Add ISRC-grouped songs
 	for _, isrc := range isrcs {
 		song := isrcToSong[isrc]
-		h.sortPlatformsByPreference(song.Platforms)
+		sortPlatformsByPreference(song.Platforms, cfg.PlatformOrder)
 		groupedSongs = append(groupedSongs, *song)
 	}
 	
@@ -953,24 +1431,38 @@ func (h *SongHandler) groupSongsByISRC(results map[string][]render.SearchResult)
 	
 	// Add title+artist grouped songs
 	for _, key := range titleArtistKeys {
-		song := titleArtistToSong[key]
-		h.sortPlatformsByPreference(song.Platforms)
-		groupedSongs = append(groupedSongs, *song)
+		for _, song := range titleArtistToSong[key] {
+			sortPlatformsByPreference(song.Platforms, cfg.PlatformOrder)
+			groupedSongs = append(groupedSongs, *song)
+		}
 	}
 	
 	// Sort grouped songs by relevance (number of platforms, then alphabetically)
-	h.sortGroupedSongs(groupedSongs)
+	h.sortGroupedSongs(groupedSongs, cfg, query, targetDurationMs)
 	
 	return groupedSongs
 }
 
-// sortPlatformsByPreference sorts platforms in display preference order
-func (h *SongHandler) sortPlatformsByPreference(platforms []render.SearchResult) {
+// sortPlatformsByPreference sorts platforms in display preference order.
+// A configured order replaces the built-in one; unlisted platforms go last.
+func sortPlatformsByPreference(platforms []render.SearchResult, order []string) {
 	preferenceOrder := map[string]int{
-		"local":       1,
-		"apple_music": 2,
-		"spotify":     3,
-		"tidal":       4,
+		"apple_music":   1,
+		"spotify":       2,
+		"tidal":         3,
+		"deezer":        4,
+		"youtube_music": 5,
+	}
+	if len(order) > 0 {
+		preferenceOrder = make(map[string]int, len(order))
+		for i, platform := range order {
+			preferenceOrder[platform] = i + 1
+		}
+		for _, result := range platforms {
+			if _, listed := preferenceOrder[result.Platform]; !listed {
+				preferenceOrder[result.Platform] = len(order) + 1
+			}
+		}
 	}
 	
 	// Sort platforms by preference
@@ -985,87 +1477,154 @@ func (h *SongHandler) sortPlatformsByPreference(platforms []render.SearchResult)
 	}
 }
 
-// artistPopularityScore assigns popularity scores to artists (higher = more popular)
+// artistPopularityScore returns the operator-configured boost of the most
+// popular of artists, or 0 when none of them has one
 func (h *SongHandler) artistPopularityScore(artists []string) int {
-	// Popular artists get higher scores
-	popularArtists := map[string]int{
-		"chappell roan":    1000,
-		"taylor swift":     950,
-		"billie eilish":    900,
-		"dua lipa":        850,
-		"ariana grande":   800,
-		"olivia rodrigo":  750,
-		"the weeknd":      700,
-		"bad bunny":       650,
-		"drake":           600,
-		"ed sheeran":      550,
-		// Add more as needed
-	}
-	
 	maxScore := 0
 	for _, artist := range artists {
-		artistLower := strings.ToLower(strings.TrimSpace(artist))
-		if score, exists := popularArtists[artistLower]; exists && score > maxScore {
+		if score, exists := h.artistPopularity[normalizeArtist(artist)]; exists && score > maxScore {
 			maxScore = score
 		}
 	}
 	return maxScore
 }
 
-// calculateRelevanceScore calculates a comprehensive relevance score for a song
-func (h *SongHandler) calculateRelevanceScore(song GroupedSong) int {
-	score := 0
+// RelevanceBreakdown itemizes the points that make up a grouped song's relevance score.
+// TextMatch is only set when the search had a query and is scaled by
+// TextMatchMultiplier. Recency and AlbumArt are scaled by ContextMultiplier.
+// Popularity is only set when PopularityBoostEnabled is, and is scaled by
+// PopularityBoostMultiplier. TieEpsilon is compared against Total after
+// scaling, so raising the multipliers widens score gaps and leaves fewer
+// results tied; lowering them turns more near-misses into popularity tie-breaks.
+// The breakdown reports the epsilon it was ranked with for that reason.
+type RelevanceBreakdown struct {
+	TextMatch        int `json:"text_match"`
+	Platforms        int `json:"platforms"`
+	ArtistPopularity int `json:"artist_popularity"`
+	Recency          int `json:"recency"`
+	AlbumArt         int `json:"album_art"`
+	Popularity       int `json:"popularity"`
+	Duration         int `json:"duration"` // Only set when the search gave a target duration
+	Library          int `json:"library"`  // Boost for songs already in the local catalog
+	Total            int `json:"total"`
+
+	// Songs whose Totals differ by no more than this are tied
+	TieEpsilon float64 `json:"tie_epsilon"`
+}
+
+// relevanceBreakdown scores a song against cfg, itemized by signal. query is
+// the search text, and targetDurationMs the searched-for track length, or
+// empty and 0 if none was given.
+func (h *SongHandler) relevanceBreakdown(song GroupedSong, cfg *config.RankingConfig, query string, targetDurationMs int, now time.Time) RelevanceBreakdown {
+	var breakdown RelevanceBreakdown
+
+	// How closely the title and artists match what was searched for
+	breakdown.TextMatch = int(calculateTextMatchScore(song, query) * textMatchMultiplier(cfg))
 	
 	// Platform availability (more platforms = higher score)
-	score += len(song.Platforms) * 100
+	breakdown.Platforms = len(song.Platforms) * 100
 	
 	// Artist popularity bonus
-	score += h.artistPopularityScore(song.Artists)
+	breakdown.ArtistPopularity = h.artistPopularityScore(song.Artists)
 	
 	// Release date bonus (newer songs get slight preference)
+	contextWeight := contextMultiplier(cfg)
 	if song.ReleaseDate != "" {
 		// Simple heuristic: if release date contains recent years, boost score
 		if strings.Contains(song.ReleaseDate, "2024") {
-			score += 50
+			breakdown.Recency = int(50 * contextWeight)
 		} else if strings.Contains(song.ReleaseDate, "2023") {
-			score += 30
+			breakdown.Recency = int(30 * contextWeight)
 		} else if strings.Contains(song.ReleaseDate, "2022") {
-			score += 10
+			breakdown.Recency = int(10 * contextWeight)
 		}
 	}
 	
 	// Album art bonus (songs with art are likely better curated)
 	if song.ImageURL != "" {
-		score += 25
+		breakdown.AlbumArt = int(25 * contextWeight)
 	}
-	
-	return score
+
+	// Platform-reported popularity, optionally decayed for old releases
+	if cfg != nil && cfg.PopularityBoostEnabled {
+		breakdown.Popularity = int(calculatePopularityBoost(song, cfg, now))
+	}
+
+	// Closeness to a known track length, to tell same-titled tracks apart
+	breakdown.Duration = durationMatchScore(song.DurationMs, targetDurationMs)
+
+	// Songs already in the catalog; the local result isn't counted as a platform
+	if song.InLibrary && cfg != nil {
+		breakdown.Library = cfg.LibraryBoost
+	}
+
+	breakdown.Total = breakdown.TextMatch + breakdown.Platforms + breakdown.ArtistPopularity + breakdown.Recency + breakdown.AlbumArt + breakdown.Popularity + breakdown.Duration + breakdown.Library
+	if cfg != nil {
+		breakdown.TieEpsilon = cfg.TieEpsilon
+	}
+	return breakdown
+}
+
+// calculateRelevanceScore calculates a comprehensive relevance score for a song
+func (h *SongHandler) calculateRelevanceScore(song GroupedSong, cfg *config.RankingConfig, query string, targetDurationMs int, now time.Time) int {
+	return h.relevanceBreakdown(song, cfg, query, targetDurationMs, now).Total
+}
+
+// rankedBefore reports whether song a should be listed before song b. Scores within
+// the configured tie epsilon are ties, broken by popularity, then by the best
+// platform weight, then by the configured tie-break order (see compareTieBreak).
+// Popularity is decayed as of now when decay is configured, whether or not the
+// popularity boost is enabled.
+func rankedBefore(a, b GroupedSong, scoreA, scoreB int, cfg *config.RankingConfig, now time.Time) bool {
+	epsilon := 0.0
+	var tieBreakOrder []string
+	if cfg != nil {
+		epsilon = cfg.TieEpsilon
+		tieBreakOrder = cfg.TieBreakOrder
+	}
+	if diff := float64(scoreA - scoreB); math.Abs(diff) > epsilon {
+		return diff > 0
+	}
+
+	if popA, popB := getPopularityWithFallbacks(a, cfg, now), getPopularityWithFallbacks(b, cfg, now); popA != popB {
+		return popA > popB
+	}
+	if cfg != nil {
+		if weightA, weightB := bestPlatformWeight(a, cfg.PlatformWeights), bestPlatformWeight(b, cfg.PlatformWeights); weightA != weightB {
+			return weightA > weightB
+		}
+	}
+	return compareTieBreak(a, b, tieBreakOrder) < 0
+}
+
+// bestPlatformWeight returns the highest preference weight among the song's
+// platforms, counting the local catalog's weight for songs in the library
+func bestPlatformWeight(song GroupedSong, weights map[string]float64) float64 {
+	best := 0.0
+	if song.InLibrary {
+		best = weights[render.LocalSource]
+	}
+	for _, result := range song.Platforms {
+		if weight := weights[result.Platform]; weight > best {
+			best = weight
+		}
+	}
+	return best
 }
 
 // sortGroupedSongs sorts grouped songs by comprehensive relevance scoring
-func (h *SongHandler) sortGroupedSongs(songs []GroupedSong) {
+func (h *SongHandler) sortGroupedSongs(songs []GroupedSong, cfg *config.RankingConfig, query string, targetDurationMs int) {
 	// Calculate scores for all songs first
+	now := time.Now()
 	scores := make([]int, len(songs))
 	for i, song := range songs {
-		scores[i] = h.calculateRelevanceScore(song)
+		scores[i] = h.calculateRelevanceScore(song, cfg, query, targetDurationMs, now)
 	}
 	
-	// Sort by relevance score (descending), then by title (ascending) for tie-breaking
+	// Sort by relevance score (descending), breaking near-ties per rankedBefore
 	for i := 0; i < len(songs)-1; i++ {
 		for j := i + 1; j < len(songs); j++ {
-			shouldSwap := false
-			
-			// Primary sort: higher relevance score first
-			if scores[i] < scores[j] {
-				shouldSwap = true
-			} else if scores[i] == scores[j] {
-				// Tie-breaker: alphabetical by title
-				if songs[i].Title > songs[j].Title {
-					shouldSwap = true
-				}
-			}
-			
-			if shouldSwap {
+			if rankedBefore(songs[j], songs[i], scores[j], scores[i], cfg, now) {
 				songs[i], songs[j] = songs[j], songs[i]
 				scores[i], scores[j] = scores[j], scores[i]
 			}
@@ -1088,28 +1647,64 @@ func (h *SongHandler) getPlatformDisplayName(platform string) string {
 		return "Spotify"
 	case "tidal":
 		return "TIDAL"
-	case "local":
-		return "Local Library"
+	case "youtube_music":
+		return "YouTube Music"
+	case "deezer":
+		return "Deezer"
 	default:
 		return strings.Title(platform)
 	}
 }
 
-// findSongByISRC finds a song by ISRC or ID prefix
+// findSongByISRC finds a song by ISRC or ID prefix. Identifiers match
+// regardless of case and of a trailing slash.
 func (h *SongHandler) findSongByISRC(ctx context.Context, identifier string) (*models.Song, error) {
-	// Try ISRC first
-	song, err := h.songRepository.FindByISRC(ctx, identifier)
+	identifier = strings.TrimRight(strings.TrimSpace(identifier), "/")
+	if identifier == "" {
+		return nil, nil
+	}
+
+	// Try ISRC first, normalized so printed ("us-um7-17-03861") and
+	// lowercase forms match the stored uppercase code
+	isrc := identifier
+	normalized, err := models.NormalizeISRC(identifier)
+	validISRC := err == nil
+	if validISRC {
+		isrc = normalized
+	}
+	song, err := h.songRepository.FindByISRC(ctx, isrc)
 	if err != nil {
 		return nil, err
 	}
 	if song != nil {
 		return song, nil
 	}
+
+	// A valid ISRC may since have been replaced by an upstream correction or merge
+	if validISRC {
+		song, err := h.songRepository.FindByPreviousISRC(ctx, isrc)
+		if err != nil || song != nil {
+			return song, err
+		}
+	}
 	
-	// Try ID prefix as fallback
+	// Try ID prefix as fallback; ObjectID hex is lowercase
+	if isHex(identifier) {
+		identifier = strings.ToLower(identifier)
+	}
 	return h.songRepository.FindByIDPrefix(ctx, identifier)
 }
 
+// isHex reports whether s consists only of hexadecimal digits
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
 // EnhancedPlatformBadges - simple stub for backward compatibility
 func (h *SongHandler) EnhancedPlatformBadges(c *gin.Context) {
 	c.String(http.StatusOK, `<div>Enhanced badges not implemented</div>`)
@@ -1120,48 +1715,138 @@ func (h *SongHandler) EnhanceBadges(c *gin.Context) {
 	c.String(http.StatusOK, `<div>Badge enhancement not implemented</div>`)
 }
 
-// resolveSongFromPlatform resolves a song from a platform track ID
-func (h *SongHandler) resolveSongFromPlatform(ctx context.Context, platformService services.PlatformService, trackID string) (*models.Song, error) {
+// defaultPrimaryPreferences picks a primary link for songs that lost or never
+// had one, matching the song page's platform order
+var defaultPrimaryPreferences = []string{"apple_music", "spotify", "tidal"}
+
+// primaryPreferences returns the platform order for recomputing primary links:
+// the configured ranking platform order, or the default
+func primaryPreferences() []string {
+	if order := config.GetRankingConfig().PlatformOrder; len(order) > 0 {
+		return order
+	}
+	return defaultPrimaryPreferences
+}
+
+// resolveStatus reports whether a resolved song is in the catalog
+type resolveStatus int
+
+const (
+	resolveStored    resolveStatus = iota // saved, or already in the catalog
+	resolveEphemeral                      // resolved for preview, not saved
+	resolveSaving                         // save failed and is queued for retry
+)
+
+// mergeResolvedLink adds the resolved platform link to a stored song that
+// lacks one for that platform and saves it. Failures are logged, since the
+// stored song is still a valid result.
+func (h *SongHandler) mergeResolvedLink(ctx context.Context, song *models.Song, platform, trackID, url string) {
+	if song.HasPlatform(platform) {
+		return
+	}
+	if err := song.AddPlatformLink(platform, trackID, url, 1.0); err != nil {
+		slog.Warn("Rejected platform link", "platform", platform, "track_id", trackID, "error", err)
+		return
+	}
+
+	// Songs stored before primaries existed get one by preference
+	song.RecomputePrimary(primaryPreferences())
+	if err := h.songRepository.Update(ctx, song); err != nil {
+		slog.Error("Failed to update song with new platform link", "error", err)
+	}
+}
+
+// resolveSongFromPlatform resolves a song from a platform track ID. When persist
+// is false the catalog is only read, never written.
+func (h *SongHandler) resolveSongFromPlatform(ctx context.Context, platformService services.PlatformService, trackID string, persist bool) (*models.Song, resolveStatus, error) {
 	// Check if we already have this song by platform ID
 	existingSong, err := h.songRepository.FindByPlatformID(ctx, platformService.GetPlatformName(), trackID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check existing song: %w", err)
+		return nil, resolveStored, fmt.Errorf("failed to check existing song: %w", err)
 	}
 
 	if existingSong != nil {
-		return existingSong, nil
+		if persist {
+			existingSong = h.refreshStoredSong(ctx, platformService, trackID, existingSong)
+		}
+		return existingSong, resolveStored, nil
 	}
 
 	// Fetch track info from the platform
 	trackInfo, err := platformService.GetTrackByID(ctx, trackID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get track info: %w", err)
+		return nil, resolveStored, fmt.Errorf("failed to get track info: %w", err)
 	}
+	logOversizedTrack(platformService.GetPlatformName(), trackID, trackInfo)
 
 	// Try to find existing song by ISRC
 	if trackInfo.ISRC != "" {
 		existingSong, err := h.songRepository.FindByISRC(ctx, trackInfo.ISRC)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check existing song by ISRC: %w", err)
+			return nil, resolveStored, fmt.Errorf("failed to check existing song by ISRC: %w", err)
+		}
+		if existingSong == nil {
+			// The platform may still report an ISRC the song has since replaced
+			existingSong, err = h.songRepository.FindByPreviousISRC(ctx, trackInfo.ISRC)
+			if err != nil {
+				return nil, resolveStored, fmt.Errorf("failed to check existing song by previous ISRC: %w", err)
+			}
 		}
 
 		if existingSong != nil {
-			// Add this platform link if it doesn't exist
-			if !existingSong.HasPlatform(platformService.GetPlatformName()) {
-				existingSong.AddPlatformLink(platformService.GetPlatformName(), trackID, trackInfo.URL, 1.0)
-				if err := h.songRepository.Update(ctx, existingSong); err != nil {
+			if persist {
+				h.mergeResolvedLink(ctx, existingSong, platformService.GetPlatformName(), trackID, trackInfo.URL)
+			}
+			return existingSong, resolveStored, nil
+		}
+	}
+
+	// Without an ISRC match, a close enough title+artist match is the same recording
+	match, score, err := h.findCrossLinkMatch(ctx, trackInfo)
+	if err != nil {
+		return nil, resolveStored, fmt.Errorf("failed to check existing song by title and artist: %w", err)
+	}
+	if match != nil {
+		slog.Info("Merging track into title and artist match", "platform", platformService.GetPlatformName(), "track_id", trackID, "song_id", match.ID.Hex(), "score", score)
+		if persist {
+			if err := match.AddPlatformLink(platformService.GetPlatformName(), trackID, trackInfo.URL, score); err != nil {
+				slog.Warn("Rejected platform link", "platform", platformService.GetPlatformName(), "track_id", trackID, "error", err)
+			} else {
+				match.RecomputePrimary(primaryPreferences())
+				if err := h.songRepository.Update(ctx, match); err != nil {
 					slog.Error("Failed to update song with new platform link", "error", err)
 				}
 			}
-			return existingSong, nil
 		}
+		return match, resolveStored, nil
 	}
 
 	// Create new song from track info
 	song := trackInfo.ToSong()
+	if !persist {
+		return song, resolveEphemeral, nil
+	}
+
 	if err := h.songRepository.Save(ctx, song); err != nil {
-		return nil, fmt.Errorf("failed to save new song: %w", err)
+		// A concurrent resolve may have inserted the same song first
+		var dupErr *repositories.DuplicateSongError
+		if errors.As(err, &dupErr) && dupErr.ISRC != "" {
+			existingSong, findErr := h.songRepository.FindByISRC(ctx, dupErr.ISRC)
+			if findErr == nil && existingSong != nil {
+				slog.Info("Recovered existing song after duplicate save", "isrc", dupErr.ISRC)
+				h.mergeResolvedLink(ctx, existingSong, platformService.GetPlatformName(), trackID, trackInfo.URL)
+				return existingSong, resolveStored, nil
+			}
+		}
+
+		// The metadata is good; keep it and retry the save once the database recovers
+		if h.saveRetries != nil && h.saveRetries.enqueue(ctx, song, time.Now()) {
+			slog.Warn("Queued song for save retry", "isrc", song.ISRC, "error", err)
+			return song, resolveSaving, nil
+		}
+		return nil, resolveStored, fmt.Errorf("failed to save new song: %w", err)
 	}
 
-	return song, nil
+	h.queueEnrichment(ctx, song.ID.Hex(), song.ISRC)
+	return song, resolveStored, nil
 }
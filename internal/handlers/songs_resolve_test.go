@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+	"songshare/internal/models"
+	"songshare/internal/repositories"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func performResolve(t *testing.T, handler *SongHandler, query string) (*httptest.ResponseRecorder, render.ResolveSongResponse) {
+	t.Helper()
+	return performResolveURL(t, handler, testutil.SpotifyURL1, query)
+}
+
+func performResolveURL(t *testing.T, handler *SongHandler, url, query string) (*httptest.ResponseRecorder, render.ResolveSongResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/songs/resolve", handler.ResolveSong)
+
+	body, err := json.Marshal(ResolveSongRequest{URL: url})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/songs/resolve"+query, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response render.ResolveSongResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w, response
+}
+
+func TestResolveSong_PreviewDoesNotPersist(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithURL(testutil.SpotifyURL1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "?persist=false")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, response.Ephemeral)
+	assert.Empty(t, response.Song.ID)
+	assert.Equal(t, "Test Song", response.Song.Title)
+	assert.Contains(t, response.Platforms, "spotify")
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestResolveSong_MatchesReplacedISRC(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	// The platform still reports the ISRC the stored song has replaced
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithURL(testutil.SpotifyURL1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+	stored := testutil.NewSongBuilder().WithISRC(testutil.TestISRC2).Build()
+	stored.PreviousISRCs = []string{testutil.TestISRC1}
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(stored, nil)
+	repo.On("Update", mock.Anything, stored).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, testutil.TestISRC2, response.Song.ISRC)
+	assert.True(t, stored.HasPlatform("spotify"))
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "FindByTitleArtist", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolveSong_PreviewSkipsPlatformLinkUpdate(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	existing := testutil.NewSongBuilder().
+		WithISRC(testutil.TestISRC1).
+		WithAppleMusicLink(testutil.AppleMusicTrackID1, testutil.AppleMusicURL1).
+		Build()
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(existing, nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "?persist=false")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, response.Ephemeral)
+	assert.False(t, existing.HasPlatform("spotify"))
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestResolveSong_PersistsByDefault(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, response.Ephemeral)
+	repo.AssertCalled(t, "Save", mock.Anything, mock.AnythingOfType("*models.Song"))
+}
+
+func TestResolveSong_RecoversFromDuplicateSave(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	existing := testutil.NewSongBuilder().
+		WithID("64b7f0c2a1b2c3d4e5f60718").
+		WithISRC(testutil.TestISRC1).
+		Build()
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil).Once()
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).
+		Return(&repositories.DuplicateSongError{ISRC: testutil.TestISRC1})
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(existing, nil).Once()
+	var updated *models.Song
+	repo.On("Update", mock.Anything, mock.AnythingOfType("*models.Song")).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*models.Song)
+	}).Return(nil).Once()
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "64b7f0c2a1b2c3d4e5f60718", response.Song.ID)
+	repo.AssertNumberOfCalls(t, "FindByISRC", 2)
+
+	// The resolved link is merged into the song the concurrent resolve saved
+	require.NotNil(t, updated)
+	assert.Equal(t, existing.ID, updated.ID)
+	link := updated.GetPlatformLink("spotify")
+	require.NotNil(t, link)
+	assert.Equal(t, testutil.SpotifyTrackID1, link.ExternalID)
+	assert.Contains(t, response.Platforms, "spotify")
+}
+
+func TestResolveSong_HTMXReturnsOOBBadgesForMatchingResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &testutil.MockSongRepository{}
+	stored := testutil.NewSongBuilder().
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		WithAppleMusicLink(testutil.AppleMusicTrackID1, testutil.AppleMusicURL1).
+		Build()
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(stored, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", testutil.NewMockPlatformService("spotify"), nil, nil)
+	router := gin.New()
+	router.POST("/api/v1/songs/resolve", handler.ResolveSong)
+
+	body, err := json.Marshal(ResolveSongRequest{
+		URL: testutil.SpotifyURL1,
+		Results: map[string]string{
+			"result-0":             testutil.TestISRC1,
+			"result-3":             testutil.TestISRC1,
+			"result-1":             "GBUM71505078",
+			`"><script>x</script>`: testutil.TestISRC1,
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/songs/resolve", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "http://localhost/s/"+testutil.TestISRC1, w.Header().Get("HX-Redirect"))
+	responseBody := w.Body.String()
+	assert.Contains(t, responseBody, `id="result-0-platforms" hx-swap-oob="true"`)
+	assert.Contains(t, responseBody, `id="result-3-platforms" hx-swap-oob="true"`)
+	assert.NotContains(t, responseBody, `result-1-platforms`)
+	assert.NotContains(t, responseBody, `<script>`)
+	assert.Contains(t, responseBody, "Apple Music")
+}
+
+func TestResolveSong_URLVariantsShortCircuit(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	existing := testutil.NewSongBuilder().
+		WithISRC(testutil.TestISRC1).
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(existing, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	variants := []string{
+		testutil.SpotifyURL1 + "?si=abc",
+		testutil.SpotifyURL1 + "?si=xyz&utm_source=copy-link#share",
+		"https://play.spotify.com/track/" + testutil.SpotifyTrackID1,
+		"https://spotify.com/track/" + testutil.SpotifyTrackID1,
+		"https://open.spotify.com/intl-de/track/" + testutil.SpotifyTrackID1 + "?si=abc",
+	}
+	for _, url := range variants {
+		w, response := performResolveURL(t, handler, url, "")
+		require.Equal(t, http.StatusOK, w.Code, url)
+		assert.Equal(t, existing.ID.Hex(), response.Song.ID)
+	}
+
+	// Each variant is a single lookup by the canonical ID, with no platform fetch
+	repo.AssertNumberOfCalls(t, "FindByPlatformID", len(variants))
+	spotify.AssertNotCalled(t, "GetTrackByID", mock.Anything, mock.Anything)
+}
+
+func TestResolveSong_MarksResolvedPlatformPrimary(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithURL(testutil.SpotifyURL1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	repo.On("FindByPreviousISRC", mock.Anything, testutil.TestISRC1).Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "?persist=false")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, response.Platforms["spotify"].Primary)
+}
+
+func TestResolveSong_KeepsExistingPrimaryWhenAddingLink(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	existing := testutil.NewSongBuilder().
+		WithISRC(testutil.TestISRC1).
+		WithAppleMusicLink(testutil.AppleMusicTrackID1, testutil.AppleMusicURL1).
+		Build()
+	track := testutil.NewTrackInfoBuilder().
+		WithExternalID(testutil.SpotifyTrackID1).
+		WithURL(testutil.SpotifyURL1).
+		WithISRC(testutil.TestISRC1).
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(existing, nil)
+	repo.On("Update", mock.Anything, existing).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(spotify, testutil.SpotifyTrackID1, track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	w, response := performResolve(t, handler, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, response.Platforms["apple_music"].Primary)
+	assert.False(t, response.Platforms["spotify"].Primary)
+}
+
+func TestResolveSong_RegisteredPlatformService(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	youtube := testutil.NewMockPlatformService("youtube_music")
+	track := testutil.NewTrackInfoBuilder().
+		WithPlatform("youtube_music").
+		WithExternalID("dQw4w9WgXcQ").
+		WithURL("https://music.youtube.com/watch?v=dQw4w9WgXcQ").
+		WithISRC("").
+		Build()
+
+	repo.On("FindByPlatformID", mock.Anything, "youtube_music", "dQw4w9WgXcQ").Return(nil, nil)
+	testutil.ExpectSongRepositoryFindByTitleArtist(repo)
+	repo.On("Save", mock.Anything, mock.AnythingOfType("*models.Song")).Return(nil)
+	testutil.ExpectPlatformServiceGetTrackByID(youtube, "dQw4w9WgXcQ", track, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	handler.RegisterPlatformService(youtube)
+	w, response := performResolveURL(t, handler, "https://music.youtube.com/watch?v=dQw4w9WgXcQ", "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, response.Platforms, "youtube_music")
+	repo.AssertCalled(t, "Save", mock.Anything, mock.AnythingOfType("*models.Song"))
+}
+
+func TestResolveSong_UnregisteredPlatformIsUnsupported(t *testing.T) {
+	handler := NewSongHandler(&testutil.MockSongRepository{}, "http://localhost", nil, nil, nil)
+	w, _ := performResolveURL(t, handler, "https://music.youtube.com/watch?v=dQw4w9WgXcQ", "")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Unsupported platform: youtube_music")
+}
+
+func TestResolveSong_RejectsAlbumAndPlaylistURLs(t *testing.T) {
+	spotify := testutil.NewMockPlatformService("spotify")
+	apple := testutil.NewMockPlatformService("apple_music")
+	handler := NewSongHandler(&testutil.MockSongRepository{}, "http://localhost", spotify, apple, nil)
+
+	for _, url := range []string{
+		"https://open.spotify.com/album/6i6folBtxKV28WX3msQ4FE",
+		"https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M",
+		"https://music.apple.com/us/album/a-night-at-the-opera/1440857777",
+	} {
+		w, _ := performResolveURL(t, handler, url, "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+		assert.Contains(t, w.Body.String(), "Album/playlist resolution not yet supported", url)
+	}
+	spotify.AssertNotCalled(t, "GetTrackByID", mock.Anything, mock.Anything)
+	apple.AssertNotCalled(t, "GetTrackByID", mock.Anything, mock.Anything)
+}
+
+func TestApplyConfig_RegistersEnabledYouTubeMusic(t *testing.T) {
+	handler := NewSongHandler(&testutil.MockSongRepository{}, "http://localhost", nil, nil, nil)
+	handler.ApplyConfig(&config.Config{Platforms: map[string]*config.PlatformConfig{
+		"youtube_music": {
+			Name:       "youtube_music",
+			Enabled:    true,
+			AuthMethod: config.AuthMethodAPIKey,
+			APIKey:     "test-key",
+		},
+	}})
+
+	service, ok := handler.platforms.get("youtube_music")
+	require.True(t, ok)
+	assert.IsType(t, &services.YouTubeMusicService{}, service)
+	assert.Len(t, handler.platformServiceList(), 1)
+}
+
+func TestResolveSong_DisabledPlatformServesStoredSong(t *testing.T) {
+	song := testutil.CreateTestSong()
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(song, nil)
+
+	// Neither a missing nor an unconfigured Spotify service is called
+	for _, spotify := range []services.PlatformService{nil, services.NewSpotifyService("", "", nil)} {
+		handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+		w, response := performResolve(t, handler, "")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, song.ISRC, response.Song.ISRC)
+		assert.Equal(t, song.ID.Hex(), response.Song.ID)
+	}
+}
+
+func TestResolveSong_DisabledPlatformWithoutStoredSong(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	repo.On("FindByPlatformID", mock.Anything, "spotify", testutil.SpotifyTrackID1).Return(nil, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	w, _ := performResolve(t, handler, "")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "Platform not configured: spotify")
+}
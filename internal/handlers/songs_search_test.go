@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/models"
+	"songshare/internal/services"
+	"songshare/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchSongs_ReportsPlatformStatus(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	tidal := testutil.NewMockPlatformService("tidal")
+
+	repo.On("Search", mock.Anything, "test song", mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).
+		Return([]*services.TrackInfo(nil), &services.PlatformError{Platform: "spotify", Operation: "auth", Message: "failed to get access token"})
+	appleMusic.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{}, nil)
+	tidal.On("SearchTrack", mock.Anything, mock.Anything).
+		Return([]*services.TrackInfo{testutil.NewTrackInfoBuilder().WithPlatform("tidal").Build()}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, tidal)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/songs/search", handler.SearchSongs)
+
+	body, err := json.Marshal(SearchSongsRequest{Query: "test song"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/songs/search", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response SearchSongsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, services.ErrorCategoryAuth, response.PlatformStatus["spotify"])
+	assert.Equal(t, services.ErrorCategoryNoResults, response.PlatformStatus["apple_music"])
+	assert.Equal(t, platformStatusOK, response.PlatformStatus["tidal"])
+	assert.Empty(t, response.Results["spotify"])
+	assert.Len(t, response.Results["tidal"], 1)
+}
+
+func TestSearchSongs_ExplicitFilter(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	explicitSong := testutil.NewSongBuilder().WithTitle("Explicit Song").WithISRC("USUM71703861").Build()
+	explicitSong.Metadata.Explicit = true
+	cleanSong := testutil.NewSongBuilder().WithTitle("Clean Song").WithISRC("USUM71703862").Build()
+
+	repo.On("Search", mock.Anything, "test song", mock.Anything).Return([]*models.Song{explicitSong, cleanSong}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.MatchedBy(func(q services.SearchQuery) bool {
+		return q.Explicit == services.ExplicitExclude
+	})).Return([]*services.TrackInfo{}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/songs/search", handler.SearchSongs)
+
+	post := func(req SearchSongsRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/songs/search", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		return w
+	}
+
+	w := post(SearchSongsRequest{Query: "test song", Explicit: "exclude"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response SearchSongsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Results["local"], 1)
+	assert.Equal(t, "Clean Song", response.Results["local"][0].Title)
+	spotify.AssertExpectations(t)
+
+	w = post(SearchSongsRequest{Query: "test song", Explicit: "sometimes"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSearchSongs_DurationRanksMatchFirst(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	// The platform lists the radio edit ahead of the album version
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{
+		testutil.NewTrackInfoBuilder().WithExternalID("edit").WithURL("https://open.spotify.com/track/edit").
+			WithTitle("Song").WithISRC(testutil.TestISRC1).WithDuration(210000).Build(),
+		testutil.NewTrackInfoBuilder().WithExternalID("album").WithURL("https://open.spotify.com/track/album").
+			WithTitle("Song").WithISRC(testutil.TestISRC2).WithDuration(354000).Build(),
+	}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/songs/search", handler.SearchSongs)
+
+	post := func(req SearchSongsRequest) SearchSongsResponse {
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/songs/search", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response SearchSongsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	externalIDs := func(response SearchSongsResponse) []string {
+		var ids []string
+		for _, result := range response.Results["spotify"] {
+			ids = append(ids, result.ExternalID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"edit", "album"}, externalIDs(post(SearchSongsRequest{Query: "song"})))
+
+	// A local file's length picks out the album version
+	response := post(SearchSongsRequest{Query: "song", DurationMs: 354700})
+	assert.Equal(t, []string{"album", "edit"}, externalIDs(response))
+	assert.Equal(t, 354700, response.Query.DurationMs)
+}
+
+func TestSearchSongs_IncludeKinds(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+
+	track := testutil.NewTrackInfoBuilder().WithExternalID("track1").WithISRC(testutil.TestISRC1).Build()
+	album := testutil.NewTrackInfoBuilder().WithExternalID("album1").WithURL("https://open.spotify.com/album/album1").Build()
+	album.Kind = services.EntityAlbum
+
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.MatchedBy(func(q services.SearchQuery) bool {
+		return len(q.IncludeKinds) == 1 && q.IncludeKinds[0] == services.EntityAlbum
+	})).Return([]*services.TrackInfo{track, album}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.ApplyConfig(&config.Config{SearchIncludeKinds: []string{"album"}})
+
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "test song", Limit: 10})
+	require.Len(t, response.Results["spotify"], 2)
+	assert.Equal(t, "", response.Results["spotify"][0].Kind)
+	assert.Equal(t, "album", response.Results["spotify"][1].Kind)
+
+	// Albums are returned in the results but never grouped as songs
+	groups := handler.groupSongsByISRC(response.Results)
+	require.Len(t, groups, 1)
+	assert.Equal(t, testutil.TestISRC1, groups[0].ISRC)
+	spotify.AssertExpectations(t)
+}
+
+func TestSearch_SkipsUnconfiguredPlatform(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+
+	repo.On("Search", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Song{}, nil)
+	appleMusic.On("SearchTrack", mock.Anything, mock.Anything).Return([]*services.TrackInfo{}, nil)
+
+	spotify := services.NewSpotifyService("", "", nil)
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, nil)
+
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "test song", Limit: 10})
+	assert.NotContains(t, response.Results, "spotify")
+	assert.NotContains(t, response.PlatformStatus, "spotify")
+	assert.Contains(t, response.Results, "apple_music")
+	assert.NotContains(t, handler.platformServiceList(), spotify)
+}
+
+func TestSearch_CachedResultsRerankAfterConfigChange(t *testing.T) {
+	original := config.GetRankingConfig()
+	t.Cleanup(func() { config.SetRankingConfig(original) })
+	boosted := original.WithOverrides(&config.RankingConfig{PopularityBoostEnabled: true})
+	config.SetRankingConfig(boosted)
+
+	handler := newExperimentHandler()
+	req := SearchSongsRequest{Query: "song", Platform: "spotify", Limit: 10}
+
+	first := handler.performSearch(context.Background(), "http://localhost", req)
+	groups := handler.groupSongsByISRC(first.Results)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "Classic", groups[0].Title)
+
+	// Decaying old releases drops the classic below the recent track
+	config.SetRankingConfig(boosted.WithOverrides(&config.RankingConfig{PopularityDecayHalfLifeYears: 20}))
+
+	second := handler.performSearch(context.Background(), "http://localhost", req)
+	groups = handler.groupSongsByISRC(second.Results)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "Recent", groups[0].Title)
+
+	// The second search was served from the cache
+	spotify, _ := handler.platforms.get("spotify")
+	spotify.(*testutil.MockPlatformService).AssertNumberOfCalls(t, "SearchTrack", 1)
+}
+
+func TestSearch_BelowMinQueryLengthSkipsPlatforms(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+
+	for _, query := range []string{"a", "!!", " é ", "日"} {
+		response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: query, Limit: 10})
+		assert.Equal(t, queryTooShortMessage, response.Message, query)
+		assert.Empty(t, response.Results, query)
+	}
+
+	repo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	spotify.AssertNotCalled(t, "SearchTrack", mock.Anything, mock.Anything)
+}
+
+func TestSearch_AtMinQueryLengthSearches(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	repo.On("Search", mock.Anything, "日本", 10).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).
+		Return([]*services.TrackInfo{testutil.NewTrackInfoBuilder().Build()}, nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "日本", Limit: 10})
+
+	assert.Empty(t, response.Message)
+	assert.Len(t, response.Results["spotify"], 1)
+	spotify.AssertCalled(t, "SearchTrack", mock.Anything, mock.Anything)
+}
+
+func TestSearchResults_BelowMinQueryLengthShowsKeepTyping(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	handler := NewSongHandler(repo, "http://localhost", spotify, nil, nil)
+	handler.ApplyConfig(&config.Config{SearchMinQueryLength: 3})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/search/results", handler.SearchResults)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search/results?q=ab", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), queryTooShortMessage)
+	repo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	spotify.AssertNotCalled(t, "SearchTrack", mock.Anything, mock.Anything)
+}
+
+func TestPerformSearch_LimitsConcurrencyAndTimesOut(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	repo.On("Search", mock.Anything, "test song", mock.Anything).Return([]*models.Song{}, nil)
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	slowSearch := func(args mock.Arguments) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		// Every search outlasts the timeout
+		<-args.Get(0).(context.Context).Done()
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}
+	newService := func(name string) *testutil.MockPlatformService {
+		service := testutil.NewMockPlatformService(name)
+		service.On("SearchTrack", mock.Anything, mock.Anything).Run(slowSearch).
+			Return([]*services.TrackInfo(nil), context.DeadlineExceeded)
+		return service
+	}
+
+	search := func(maxConcurrent int) SearchSongsResponse {
+		peak = 0
+		handler := NewSongHandler(repo, "http://localhost", newService("spotify"), newService("apple_music"), newService("tidal"))
+		handler.RegisterPlatformService(newService("deezer"))
+		handler.ApplyConfig(&config.Config{SearchConfig: config.SearchConfig{
+			PerPlatformTimeout:     20 * time.Millisecond,
+			MaxConcurrentPlatforms: maxConcurrent,
+		}})
+		return handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "test song", Limit: 10})
+	}
+
+	response := search(2)
+	assert.Equal(t, 2, peak)
+	require.Len(t, response.PlatformStatus, 4)
+	for platform, status := range response.PlatformStatus {
+		assert.Equal(t, services.ErrorCategoryTimeout, status, platform)
+	}
+
+	// The default of 0 searches every platform at once
+	response = search(0)
+	assert.Equal(t, 4, peak)
+	require.Len(t, response.PlatformStatus, 4)
+}
+
+func TestSearch_PerSourceResultCaps(t *testing.T) {
+	original := config.GetRankingConfig()
+	t.Cleanup(func() { config.SetRankingConfig(original) })
+	config.SetRankingConfig(original.WithOverrides(&config.RankingConfig{
+		PlatformResultCaps: map[string]int{"spotify": 2, "tidal": 4},
+	}))
+
+	tracks := func(platform string, n int) []*services.TrackInfo {
+		result := make([]*services.TrackInfo, 0, n)
+		for i := range n {
+			id := fmt.Sprintf("%s-%d", platform, i)
+			result = append(result, testutil.NewTrackInfoBuilder().WithPlatform(platform).WithExternalID(id).WithTitle(id).WithISRC("").Build())
+		}
+		return result
+	}
+
+	repo := &testutil.MockSongRepository{}
+	spotify := testutil.NewMockPlatformService("spotify")
+	appleMusic := testutil.NewMockPlatformService("apple_music")
+	tidal := testutil.NewMockPlatformService("tidal")
+	repo.On("Search", mock.Anything, "song", mock.Anything).Return([]*models.Song{}, nil)
+	spotify.On("SearchTrack", mock.Anything, mock.Anything).Return(tracks("spotify", 5), nil)
+	appleMusic.On("SearchTrack", mock.Anything, mock.Anything).Return(tracks("apple_music", 5), nil)
+	tidal.On("SearchTrack", mock.Anything, mock.Anything).Return(tracks("tidal", 5), nil)
+
+	handler := NewSongHandler(repo, "http://localhost", spotify, appleMusic, tidal)
+	response := handler.performSearch(context.Background(), "http://localhost", SearchSongsRequest{Query: "song", Limit: 10})
+
+	require.Len(t, response.Results["spotify"], 2)
+	assert.Equal(t, "spotify-0", response.Results["spotify"][0].Title, "the source's own order is kept")
+	assert.Equal(t, "spotify-1", response.Results["spotify"][1].Title)
+	assert.Len(t, response.Results["tidal"], 4)
+	assert.Len(t, response.Results["apple_music"], 5, "uncapped sources keep every result")
+}
+
+func TestSongHandler_ApplyConfigSearchQueryStrategies(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, services.SetSearchQueryStrategy("tidal", services.SearchStrategyCombined))
+	})
+	query := services.SearchQuery{Title: "Shape of You", Artist: "Ed Sheeran", ISRC: testutil.TestISRC1}
+	handler := NewSongHandler(nil, "http://localhost", nil, nil, nil)
+
+	assert.Equal(t, "Shape of You Ed Sheeran", services.BuildSearchQuery(services.GetSearchQueryStrategy("tidal"), query))
+
+	handler.ApplyConfig(&config.Config{SearchQueryStrategies: map[string]string{"tidal": "isrc_filter"}})
+	assert.Equal(t, "isrc:"+testutil.TestISRC1, services.BuildSearchQuery(services.GetSearchQueryStrategy("tidal"), query))
+
+	// Invalid overrides are logged and leave the strategy unchanged
+	handler.ApplyConfig(&config.Config{SearchQueryStrategies: map[string]string{"tidal": "fuzzy"}})
+	assert.Equal(t, services.SearchStrategyISRCFilter, services.GetSearchQueryStrategy("tidal"))
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+)
+
+// Keys for RankingConfig.TieBreakOrder
+const (
+	tieBreakTitle     = "title"
+	tieBreakArtist    = "artist"
+	tieBreakAlbum     = "album"
+	tieBreakISRC      = "isrc"
+	tieBreakPlatforms = "platforms"
+)
+
+// defaultTieBreakOrder applies when the ranking config doesn't set one
+var defaultTieBreakOrder = []string{tieBreakTitle, tieBreakArtist, tieBreakAlbum, tieBreakISRC, tieBreakPlatforms}
+
+// compareTieBreak orders two songs that tie on score, popularity and platform
+// weight, by each key of order in turn. ISRC and then the sorted platform list
+// always decide last, so variants with identical metadata (explicit and clean,
+// album and single) sort the same however the platform searches finished.
+// Returns a negative number when a goes first.
+func compareTieBreak(a, b GroupedSong, order []string) int {
+	if len(order) == 0 {
+		order = defaultTieBreakOrder
+	}
+	for _, key := range append(slices.Clip(order), tieBreakISRC, tieBreakPlatforms) {
+		if c := strings.Compare(tieBreakValue(a, key), tieBreakValue(b, key)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// tieBreakValue is the song's value for a tie-break key; unknown keys tie
+func tieBreakValue(song GroupedSong, key string) string {
+	switch key {
+	case tieBreakTitle:
+		return song.Title
+	case tieBreakArtist:
+		return strings.Join(song.Artists, ", ")
+	case tieBreakAlbum:
+		return song.Album
+	case tieBreakISRC:
+		return song.ISRC
+	case tieBreakPlatforms:
+		platforms := make([]string, 0, len(song.Platforms))
+		for _, result := range song.Platforms {
+			platforms = append(platforms, result.Platform+":"+result.URL)
+		}
+		slices.Sort(platforms)
+		return strings.Join(platforms, ",")
+	default:
+		return ""
+	}
+}
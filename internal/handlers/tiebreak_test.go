@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"math/rand"
+	"testing"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tiedVariants returns search results for variants of one recording whose
+// metadata and scores are identical, shuffled as fan-out timing would
+func tiedVariants(rng *rand.Rand) map[string][]render.SearchResult {
+	variant := func(platform, isrc, url string) render.SearchResult {
+		return render.SearchResult{
+			Title: "Same Song", Artists: []string{"Same Artist"}, Album: "Same Album",
+			Platform: platform, ISRC: isrc, URL: url, Popularity: 50, Available: true,
+		}
+	}
+	results := map[string][]render.SearchResult{
+		"spotify": {
+			variant("spotify", testutil.TestISRC1, "https://open.spotify.com/track/explicit"),
+			variant("spotify", testutil.TestISRC2, "https://open.spotify.com/track/clean"),
+			variant("spotify", testutil.TestISRC3, "https://open.spotify.com/track/single"),
+		},
+		"apple_music": {
+			variant("apple_music", testutil.TestISRC3, "https://music.apple.com/us/song/1"),
+			variant("apple_music", testutil.TestISRC1, "https://music.apple.com/us/song/2"),
+		},
+		"tidal": {
+			variant("tidal", testutil.TestISRC2, "https://tidal.com/browse/track/3"),
+		},
+	}
+	for _, platformResults := range results {
+		rng.Shuffle(len(platformResults), func(i, j int) {
+			platformResults[i], platformResults[j] = platformResults[j], platformResults[i]
+		})
+	}
+	return results
+}
+
+func TestGroupSongs_TiedVariantsSortDeterministically(t *testing.T) {
+	handler := NewSongHandler(&testutil.MockSongRepository{}, "http://localhost", nil, nil, nil)
+	rng := rand.New(rand.NewSource(1))
+
+	order := func(groups []GroupedSong) []string {
+		isrcs := make([]string, len(groups))
+		for i, group := range groups {
+			isrcs[i] = group.ISRC
+		}
+		return isrcs
+	}
+
+	expected := order(handler.groupSongsWithRanking(tiedVariants(rng), config.DefaultRankingConfig()))
+	require.Equal(t, []string{testutil.TestISRC3, testutil.TestISRC2, testutil.TestISRC1}, expected,
+		"identical variants fall through to the ISRC")
+
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, expected, order(handler.groupSongsWithRanking(tiedVariants(rng), config.DefaultRankingConfig())))
+	}
+}
+
+func TestCompareTieBreak_ConfiguredOrder(t *testing.T) {
+	a := GroupedSong{Title: "Song", Album: "B Album", ISRC: testutil.TestISRC3}
+	b := GroupedSong{Title: "Song", Album: "A Album", ISRC: testutil.TestISRC1}
+
+	// Built-in order compares albums before ISRCs
+	assert.Positive(t, compareTieBreak(a, b, nil))
+	assert.Negative(t, compareTieBreak(a, b, []string{tieBreakISRC}))
+
+	// Unknown keys tie, leaving ISRC and platforms to decide
+	assert.Negative(t, compareTieBreak(a, b, []string{"label"}))
+	assert.Zero(t, compareTieBreak(a, a, nil))
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// groupingDurationToleranceMs is how far apart two ISRC-less results' durations
+// can be and still group as one song; remixes and live takes differ by more
+const groupingDurationToleranceMs = 3000
+
+// editionSuffix matches the edition labels platforms append to a title
+// ("Remastered 2011", "Deluxe Edition", "Single Version"), which don't make it
+// a different recording. Remixes, live and acoustic versions aren't included.
+var editionSuffix = regexp.MustCompile(`^(?:(?:\d{4} )?(?:digital(?:ly)? )?remaster(?:ed)?(?: \d{4})?(?: version)?|deluxe(?: edition| version)?|(?:single|album|lp) version|explicit(?: version)?|clean(?: version)?)$`)
+
+// titleSuffix captures a trailing "(...)", "[...]" or " - ..." part of a title
+var titleSuffix = regexp.MustCompile(`^(.+?)\s*(?:\(([^()]*)\)|\[([^\[\]]*)\]|\s-\s([^-]+))$`)
+
+// NormalizeTitleForGrouping returns the key ISRC-less results are grouped by:
+// the title lowercased with whitespace collapsed and edition suffixes such as
+// "(Remastered)", "(Deluxe)" and "- Single Version" removed, so
+// "Song (Remastered)" and "Song" group together while "Song (Remix)" doesn't
+func NormalizeTitleForGrouping(title string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(title)), " ")
+	for {
+		match := titleSuffix.FindStringSubmatch(normalized)
+		if match == nil {
+			return normalized
+		}
+		suffix := strings.TrimSpace(match[2] + match[3] + match[4])
+		if !editionSuffix.MatchString(suffix) {
+			return normalized
+		}
+		normalized = match[1]
+	}
+}
+
+// durationsMatch reports whether two track lengths are close enough to be the
+// same recording; an unknown (zero) length matches anything
+func durationsMatch(a, b int) bool {
+	if a <= 0 || b <= 0 {
+		return true
+	}
+	diff := a - b
+	return diff <= groupingDurationToleranceMs && diff >= -groupingDurationToleranceMs
+}
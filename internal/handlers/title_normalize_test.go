@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"testing"
+
+	"songshare/internal/handlers/render"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTitleForGrouping(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Lowercases and collapses whitespace", input: "  Hey   Jude ", expected: "hey jude"},
+		{name: "Strips remastered", input: "Hey Jude (Remastered)", expected: "hey jude"},
+		{name: "Strips dated remaster", input: "Hey Jude - Remastered 2015", expected: "hey jude"},
+		{name: "Strips year-first remaster", input: "Hey Jude - 2015 Remaster", expected: "hey jude"},
+		{name: "Strips deluxe", input: "Midnights (Deluxe)", expected: "midnights"},
+		{name: "Strips bracketed deluxe edition", input: "Midnights [Deluxe Edition]", expected: "midnights"},
+		{name: "Strips single version", input: "Bohemian Rhapsody - Single Version", expected: "bohemian rhapsody"},
+		{name: "Strips stacked suffixes", input: "Halo (Single Version) [Remastered]", expected: "halo"},
+		{name: "Keeps remix", input: "Halo (Remix)", expected: "halo (remix)"},
+		{name: "Keeps live", input: "Halo - Live", expected: "halo - live"},
+		{name: "Keeps featured artists", input: "Halo (feat. Someone)", expected: "halo (feat. someone)"},
+		{name: "Keeps a title that is only a suffix", input: "(Remastered)", expected: "(remastered)"},
+		{name: "Empty string", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeTitleForGrouping(tt.input))
+		})
+	}
+}
+
+func TestGroupSongsByISRC_FuzzyTitleGrouping(t *testing.T) {
+	h := &SongHandler{}
+	results := map[string][]render.SearchResult{
+		"spotify": {
+			{Title: "Song (Remastered)", Artists: []string{"Band"}, Platform: "spotify", DurationMs: 200000},
+			{Title: "Song", Artists: []string{"Band"}, Platform: "spotify", DurationMs: 320000, URL: "https://open.spotify.com/track/extended"},
+		},
+		"tidal": {
+			{Title: "Song - Single Version", Artists: []string{"Band"}, Platform: "tidal", DurationMs: 202500},
+		},
+		"apple_music": {
+			{Title: "Song", Artists: []string{"Band"}, Platform: "apple_music", DurationMs: 201000},
+		},
+	}
+
+	groups := h.groupSongsByISRC(results)
+	require.Len(t, groups, 2, "the much longer version stays a separate song")
+
+	byPlatforms := map[int]GroupedSong{}
+	for _, group := range groups {
+		byPlatforms[len(group.Platforms)] = group
+	}
+	require.Contains(t, byPlatforms, 3)
+	require.Contains(t, byPlatforms, 1)
+	assert.Equal(t, 320000, byPlatforms[1].DurationMs)
+	assert.Equal(t, "Song", byPlatforms[3].Title, "Apple Music is grouped first and names the group")
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"songshare/internal/repositories"
+)
+
+// View counting defaults; the interval matches the config default
+const (
+	defaultViewFlushInterval = 30 * time.Second
+	viewFlushTimeout         = 10 * time.Second
+)
+
+// viewCounter batches universal link views in memory, so a popular song costs
+// one write per flush instead of one per view
+type viewCounter struct {
+	mu      sync.Mutex
+	pending map[primitive.ObjectID]int64
+	flushFn func(ctx context.Context, counts map[primitive.ObjectID]int64) error
+}
+
+func newViewCounter(flush func(context.Context, map[primitive.ObjectID]int64) error) *viewCounter {
+	return &viewCounter{
+		pending: make(map[primitive.ObjectID]int64),
+		flushFn: flush,
+	}
+}
+
+// record counts one view of the song
+func (v *viewCounter) record(id primitive.ObjectID) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pending[id]++
+}
+
+// unflushed returns the song's views not yet written to the database
+func (v *viewCounter) unflushed(id primitive.ObjectID) int64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.pending[id]
+}
+
+// flush writes the pending counts and returns how many songs were updated.
+// Counts that fail to write are kept for the next flush, merged with views
+// recorded meanwhile. After a partial failure only the songs the repository
+// reports as failed are kept, so no view is counted twice.
+func (v *viewCounter) flush(ctx context.Context) (int, error) {
+	v.mu.Lock()
+	counts := v.pending
+	v.pending = make(map[primitive.ObjectID]int64)
+	v.mu.Unlock()
+
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	if err := v.flushFn(ctx, counts); err != nil {
+		failed := counts
+		var partial *repositories.ViewIncrementError
+		if errors.As(err, &partial) {
+			failed = make(map[primitive.ObjectID]int64, len(partial.Failed))
+			for _, id := range partial.Failed {
+				failed[id] = counts[id]
+			}
+		}
+		v.mu.Lock()
+		for id, count := range failed {
+			v.pending[id] += count
+		}
+		v.mu.Unlock()
+		return len(counts) - len(failed), err
+	}
+	return len(counts), nil
+}
+
+// recordView counts a human view of song; crawlers fetching link previews
+// aren't counted. Views are only counted once the view count worker runs.
+func (h *SongHandler) recordView(isBot bool, songID primitive.ObjectID) {
+	if h.views == nil || isBot || songID.IsZero() {
+		return
+	}
+	h.views.record(songID)
+}
+
+// StartViewCountWorker enables view counting on universal links and writes the
+// counts to the database every flush interval until ctx is cancelled, when
+// the remaining counts are flushed one last time
+func (h *SongHandler) StartViewCountWorker(ctx context.Context) {
+	counter := newViewCounter(h.songRepository.IncrementViews)
+	h.views = counter
+
+	ticker := time.NewTicker(h.viewFlushInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				flushCtx, cancel := context.WithTimeout(context.Background(), viewFlushTimeout)
+				if _, err := counter.flush(flushCtx); err != nil {
+					slog.Error("Failed to flush view counts on shutdown", "error", err)
+				}
+				cancel()
+				slog.Info("View count worker stopped")
+				return
+			case <-ticker.C:
+				flushCtx, cancel := context.WithTimeout(ctx, viewFlushTimeout)
+				if songs, err := counter.flush(flushCtx); err != nil {
+					slog.Warn("Failed to flush view counts, keeping them for the next flush", "error", err)
+				} else if songs > 0 {
+					slog.Debug("Flushed view counts", "songs", songs)
+				}
+				cancel()
+			}
+		}
+	}()
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+	"songshare/internal/handlers/render"
+	"songshare/internal/repositories"
+	"songshare/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const viewTestSongID = "507f1f77bcf86cd799439011"
+
+func newViewTestHandler(t *testing.T) (*SongHandler, *testutil.MockSongRepository, primitive.ObjectID) {
+	t.Helper()
+	repo := &testutil.MockSongRepository{}
+	song := testutil.NewSongBuilder().
+		WithID(viewTestSongID).
+		WithISRC(testutil.TestISRC1).
+		WithImageURL("https://example.com/art.jpg").
+		WithSpotifyLink(testutil.SpotifyTrackID1, testutil.SpotifyURL1).
+		Build()
+	song.Views = 40
+	repo.On("FindByISRC", mock.Anything, testutil.TestISRC1).Return(song, nil)
+
+	handler := NewSongHandler(repo, "https://songshare.example", nil, nil, nil)
+	handler.views = newViewCounter(repo.IncrementViews)
+	return handler, repo, song.ID
+}
+
+func TestRedirectToSong_CountsHumanViewsOnly(t *testing.T) {
+	handler, repo, songID := newViewTestHandler(t)
+
+	performSongPageRequest(t, handler, "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Safari/605.1.15", "text/html")
+	performSongPageRequest(t, handler, "facebookexternalhit/1.1", "*/*")
+	performSongPageRequest(t, handler, "Twitterbot/1.0", "*/*")
+	w := performSongPageRequest(t, handler, "curl/8.4.0", "application/json")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The response counts stored views plus those not yet flushed
+	var response render.ResolveSongResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(42), response.Song.Views)
+	assert.Equal(t, int64(2), handler.views.unflushed(songID))
+
+	repo.On("IncrementViews", mock.Anything, map[primitive.ObjectID]int64{songID: 2}).Return(nil).Once()
+	songs, err := handler.views.flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, songs)
+	assert.Zero(t, handler.views.unflushed(songID))
+
+	// Nothing pending means no write
+	songs, err = handler.views.flush(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, songs)
+	repo.AssertNumberOfCalls(t, "IncrementViews", 1)
+}
+
+func TestRedirectToSong_NoCountingWithoutWorker(t *testing.T) {
+	handler := newBotTestHandler(t)
+	w := performSongPageRequest(t, handler, "curl/8.4.0", "application/json")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, handler.views)
+}
+
+func TestViewCounter_FailedFlushKeepsCounts(t *testing.T) {
+	id := primitive.NewObjectID()
+	var flushed []map[primitive.ObjectID]int64
+	fail := true
+	counter := newViewCounter(func(ctx context.Context, counts map[primitive.ObjectID]int64) error {
+		if fail {
+			return errors.New("database unavailable")
+		}
+		flushed = append(flushed, counts)
+		return nil
+	})
+
+	counter.record(id)
+	counter.record(id)
+	_, err := counter.flush(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int64(2), counter.unflushed(id))
+
+	// Views recorded after the failure are merged into the retry
+	counter.record(id)
+	fail = false
+	songs, err := counter.flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, songs)
+	assert.Equal(t, []map[primitive.ObjectID]int64{{id: 3}}, flushed)
+	assert.Zero(t, counter.unflushed(id))
+}
+
+func TestViewCounter_PartialFailureKeepsOnlyFailedCounts(t *testing.T) {
+	stored, failed := primitive.NewObjectID(), primitive.NewObjectID()
+	counter := newViewCounter(func(ctx context.Context, counts map[primitive.ObjectID]int64) error {
+		return &repositories.ViewIncrementError{Failed: []primitive.ObjectID{failed}, Err: errors.New("document failed validation")}
+	})
+
+	counter.record(stored)
+	counter.record(failed)
+	counter.record(failed)
+	songs, err := counter.flush(context.Background())
+
+	require.Error(t, err)
+	assert.Equal(t, 1, songs)
+	assert.Zero(t, counter.unflushed(stored), "written counts aren't retried")
+	assert.Equal(t, int64(2), counter.unflushed(failed))
+}
+
+func TestStartViewCountWorker_FlushesOnShutdown(t *testing.T) {
+	repo := &testutil.MockSongRepository{}
+	handler := NewSongHandler(repo, "http://localhost", nil, nil, nil)
+	handler.ApplyConfig(&config.Config{ViewFlushInterval: time.Hour})
+	assert.Equal(t, time.Hour, handler.viewFlushInterval)
+
+	id := primitive.NewObjectID()
+	done := make(chan struct{})
+	repo.On("IncrementViews", mock.Anything, map[primitive.ObjectID]int64{id: 1}).
+		Run(func(mock.Arguments) { close(done) }).
+		Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handler.StartViewCountWorker(ctx)
+	handler.recordView(false, id)
+	handler.recordView(true, id)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending views were not flushed on shutdown")
+	}
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,76 @@
+// Package metrics defines the Prometheus metrics exposed at /metrics
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every songshare metric plus the Go runtime and process
+// collectors. It is separate from the default registry so tests and tools
+// importing this package don't share global state with libraries.
+var Registry = prometheus.NewRegistry()
+
+var (
+	platformRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "songshare_platform_requests_total",
+		Help: "Platform API requests by platform, operation and HTTP status (\"error\" when no response arrived).",
+	}, []string{"platform", "operation", "status"})
+
+	platformRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "songshare_platform_request_duration_seconds",
+		Help:    "Platform API request latency by platform and operation.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"platform", "operation"})
+
+	cacheOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "songshare_cache_operations_total",
+		Help: "Cache operations by source (the component using the cache), operation and result (hit, miss, ok or error).",
+	}, []string{"source", "operation", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		platformRequests,
+		platformRequestDuration,
+		cacheOperations,
+	)
+}
+
+// Cache sources, telling apart the components sharing the Valkey cache
+const (
+	CacheSourcePlatform       = "platform"
+	CacheSourceSongRepository = "song_repository"
+)
+
+// Cache operation results
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheOK    = "ok"
+	CacheError = "error"
+)
+
+// ObservePlatformRequest records one platform API request and its latency.
+// status is the HTTP status code, or "error" when the request failed before a
+// response arrived.
+func ObservePlatformRequest(platform, operation, status string, elapsed time.Duration) {
+	platformRequests.WithLabelValues(platform, operation, status).Inc()
+	platformRequestDuration.WithLabelValues(platform, operation).Observe(elapsed.Seconds())
+}
+
+// RecordCacheOperation counts one cache operation by its source and result
+func RecordCacheOperation(source, operation, result string) {
+	cacheOperations.WithLabelValues(source, operation, result).Inc()
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
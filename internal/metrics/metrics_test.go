@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObservePlatformRequest(t *testing.T) {
+	before := testutil.ToFloat64(platformRequests.WithLabelValues("spotify", "get_track", "200"))
+
+	ObservePlatformRequest("spotify", "get_track", "200", 300*time.Millisecond)
+	ObservePlatformRequest("spotify", "get_track", "error", 5*time.Second)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(platformRequests.WithLabelValues("spotify", "get_track", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(platformRequests.WithLabelValues("spotify", "get_track", "error")))
+	assert.Equal(t, 1, testutil.CollectAndCount(platformRequestDuration, "songshare_platform_request_duration_seconds"))
+}
+
+func TestRecordCacheOperation(t *testing.T) {
+	RecordCacheOperation(CacheSourcePlatform, "get", CacheHit)
+	RecordCacheOperation(CacheSourcePlatform, "get", CacheHit)
+	RecordCacheOperation(CacheSourceSongRepository, "get", CacheMiss)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(cacheOperations.WithLabelValues(CacheSourcePlatform, "get", CacheHit)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(cacheOperations.WithLabelValues(CacheSourceSongRepository, "get", CacheMiss)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(cacheOperations.WithLabelValues(CacheSourcePlatform, "get", CacheMiss)))
+}
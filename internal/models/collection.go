@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection is a resolved playlist or album: an ordered list of catalog songs
+// shared under a single link
+type Collection struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Kind      string             `bson:"kind" json:"kind"`         // "playlist" or "album"
+	Platform  string             `bson:"platform" json:"platform"` // Platform the source URL belongs to
+	SourceURL string             `bson:"source_url" json:"source_url"`
+	ImageURL  string             `bson:"image_url,omitempty" json:"image_url,omitempty"` // Cover art for link previews
+	Tracks    []CollectionTrack  `bson:"tracks" json:"tracks"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// CollectionTrack is one entry of a collection, in playlist order
+type CollectionTrack struct {
+	SongID primitive.ObjectID `bson:"song_id" json:"song_id"`
+	ISRC   string             `bson:"isrc,omitempty" json:"isrc,omitempty"`
+	Title  string             `bson:"title" json:"title"`
+	Artist string             `bson:"artist" json:"artist"`
+}
+
+// NewCollection creates an empty collection for a playlist or album
+func NewCollection(name, kind, platform, sourceURL string) *Collection {
+	return &Collection{
+		Name:      name,
+		Kind:      kind,
+		Platform:  platform,
+		SourceURL: sourceURL,
+		Tracks:    make([]CollectionTrack, 0),
+		CreatedAt: time.Now(),
+	}
+}
+
+// AddSong appends song to the collection. Collections without their own
+// cover borrow the first track's artwork.
+func (c *Collection) AddSong(song *Song) {
+	if c.ImageURL == "" {
+		c.ImageURL = song.Metadata.ImageURL
+	}
+	c.Tracks = append(c.Tracks, CollectionTrack{
+		SongID: song.ID,
+		ISRC:   song.ISRC,
+		Title:  song.Title,
+		Artist: song.Artist,
+	})
+}
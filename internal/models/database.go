@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -51,6 +52,11 @@ func (d *Database) Close(ctx context.Context) error {
 	return d.Client.Disconnect(ctx)
 }
 
+// Health pings the database primary
+func (d *Database) Health(ctx context.Context) error {
+	return d.Client.Ping(ctx, nil)
+}
+
 // CreateIndexes creates necessary indexes for optimal performance
 func (d *Database) CreateIndexes(ctx context.Context) error {
 	songsCollection := d.DB.Collection("songs")
@@ -63,10 +69,6 @@ func (d *Database) CreateIndexes(ctx context.Context) error {
 
 	// Create indexes
 	indexes := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "isrc", Value: 1}},
-			Options: options.Index().SetSparse(true),
-		},
 		{
 			Keys: bson.D{{Key: "title", Value: 1}, {Key: "artist", Value: 1}},
 		},
@@ -81,6 +83,13 @@ func (d *Database) CreateIndexes(ctx context.Context) error {
 			},
 			Options: options.Index().SetDefaultLanguage("english"),
 		},
+		{
+			Keys: bson.D{{Key: "search_text", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "previous_isrcs", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 		{
 			Keys: bson.D{{Key: "created_at", Value: 1}},
 		},
@@ -89,7 +98,38 @@ func (d *Database) CreateIndexes(ctx context.Context) error {
 		},
 	}
 
-	_, err = songsCollection.Indexes().CreateMany(ctx, indexes)
+	if _, err = songsCollection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return err
+	}
+	return createISRCIndex(ctx, songsCollection)
+}
+
+// isrcIndex is unique over songs with an ISRC, so concurrent resolves of one
+// recording can't store it twice. Songs stored without an ISRC hold "", which
+// a sparse index would still cover, so a partial filter leaves them out.
+func isrcIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{{Key: "isrc", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"isrc": bson.M{"$gt": ""}}),
+	}
+}
+
+// createISRCIndex creates the unique ISRC index. While stored songs still
+// share an ISRC (the consistency checker merges them), it falls back to a
+// non-unique index; the next startup tries the unique one again.
+func createISRCIndex(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, isrcIndex())
+	if err == nil || !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	slog.Warn("Songs share an ISRC, creating a non-unique ISRC index until they are merged", "error", err)
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "isrc", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
 	return err
 }
 
@@ -107,12 +147,10 @@ func (d *Database) handleIndexConflicts(ctx context.Context, collection *mongo.C
 		return err
 	}
 
-	// Check for conflicting ISRC index (unique vs non-unique)
+	// Replace a non-unique ISRC index with the unique one
 	for _, index := range existingIndexes {
 		if indexName, ok := index["name"].(string); ok && indexName == "isrc_1" {
-			// Check if it has unique constraint
-			if unique, exists := index["unique"]; exists && unique == true {
-				// Drop the conflicting unique index
+			if unique, exists := index["unique"]; !exists || unique != true {
 				_, err := collection.Indexes().DropOne(ctx, "isrc_1")
 				if err != nil {
 					return err
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidISRC is returned for strings that are not valid ISRC codes
+var ErrInvalidISRC = errors.New("invalid ISRC")
+
+// isrcPattern matches a normalized ISRC: country (2 letters), registrant
+// (3 alphanumerics), year (2 digits) and designation (5 digits)
+var isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+
+// NormalizeISRC uppercases an ISRC and strips the hyphens and spaces commonly
+// used when printing it (e.g. "us-um7-17-03861"), then validates the result
+func NormalizeISRC(raw string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(raw))
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+	if !isrcPattern.MatchString(normalized) {
+		return "", ErrInvalidISRC
+	}
+	return normalized, nil
+}
+
+// CanonicalISRC returns the stored form of an ISRC: the normalized code when
+// raw is valid, and otherwise raw trimmed and uppercased, so lookups match
+// regardless of the case a platform reported it in
+func CanonicalISRC(raw string) string {
+	if isrc, err := NormalizeISRC(raw); err == nil {
+		return isrc
+	}
+	return strings.ToUpper(strings.TrimSpace(raw))
+}
+
+// CanonicalizeISRC rewrites the song's ISRC to its canonical stored form
+func (s *Song) CanonicalizeISRC() {
+	s.ISRC = CanonicalISRC(s.ISRC)
+}
+
+// AddPreviousISRC records isrc as an alias of the song. Empty codes, the
+// song's current ISRC and aliases already recorded are ignored.
+func (s *Song) AddPreviousISRC(isrc string) {
+	isrc = CanonicalISRC(isrc)
+	if isrc == "" || isrc == s.ISRC || slices.Contains(s.PreviousISRCs, isrc) {
+		return
+	}
+	s.PreviousISRCs = append(s.PreviousISRCs, isrc)
+}
+
+// ISRCChange records one upstream correction of a song's ISRC
+type ISRCChange struct {
+	From      string    `bson:"from" json:"from"`
+	To        string    `bson:"to" json:"to"`
+	Platform  string    `bson:"platform" json:"platform"` // Platform that reported the new code
+	ChangedAt time.Time `bson:"changed_at" json:"changed_at"`
+
+	// ID of the song that held From, when the change merged it into the
+	// song already holding To
+	MergedFrom string `bson:"merged_from,omitempty" json:"merged_from,omitempty"`
+}
+
+// RecordISRCChange adds change to the song's ISRC history
+func (s *Song) RecordISRCChange(change ISRCChange) {
+	s.ISRCHistory = append(s.ISRCHistory, change)
+}
+
+// ReplaceISRC changes the song's ISRC, keeping the old code as an alias
+func (s *Song) ReplaceISRC(isrc string) {
+	old := s.ISRC
+	s.ISRC = CanonicalISRC(isrc)
+	s.PreviousISRCs = slices.DeleteFunc(s.PreviousISRCs, func(alias string) bool { return alias == s.ISRC })
+	s.AddPreviousISRC(old)
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeISRC(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "Already normalized", input: "USUM71703861", expected: "USUM71703861"},
+		{name: "Lowercase", input: "gbum71505078", expected: "GBUM71505078"},
+		{name: "Hyphenated", input: "US-UM7-17-03861", expected: "USUM71703861"},
+		{name: "Surrounding whitespace", input: "  USRC17607839 ", expected: "USRC17607839"},
+		{name: "Too short", input: "USUM7170386", wantErr: true},
+		{name: "Non-numeric designation", input: "USUM717038AB", wantErr: true},
+		{name: "Numeric country code", input: "12UM71703861", wantErr: true},
+		{name: "Empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isrc, err := NormalizeISRC(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidISRC)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, isrc)
+		})
+	}
+}
+
+func TestCanonicalISRC(t *testing.T) {
+	assert.Equal(t, "USUM71703861", CanonicalISRC("usum71703861"))
+	assert.Equal(t, "USUM71703861", CanonicalISRC("us-um7-17-03861"))
+	assert.Equal(t, "", CanonicalISRC(""))
+
+	// Invalid codes are kept, only case-folded
+	assert.Equal(t, "NOT-AN-ISRC", CanonicalISRC(" not-an-isrc "))
+
+	song := &Song{ISRC: "gbum71505078"}
+	song.CanonicalizeISRC()
+	assert.Equal(t, "GBUM71505078", song.ISRC)
+}
+
+func TestReplaceISRC_KeepsAliases(t *testing.T) {
+	song := &Song{ISRC: "USUM71703861"}
+
+	song.ReplaceISRC("usum72000001")
+	assert.Equal(t, "USUM72000001", song.ISRC)
+	assert.Equal(t, []string{"USUM71703861"}, song.PreviousISRCs)
+
+	// Changing back drops the alias that became current again
+	song.ReplaceISRC("USUM71703861")
+	assert.Equal(t, "USUM71703861", song.ISRC)
+	assert.Equal(t, []string{"USUM72000001"}, song.PreviousISRCs)
+
+	song.AddPreviousISRC("")
+	song.AddPreviousISRC("USUM71703861")
+	song.AddPreviousISRC("us-um7-20-00001")
+	assert.Equal(t, []string{"USUM72000001"}, song.PreviousISRCs)
+}
+
+func TestMergeFrom_KeepsMergedISRCsAsAliases(t *testing.T) {
+	kept := &Song{ISRC: "USUM72000001"}
+	merged := &Song{ISRC: "USUM71703861", PreviousISRCs: []string{"GBUM71505078", "USUM72000001"}}
+
+	kept.MergeFrom(merged)
+
+	assert.Equal(t, []string{"USUM71703861", "GBUM71505078"}, kept.PreviousISRCs)
+}
+
+func TestMergeFrom_KeepsISRCHistory(t *testing.T) {
+	kept := &Song{ISRC: "USUM72000001", ISRCHistory: []ISRCChange{{From: "GBUM71505078", To: "USUM72000001"}}}
+	merged := &Song{ISRC: "USUM71703861", ISRCHistory: []ISRCChange{{From: "USRC17607839", To: "USUM71703861"}}}
+
+	kept.MergeFrom(merged)
+
+	assert.Equal(t, []ISRCChange{
+		{From: "GBUM71505078", To: "USUM72000001"},
+		{From: "USRC17607839", To: "USUM71703861"},
+	}, kept.ISRCHistory)
+}
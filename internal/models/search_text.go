@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeSearchText returns a comparison form of s: lowercased, diacritics
+// and punctuation removed, "&" spelled as "and", whitespace collapsed and a
+// leading "the" dropped, so "The Beatles" and "Beatles" compare equal
+func NormalizeSearchText(s string) string {
+	folded := foldDiacritics(strings.ToLower(s))
+
+	var b strings.Builder
+	b.Grow(len(folded))
+	for _, r := range folded {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '&':
+			b.WriteString(" and ")
+		default:
+			// Punctuation and whitespace both become separators
+			b.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// foldDiacritics strips combining marks, e.g. "beyoncé" -> "beyonce"
+func foldDiacritics(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return folded
+}
+
+// UpdateSearchText recomputes the denormalized SearchText from title, artist and album
+func (s *Song) UpdateSearchText() {
+	parts := make([]string, 0, 3)
+	for _, field := range []string{s.Title, s.Artist, s.Album} {
+		if normalized := NormalizeSearchText(field); normalized != "" {
+			parts = append(parts, normalized)
+		}
+	}
+	s.SearchText = strings.Join(parts, " ")
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearchText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Beyoncé", "beyonce"},
+		{"Beyonce", "beyonce"},
+		{"Sigur Rós", "sigur ros"},
+		{"The Beatles", "beatles"},
+		{"the weeknd", "weeknd"},
+		{"The", "the"},
+		{"Simon & Garfunkel", "simon and garfunkel"},
+		{"AC/DC", "ac dc"},
+		{"  Don't   Stop  ", "don t stop"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeSearchText(tt.input))
+		})
+	}
+}
+
+func TestSong_UpdateSearchText(t *testing.T) {
+	song := NewSong("Déjà Vu", "Beyoncé")
+	assert.Equal(t, "deja vu beyonce", song.SearchText)
+
+	song.Album = "B'Day"
+	song.UpdateSearchText()
+	assert.Equal(t, "deja vu beyonce b day", song.SearchText)
+
+	// Diacritic-free queries normalize to the same words
+	assert.Contains(t, song.SearchText, NormalizeSearchText("Deja Vu"))
+	assert.Contains(t, song.SearchText, NormalizeSearchText("BEYONCE"))
+}
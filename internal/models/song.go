@@ -1,12 +1,15 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-const CurrentSchemaVersion = 1
+const CurrentSchemaVersion = 2
 
 // Song represents a song with metadata and platform links
 type Song struct {
@@ -19,12 +22,35 @@ type Song struct {
 	Artist string `bson:"artist" json:"artist"`
 	Album  string `bson:"album,omitempty" json:"album,omitempty"`
 
+	// Each artist when the platform credited several; Artist joins them with
+	// ", " and is the one to show. Empty for single-artist songs and for songs
+	// stored before the list was kept.
+	Artists []string `bson:"artists,omitempty" json:"artists,omitempty"`
+
+	// ISRCs the song was stored under before an upstream correction or a merge,
+	// so links shared with an old ISRC keep resolving
+	PreviousISRCs []string `bson:"previous_isrcs,omitempty" json:"previous_isrcs,omitempty"`
+
+	// Upstream ISRC corrections applied to the song, oldest first
+	ISRCHistory []ISRCChange `bson:"isrc_history,omitempty" json:"isrc_history,omitempty"`
+
+	// Normalized title/artist/album for diacritic-insensitive search (see UpdateSearchText)
+	SearchText string `bson:"search_text,omitempty" json:"-"`
+
 	// Platform Links (Embedded for Performance)
 	PlatformLinks []PlatformLink `bson:"platform_links" json:"platform_links"`
 
 	// Additional Metadata
 	Metadata SongMetadata `bson:"metadata" json:"metadata"`
 
+	// Retired songs had no available link when last verified; they keep their
+	// last-known metadata for the share page until a link becomes available again
+	Retired bool `bson:"retired,omitempty" json:"retired,omitempty"`
+
+	// Views counts human visits to the song's universal link. Only the
+	// repository's IncrementViews changes it; Update keeps the stored count.
+	Views int64 `bson:"views,omitempty" json:"views,omitempty"`
+
 	// Timestamps
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
@@ -32,12 +58,13 @@ type Song struct {
 
 // PlatformLink represents a link to a song on a specific music platform
 type PlatformLink struct {
-	Platform     string    `bson:"platform" json:"platform"`           // "spotify", "apple_music", etc.
-	ExternalID   string    `bson:"external_id" json:"external_id"`     // Platform-specific track ID
-	URL          string    `bson:"url" json:"url"`                     // Direct link to the song
-	Available    bool      `bson:"available" json:"available"`         // Whether the song is currently available
-	Confidence   float64   `bson:"confidence" json:"confidence"`       // Match confidence score (0-1)
-	LastVerified time.Time `bson:"last_verified" json:"last_verified"` // When this link was last checked
+	Platform     string    `bson:"platform" json:"platform"`                   // "spotify", "apple_music", etc.
+	ExternalID   string    `bson:"external_id" json:"external_id"`             // Platform-specific track ID
+	URL          string    `bson:"url" json:"url"`                             // Direct link to the song
+	Available    bool      `bson:"available" json:"available"`                 // Whether the song is currently available
+	Confidence   float64   `bson:"confidence" json:"confidence"`               // Match confidence score (0-1)
+	LastVerified time.Time `bson:"last_verified" json:"last_verified"`         // When this link was last checked
+	Primary      bool      `bson:"primary,omitempty" json:"primary,omitempty"` // Default link to open; at most one per song
 }
 
 // SongMetadata contains additional song information
@@ -48,13 +75,14 @@ type SongMetadata struct {
 	Language    string    `bson:"language,omitempty" json:"language,omitempty"`
 	Popularity  int       `bson:"popularity,omitempty" json:"popularity,omitempty"` // Platform-specific popularity score
 	Explicit    bool      `bson:"explicit,omitempty" json:"explicit,omitempty"`
-	ImageURL    string    `bson:"image_url,omitempty" json:"image_url,omitempty"` // Album art image URL
+	ImageURL    string    `bson:"image_url,omitempty" json:"image_url,omitempty"`   // Album art image URL
+	LyricsURL   string    `bson:"lyrics_url,omitempty" json:"lyrics_url,omitempty"` // Third-party lyrics page, never hosted by us
 }
 
 // NewSong creates a new Song with default values
 func NewSong(title, artist string) *Song {
 	now := time.Now()
-	return &Song{
+	song := &Song{
 		SchemaVersion: CurrentSchemaVersion,
 		Title:         title,
 		Artist:        artist,
@@ -62,10 +90,48 @@ func NewSong(title, artist string) *Song {
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
+	song.UpdateSearchText()
+	return song
+}
+
+// ErrPlatformURLMismatch is returned when a link URL doesn't belong to its platform
+var ErrPlatformURLMismatch = errors.New("platform link URL does not match platform")
+
+// PlatformURLValidator reports whether url is a valid link for platform
+type PlatformURLValidator func(platform, url string) bool
+
+var (
+	platformURLValidator   PlatformURLValidator
+	platformURLValidatorMu sync.RWMutex
+)
+
+// SetPlatformURLValidator installs the validator used by AddPlatformLink.
+// The services package registers one backed by its URL pattern registry.
+func SetPlatformURLValidator(validator PlatformURLValidator) {
+	platformURLValidatorMu.Lock()
+	defer platformURLValidatorMu.Unlock()
+	platformURLValidator = validator
+}
+
+// validatePlatformURL checks url against the installed validator, if any
+func validatePlatformURL(platform, url string) error {
+	platformURLValidatorMu.RLock()
+	validator := platformURLValidator
+	platformURLValidatorMu.RUnlock()
+
+	if validator == nil || url == "" || validator(platform, url) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s link %q", ErrPlatformURLMismatch, platform, url)
 }
 
-// AddPlatformLink adds or updates a platform link for the song
-func (s *Song) AddPlatformLink(platform, externalID, url string, confidence float64) {
+// AddPlatformLink adds or updates a platform link for the song.
+// Links whose URL doesn't match the platform are rejected with ErrPlatformURLMismatch.
+func (s *Song) AddPlatformLink(platform, externalID, url string, confidence float64) error {
+	if err := validatePlatformURL(platform, url); err != nil {
+		return err
+	}
+
 	now := time.Now()
 
 	// Check if platform link already exists
@@ -77,12 +143,13 @@ func (s *Song) AddPlatformLink(platform, externalID, url string, confidence floa
 			s.PlatformLinks[i].Available = true
 			s.PlatformLinks[i].Confidence = confidence
 			s.PlatformLinks[i].LastVerified = now
+			s.Retired = false
 			s.UpdatedAt = now
-			return
+			return nil
 		}
 	}
 
-	// Add new platform link
+	// Add new platform link; the link a song is first resolved from is its primary
 	s.PlatformLinks = append(s.PlatformLinks, PlatformLink{
 		Platform:     platform,
 		ExternalID:   externalID,
@@ -90,8 +157,27 @@ func (s *Song) AddPlatformLink(platform, externalID, url string, confidence floa
 		Available:    true,
 		Confidence:   confidence,
 		LastVerified: now,
+		Primary:      len(s.PlatformLinks) == 0,
 	})
+	s.Retired = false
 	s.UpdatedAt = now
+	return nil
+}
+
+// MarkPlatformUnavailable records that verification found the platform's link
+// gone upstream. It reports false, changing nothing, when the song has no link
+// for platform.
+func (s *Song) MarkPlatformUnavailable(platform string) bool {
+	for i, link := range s.PlatformLinks {
+		if link.Platform == platform {
+			now := time.Now()
+			s.PlatformLinks[i].Available = false
+			s.PlatformLinks[i].LastVerified = now
+			s.UpdatedAt = now
+			return true
+		}
+	}
+	return false
 }
 
 // GetPlatformLink returns the platform link for a specific platform
@@ -104,6 +190,85 @@ func (s *Song) GetPlatformLink(platform string) *PlatformLink {
 	return nil
 }
 
+// PrimaryPlatformLink returns the song's primary link, or nil if none is marked
+func (s *Song) PrimaryPlatformLink() *PlatformLink {
+	for _, link := range s.PlatformLinks {
+		if link.Primary {
+			return &link
+		}
+	}
+	return nil
+}
+
+// SetPrimaryPlatform marks the platform's link as primary and demotes the rest.
+// It reports false, changing nothing, when the song has no link for platform.
+func (s *Song) SetPrimaryPlatform(platform string) bool {
+	if !s.HasPlatform(platform) {
+		return false
+	}
+	for i := range s.PlatformLinks {
+		s.PlatformLinks[i].Primary = s.PlatformLinks[i].Platform == platform
+	}
+	return true
+}
+
+// RecomputePrimary keeps an available primary link as it is; otherwise it makes
+// the best link by preference order primary. It reports whether the primary changed.
+func (s *Song) RecomputePrimary(preferences []string) bool {
+	current := s.PrimaryPlatformLink()
+	if current != nil && current.Available {
+		return false
+	}
+	best := s.BestPlatformLink(preferences)
+	if best == nil || (current != nil && best.Platform == current.Platform) {
+		return false
+	}
+	return s.SetPrimaryPlatform(best.Platform)
+}
+
+// BestPlatformLink returns the link to open for a listener with the given
+// platform preference order. The first available preferred platform wins; when
+// none of them are available it falls back to the primary link if available,
+// then any available link, then any link at all. Returns nil when the song has no links.
+func (s *Song) BestPlatformLink(preferences []string) *PlatformLink {
+	for _, platform := range preferences {
+		if link := s.GetPlatformLink(platform); link != nil && link.Available {
+			return link
+		}
+	}
+
+	if primary := s.PrimaryPlatformLink(); primary != nil && primary.Available {
+		return primary
+	}
+
+	for _, link := range s.PlatformLinks {
+		if link.Available {
+			return &link
+		}
+	}
+
+	if len(s.PlatformLinks) > 0 {
+		link := s.PlatformLinks[0]
+		return &link
+	}
+	return nil
+}
+
+// IsVerified reports whether every platform link is a certain match (confidence
+// 1.0) that was verified within maxAge of now. Songs without links are never
+// verified. It is computed on each load rather than stored, so links age out.
+func (s *Song) IsVerified(maxAge time.Duration, now time.Time) bool {
+	if len(s.PlatformLinks) == 0 {
+		return false
+	}
+	for _, link := range s.PlatformLinks {
+		if link.Confidence < 1.0 || link.LastVerified.IsZero() || now.Sub(link.LastVerified) > maxAge {
+			return false
+		}
+	}
+	return true
+}
+
 // HasPlatform checks if the song has a link for the specified platform
 func (s *Song) HasPlatform(platform string) bool {
 	return s.GetPlatformLink(platform) != nil
@@ -119,3 +284,45 @@ func (s *Song) GetAvailablePlatforms() []string {
 	}
 	return platforms
 }
+
+// MergeFrom copies platform links the song lacks from other and fills empty
+// metadata fields. Existing links and metadata on s are never overwritten.
+// Other's ISRCs are kept as aliases of s.
+func (s *Song) MergeFrom(other *Song) {
+	s.AddPreviousISRC(other.ISRC)
+	for _, isrc := range other.PreviousISRCs {
+		s.AddPreviousISRC(isrc)
+	}
+	s.ISRCHistory = append(s.ISRCHistory, other.ISRCHistory...)
+
+	for _, link := range other.PlatformLinks {
+		if !s.HasPlatform(link.Platform) {
+			// s keeps its own primary
+			link.Primary = false
+			s.PlatformLinks = append(s.PlatformLinks, link)
+		}
+	}
+
+	if s.Album == "" {
+		s.Album = other.Album
+	}
+	if s.Metadata.ImageURL == "" {
+		s.Metadata.ImageURL = other.Metadata.ImageURL
+	}
+	if s.Metadata.LyricsURL == "" {
+		s.Metadata.LyricsURL = other.Metadata.LyricsURL
+	}
+	if s.Metadata.Duration == 0 {
+		s.Metadata.Duration = other.Metadata.Duration
+	}
+	if s.Metadata.ReleaseDate.IsZero() {
+		s.Metadata.ReleaseDate = other.Metadata.ReleaseDate
+	}
+	if len(s.Metadata.Genre) == 0 {
+		s.Metadata.Genre = other.Metadata.Genre
+	}
+	if s.Metadata.Popularity < other.Metadata.Popularity {
+		s.Metadata.Popularity = other.Metadata.Popularity
+	}
+	s.UpdatedAt = time.Now()
+}
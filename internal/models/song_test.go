@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -112,6 +113,22 @@ func TestSong_HasPlatform(t *testing.T) {
 	assert.False(t, song.HasPlatform("youtube"))
 }
 
+func TestSong_MarkPlatformUnavailable(t *testing.T) {
+	song := NewSong("Test Song", "Test Artist")
+	require.NoError(t, song.AddPlatformLink("spotify", "123", "https://open.spotify.com/track/123", 1.0))
+
+	assert.False(t, song.MarkPlatformUnavailable("tidal"))
+	assert.True(t, song.MarkPlatformUnavailable("spotify"))
+	assert.False(t, song.GetPlatformLink("spotify").Available)
+	assert.Empty(t, song.GetAvailablePlatforms())
+
+	// A link verified available again brings a retired song back
+	song.Retired = true
+	require.NoError(t, song.AddPlatformLink("spotify", "123", "https://open.spotify.com/track/123", 1.0))
+	assert.True(t, song.GetPlatformLink("spotify").Available)
+	assert.False(t, song.Retired)
+}
+
 func TestSong_GetAvailablePlatforms(t *testing.T) {
 	song := NewSong("Test Song", "Test Artist")
 
@@ -142,7 +159,7 @@ func TestSong_SchemaVersion(t *testing.T) {
 	assert.Equal(t, CurrentSchemaVersion, song.SchemaVersion)
 
 	// Verify that CurrentSchemaVersion is set to expected value
-	assert.Equal(t, 1, CurrentSchemaVersion)
+	assert.Equal(t, 2, CurrentSchemaVersion)
 }
 
 func TestPlatformLink_DefaultValues(t *testing.T) {
@@ -205,3 +222,211 @@ func TestSong_LongValues(t *testing.T) {
 	assert.Equal(t, longTitle, song.Title)
 	assert.Equal(t, longArtist, song.Artist)
 }
+
+func TestSong_AddPlatformLink_RejectsMismatchedURL(t *testing.T) {
+	SetPlatformURLValidator(func(platform, url string) bool {
+		return platform != "apple_music" || strings.Contains(url, "music.apple.com")
+	})
+	defer SetPlatformURLValidator(nil)
+
+	song := NewSong("Test Song", "Test Artist")
+
+	err := song.AddPlatformLink("apple_music", "track123", "https://open.spotify.com/track/track123", 1.0)
+	assert.ErrorIs(t, err, ErrPlatformURLMismatch)
+	assert.False(t, song.HasPlatform("apple_music"))
+
+	err = song.AddPlatformLink("apple_music", "456789", "https://music.apple.com/us/song/test-song/456789", 1.0)
+	assert.NoError(t, err)
+	assert.True(t, song.HasPlatform("apple_music"))
+}
+
+func TestSong_MergeFrom(t *testing.T) {
+	target := NewSong("Test Song", "Test Artist")
+	require.NoError(t, target.AddPlatformLink("apple_music", "am1", "", 1.0))
+	target.Metadata.ImageURL = "https://example.com/target.jpg"
+
+	source := NewSong("Test Song", "Test Artist")
+	source.Album = "Test Album"
+	source.Metadata.ImageURL = "https://example.com/source.jpg"
+	source.Metadata.Popularity = 70
+	require.NoError(t, source.AddPlatformLink("spotify", "sp1", "", 1.0))
+	require.NoError(t, source.AddPlatformLink("apple_music", "am2", "", 0.5))
+
+	target.MergeFrom(source)
+
+	assert.Len(t, target.PlatformLinks, 2)
+	assert.Equal(t, "am1", target.GetPlatformLink("apple_music").ExternalID)
+	assert.Equal(t, "sp1", target.GetPlatformLink("spotify").ExternalID)
+	assert.Equal(t, "Test Album", target.Album)
+	assert.Equal(t, "https://example.com/target.jpg", target.Metadata.ImageURL)
+	assert.Equal(t, 70, target.Metadata.Popularity)
+}
+
+func TestSong_BestPlatformLink(t *testing.T) {
+	newSong := func() *Song {
+		song := NewSong("Test Song", "Test Artist")
+		song.AddPlatformLink("spotify", "sp1", "https://open.spotify.com/track/sp1", 1.0)
+		song.AddPlatformLink("apple_music", "am1", "https://music.apple.com/us/song/am1", 1.0)
+		song.AddPlatformLink("tidal", "td1", "https://tidal.com/browse/track/td1", 1.0)
+		return song
+	}
+
+	tests := []struct {
+		name        string
+		preferences []string
+		unavailable []string
+		expected    string
+	}{
+		{name: "First preference available", preferences: []string{"tidal", "spotify"}, expected: "tidal"},
+		{name: "Skips unavailable preference", preferences: []string{"tidal", "spotify"}, unavailable: []string{"tidal"}, expected: "spotify"},
+		{name: "Skips missing preference", preferences: []string{"deezer", "apple_music"}, expected: "apple_music"},
+		{name: "Falls back to any available", preferences: []string{"deezer", "tidal"}, unavailable: []string{"tidal"}, expected: "spotify"},
+		{name: "No preferences", expected: "spotify"},
+		{name: "Falls back to unavailable link", preferences: []string{"tidal"}, unavailable: []string{"spotify", "apple_music", "tidal"}, expected: "spotify"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			song := newSong()
+			for i := range song.PlatformLinks {
+				for _, platform := range tt.unavailable {
+					if song.PlatformLinks[i].Platform == platform {
+						song.PlatformLinks[i].Available = false
+					}
+				}
+			}
+
+			link := song.BestPlatformLink(tt.preferences)
+			require.NotNil(t, link)
+			assert.Equal(t, tt.expected, link.Platform)
+		})
+	}
+}
+
+func TestSong_BestPlatformLink_NoLinks(t *testing.T) {
+	song := NewSong("Test Song", "Test Artist")
+	assert.Nil(t, song.BestPlatformLink([]string{"spotify"}))
+}
+
+func TestSong_IsVerified(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	maxAge := 30 * 24 * time.Hour
+	link := func(platform string, confidence float64, age time.Duration) PlatformLink {
+		return PlatformLink{Platform: platform, Available: true, Confidence: confidence, LastVerified: now.Add(-age)}
+	}
+
+	tests := []struct {
+		name     string
+		links    []PlatformLink
+		expected bool
+	}{
+		{
+			name:     "All links certain and recently verified",
+			links:    []PlatformLink{link("spotify", 1.0, time.Hour), link("apple_music", 1.0, 29*24*time.Hour)},
+			expected: true,
+		},
+		{
+			name:     "One link stale",
+			links:    []PlatformLink{link("spotify", 1.0, time.Hour), link("apple_music", 1.0, 31*24*time.Hour)},
+			expected: false,
+		},
+		{
+			name:     "One link low confidence",
+			links:    []PlatformLink{link("spotify", 1.0, time.Hour), link("apple_music", 0.85, time.Hour)},
+			expected: false,
+		},
+		{
+			name:     "Link never verified",
+			links:    []PlatformLink{{Platform: "spotify", Available: true, Confidence: 1.0}},
+			expected: false,
+		},
+		{
+			name:     "No links",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			song := NewSong("Test Song", "Test Artist")
+			song.PlatformLinks = tt.links
+			assert.Equal(t, tt.expected, song.IsVerified(maxAge, now))
+		})
+	}
+}
+
+func TestSong_PrimaryPlatformLink(t *testing.T) {
+	song := NewSong("Test Song", "Test Artist")
+	assert.Nil(t, song.PrimaryPlatformLink())
+
+	require.NoError(t, song.AddPlatformLink("spotify", "sp1", "", 1.0))
+	require.NoError(t, song.AddPlatformLink("apple_music", "am1", "", 1.0))
+
+	primary := song.PrimaryPlatformLink()
+	require.NotNil(t, primary)
+	assert.Equal(t, "spotify", primary.Platform)
+	assert.False(t, song.GetPlatformLink("apple_music").Primary)
+
+	// Re-adding the primary platform doesn't move it
+	require.NoError(t, song.AddPlatformLink("apple_music", "am2", "", 1.0))
+	assert.Equal(t, "spotify", song.PrimaryPlatformLink().Platform)
+}
+
+func TestSong_SetPrimaryPlatform(t *testing.T) {
+	song := NewSong("Test Song", "Test Artist")
+	require.NoError(t, song.AddPlatformLink("spotify", "sp1", "", 1.0))
+	require.NoError(t, song.AddPlatformLink("apple_music", "am1", "", 1.0))
+
+	assert.True(t, song.SetPrimaryPlatform("apple_music"))
+	assert.Equal(t, "apple_music", song.PrimaryPlatformLink().Platform)
+	assert.False(t, song.GetPlatformLink("spotify").Primary)
+
+	assert.False(t, song.SetPrimaryPlatform("tidal"))
+	assert.Equal(t, "apple_music", song.PrimaryPlatformLink().Platform)
+}
+
+func TestSong_RecomputePrimary(t *testing.T) {
+	newSong := func() *Song {
+		song := NewSong("Test Song", "Test Artist")
+		song.AddPlatformLink("spotify", "sp1", "", 1.0)
+		song.AddPlatformLink("apple_music", "am1", "", 1.0)
+		song.AddPlatformLink("tidal", "td1", "", 1.0)
+		return song
+	}
+
+	t.Run("Keeps available primary", func(t *testing.T) {
+		song := newSong()
+		assert.False(t, song.RecomputePrimary([]string{"apple_music"}))
+		assert.Equal(t, "spotify", song.PrimaryPlatformLink().Platform)
+	})
+
+	t.Run("Assigns by preference when none is marked", func(t *testing.T) {
+		song := newSong()
+		for i := range song.PlatformLinks {
+			song.PlatformLinks[i].Primary = false
+		}
+		assert.True(t, song.RecomputePrimary([]string{"tidal", "apple_music"}))
+		assert.Equal(t, "tidal", song.PrimaryPlatformLink().Platform)
+	})
+
+	t.Run("Replaces unavailable primary", func(t *testing.T) {
+		song := newSong()
+		song.PlatformLinks[0].Available = false
+		assert.True(t, song.RecomputePrimary([]string{"apple_music"}))
+		assert.Equal(t, "apple_music", song.PrimaryPlatformLink().Platform)
+		assert.False(t, song.GetPlatformLink("spotify").Primary)
+	})
+}
+
+func TestSong_MergeFrom_KeepsTargetPrimary(t *testing.T) {
+	target := NewSong("Test Song", "Test Artist")
+	require.NoError(t, target.AddPlatformLink("apple_music", "am1", "", 1.0))
+
+	source := NewSong("Test Song", "Test Artist")
+	require.NoError(t, source.AddPlatformLink("spotify", "sp1", "", 1.0))
+
+	target.MergeFrom(source)
+
+	assert.Equal(t, "apple_music", target.PrimaryPlatformLink().Platform)
+	assert.False(t, target.GetPlatformLink("spotify").Primary)
+}
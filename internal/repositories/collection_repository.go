@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"songshare/internal/models"
+)
+
+// CollectionRepository defines the interface for collection data operations
+type CollectionRepository interface {
+	Save(ctx context.Context, collection *models.Collection) error
+	FindByID(ctx context.Context, id string) (*models.Collection, error)
+}
+
+// mongoCollectionRepository implements CollectionRepository using MongoDB
+type mongoCollectionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoCollectionRepository creates a new MongoDB-backed collection repository
+func NewMongoCollectionRepository(db *models.Database) CollectionRepository {
+	return &mongoCollectionRepository{
+		collection: db.DB.Collection("collections"),
+	}
+}
+
+// Save inserts a new collection and sets its ID
+func (r *mongoCollectionRepository) Save(ctx context.Context, collection *models.Collection) error {
+	result, err := r.collection.InsertOne(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("failed to insert collection: %w", err)
+	}
+	collection.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID finds a collection by its ObjectID, returning nil when it doesn't exist
+func (r *mongoCollectionRepository) FindByID(ctx context.Context, id string) (*models.Collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object ID: %w", err)
+	}
+
+	var collection models.Collection
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&collection); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find collection: %w", err)
+	}
+	return &collection, nil
+}
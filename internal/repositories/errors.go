@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"songshare/internal/models"
+)
+
+// ErrDuplicateSong is returned when a write conflicts with a unique index
+var ErrDuplicateSong = errors.New("song already exists")
+
+// DuplicateSongError describes a duplicate-key conflict on save. ISRC is set
+// when the conflicting song carried one, so callers can fetch the existing record.
+type DuplicateSongError struct {
+	ID   string
+	ISRC string
+	Err  error
+}
+
+func (e *DuplicateSongError) Error() string {
+	msg := ErrDuplicateSong.Error()
+	if e.ISRC != "" {
+		msg += fmt.Sprintf(" (ISRC: %s)", e.ISRC)
+	} else if e.ID != "" {
+		msg += fmt.Sprintf(" (ID: %s)", e.ID)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *DuplicateSongError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrDuplicateSong
+func (e *DuplicateSongError) Is(target error) bool {
+	return target == ErrDuplicateSong
+}
+
+// classifyWriteError converts duplicate-key errors into a DuplicateSongError
+// and returns nil if err is not a duplicate-key error
+func classifyWriteError(err error, song *models.Song) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	dupErr := &DuplicateSongError{Err: err}
+	if song != nil {
+		dupErr.ISRC = song.ISRC
+		if !song.ID.IsZero() {
+			dupErr.ID = song.ID.Hex()
+		}
+	}
+	return dupErr
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"songshare/internal/models"
+)
+
+func TestClassifyWriteError(t *testing.T) {
+	song := models.NewSong("Test Song", "Test Artist")
+	song.ISRC = "USUM71703861"
+
+	t.Run("Duplicate key error", func(t *testing.T) {
+		writeErr := mongo.WriteException{
+			WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}},
+		}
+
+		err := classifyWriteError(writeErr, song)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrDuplicateSong))
+
+		var dupErr *DuplicateSongError
+		require.True(t, errors.As(err, &dupErr))
+		assert.Equal(t, "USUM71703861", dupErr.ISRC)
+		assert.Empty(t, dupErr.ID)
+		assert.Contains(t, err.Error(), "USUM71703861")
+	})
+
+	t.Run("Other write error", func(t *testing.T) {
+		writeErr := mongo.WriteException{
+			WriteErrors: mongo.WriteErrors{{Code: 121, Message: "Document failed validation"}},
+		}
+
+		assert.NoError(t, classifyWriteError(writeErr, song))
+	})
+
+	t.Run("Non-mongo error", func(t *testing.T) {
+		assert.NoError(t, classifyWriteError(errors.New("network down"), song))
+	})
+}
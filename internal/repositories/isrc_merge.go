@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"songshare/internal/models"
+)
+
+// songStore is the storage saveMergingISRC writes through
+type songStore interface {
+	insertSong(ctx context.Context, song *models.Song) error
+	FindByISRC(ctx context.Context, isrc string) (*models.Song, error)
+	replaceSong(ctx context.Context, song *models.Song) error
+}
+
+// saveMergingISRC inserts a new song. When the unique ISRC index rejects it
+// because a concurrent resolve stored the same recording first, song's
+// platform links are merged into the stored song instead, and song becomes
+// the stored one.
+func saveMergingISRC(ctx context.Context, store songStore, song *models.Song) error {
+	err := store.insertSong(ctx, song)
+	var dupErr *DuplicateSongError
+	if err == nil || !errors.As(err, &dupErr) || dupErr.ISRC == "" {
+		return err
+	}
+
+	stored, findErr := store.FindByISRC(ctx, dupErr.ISRC)
+	if findErr != nil || stored == nil {
+		// Nothing to merge into; callers see the duplicate as before
+		return err
+	}
+
+	mergePlatformLinks(stored, song)
+	if err := store.replaceSong(ctx, stored); err != nil {
+		return fmt.Errorf("failed to merge duplicate song: %w", err)
+	}
+	slog.Info("Merged duplicate song into existing ISRC", "isrc", stored.ISRC, "song_id", stored.ID.Hex())
+	*song = *stored
+	return nil
+}
+
+// mergePlatformLinks adds from's platform links to into. Where both have a
+// link for the same platform, the higher-confidence link is kept.
+func mergePlatformLinks(into, from *models.Song) {
+	for _, link := range from.PlatformLinks {
+		if existing := into.GetPlatformLink(link.Platform); existing != nil && existing.Confidence >= link.Confidence {
+			continue
+		}
+		if err := into.AddPlatformLink(link.Platform, link.ExternalID, link.URL, link.Confidence); err != nil {
+			slog.Warn("Rejected platform link while merging duplicate ISRC", "platform", link.Platform, "isrc", into.ISRC, "error", err)
+		}
+	}
+}
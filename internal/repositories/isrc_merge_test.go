@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"songshare/internal/models"
+)
+
+// memorySongStore enforces a unique ISRC like the songs collection's index
+type memorySongStore struct {
+	mu     sync.Mutex
+	byISRC map[string]models.Song
+}
+
+func (s *memorySongStore) insertSong(_ context.Context, song *models.Song) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.byISRC[song.ISRC]; exists {
+		return &DuplicateSongError{ISRC: song.ISRC}
+	}
+	song.ID = primitive.NewObjectID()
+	s.byISRC[song.ISRC] = *song
+	return nil
+}
+
+func (s *memorySongStore) FindByISRC(_ context.Context, isrc string) (*models.Song, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	song, exists := s.byISRC[isrc]
+	if !exists {
+		return nil, nil
+	}
+	song.PlatformLinks = append([]models.PlatformLink(nil), song.PlatformLinks...)
+	return &song, nil
+}
+
+func (s *memorySongStore) replaceSong(_ context.Context, song *models.Song) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byISRC[song.ISRC] = *song
+	return nil
+}
+
+func newLinkedSong(t *testing.T, platform, externalID, url string, confidence float64) *models.Song {
+	t.Helper()
+	song := models.NewSong("Bohemian Rhapsody", "Queen")
+	song.ISRC = "GBUM71029604"
+	require.NoError(t, song.AddPlatformLink(platform, externalID, url, confidence))
+	return song
+}
+
+func TestSaveMergingISRC_ConcurrentSaves(t *testing.T) {
+	store := &memorySongStore{byISRC: map[string]models.Song{}}
+	songs := []*models.Song{
+		newLinkedSong(t, "spotify", "4u7EnebtmKWzUH433cf5Qv", "https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv", 1.0),
+		newLinkedSong(t, "apple_music", "1440806041", "https://music.apple.com/us/song/1440806041", 1.0),
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, len(songs))
+	for i, song := range songs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = saveMergingISRC(context.Background(), store, song)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.Len(t, store.byISRC, 1)
+
+	stored := store.byISRC["GBUM71029604"]
+	assert.True(t, stored.HasPlatform("spotify"))
+	assert.True(t, stored.HasPlatform("apple_music"))
+	assert.Equal(t, stored.ID, songs[0].ID)
+	assert.Equal(t, stored.ID, songs[1].ID)
+}
+
+func TestMergePlatformLinks_KeepsHigherConfidence(t *testing.T) {
+	stored := newLinkedSong(t, "spotify", "stored", "https://open.spotify.com/track/stored", 0.9)
+	require.NoError(t, stored.AddPlatformLink("tidal", "77646168", "https://tidal.com/browse/track/77646168", 0.6))
+
+	incoming := newLinkedSong(t, "spotify", "incoming", "https://open.spotify.com/track/incoming", 0.7)
+	require.NoError(t, incoming.AddPlatformLink("tidal", "77646169", "https://tidal.com/browse/track/77646169", 0.95))
+	require.NoError(t, incoming.AddPlatformLink("apple_music", "1440806041", "https://music.apple.com/us/song/1440806041", 0.8))
+
+	mergePlatformLinks(stored, incoming)
+
+	require.Len(t, stored.PlatformLinks, 3)
+	assert.Equal(t, "stored", stored.GetPlatformLink("spotify").ExternalID)
+	assert.Equal(t, "77646169", stored.GetPlatformLink("tidal").ExternalID)
+	assert.Equal(t, 0.95, stored.GetPlatformLink("tidal").Confidence)
+	assert.Equal(t, "1440806041", stored.GetPlatformLink("apple_music").ExternalID)
+	assert.Equal(t, "stored", stored.PrimaryPlatformLink().ExternalID)
+}
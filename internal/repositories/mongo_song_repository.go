@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,6 +15,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"songshare/internal/cache"
+	"songshare/internal/metrics"
 	"songshare/internal/models"
 )
 
@@ -40,11 +42,12 @@ func NewMongoSongRepository(db *models.Database) SongRepository {
 	}
 }
 
-// NewCachedMongoSongRepository creates a new MongoDB-backed song repository with caching
-func NewCachedMongoSongRepository(db *models.Database, cache cache.Cache) SongRepository {
+// NewCachedMongoSongRepository creates a new MongoDB-backed song repository with
+// caching; its cache use is counted under the song_repository metrics source
+func NewCachedMongoSongRepository(db *models.Database, c cache.Cache) SongRepository {
 	return &mongoSongRepository{
 		collection: db.DB.Collection("songs"),
-		cache:      cache,
+		cache:      cache.Instrument(c, metrics.CacheSourceSongRepository),
 	}
 }
 
@@ -52,26 +55,49 @@ func NewCachedMongoSongRepository(db *models.Database, cache cache.Cache) SongRe
 func (r *mongoSongRepository) Save(ctx context.Context, song *models.Song) error {
 	song.SchemaVersion = models.CurrentSchemaVersion
 	song.UpdatedAt = time.Now()
+	song.UpdateSearchText()
+	song.CanonicalizeISRC()
 
 	if song.ID.IsZero() {
-		// New song
+		// New song; a concurrent save of the same ISRC is merged, not duplicated
 		song.CreatedAt = time.Now()
-		result, err := r.collection.InsertOne(ctx, song)
-		if err != nil {
-			return fmt.Errorf("failed to insert song: %w", err)
+		return saveMergingISRC(ctx, r, song)
+	}
+
+	return r.replaceSong(ctx, song)
+}
+
+// insertSong inserts a new song, assigning its ID
+func (r *mongoSongRepository) insertSong(ctx context.Context, song *models.Song) error {
+	result, err := r.collection.InsertOne(ctx, song)
+	if err != nil {
+		if dupErr := classifyWriteError(err, song); dupErr != nil {
+			return dupErr
 		}
-		song.ID = result.InsertedID.(primitive.ObjectID)
-		return nil
+		return fmt.Errorf("failed to insert song: %w", err)
 	}
+	song.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
 
-	// Update existing song
-	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": song.ID}, song)
+// replaceSong overwrites a stored song, keeping its stored view count, and
+// invalidates its cache entries
+func (r *mongoSongRepository) replaceSong(ctx context.Context, song *models.Song) error {
+	stored := r.storedSong(ctx, song.ID)
+	doc, err := bson.Marshal(song)
+	if err != nil {
+		return fmt.Errorf("failed to encode song: %w", err)
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": song.ID}, replaceKeepingViews(doc))
 	if err != nil {
+		if dupErr := classifyWriteError(err, song); dupErr != nil {
+			return dupErr
+		}
 		return fmt.Errorf("failed to update song: %w", err)
 	}
 	
 	// Invalidate cache if enabled
-	r.invalidateCache(ctx, song)
+	r.invalidateCache(ctx, song, stored)
 	
 	return nil
 }
@@ -84,11 +110,24 @@ func (r *mongoSongRepository) Update(ctx context.Context, song *models.Song) err
 
 	song.UpdatedAt = time.Now()
 	song.SchemaVersion = models.CurrentSchemaVersion
+	song.UpdateSearchText()
+	song.CanonicalizeISRC()
+
+	// Keys of the stored version go stale too when the ISRC or links change
+	stored := r.storedSong(ctx, song.ID)
 
-	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": song.ID}, song)
+	// Views are counted concurrently with edits, so the possibly stale count
+	// on song is never written back
+	doc, err := bson.Marshal(song)
+	if err != nil {
+		return fmt.Errorf("failed to encode song: %w", err)
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": song.ID}, replaceKeepingViews(doc))
 	if err != nil {
 		return fmt.Errorf("failed to update song: %w", err)
 	}
+
+	r.invalidateCache(ctx, song, stored)
 	return nil
 }
 
@@ -126,10 +165,10 @@ func (r *mongoSongRepository) FindByID(ctx context.Context, id string) (*models.
 	return &song, nil
 }
 
-// FindByISRC finds a song by its ISRC code
+// FindByISRC finds a song by its ISRC code, in any case or hyphenation
 func (r *mongoSongRepository) FindByISRC(ctx context.Context, isrc string) (*models.Song, error) {
 	var song models.Song
-	err := r.collection.FindOne(ctx, bson.M{"isrc": isrc}).Decode(&song)
+	err := r.collection.FindOne(ctx, bson.M{"isrc": models.CanonicalISRC(isrc)}).Decode(&song)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -141,23 +180,32 @@ func (r *mongoSongRepository) FindByISRC(ctx context.Context, isrc string) (*mod
 	return &song, nil
 }
 
-// FindByISRCBatch finds multiple songs by their ISRC codes in a single query
+// FindByPreviousISRC finds the song that replaced isrc, after an upstream ISRC
+// correction or a merge
+func (r *mongoSongRepository) FindByPreviousISRC(ctx context.Context, isrc string) (*models.Song, error) {
+	var song models.Song
+	err := r.collection.FindOne(ctx, bson.M{"previous_isrcs": models.CanonicalISRC(isrc)}).Decode(&song)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find song by previous ISRC: %w", err)
+	}
+
+	r.handleSchemaEvolution(&song)
+	return &song, nil
+}
+
+// FindByISRCBatch finds multiple songs by their ISRC codes in a single query.
+// The result is keyed by the ISRCs as requested, whatever their case.
 func (r *mongoSongRepository) FindByISRCBatch(ctx context.Context, isrcs []string) (map[string]*models.Song, error) {
 	if len(isrcs) == 0 {
 		return make(map[string]*models.Song), nil
 	}
 
-	// Remove duplicates and empty strings
-	uniqueISRCs := make(map[string]bool)
-	for _, isrc := range isrcs {
-		if isrc != "" {
-			uniqueISRCs[isrc] = true
-		}
-	}
-
-	// Convert to slice
-	isrcList := make([]string, 0, len(uniqueISRCs))
-	for isrc := range uniqueISRCs {
+	requested := canonicalISRCLookups(isrcs)
+	isrcList := make([]string, 0, len(requested))
+	for isrc := range requested {
 		isrcList = append(isrcList, isrc)
 	}
 
@@ -178,7 +226,9 @@ func (r *mongoSongRepository) FindByISRCBatch(ctx context.Context, isrcs []strin
 			continue
 		}
 		r.handleSchemaEvolution(&song)
-		result[song.ISRC] = &song
+		for _, key := range requested[models.CanonicalISRC(song.ISRC)] {
+			result[key] = &song
+		}
 	}
 
 	if err := cursor.Err(); err != nil {
@@ -243,7 +293,9 @@ func (r *mongoSongRepository) FindByPlatformID(ctx context.Context, platform, ex
 	return &song, nil
 }
 
-// Search performs full-text search on songs
+// Search performs full-text search on songs. Results from $text are topped up
+// with matches on the normalized search_text field, which catches queries that
+// differ only in diacritics or a leading "The" (e.g. "Beyonce" vs "Beyoncé").
 func (r *mongoSongRepository) Search(ctx context.Context, query string, limit int) ([]*models.Song, error) {
 	filter := bson.M{
 		"$text": bson.M{
@@ -255,10 +307,62 @@ func (r *mongoSongRepository) Search(ctx context.Context, query string, limit in
 		SetLimit(int64(limit)).
 		SetSort(bson.M{"score": bson.M{"$meta": "textScore"}})
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	songs, err := r.findSongs(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search songs: %w", err)
 	}
+
+	if len(songs) >= limit {
+		return songs, nil
+	}
+
+	textFilter := searchTextFilter(query)
+	if textFilter == nil {
+		return songs, nil
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(songs))
+	for _, song := range songs {
+		seen[song.ID] = true
+	}
+
+	extra, err := r.findSongs(ctx, textFilter, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		slog.Error("Normalized search failed", "query", query, "error", err)
+		return songs, nil
+	}
+	for _, song := range extra {
+		if len(songs) >= limit {
+			break
+		}
+		if !seen[song.ID] {
+			seen[song.ID] = true
+			songs = append(songs, song)
+		}
+	}
+
+	return songs, nil
+}
+
+// searchTextFilter matches songs whose search_text starts with the normalized
+// query, so "beyonce" finds "Beyoncé" and "beatles" finds "The Beatles". The
+// anchored regex is a range scan on the search_text index; matching words
+// anywhere, in any order, is left to the $text search. Returns nil for queries
+// with no searchable words.
+func searchTextFilter(query string) bson.M {
+	normalized := models.NormalizeSearchText(query)
+	if normalized == "" {
+		return nil
+	}
+	return bson.M{"search_text": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(normalized)}}
+}
+
+// findSongs runs a find query and decodes the results, applying schema evolution
+func (r *mongoSongRepository) findSongs(ctx context.Context, filter interface{}, opts *options.FindOptions) ([]*models.Song, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
 	defer cursor.Close(ctx)
 
 	var songs []*models.Song
@@ -385,9 +489,15 @@ func (r *mongoSongRepository) SaveMany(ctx context.Context, songs []*models.Song
 	for i, song := range songs {
 		song.SchemaVersion = models.CurrentSchemaVersion
 		song.UpdatedAt = now
+		song.UpdateSearchText()
+		song.CanonicalizeISRC()
 		if song.CreatedAt.IsZero() {
 			song.CreatedAt = now
 		}
+		// Assign IDs up front so callers can link to the inserted songs
+		if song.ID.IsZero() {
+			song.ID = primitive.NewObjectID()
+		}
 		docs[i] = song
 	}
 
@@ -406,6 +516,9 @@ func (r *mongoSongRepository) DeleteByID(ctx context.Context, id string) error {
 		return fmt.Errorf("invalid object ID: %w", err)
 	}
 
+	// Load the song first so its ISRC and platform keys can be invalidated
+	stored := r.storedSong(ctx, objectID)
+
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
 	if err != nil {
 		return fmt.Errorf("failed to delete song: %w", err)
@@ -415,6 +528,9 @@ func (r *mongoSongRepository) DeleteByID(ctx context.Context, id string) error {
 		return fmt.Errorf("song not found")
 	}
 
+	// The ID key is dropped even if the stored song couldn't be loaded
+	r.invalidateCache(ctx, stored, &models.Song{ID: objectID})
+
 	return nil
 }
 
@@ -427,6 +543,196 @@ func (r *mongoSongRepository) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// FindDuplicateISRCs returns songs grouped by each non-empty ISRC that more than one document shares
+func (r *mongoSongRepository) FindDuplicateISRCs(ctx context.Context) (map[string][]*models.Song, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"isrc": bson.M{"$nin": bson.A{"", nil}}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$isrc"}, {Key: "count", Value: bson.M{"$sum": 1}}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate duplicate ISRCs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		ISRC string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode duplicate ISRCs: %w", err)
+	}
+
+	duplicates := make(map[string][]*models.Song, len(groups))
+	if len(groups) == 0 {
+		return duplicates, nil
+	}
+
+	isrcs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		isrcs = append(isrcs, group.ISRC)
+	}
+
+	songs, err := r.findSongs(ctx, bson.M{"isrc": bson.M{"$in": isrcs}}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate ISRC songs: %w", err)
+	}
+	for _, song := range songs {
+		duplicates[song.ISRC] = append(duplicates[song.ISRC], song)
+	}
+
+	return duplicates, nil
+}
+
+// NormalizeISRCs rewrites stored ISRCs that aren't in canonical form (lowercase,
+// hyphenated, padded) and returns how many songs were updated. A song whose
+// canonical ISRC another song already holds is rejected by the unique ISRC
+// index; it is skipped and counted, and left for the consistency checker to
+// report or merge.
+func (r *mongoSongRepository) NormalizeISRCs(ctx context.Context) (updated, skipped int64, err error) {
+	songs, err := r.findSongs(ctx, nonCanonicalISRCFilter(), options.Find())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find non-canonical ISRCs: %w", err)
+	}
+
+	return normalizeISRCs(ctx, songs, func(ctx context.Context, id primitive.ObjectID, isrc string) error {
+		_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"isrc": isrc, "updated_at": time.Now()}})
+		return err
+	})
+}
+
+// normalizeISRCs sets each song's canonical ISRC through setISRC, skipping
+// songs whose update hits a duplicate key
+func normalizeISRCs(ctx context.Context, songs []*models.Song, setISRC func(ctx context.Context, id primitive.ObjectID, isrc string) error) (updated, skipped int64, err error) {
+	for _, song := range songs {
+		canonical := models.CanonicalISRC(song.ISRC)
+		if canonical == song.ISRC {
+			continue
+		}
+		if err := setISRC(ctx, song.ID, canonical); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				slog.Warn("Skipped ISRC normalization, another song holds the canonical ISRC", "song_id", song.ID.Hex(), "isrc", song.ISRC, "canonical_isrc", canonical)
+				skipped++
+				continue
+			}
+			return updated, skipped, fmt.Errorf("failed to normalize ISRC for song %s: %w", song.ID.Hex(), err)
+		}
+		updated++
+	}
+	return updated, skipped, nil
+}
+
+// nonCanonicalISRCFilter matches songs whose ISRC contains anything other than
+// uppercase letters and digits
+func nonCanonicalISRCFilter() bson.M {
+	return bson.M{"isrc": primitive.Regex{Pattern: "[^A-Z0-9]"}}
+}
+
+// canonicalISRCLookups maps each canonical ISRC to the requested forms it
+// answers, skipping empty values
+func canonicalISRCLookups(isrcs []string) map[string][]string {
+	requested := make(map[string][]string)
+	for _, isrc := range isrcs {
+		canonical := models.CanonicalISRC(isrc)
+		if canonical == "" {
+			continue
+		}
+		if !slices.Contains(requested[canonical], isrc) {
+			requested[canonical] = append(requested[canonical], isrc)
+		}
+	}
+	return requested
+}
+
+// FindRecentAfter returns up to limit songs, newest first, that come after cursor
+// in the recent feed. A nil cursor starts from the newest song.
+func (r *mongoSongRepository) FindRecentAfter(ctx context.Context, cursor *RecentCursor, limit int) ([]*models.Song, error) {
+	opts := options.Find().SetSort(recentSort).SetLimit(int64(limit))
+	songs, err := r.findSongs(ctx, recentAfterFilter(cursor), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recent songs: %w", err)
+	}
+	return songs, nil
+}
+
+// paginatedFindOptions builds the find options for an offset/limit page sorted by _id
+func paginatedFindOptions(offset, limit int) (*options.FindOptions, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative, got %d", offset)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	return options.Find().SetSkip(int64(offset)).SetLimit(int64(limit)).SetSort(bson.M{"_id": 1}), nil
+}
+
+// FindMissingAlbumArt returns up to limit songs in _id order, after afterID
+// (or from the first song when empty), that have no album art but do have
+// platform links to fetch it from. Paging by the last returned ID moves past
+// songs no platform has art for, which stay candidates for the next run.
+func (r *mongoSongRepository) FindMissingAlbumArt(ctx context.Context, afterID string, limit int) ([]*models.Song, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	filter, err := missingAlbumArtFilter(afterID)
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit))
+	songs, err := r.findSongs(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find songs missing album art: %w", err)
+	}
+	return songs, nil
+}
+
+// missingAlbumArtFilter matches songs after afterID whose image URL is empty
+// or unset and that have at least one platform link; songs without links can
+// never be backfilled
+func missingAlbumArtFilter(afterID string) (bson.M, error) {
+	filter, err := afterIDFilter(afterID)
+	if err != nil {
+		return nil, err
+	}
+	filter["metadata.image_url"] = bson.M{"$in": []interface{}{"", nil}}
+	// Excludes empty, null and missing link arrays alike
+	filter["platform_links.0"] = bson.M{"$exists": true}
+	return filter, nil
+}
+
+// FindMissingPlatform returns up to limit songs in _id order, after afterID
+// (or from the first song when empty), that have an ISRC but no link on
+// platform; the ISRC is what enrichment looks them up by. Paging by the last
+// returned ID stays correct while earlier songs gain the link and drop out.
+func (r *mongoSongRepository) FindMissingPlatform(ctx context.Context, platform, afterID string, limit int) ([]*models.Song, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	filter, err := missingPlatformFilter(platform, afterID)
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit))
+	songs, err := r.findSongs(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find songs missing %s: %w", platform, err)
+	}
+	return songs, nil
+}
+
+// missingPlatformFilter matches songs after afterID with a non-empty ISRC and
+// no link on platform
+func missingPlatformFilter(platform, afterID string) (bson.M, error) {
+	filter, err := afterIDFilter(afterID)
+	if err != nil {
+		return nil, err
+	}
+	filter["isrc"] = bson.M{"$nin": []interface{}{"", nil}}
+	filter["platform_links.platform"] = bson.M{"$ne": platform}
+	return filter, nil
+}
+
 // FindByIDPrefix finds a song by ObjectID prefix (for short ID lookup)
 func (r *mongoSongRepository) FindByIDPrefix(ctx context.Context, prefix string) (*models.Song, error) {
 	// Pad the prefix to create a range query
@@ -484,17 +790,28 @@ func (r *mongoSongRepository) handleSchemaEvolution(song *models.Song) {
 		// Add any necessary field transformations here
 		song.SchemaVersion = 1
 		fallthrough
+	case 1:
+		// Migration from version 1 to 2: backfill normalized search text
+		song.UpdateSearchText()
+		song.SchemaVersion = 2
+		fallthrough
 	default:
 		song.SchemaVersion = models.CurrentSchemaVersion
 	}
 
-	// Lazy update the document in the database
-	// This could be done in a background process if preferred
+	// Lazily persist only the migrated fields: a full Update from a read path
+	// could overwrite a concurrent edit with this copy. Songs that are never
+	// read stay unmigrated, and the normalized search can't match them, until
+	// POST /api/v1/admin/reindex backfills search_text for the whole collection.
+	id, searchText, version := song.ID, song.SearchText, song.SchemaVersion
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if err := r.Update(ctx, song); err != nil {
-			slog.Error("Failed to update song schema version", "songID", song.ID, "error", err)
+		_, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": id, "schema_version": bson.M{"$lt": version}},
+			bson.M{"$set": bson.M{"search_text": searchText, "schema_version": version}})
+		if err != nil {
+			slog.Error("Failed to update song schema version", "songID", id, "error", err)
 		}
 	}()
 }
@@ -552,23 +869,63 @@ func (r *mongoSongRepository) cacheResult(ctx context.Context, key string, song
 	}
 }
 
-// invalidateCache removes cache entries for a song
-func (r *mongoSongRepository) invalidateCache(ctx context.Context, song *models.Song) {
+// storedSong loads the stored version of a song so its cache keys can be
+// invalidated alongside the new ones. It returns nil when caching is disabled
+// or the song can't be loaded.
+func (r *mongoSongRepository) storedSong(ctx context.Context, id primitive.ObjectID) *models.Song {
+	if r.cache == nil || id.IsZero() {
+		return nil
+	}
+
+	var song models.Song
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&song); err != nil {
+		if err != mongo.ErrNoDocuments {
+			slog.Error("Failed to load song for cache invalidation", "id", id.Hex(), "error", err)
+		}
+		return nil
+	}
+	return &song
+}
+
+// invalidateCache removes cache entries for each given song, skipping nils
+func (r *mongoSongRepository) invalidateCache(ctx context.Context, songs ...*models.Song) {
 	if r.cache == nil {
 		return
 	}
-	
-	// Delete primary cache keys
-	if !song.ID.IsZero() {
-		r.cache.Delete(ctx, songIDKey(song.ID.Hex()))
+
+	for _, key := range songCacheKeys(songs...) {
+		r.cache.Delete(ctx, key)
 	}
+}
 
-	if song.ISRC != "" {
-		r.cache.Delete(ctx, songISRCKey(song.ISRC))
+// songCacheKeys returns the distinct cache keys that can hold the given songs
+func songCacheKeys(songs ...*models.Song) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
 	}
 
-	// Delete platform-specific cache keys
-	for _, link := range song.PlatformLinks {
-		r.cache.Delete(ctx, songPlatformKey(link.Platform, link.ExternalID))
+	for _, song := range songs {
+		if song == nil {
+			continue
+		}
+
+		// Primary cache keys
+		if !song.ID.IsZero() {
+			add(songIDKey(song.ID.Hex()))
+		}
+		if song.ISRC != "" {
+			add(songISRCKey(song.ISRC))
+		}
+
+		// Platform-specific cache keys
+		for _, link := range song.PlatformLinks {
+			add(songPlatformKey(link.Platform, link.ExternalID))
+		}
 	}
+	return keys
 }
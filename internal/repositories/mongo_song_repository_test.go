@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"songshare/internal/models"
+)
+
+// matchesSearchTextFilter evaluates a searchTextFilter against a song in memory
+func matchesSearchTextFilter(t *testing.T, filter bson.M, song *models.Song) bool {
+	t.Helper()
+	pattern, ok := filter["search_text"].(primitive.Regex)
+	require.True(t, ok)
+	// Anchored at the start, so Mongo bounds it with the search_text index
+	require.True(t, strings.HasPrefix(pattern.Pattern, "^"))
+	return regexp.MustCompile(pattern.Pattern).MatchString(song.SearchText)
+}
+
+func TestSearchTextFilter_DiacriticInsensitive(t *testing.T) {
+	song := models.NewSong("Halo", "Beyoncé")
+	song.Album = "I Am... Sasha Fierce"
+	song.UpdateSearchText()
+
+	tests := []struct {
+		query   string
+		matches bool
+	}{
+		{"halo beyonce", true},
+		{"Halo Beyoncé", true},
+		{"HALO BEY", true},
+		{"halo", true},
+		{"beyonce halo", false},
+		{"sasha fierce", false},
+		{"yonce", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			filter := searchTextFilter(tt.query)
+			require.NotNil(t, filter)
+			assert.Equal(t, tt.matches, matchesSearchTextFilter(t, filter, song))
+		})
+	}
+}
+
+func TestSearchTextFilter_LeadingThe(t *testing.T) {
+	song := models.NewSong("The Scientist", "Coldplay")
+
+	assert.True(t, matchesSearchTextFilter(t, searchTextFilter("scientist coldplay"), song))
+	assert.True(t, matchesSearchTextFilter(t, searchTextFilter("The Scientist"), song))
+}
+
+func TestSearchTextFilter_EmptyQuery(t *testing.T) {
+	assert.Nil(t, searchTextFilter(""))
+	assert.Nil(t, searchTextFilter("  !? "))
+}
+
+func TestNonCanonicalISRCFilter(t *testing.T) {
+	pattern, ok := nonCanonicalISRCFilter()["isrc"].(primitive.Regex)
+	require.True(t, ok)
+	re := regexp.MustCompile(pattern.Pattern)
+
+	assert.False(t, re.MatchString("USUM71703861"))
+	assert.True(t, re.MatchString("usum71703861"))
+	assert.True(t, re.MatchString("US-UM7-17-03861"))
+	assert.True(t, re.MatchString("USUM71703861 "))
+}
+
+func TestCanonicalISRCLookups_MixedCase(t *testing.T) {
+	requested := canonicalISRCLookups([]string{"usum71703861", "USUM71703861", "", "gb-um7-15-05078", "usum71703861"})
+
+	// A lowercase request finds the uppercase-stored record and is answered under its own key
+	assert.Equal(t, []string{"usum71703861", "USUM71703861"}, requested["USUM71703861"])
+	assert.Equal(t, []string{"gb-um7-15-05078"}, requested["GBUM71505078"])
+	assert.Len(t, requested, 2)
+}
+
+func TestNormalizeISRCs_SkipsDuplicates(t *testing.T) {
+	songs := []*models.Song{
+		{ID: primitive.NewObjectID(), ISRC: "usum71703861"},
+		{ID: primitive.NewObjectID(), ISRC: "gb-um7-15-05078"},
+		{ID: primitive.NewObjectID(), ISRC: "usum72000001"},
+	}
+	duplicate := mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}}}
+
+	var written []string
+	updated, skipped, err := normalizeISRCs(context.Background(), songs, func(_ context.Context, _ primitive.ObjectID, isrc string) error {
+		if isrc == "GBUM71505078" {
+			return duplicate
+		}
+		written = append(written, isrc)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	assert.Equal(t, int64(1), skipped)
+	assert.Equal(t, []string{"USUM71703861", "USUM72000001"}, written, "a duplicate doesn't stop the run")
+
+	_, _, err = normalizeISRCs(context.Background(), songs, func(context.Context, primitive.ObjectID, string) error {
+		return errors.New("connection reset")
+	})
+	assert.Error(t, err, "other write errors stop the run")
+}
+
+func TestPaginatedFindOptions(t *testing.T) {
+	opts, err := paginatedFindOptions(200, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), *opts.Skip)
+	assert.Equal(t, int64(100), *opts.Limit)
+	assert.Equal(t, bson.M{"_id": 1}, opts.Sort)
+
+	_, err = paginatedFindOptions(-1, 100)
+	assert.Error(t, err)
+	_, err = paginatedFindOptions(0, 0)
+	assert.Error(t, err)
+}
+
+func TestMissingAlbumArtFilter(t *testing.T) {
+	filter, err := missingAlbumArtFilter("")
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"$in": []interface{}{"", nil}}, filter["metadata.image_url"])
+	assert.Equal(t, bson.M{"$exists": true}, filter["platform_links.0"])
+	assert.NotContains(t, filter, "_id")
+
+	id := primitive.NewObjectID()
+	filter, err = missingAlbumArtFilter(id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"$gt": id}, filter["_id"])
+
+	_, err = missingAlbumArtFilter("not-an-id")
+	assert.Error(t, err)
+}
+
+// matchesMissingPlatformFilter evaluates a missingPlatformFilter against a song in memory
+func matchesMissingPlatformFilter(t *testing.T, filter bson.M, song *models.Song) bool {
+	t.Helper()
+	isrcClause, ok := filter["isrc"].(bson.M)
+	require.True(t, ok)
+	for _, excluded := range isrcClause["$nin"].([]interface{}) {
+		if excluded == nil && song.ISRC == "" || excluded == song.ISRC {
+			return false
+		}
+	}
+
+	platformClause, ok := filter["platform_links.platform"].(bson.M)
+	require.True(t, ok)
+	for _, link := range song.PlatformLinks {
+		if link.Platform == platformClause["$ne"] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMissingPlatformFilter(t *testing.T) {
+	withLinks := func(isrc string, platforms ...string) *models.Song {
+		song := &models.Song{ISRC: isrc}
+		for _, platform := range platforms {
+			song.PlatformLinks = append(song.PlatformLinks, models.PlatformLink{Platform: platform})
+		}
+		return song
+	}
+
+	songs := map[string]*models.Song{
+		"missing tidal":          withLinks("GBUM71029604", "spotify", "apple_music"),
+		"has tidal":              withLinks("USUM71703861", "spotify", "tidal"),
+		"only tidal":             withLinks("USRC17607839", "tidal"),
+		"no links":               withLinks("GBAYE0601498"),
+		"missing tidal, no ISRC": withLinks("", "spotify"),
+	}
+
+	filter, err := missingPlatformFilter("tidal", "")
+	require.NoError(t, err)
+	assert.NotContains(t, filter, "_id")
+	var matched []string
+	for name, song := range songs {
+		if matchesMissingPlatformFilter(t, filter, song) {
+			matched = append(matched, name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"missing tidal", "no links"}, matched)
+
+	id := primitive.NewObjectID()
+	filter, err = missingPlatformFilter("tidal", id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"$gt": id}, filter["_id"])
+
+	_, err = missingPlatformFilter("tidal", "not-an-id")
+	assert.Error(t, err)
+}
+
+func TestAfterIDFilter(t *testing.T) {
+	filter, err := afterIDFilter("")
+	require.NoError(t, err)
+	assert.Empty(t, filter)
+
+	id := primitive.NewObjectID()
+	filter, err = afterIDFilter(id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"_id": bson.M{"$gt": id}}, filter)
+
+	_, err = afterIDFilter("not-an-id")
+	assert.Error(t, err)
+}
+
+func TestViewIncrementWrites(t *testing.T) {
+	viewed := primitive.NewObjectID()
+	writes, ids := viewIncrementWrites(map[primitive.ObjectID]int64{
+		viewed:                  3,
+		primitive.NewObjectID(): 0,
+	})
+
+	require.Len(t, writes, 1)
+	assert.Equal(t, []primitive.ObjectID{viewed}, ids)
+	update, ok := writes[0].(*mongo.UpdateOneModel)
+	require.True(t, ok)
+	assert.Equal(t, bson.M{"_id": viewed}, update.Filter)
+	assert.Equal(t, bson.M{"$inc": bson.M{"views": int64(3)}}, update.Update)
+}
+
+func TestFailedViewIncrements(t *testing.T) {
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+
+	failed, ok := failedViewIncrements(mongo.BulkWriteException{
+		WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Index: 1, Code: 121}}},
+	}, ids)
+	require.True(t, ok)
+	assert.Equal(t, []primitive.ObjectID{ids[1]}, failed)
+
+	// Without per-write errors it's unknown which writes were applied
+	_, ok = failedViewIncrements(mongo.BulkWriteException{
+		WriteConcernError: &mongo.WriteConcernError{Code: 64},
+		WriteErrors:       []mongo.BulkWriteError{{WriteError: mongo.WriteError{Index: 0}}},
+	}, ids)
+	assert.False(t, ok)
+	_, ok = failedViewIncrements(errors.New("connection reset"), ids)
+	assert.False(t, ok)
+}
+
+func TestReplaceKeepingViews(t *testing.T) {
+	doc, err := bson.Marshal(bson.M{"title": "$money", "views": 1})
+	require.NoError(t, err)
+
+	pipeline := replaceKeepingViews(doc)
+	require.Len(t, pipeline, 1)
+	merge := pipeline[0][0].Value.(bson.M)["$mergeObjects"].(bson.A)
+	assert.Equal(t, bson.M{"$literal": bson.Raw(doc)}, merge[0], "the song is not evaluated as an expression")
+	assert.Equal(t, bson.M{"views": "$views"}, merge[1], "the stored count wins")
+}
+
+func TestSongCacheKeys_OldAndNew(t *testing.T) {
+	id := primitive.NewObjectID()
+	stored := &models.Song{ID: id, ISRC: "USRC17607839"}
+	stored.AddPlatformLink("spotify", "old", "https://open.spotify.com/track/old", 1)
+	updated := &models.Song{ID: id, ISRC: "GBAYE0601498"}
+	updated.AddPlatformLink("spotify", "new", "https://open.spotify.com/track/new", 1)
+
+	keys := songCacheKeys(updated, stored, nil)
+	assert.ElementsMatch(t, []string{
+		songIDKey(id.Hex()),
+		songISRCKey("GBAYE0601498"),
+		songISRCKey("USRC17607839"),
+		songPlatformKey("spotify", "new"),
+		songPlatformKey("spotify", "old"),
+	}, keys, "stale keys of the stored version are included once")
+}
+
+func TestUpsertFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+	assert.Equal(t, bson.M{"_id": id}, upsertFilter(&models.Song{ID: id, ISRC: "USRC17607839"}))
+	assert.Equal(t, bson.M{"isrc": "USRC17607839"}, upsertFilter(&models.Song{ISRC: "USRC17607839"}))
+
+	song := &models.Song{PlatformLinks: []models.PlatformLink{{Platform: "spotify", ExternalID: "abc"}}}
+	assert.Equal(t, bson.M{
+		"platform_links": bson.M{"$elemMatch": bson.M{"platform": "spotify", "external_id": "abc"}},
+	}, upsertFilter(song))
+
+	assert.Nil(t, upsertFilter(&models.Song{Title: "Unidentified"}))
+}
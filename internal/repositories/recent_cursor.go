@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"songshare/internal/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor token can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// RecentCursor marks the last song a client has seen in the recent songs feed.
+// The feed is ordered newest first by created_at then _id, so songs added while
+// a client is paginating sort ahead of the cursor and never shift later pages.
+type RecentCursor struct {
+	CreatedAt time.Time
+	ID        primitive.ObjectID
+}
+
+// RecentCursorFor returns the cursor positioned just after song
+func RecentCursorFor(song *models.Song) *RecentCursor {
+	return &RecentCursor{CreatedAt: song.CreatedAt, ID: song.ID}
+}
+
+// Encode returns the cursor as an opaque URL-safe token
+func (c *RecentCursor) Encode() string {
+	// MongoDB stores dates with millisecond precision, so nothing finer is kept
+	raw := strconv.FormatInt(c.CreatedAt.UnixMilli(), 10) + ":" + c.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeRecentCursor parses a token produced by RecentCursor.Encode
+func DecodeRecentCursor(token string) (*RecentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	millis, hexID, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &RecentCursor{CreatedAt: time.UnixMilli(createdAt).UTC(), ID: id}, nil
+}
+
+// recentSort orders the recent songs feed newest first, breaking created_at ties by _id
+var recentSort = bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+
+// recentAfterFilter matches songs that sort after cursor in the recent feed;
+// a nil cursor matches every song
+func recentAfterFilter(cursor *RecentCursor) bson.M {
+	if cursor == nil {
+		return bson.M{}
+	}
+	createdAt := cursor.CreatedAt.Truncate(time.Millisecond)
+	return bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$lt": createdAt}},
+		bson.M{"created_at": createdAt, "_id": bson.M{"$lt": cursor.ID}},
+	}}
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"songshare/internal/models"
+)
+
+// matchesRecentAfterFilter evaluates a recentAfterFilter against a song in memory
+func matchesRecentAfterFilter(t *testing.T, filter bson.M, song *models.Song) bool {
+	t.Helper()
+	clauses, ok := filter["$or"].(bson.A)
+	if !ok {
+		require.Empty(t, filter)
+		return true
+	}
+	require.Len(t, clauses, 2)
+
+	older := clauses[0].(bson.M)["created_at"].(bson.M)["$lt"].(time.Time)
+	tie := clauses[1].(bson.M)
+	tieCreatedAt := tie["created_at"].(time.Time)
+	tieID := tie["_id"].(bson.M)["$lt"].(primitive.ObjectID)
+
+	createdAt := song.CreatedAt.Truncate(time.Millisecond)
+	return createdAt.Before(older) ||
+		(createdAt.Equal(tieCreatedAt) && song.ID.Hex() < tieID.Hex())
+}
+
+// fakeRecentStore mimics FindRecentAfter over an in-memory collection
+type fakeRecentStore struct {
+	songs []*models.Song
+}
+
+func (s *fakeRecentStore) insert(createdAt time.Time) *models.Song {
+	song := models.NewSong("Song", "Artist")
+	song.ID = primitive.NewObjectID()
+	song.CreatedAt = createdAt
+	s.songs = append(s.songs, song)
+	return song
+}
+
+func (s *fakeRecentStore) findRecentAfter(t *testing.T, cursor *RecentCursor, limit int) []*models.Song {
+	filter := recentAfterFilter(cursor)
+	var matched []*models.Song
+	for _, song := range s.songs {
+		if matchesRecentAfterFilter(t, filter, song) {
+			matched = append(matched, song)
+		}
+	}
+	// recentSort: created_at descending, then _id descending
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].ID.Hex() > matched[j].ID.Hex()
+	})
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched
+}
+
+func TestRecentAfterFilter_PaginationWithInterleavedInserts(t *testing.T) {
+	store := &fakeRecentStore{}
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Several songs share a created_at so the _id tie-break is exercised
+	var original []*models.Song
+	for i := 0; i < 7; i++ {
+		original = append(original, store.insert(base.Add(time.Duration(i/2)*time.Second)))
+	}
+
+	seen := make(map[primitive.ObjectID]bool)
+	var cursor *RecentCursor
+	for page := 0; ; page++ {
+		songs := store.findRecentAfter(t, cursor, 3)
+		if len(songs) == 0 {
+			break
+		}
+		for _, song := range songs {
+			assert.False(t, seen[song.ID], "song returned twice")
+			seen[song.ID] = true
+		}
+
+		// New songs arrive between page requests
+		store.insert(base.Add(time.Hour + time.Duration(page)*time.Second))
+		store.insert(base.Add(time.Hour + time.Duration(page)*time.Second))
+
+		// Round-trip the cursor through its token like a client would
+		token := RecentCursorFor(songs[len(songs)-1]).Encode()
+		decoded, err := DecodeRecentCursor(token)
+		require.NoError(t, err)
+		cursor = decoded
+	}
+
+	require.Len(t, seen, len(original))
+	for _, song := range original {
+		assert.True(t, seen[song.ID], "song skipped")
+	}
+}
+
+func TestRecentAfterFilter_NilCursor(t *testing.T) {
+	assert.Empty(t, recentAfterFilter(nil))
+}
+
+func TestDecodeRecentCursor(t *testing.T) {
+	cursor := &RecentCursor{
+		CreatedAt: time.Date(2024, 6, 1, 12, 0, 0, 123456789, time.UTC),
+		ID:        primitive.NewObjectID(),
+	}
+
+	decoded, err := DecodeRecentCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, decoded.CreatedAt.Equal(cursor.CreatedAt.Truncate(time.Millisecond)))
+
+	for _, token := range []string{"", "not base64!", "MTIz", "YWJjOjEyMw"} {
+		_, err := DecodeRecentCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
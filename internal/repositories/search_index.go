@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"songshare/internal/models"
+)
+
+// songsTextIndexName names the text index behind Search's $text queries
+const songsTextIndexName = "songs_text"
+
+// Index errors meaning an equivalent index already exists under another name
+// or with other options; a collection can only have one text index
+const (
+	indexOptionsConflictCode  = 85
+	indexKeySpecsConflictCode = 86
+)
+
+// FindAfterID returns up to limit songs in _id order whose ID is greater than
+// afterID, or from the first song when afterID is empty. Walking the collection
+// by the last returned ID resumes exactly where a previous walk stopped.
+func (r *mongoSongRepository) FindAfterID(ctx context.Context, afterID string, limit int) ([]*models.Song, error) {
+	filter, err := afterIDFilter(afterID)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := paginatedFindOptions(0, limit)
+	if err != nil {
+		return nil, err
+	}
+	songs, err := r.findSongs(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find songs after %q: %w", afterID, err)
+	}
+	return songs, nil
+}
+
+// afterIDFilter matches songs whose _id sorts after afterID; empty matches all
+func afterIDFilter(afterID string) (bson.M, error) {
+	if afterID == "" {
+		return bson.M{}, nil
+	}
+	id, err := primitive.ObjectIDFromHex(afterID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object ID: %w", err)
+	}
+	return bson.M{"_id": bson.M{"$gt": id}}, nil
+}
+
+// UpdateSearchText stores the denormalized search fields of songs, as set by
+// models.Song.UpdateSearchText, in one bulk write and returns how many
+// documents changed
+func (r *mongoSongRepository) UpdateSearchText(ctx context.Context, songs []*models.Song) (int64, error) {
+	if len(songs) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(songs))
+	for _, song := range songs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": song.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"search_text": song.SearchText}}))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to update search text: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// EnsureTextIndex creates the title/artist/album text index Search relies on,
+// if the collection doesn't already have a text index
+func (r *mongoSongRepository) EnsureTextIndex(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "artist", Value: "text"},
+			{Key: "album", Value: "text"},
+		},
+		Options: options.Index().SetName(songsTextIndexName),
+	})
+	if err != nil && !isIndexConflict(err) {
+		return fmt.Errorf("failed to create text index: %w", err)
+	}
+	return nil
+}
+
+// isIndexConflict reports whether err says an equivalent index already exists
+func isIndexConflict(err error) bool {
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) {
+		return false
+	}
+	return cmdErr.Code == indexOptionsConflictCode || cmdErr.Code == indexKeySpecsConflictCode
+}
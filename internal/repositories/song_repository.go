@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
 	"songshare/internal/models"
 )
 
@@ -15,6 +17,7 @@ type SongRepository interface {
 	// Find operations
 	FindByID(ctx context.Context, id string) (*models.Song, error)
 	FindByISRC(ctx context.Context, isrc string) (*models.Song, error)
+	FindByPreviousISRC(ctx context.Context, isrc string) (*models.Song, error)
 	FindByISRCBatch(ctx context.Context, isrcs []string) (map[string]*models.Song, error)
 	FindByTitleArtist(ctx context.Context, title, artist string) ([]*models.Song, error)
 	FindByPlatformID(ctx context.Context, platform, externalID string) (*models.Song, error)
@@ -23,12 +26,26 @@ type SongRepository interface {
 	Search(ctx context.Context, query string, limit int) ([]*models.Song, error)
 	FindSimilar(ctx context.Context, song *models.Song, limit int) ([]*models.Song, error)
 	FindByIDPrefix(ctx context.Context, prefix string) (*models.Song, error)
+	FindRecentAfter(ctx context.Context, cursor *RecentCursor, limit int) ([]*models.Song, error)
+	FindMissingAlbumArt(ctx context.Context, afterID string, limit int) ([]*models.Song, error)
+	FindMissingPlatform(ctx context.Context, platform, afterID string, limit int) ([]*models.Song, error)
 
 	// Bulk operations
 	FindMany(ctx context.Context, ids []string) ([]*models.Song, error)
 	SaveMany(ctx context.Context, songs []*models.Song) error
+	UpsertMany(ctx context.Context, songs []*models.Song) error
 
 	// Maintenance operations
 	DeleteByID(ctx context.Context, id string) error
 	Count(ctx context.Context) (int64, error)
+	FindDuplicateISRCs(ctx context.Context) (map[string][]*models.Song, error)
+	NormalizeISRCs(ctx context.Context) (updated, skipped int64, err error)
+
+	// Search index maintenance
+	FindAfterID(ctx context.Context, afterID string, limit int) ([]*models.Song, error)
+	UpdateSearchText(ctx context.Context, songs []*models.Song) (int64, error)
+	EnsureTextIndex(ctx context.Context) error
+
+	// View counting
+	IncrementViews(ctx context.Context, counts map[primitive.ObjectID]int64) error
 }
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"songshare/internal/models"
+)
+
+// UpsertMany stores songs in one bulk write. Each song replaces the stored
+// song with the same ID, ISRC, or, lacking an ISRC, the same first platform
+// link, and is inserted when there is none. Every song's ID is set to the ID
+// it was stored under.
+func (r *mongoSongRepository) UpsertMany(ctx context.Context, songs []*models.Song) error {
+	if len(songs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(songs))
+	for _, song := range songs {
+		song.SchemaVersion = models.CurrentSchemaVersion
+		song.UpdatedAt = now
+		song.UpdateSearchText()
+		song.CanonicalizeISRC()
+		if song.CreatedAt.IsZero() {
+			song.CreatedAt = now
+		}
+
+		filter := upsertFilter(song)
+		if filter == nil {
+			// Nothing identifies the song, so it can only be inserted
+			song.ID = primitive.NewObjectID()
+			writes = append(writes, mongo.NewInsertOneModel().SetDocument(song))
+			continue
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(filter).
+			SetReplacement(song).
+			SetUpsert(true))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		if dupErr := classifyWriteError(err, nil); dupErr != nil {
+			return dupErr
+		}
+		return fmt.Errorf("failed to upsert songs: %w", err)
+	}
+
+	// New documents get their IDs from the upsert; replaced ones keep theirs
+	for i, song := range songs {
+		if id, ok := result.UpsertedIDs[int64(i)].(primitive.ObjectID); ok {
+			song.ID = id
+			continue
+		}
+		if !song.ID.IsZero() {
+			continue
+		}
+
+		var stored struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		opts := options.FindOne().SetProjection(bson.M{"_id": 1})
+		if err := r.collection.FindOne(ctx, upsertFilter(song), opts).Decode(&stored); err != nil {
+			return fmt.Errorf("failed to load upserted song ID: %w", err)
+		}
+		song.ID = stored.ID
+	}
+
+	r.invalidateCache(ctx, songs...)
+	return nil
+}
+
+// upsertFilter matches the stored version of song by ID, then ISRC, then its
+// first platform link, or returns nil when song has none of them. Songs
+// without an ID are matched without one in the replacement, so a replaced
+// document keeps its own.
+func upsertFilter(song *models.Song) bson.M {
+	switch {
+	case !song.ID.IsZero():
+		return bson.M{"_id": song.ID}
+	case song.ISRC != "":
+		return bson.M{"isrc": song.ISRC}
+	case len(song.PlatformLinks) > 0:
+		link := song.PlatformLinks[0]
+		return bson.M{
+			"platform_links": bson.M{
+				"$elemMatch": bson.M{
+					"platform":    link.Platform,
+					"external_id": link.ExternalID,
+				},
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ViewIncrementError reports the songs whose view counts IncrementViews
+// couldn't write; every other song's count was stored
+type ViewIncrementError struct {
+	Failed []primitive.ObjectID
+	Err    error
+}
+
+func (e *ViewIncrementError) Error() string {
+	return fmt.Sprintf("failed to increment views for %d songs: %v", len(e.Failed), e.Err)
+}
+
+func (e *ViewIncrementError) Unwrap() error {
+	return e.Err
+}
+
+// IncrementViews adds each song's count to its stored view count in one bulk
+// write. Songs deleted in the meantime are skipped. When only some writes
+// fail, the error is a *ViewIncrementError naming them, since retrying the
+// others would count their views twice.
+func (r *mongoSongRepository) IncrementViews(ctx context.Context, counts map[primitive.ObjectID]int64) error {
+	writes, ids := viewIncrementWrites(counts)
+	if len(writes) == 0 {
+		return nil
+	}
+	if _, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		if failed, ok := failedViewIncrements(err, ids); ok {
+			return &ViewIncrementError{Failed: failed, Err: err}
+		}
+		return fmt.Errorf("failed to increment views: %w", err)
+	}
+	return nil
+}
+
+// viewIncrementWrites builds one $inc per song with a positive count, and
+// the song ID of each write in the same order
+func viewIncrementWrites(counts map[primitive.ObjectID]int64) ([]mongo.WriteModel, []primitive.ObjectID) {
+	writes := make([]mongo.WriteModel, 0, len(counts))
+	ids := make([]primitive.ObjectID, 0, len(counts))
+	for id, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$inc": bson.M{"views": count}}))
+		ids = append(ids, id)
+	}
+	return writes, ids
+}
+
+// failedViewIncrements returns the IDs of the writes a bulk write exception
+// rejected. It reports false when the error doesn't say which writes were
+// applied, such as a network or write concern failure.
+func failedViewIncrements(err error, ids []primitive.ObjectID) ([]primitive.ObjectID, bool) {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return nil, false
+	}
+	failed := make([]primitive.ObjectID, 0, len(bulkErr.WriteErrors))
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index < 0 || writeErr.Index >= len(ids) {
+			return nil, false
+		}
+		failed = append(failed, ids[writeErr.Index])
+	}
+	return failed, true
+}
+
+// replaceKeepingViews is an update pipeline that replaces a song document with
+// doc but keeps the stored view count, which only IncrementViews changes.
+// doc is taken literally so field values starting with "$" aren't read as
+// field paths; a song never viewed has no views field to keep.
+func replaceKeepingViews(doc bson.Raw) mongo.Pipeline {
+	return mongo.Pipeline{{{Key: "$replaceWith", Value: bson.M{
+		"$mergeObjects": bson.A{
+			bson.M{"$literal": doc},
+			bson.M{"views": "$views"},
+		},
+	}}}}
+}
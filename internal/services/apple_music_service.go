@@ -8,6 +8,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -28,7 +29,9 @@ type appleMusicService struct {
 	jwtToken    string
 	tokenExpiry time.Time
 	cache       cache.Cache
-	mu          sync.RWMutex
+	// negativeCacheTTL is how long a track ID Apple Music reports missing is cached
+	negativeCacheTTL time.Duration
+	mu               sync.RWMutex
 }
 
 // Apple Music API endpoints
@@ -52,11 +55,12 @@ func NewAppleMusicService(keyID, teamID, keyFile string, cache cache.Cache) Plat
 		SetRetryMaxWaitTime(5 * time.Second)
 
 	service := &appleMusicService{
-		client:  client,
-		keyID:   keyID,
-		teamID:  teamID,
-		keyFile: keyFile,
-		cache:   cache,
+		client:           client,
+		keyID:            keyID,
+		teamID:           teamID,
+		keyFile:          keyFile,
+		cache:            newCountingCache(cache),
+		negativeCacheTTL: defaultNegativeCacheTTL,
 	}
 
 	// Load private key
@@ -67,6 +71,26 @@ func NewAppleMusicService(keyID, teamID, keyFile string, cache cache.Cache) Plat
 	return service
 }
 
+// setTransport sends API calls through transport
+func (s *appleMusicService) setTransport(transport http.RoundTripper) {
+	s.client.SetTransport(transport)
+}
+
+// setNegativeCacheTTL sets how long missing track IDs are cached
+func (s *appleMusicService) setNegativeCacheTTL(ttl time.Duration) {
+	s.negativeCacheTTL = ttl
+}
+
+// CacheStats reports the service's track and search cache use
+func (s *appleMusicService) CacheStats() (hits, misses, entries int) {
+	return cacheCounts(s.cache)
+}
+
+// ResetCacheStats zeroes the cache counters
+func (s *appleMusicService) ResetCacheStats() {
+	resetCacheCounts(s.cache)
+}
+
 // GetPlatformName returns the platform name
 func (s *appleMusicService) GetPlatformName() string {
 	return "apple_music"
@@ -99,6 +123,9 @@ func (s *appleMusicService) GetTrackByID(ctx context.Context, trackID string) (*
 	// Check cache first
 	cacheKey := fmt.Sprintf("api:apple_music:track:%s", trackID)
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != nil {
+		if isNotFoundSentinel(cached) {
+			return nil, s.trackNotFoundError()
+		}
 		var trackInfo TrackInfo
 		if err := json.Unmarshal(cached, &trackInfo); err == nil {
 			return &trackInfo, nil
@@ -114,11 +141,14 @@ func (s *appleMusicService) GetTrackByID(ctx context.Context, trackID string) (*
 	s.mu.RUnlock()
 
 	var appleMusicTrack AppleMusicTrack
+	start := time.Now()
 	resp, err := s.client.R().
 		SetContext(ctx).
 		SetAuthToken(token).
 		SetResult(&appleMusicTrack).
 		Get(fmt.Sprintf("%s/catalog/us/songs/%s", appleMusicAPIURL, trackID))
+	observeRestyRequest("apple_music", "get_track", start, resp, err)
+	logRestyRawResponse(ctx, "apple_music", "get_track", resp, err)
 
 	if err != nil {
 		return nil, &PlatformError{
@@ -130,11 +160,8 @@ func (s *appleMusicService) GetTrackByID(ctx context.Context, trackID string) (*
 	}
 
 	if resp.StatusCode() == 404 {
-		return nil, &PlatformError{
-			Platform:  "apple_music",
-			Operation: "get_track",
-			Message:   "track not found",
-		}
+		cacheNotFound(ctx, s.cache, cacheKey, s.negativeCacheTTL)
+		return nil, s.trackNotFoundError()
 	}
 
 	if resp.StatusCode() != 200 {
@@ -142,6 +169,7 @@ func (s *appleMusicService) GetTrackByID(ctx context.Context, trackID string) (*
 			Platform:  "apple_music",
 			Operation: "get_track",
 			Message:   fmt.Sprintf("API returned status %d", resp.StatusCode()),
+			Category:  CategoryForStatus(resp.StatusCode()),
 		}
 	}
 
@@ -158,13 +186,28 @@ func (s *appleMusicService) GetTrackByID(ctx context.Context, trackID string) (*
 	// Cache the result
 	if data, err := json.Marshal(trackInfo); err == nil {
 		if err := s.cache.Set(ctx, cacheKey, data, appleMusicTrackCacheTTL); err != nil {
-			slog.Error("Failed to cache Apple Music track", "trackID", trackID, "error", err)
+			slog.Error("Failed to cache Apple Music track", "trackID", trackID, "error", err, requestIDAttr(ctx))
 		}
 	}
 
 	return trackInfo, nil
 }
 
+// GetTrackByURL fetches track details for a Apple Music track URL
+func (s *appleMusicService) GetTrackByURL(ctx context.Context, url string) (*TrackInfo, error) {
+	return trackByURL(ctx, s, url)
+}
+
+// trackNotFoundError is returned for a track ID Apple Music reports missing
+func (s *appleMusicService) trackNotFoundError() error {
+	return &PlatformError{
+		Platform:  "apple_music",
+		Operation: "get_track",
+		Message:   "track not found",
+		Category:  ErrorCategoryNoResults,
+	}
+}
+
 // SearchTrack searches for tracks on Apple Music
 func (s *appleMusicService) SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error) {
 	searchQuery := s.buildSearchQuery(query)
@@ -177,11 +220,15 @@ func (s *appleMusicService) SearchTrack(ctx context.Context, query SearchQuery)
 	}
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("api:apple_music:search:%s:limit:%d", searchQuery, limit)
+	cacheKey := fmt.Sprintf("api:apple_music:search:%s:limit:%d", searchQuery, limit) + query.entityKindsCacheKey()
+	explicitParams := appleMusicExplicitParams(query.Explicit)
+	if explicitParams != nil {
+		cacheKey += ":explicit:" + string(query.Explicit)
+	}
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != nil {
 		var tracks []*TrackInfo
 		if err := json.Unmarshal(cached, &tracks); err == nil {
-			return tracks, nil
+			return FilterExplicit(tracks, query.Explicit), nil
 		}
 	}
 
@@ -194,16 +241,20 @@ func (s *appleMusicService) SearchTrack(ctx context.Context, query SearchQuery)
 	s.mu.RUnlock()
 
 	var searchResult AppleMusicSearchResult
+	start := time.Now()
 	resp, err := s.client.R().
 		SetContext(ctx).
 		SetAuthToken(token).
 		SetQueryParams(map[string]string{
 			"term":  searchQuery,
-			"types": "songs",
+			"types": appleMusicSearchTypes(query),
 			"limit": fmt.Sprintf("%d", limit),
 		}).
+		SetQueryParams(explicitParams).
 		SetResult(&searchResult).
 		Get(fmt.Sprintf("%s/catalog/us/search", appleMusicAPIURL))
+	observeRestyRequest("apple_music", "search", start, resp, err)
+	logRestyRawResponse(ctx, "apple_music", "search", resp, err)
 
 	if err != nil {
 		return nil, &PlatformError{
@@ -219,13 +270,11 @@ func (s *appleMusicService) SearchTrack(ctx context.Context, query SearchQuery)
 			Platform:  "apple_music",
 			Operation: "search",
 			Message:   fmt.Sprintf("API returned status %d", resp.StatusCode()),
+			Category:  CategoryForStatus(resp.StatusCode()),
 		}
 	}
 
-	tracks := make([]*TrackInfo, 0, len(searchResult.Results.Songs.Data))
-	for _, track := range searchResult.Results.Songs.Data {
-		tracks = append(tracks, s.convertAppleMusicTrack(&track))
-	}
+	tracks := s.convertAppleMusicSearchResult(&searchResult, query)
 
 	// Cache the results
 	if data, err := json.Marshal(tracks); err == nil {
@@ -236,11 +285,11 @@ func (s *appleMusicService) SearchTrack(ctx context.Context, query SearchQuery)
 		}
 
 		if err := s.cache.Set(ctx, cacheKey, data, cacheTTL); err != nil {
-			slog.Error("Failed to cache Apple Music search results", "query", searchQuery, "error", err)
+			slog.Error("Failed to cache Apple Music search results", "query", searchQuery, "error", err, requestIDAttr(ctx))
 		}
 	}
 
-	return tracks, nil
+	return FilterExplicit(tracks, query.Explicit), nil
 }
 
 // GetTrackByISRC finds track by ISRC code
@@ -260,6 +309,7 @@ func (s *appleMusicService) GetTrackByISRC(ctx context.Context, isrc string) (*T
 			Platform:  "apple_music",
 			Operation: "get_by_isrc",
 			Message:   "no tracks found with ISRC " + isrc,
+			Category:  ErrorCategoryNoResults,
 		}
 	}
 
@@ -378,32 +428,89 @@ func (s *appleMusicService) generateJWT() (string, error) {
 	return token.SignedString(s.privateKey)
 }
 
-// buildSearchQuery constructs a search query string for Apple Music
+// buildSearchQuery constructs a search query string for Apple Music using its configured strategy
 func (s *appleMusicService) buildSearchQuery(query SearchQuery) string {
-	if query.ISRC != "" {
-		return fmt.Sprintf("isrc:%s", query.ISRC)
+	searchQuery := BuildSearchQuery(GetSearchQueryStrategy("apple_music"), query)
+	if searchQuery == "" {
+		return "music" // Default search term
 	}
+	return searchQuery
+}
 
-	if query.Query != "" {
-		return query.Query
+// appleMusicSearchTypes returns the catalog search types parameter: songs, plus
+// any extra entity kinds the query asked for
+func appleMusicSearchTypes(query SearchQuery) string {
+	types := []string{"songs"}
+	if query.includesKind(EntityAlbum) {
+		types = append(types, "albums")
+	}
+	if query.includesKind(EntityArtist) {
+		types = append(types, "artists")
 	}
+	return strings.Join(types, ",")
+}
 
-	var parts []string
-	if query.Title != "" {
-		parts = append(parts, query.Title)
+// convertAppleMusicSearchResult converts a catalog search response to TrackInfo,
+// dropping any entity the query didn't ask for
+func (s *appleMusicService) convertAppleMusicSearchResult(result *AppleMusicSearchResult, query SearchQuery) []*TrackInfo {
+	converted := make([]*TrackInfo, 0, len(result.Results.Songs.Data))
+	for _, track := range result.Results.Songs.Data {
+		// Guard against music videos or other resources mixed into the songs group
+		if track.Type != "" && track.Type != "songs" {
+			continue
+		}
+		converted = append(converted, s.convertAppleMusicTrack(&track))
 	}
-	if query.Artist != "" {
-		parts = append(parts, query.Artist)
+
+	if query.includesKind(EntityAlbum) {
+		for _, album := range result.Results.Albums.Data {
+			converted = append(converted, convertAppleMusicResource(&album, EntityAlbum))
+		}
 	}
-	if query.Album != "" {
-		parts = append(parts, query.Album)
+	if query.includesKind(EntityArtist) {
+		for _, artist := range result.Results.Artists.Data {
+			converted = append(converted, convertAppleMusicResource(&artist, EntityArtist))
+		}
 	}
 
-	if len(parts) == 0 {
-		return "music" // Default search term
+	return FilterEntityKinds(converted, query)
+}
+
+// convertAppleMusicResource converts an album or artist search result to TrackInfo
+func convertAppleMusicResource(resource *AppleMusicResource, kind EntityKind) *TrackInfo {
+	artists := []string{}
+	if resource.Attributes.ArtistName != "" {
+		artists = append(artists, resource.Attributes.ArtistName)
+	} else if kind == EntityArtist {
+		artists = append(artists, resource.Attributes.Name)
 	}
 
-	return strings.Join(parts, " ") // Apple Music search handles multiple terms well in a single string
+	info := &TrackInfo{
+		Kind:        kind,
+		Platform:    "apple_music",
+		ExternalID:  resource.ID,
+		URL:         resource.Attributes.URL,
+		Title:       resource.Attributes.Name,
+		Artists:     artists,
+		ReleaseDate: resource.Attributes.ReleaseDate,
+		Genres:      resource.Attributes.GenreNames,
+		Explicit:    resource.Attributes.ContentRating == "explicit",
+		ImageURL:    appleMusicArtworkURL(resource.Attributes.Artwork),
+		Available:   true,
+	}
+	if kind == EntityAlbum {
+		info.Album = resource.Attributes.Name
+	}
+	return info.Sanitize()
+}
+
+// appleMusicArtworkURL fills in the artwork URL template at 400x400
+func appleMusicArtworkURL(artwork AppleMusicArtwork) string {
+	if artwork.URL == "" {
+		return ""
+	}
+	imageURL := strings.ReplaceAll(artwork.URL, "{w}", "400")
+	return strings.ReplaceAll(imageURL, "{h}", "400")
 }
 
 // convertAppleMusicTrack converts Apple Music API response to TrackInfo
@@ -413,15 +520,7 @@ func (s *appleMusicService) convertAppleMusicTrack(track *AppleMusicSong) *Track
 		artists = append(artists, track.Attributes.ArtistName)
 	}
 
-	// Get image URL
-	var imageURL string
-	if track.Attributes.Artwork.URL != "" {
-		// Apple Music artwork URLs use template format
-		imageURL = strings.ReplaceAll(track.Attributes.Artwork.URL, "{w}", "400")
-		imageURL = strings.ReplaceAll(imageURL, "{h}", "400")
-	}
-
-	return &TrackInfo{
+	return (&TrackInfo{
 		Platform:    "apple_music",
 		ExternalID:  track.ID,
 		URL:         s.BuildURL(track.ID),
@@ -432,9 +531,9 @@ func (s *appleMusicService) convertAppleMusicTrack(track *AppleMusicSong) *Track
 		Duration:    track.Attributes.DurationInMillis,
 		ReleaseDate: track.Attributes.ReleaseDate,
 		Explicit:    track.Attributes.ContentRating == "explicit",
-		ImageURL:    imageURL,
+		ImageURL:    appleMusicArtworkURL(track.Attributes.Artwork),
 		Available:   true,
-	}
+	}).Sanitize()
 }
 
 // Apple Music API response structures
@@ -447,7 +546,9 @@ type AppleMusicSearchResult struct {
 }
 
 type AppleMusicResults struct {
-	Songs AppleMusicSongs `json:"songs"`
+	Songs   AppleMusicSongs     `json:"songs"`
+	Albums  AppleMusicResources `json:"albums"`
+	Artists AppleMusicResources `json:"artists"`
 }
 
 type AppleMusicSongs struct {
@@ -476,3 +577,126 @@ type AppleMusicArtwork struct {
 	Width  int    `json:"width"`
 	Height int    `json:"height"`
 }
+
+// AppleMusicResources holds album or artist search results
+type AppleMusicResources struct {
+	Data []AppleMusicResource `json:"data"`
+}
+
+type AppleMusicResource struct {
+	ID         string                       `json:"id"`
+	Type       string                       `json:"type"`
+	Attributes AppleMusicResourceAttributes `json:"attributes"`
+}
+
+type AppleMusicResourceAttributes struct {
+	Name          string            `json:"name"`
+	ArtistName    string            `json:"artistName,omitempty"`
+	URL           string            `json:"url"`
+	ReleaseDate   string            `json:"releaseDate,omitempty"`
+	ContentRating string            `json:"contentRating,omitempty"`
+	GenreNames    []string          `json:"genreNames,omitempty"`
+	Artwork       AppleMusicArtwork `json:"artwork"`
+}
+
+// appleMusicPlaylistPageSize is the track page size for playlists and albums (the API maximum)
+const appleMusicPlaylistPageSize = 100
+
+// GetPlaylistPage fetches one page of an Apple Music playlist's or album's tracks
+func (s *appleMusicService) GetPlaylistPage(ctx context.Context, ref PlaylistRef, offset int) (*PlaylistPage, error) {
+	resource := "playlists"
+	if ref.Kind == PlaylistKindAlbum {
+		resource = "albums"
+	}
+
+	page := &PlaylistPage{}
+	if offset == 0 {
+		var container AppleMusicResources
+		if err := s.getJSON(ctx, "get_playlist", fmt.Sprintf("%s/catalog/us/%s/%s", appleMusicAPIURL, resource, ref.ID), nil, &container); err != nil {
+			return nil, err
+		}
+		if len(container.Data) > 0 {
+			page.Name = container.Data[0].Attributes.Name
+			page.ImageURL = appleMusicArtworkURL(container.Data[0].Attributes.Artwork)
+		}
+	}
+
+	var result AppleMusicPlaylistTracks
+	params := map[string]string{
+		"offset": fmt.Sprintf("%d", offset),
+		"limit":  fmt.Sprintf("%d", appleMusicPlaylistPageSize),
+	}
+	if err := s.getJSON(ctx, "get_playlist", fmt.Sprintf("%s/catalog/us/%s/%s/tracks", appleMusicAPIURL, resource, ref.ID), params, &result); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Data {
+		// Playlists can also hold music videos
+		if result.Data[i].Type != "songs" {
+			continue
+		}
+		page.Tracks = append(page.Tracks, s.convertAppleMusicTrack(&result.Data[i]))
+	}
+	page.Total = result.Meta.Total
+	if result.Next != "" {
+		page.Next = offset + len(result.Data)
+	}
+	return page, nil
+}
+
+// getJSON performs an authenticated GET against the Apple Music API and decodes the response into result
+func (s *appleMusicService) getJSON(ctx context.Context, operation, url string, params map[string]string, result interface{}) error {
+	if err := s.ensureValidToken(); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	token := s.jwtToken
+	s.mu.RUnlock()
+
+	start := time.Now()
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetAuthToken(token).
+		SetQueryParams(params).
+		SetResult(result).
+		Get(url)
+	observeRestyRequest("apple_music", operation, start, resp, err)
+	logRestyRawResponse(ctx, "apple_music", operation, resp, err)
+	if err != nil {
+		return &PlatformError{
+			Platform:  "apple_music",
+			Operation: operation,
+			Message:   "request failed",
+			Err:       err,
+		}
+	}
+
+	if resp.StatusCode() == 404 {
+		return &PlatformError{
+			Platform:  "apple_music",
+			Operation: operation,
+			Message:   "not found",
+			Category:  ErrorCategoryNoResults,
+		}
+	}
+
+	if resp.StatusCode() != 200 {
+		return &PlatformError{
+			Platform:  "apple_music",
+			Operation: operation,
+			Message:   fmt.Sprintf("API returned status %d", resp.StatusCode()),
+			Category:  CategoryForStatus(resp.StatusCode()),
+		}
+	}
+	return nil
+}
+
+// AppleMusicPlaylistTracks is a page of playlist or album tracks
+type AppleMusicPlaylistTracks struct {
+	Data []AppleMusicSong `json:"data"`
+	Next string           `json:"next,omitempty"`
+	Meta struct {
+		Total int `json:"total"`
+	} `json:"meta"`
+}
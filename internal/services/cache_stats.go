@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"songshare/internal/cache"
+	"songshare/internal/metrics"
+)
+
+// CacheStatsReporter is implemented by platform services that cache track
+// lookups and searches
+type CacheStatsReporter interface {
+	// CacheStats returns the lookups answered from the cache, the lookups
+	// that went to the platform and the entries stored, counted since the
+	// service started or its counters were last reset
+	CacheStats() (hits, misses, entries int)
+
+	// ResetCacheStats zeroes the counters
+	ResetCacheStats()
+}
+
+// countingCache wraps a platform service's cache, counting its lookups and
+// stores. Lookups that fail count as misses, since the service then asks the
+// platform.
+type countingCache struct {
+	cache.Cache
+	hits    atomic.Int64
+	misses  atomic.Int64
+	entries atomic.Int64
+}
+
+func newCountingCache(c cache.Cache) *countingCache {
+	return &countingCache{Cache: platformCache(c)}
+}
+
+// platformCache counts c's operations under the platform cache metrics source
+func platformCache(c cache.Cache) cache.Cache {
+	return cache.Instrument(c, metrics.CacheSourcePlatform)
+}
+
+// Get looks key up in the wrapped cache
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.Cache.Get(ctx, key)
+	if err == nil && data != nil {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return data, err
+}
+
+// Set stores value in the wrapped cache
+func (c *countingCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	err := c.Cache.Set(ctx, key, value, expiration)
+	if err == nil {
+		c.entries.Add(1)
+	}
+	return err
+}
+
+// cacheCounts returns a service cache's counters; caches that aren't counted report zeros
+func cacheCounts(c cache.Cache) (hits, misses, entries int) {
+	counting, ok := c.(*countingCache)
+	if !ok {
+		return 0, 0, 0
+	}
+	return int(counting.hits.Load()), int(counting.misses.Load()), int(counting.entries.Load())
+}
+
+// resetCacheCounts zeroes a service cache's counters
+func resetCacheCounts(c cache.Cache) {
+	if counting, ok := c.(*countingCache); ok {
+		counting.hits.Store(0)
+		counting.misses.Store(0)
+		counting.entries.Store(0)
+	}
+}
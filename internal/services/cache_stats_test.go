@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func TestSpotifyCacheStats(t *testing.T) {
+	target, requests := notFoundServer(t)
+	service := &spotifyService{
+		client:           resty.New().SetTransport(rewriteTransport{target: target}),
+		tokenSource:      &clientcredentials.Config{},
+		accessToken:      "test-token",
+		tokenExpiry:      time.Now().Add(time.Hour),
+		cache:            newCountingCache(newMemoryCache()),
+		negativeCacheTTL: defaultNegativeCacheTTL,
+	}
+
+	// The first lookup misses and caches the answer; the rest hit it
+	for range 3 {
+		_, _ = service.GetTrackByID(context.Background(), "typo")
+	}
+	_, _ = service.GetTrackByID(context.Background(), "other")
+	assert.Equal(t, int32(2), requests.Load())
+
+	hits, misses, entries := service.CacheStats()
+	assert.Equal(t, 2, hits)
+	assert.Equal(t, 2, misses)
+	assert.Equal(t, 2, entries)
+
+	service.ResetCacheStats()
+	hits, misses, entries = service.CacheStats()
+	assert.Zero(t, hits+misses+entries)
+}
+
+func TestCacheCounts_UncountedCache(t *testing.T) {
+	hits, misses, entries := cacheCounts(newMemoryCache())
+	assert.Zero(t, hits+misses+entries)
+}
@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"songshare/internal/config"
+)
+
+// deezerDefaultBaseURL is Deezer's public API
+const deezerDefaultBaseURL = "https://api.deezer.com"
+
+// deezerShortLinkBaseURL is where deezer.page.link share links redirect from
+const deezerShortLinkBaseURL = "https://deezer.page.link"
+
+// deezerURLPattern matches deezer.com track URLs, capturing the numeric track
+// ID, and deezer.page.link share links, capturing the short code that
+// GetTrackByID expands
+const deezerURLPattern = `(?:https?://)?(?:(?:www\.)?deezer\.com/(?:[a-z]{2}(?:-[a-z]{2})?/)?track/|deezer\.page\.link/)([A-Za-z0-9]+)`
+
+// deezerErrorNoData is the API error code for an unknown ID or ISRC
+const deezerErrorNoData = 800
+
+var (
+	deezerTrackURLRegex = regexp.MustCompile(`deezer\.com/(?:[a-z]{2}(?:-[a-z]{2})?/)?track/(\d+)`)
+	deezerTrackIDRegex  = regexp.MustCompile(`^\d+$`)
+)
+
+func init() {
+	if err := RegisterURLPattern("deezer", deezerURLPattern, 1, "Deezer track URLs and share links", []string{
+		"https://www.deezer.com/track/3135556",
+		"https://www.deezer.com/en/track/3135556",
+		"deezer.com/track/3135556",
+		"https://deezer.page.link/S3RMbJ8Xn1qQ3WY36",
+	}); err != nil {
+		panic(fmt.Sprintf("invalid Deezer URL pattern: %v", err))
+	}
+}
+
+// DeezerService implements the PlatformService interface for Deezer
+type DeezerService struct {
+	config       *config.PlatformConfig
+	httpClient   *http.Client
+	shortLinkURL string
+	accessToken  string
+	tokenExpiry  time.Time
+	tokenMu      sync.RWMutex
+}
+
+// NewDeezerService creates a new Deezer service instance
+func NewDeezerService(cfg *config.PlatformConfig) (*DeezerService, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("deezer configuration is required")
+	}
+
+	if cfg.AuthMethod != config.AuthMethodOAuth2 {
+		return nil, fmt.Errorf("deezer requires OAuth2 authentication, got %s", cfg.AuthMethod)
+	}
+
+	httpClient, err := newPlatformHTTPClient(cfg, time.Duration(cfg.Timeout)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	service := &DeezerService{
+		config:       cfg,
+		httpClient:   httpClient,
+		shortLinkURL: deezerShortLinkBaseURL,
+	}
+
+	// Get initial access token
+	if err := service.refreshToken(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to get initial access token: %w", err)
+	}
+
+	return service, nil
+}
+
+// GetPlatformName returns the platform name
+func (d *DeezerService) GetPlatformName() string {
+	return "deezer"
+}
+
+// BuildURL constructs a Deezer URL from track ID
+func (d *DeezerService) BuildURL(trackID string) string {
+	return fmt.Sprintf("https://www.deezer.com/track/%s", trackID)
+}
+
+// ParseURL extracts track information from a Deezer URL
+func (d *DeezerService) ParseURL(rawURL string) (*TrackInfo, error) {
+	// Get track info from API
+	return d.GetTrackByURL(context.Background(), rawURL)
+}
+
+// GetTrackByURL fetches track information for a Deezer track or short link URL
+func (d *DeezerService) GetTrackByURL(ctx context.Context, rawURL string) (*TrackInfo, error) {
+	matches := regexp.MustCompile(deezerURLPattern).FindStringSubmatch(rawURL)
+	if len(matches) < 2 {
+		return nil, &PlatformError{
+			Platform:  "deezer",
+			Operation: "parse_url",
+			Message:   "invalid Deezer URL format",
+			URL:       rawURL,
+		}
+	}
+	return d.GetTrackByID(ctx, matches[1])
+}
+
+// GetTrackByID fetches track information using a Deezer track ID or a
+// deezer.page.link short code
+func (d *DeezerService) GetTrackByID(ctx context.Context, trackID string) (*TrackInfo, error) {
+	if !deezerTrackIDRegex.MatchString(trackID) {
+		resolved, err := d.resolveShortLink(ctx, trackID)
+		if err != nil {
+			return nil, err
+		}
+		trackID = resolved
+	}
+
+	var track deezerTrack
+	if err := d.get(ctx, "get_track", "/track/"+trackID, nil, &track); err != nil {
+		return nil, err
+	}
+	return d.convertTrack(&track), nil
+}
+
+// SearchTrack searches for tracks on Deezer
+func (d *DeezerService) SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error) {
+	searchQuery := BuildSearchQuery(GetSearchQueryStrategy("deezer"), query)
+	if searchQuery == "" {
+		return nil, &PlatformError{
+			Platform:  "deezer",
+			Operation: "search",
+			Message:   "empty search query",
+		}
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	params := url.Values{
+		"q":     {searchQuery},
+		"limit": {strconv.Itoa(limit)},
+	}
+
+	var response struct {
+		Data []deezerTrack `json:"data"`
+	}
+	if err := d.get(ctx, "search", "/search/track", params, &response); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*TrackInfo, 0, len(response.Data))
+	for i := range response.Data {
+		tracks = append(tracks, d.convertTrack(&response.Data[i]))
+	}
+
+	// Deezer search can't filter explicit content, so results are post-filtered
+	return FilterExplicit(tracks, query.Explicit), nil
+}
+
+// GetTrackByISRC finds a track by its ISRC code
+func (d *DeezerService) GetTrackByISRC(ctx context.Context, isrc string) (*TrackInfo, error) {
+	if isrc == "" {
+		return nil, &PlatformError{
+			Platform:  "deezer",
+			Operation: "search_isrc",
+			Message:   "ISRC cannot be empty",
+		}
+	}
+
+	var track deezerTrack
+	if err := d.get(ctx, "search_isrc", "/track/isrc:"+url.PathEscape(isrc), nil, &track); err != nil {
+		return nil, err
+	}
+	return d.convertTrack(&track), nil
+}
+
+// Health checks if the Deezer API is accessible
+func (d *DeezerService) Health(ctx context.Context) error {
+	var infos map[string]interface{}
+	if err := d.get(ctx, "health_check", "/infos", nil, &infos); err != nil {
+		return &PlatformError{
+			Platform:  "deezer",
+			Operation: "health_check",
+			Message:   "health check failed",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+// resolveShortLink expands a deezer.page.link short code to a track ID by
+// reading the share link's redirect
+func (d *DeezerService) resolveShortLink(ctx context.Context, code string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.shortLinkURL+"/"+code, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create short link request: %w", err)
+	}
+
+	client := *d.httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", &PlatformError{
+			Platform:  "deezer",
+			Operation: "resolve_short_link",
+			Message:   "short link request failed",
+			Err:       err,
+		}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	matches := deezerTrackURLRegex.FindStringSubmatch(resp.Header.Get("Location"))
+	if matches == nil {
+		return "", &PlatformError{
+			Platform:  "deezer",
+			Operation: "resolve_short_link",
+			Message:   "short link does not point to a track",
+			Category:  ErrorCategoryNoResults,
+			URL:       d.shortLinkURL + "/" + code,
+		}
+	}
+	return matches[1], nil
+}
+
+// ensureValidToken ensures we have a valid access token
+func (d *DeezerService) ensureValidToken(ctx context.Context) error {
+	d.tokenMu.RLock()
+	isExpired := time.Now().Add(1 * time.Minute).After(d.tokenExpiry)
+	d.tokenMu.RUnlock()
+
+	if isExpired {
+		return d.refreshToken(ctx)
+	}
+
+	return nil
+}
+
+// refreshToken gets a new access token using OAuth2 client credentials flow
+func (d *DeezerService) refreshToken(ctx context.Context) error {
+	data := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {d.config.ClientID},
+		"client_secret": {d.config.ClientSecret},
+		"output":        {"json"}, // Deezer answers form-encoded without it
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.config.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &PlatformError{
+			Platform:  "deezer",
+			Operation: "auth",
+			Message:   fmt.Sprintf("token request failed with status %d: %s", resp.StatusCode, string(respBody)),
+			Category:  ErrorCategoryAuth,
+		}
+	}
+
+	// Deezer names the lifetime "expires"; standard OAuth2 servers use "expires_in"
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		Expires     int    `json:"expires"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if err := json.Unmarshal(respBody, &tokenResp); err != nil {
+		return fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	if tokenResp.AccessToken == "" {
+		return fmt.Errorf("received empty access token")
+	}
+
+	expiresIn := tokenResp.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = tokenResp.Expires
+	}
+
+	// Update token info
+	d.tokenMu.Lock()
+	d.accessToken = tokenResp.AccessToken
+	d.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	d.tokenMu.Unlock()
+
+	return nil
+}
+
+// get performs an authenticated GET against the Deezer API and decodes the
+// response into result. Deezer reports most failures as a 200 with an error
+// object, so the body is checked for one before decoding.
+func (d *DeezerService) get(ctx context.Context, operation, endpoint string, params url.Values, result interface{}) error {
+	if err := d.ensureValidToken(ctx); err != nil {
+		return fmt.Errorf("failed to get valid token: %w", err)
+	}
+
+	baseURL := d.config.BaseURL
+	if baseURL == "" {
+		baseURL = deezerDefaultBaseURL
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+	d.tokenMu.RLock()
+	params.Set("access_token", d.accessToken)
+	d.tokenMu.RUnlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return &PlatformError{
+			Platform:  "deezer",
+			Operation: operation,
+			Message:   "request failed",
+			Err:       err,
+		}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return &PlatformError{
+			Platform:  "deezer",
+			Operation: operation,
+			Message:   fmt.Sprintf("API returned status %d: %s", resp.StatusCode, string(respBody)),
+			Category:  CategoryForStatus(resp.StatusCode),
+		}
+	}
+
+	var apiErr struct {
+		Error *struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Error != nil {
+		platformErr := &PlatformError{
+			Platform:  "deezer",
+			Operation: operation,
+			Message:   fmt.Sprintf("%s: %s", apiErr.Error.Type, apiErr.Error.Message),
+		}
+		if apiErr.Error.Code == deezerErrorNoData {
+			platformErr.Category = ErrorCategoryNoResults
+		}
+		return platformErr
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", operation, err)
+	}
+	return nil
+}
+
+// convertTrack converts a Deezer track to TrackInfo
+func (d *DeezerService) convertTrack(track *deezerTrack) *TrackInfo {
+	var artists []string
+	for _, contributor := range track.Contributors {
+		if contributor.Name != "" {
+			artists = append(artists, contributor.Name)
+		}
+	}
+	// Search results carry only the main artist
+	if len(artists) == 0 && track.Artist.Name != "" {
+		artists = []string{track.Artist.Name}
+	}
+
+	trackID := strconv.FormatInt(track.ID, 10)
+	trackURL := track.Link
+	if trackURL == "" {
+		trackURL = d.BuildURL(trackID)
+	}
+
+	releaseDate := track.ReleaseDate
+	if releaseDate == "" {
+		releaseDate = track.Album.ReleaseDate
+	}
+
+	return (&TrackInfo{
+		Platform:    "deezer",
+		ExternalID:  trackID,
+		URL:         trackURL,
+		Title:       track.Title,
+		Artists:     artists,
+		Album:       track.Album.Title,
+		ISRC:        track.ISRC,
+		Duration:    track.Duration * 1000, // Deezer reports seconds
+		ReleaseDate: releaseDate,
+		Explicit:    track.ExplicitLyrics,
+		ImageURL:    track.Album.CoverXL,
+		Available:   track.Readable,
+	}).Sanitize()
+}
+
+// deezerTrack is a track as returned by /track/{id} and /search/track.
+// Search results omit ISRC, contributors and release date.
+type deezerTrack struct {
+	ID             int64  `json:"id"`
+	Readable       bool   `json:"readable"`
+	Title          string `json:"title"`
+	ISRC           string `json:"isrc"`
+	Link           string `json:"link"`
+	Duration       int    `json:"duration"`
+	ReleaseDate    string `json:"release_date"`
+	ExplicitLyrics bool   `json:"explicit_lyrics"`
+	Artist         struct {
+		Name string `json:"name"`
+	} `json:"artist"`
+	Contributors []struct {
+		Name string `json:"name"`
+	} `json:"contributors"`
+	Album struct {
+		Title       string `json:"title"`
+		CoverXL     string `json:"cover_xl"`
+		ReleaseDate string `json:"release_date"`
+	} `json:"album"`
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deezerTestTrack = `{"id":3135556,"readable":true,"title":"Harder, Better, Faster, Stronger","isrc":"GBDUW0000059","link":"https://www.deezer.com/track/3135556","duration":224,"release_date":"2001-03-07","explicit_lyrics":false,"artist":{"name":"Daft Punk"},"contributors":[{"name":"Daft Punk"}],"album":{"title":"Discovery","cover_xl":"https://e-cdns-images.dzcdn.net/cover/1000x1000.jpg"}}`
+
+// newDeezerTestService returns a Deezer service backed by a fake API and
+// token endpoint serving the given path bodies
+func newDeezerTestService(t *testing.T, responses map[string]string) *DeezerService {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"test-token","expires":3600}`))
+			return
+		}
+		if r.URL.Query().Get("access_token") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			_, _ = w.Write([]byte(`{"error":{"type":"DataException","message":"no data","code":800}}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewDeezerService(&config.PlatformConfig{
+		Name:         "deezer",
+		AuthMethod:   config.AuthMethodOAuth2,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL,
+		Timeout:      5,
+	})
+	require.NoError(t, err)
+	return service
+}
+
+func TestDeezerURLPattern(t *testing.T) {
+	tests := []struct {
+		url     string
+		trackID string
+	}{
+		{"https://www.deezer.com/track/3135556", "3135556"},
+		{"https://www.deezer.com/fr/track/3135556?utm_source=share", "3135556"},
+		{"deezer.com/track/3135556", "3135556"},
+		{"https://deezer.page.link/S3RMbJ8Xn1qQ3WY36", "S3RMbJ8Xn1qQ3WY36"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			platform, trackID, err := ParsePlatformURL(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, "deezer", platform)
+			assert.Equal(t, tt.trackID, trackID)
+		})
+	}
+}
+
+func TestDeezerService_GetTrackByID(t *testing.T) {
+	service := newDeezerTestService(t, map[string]string{"/track/3135556": deezerTestTrack})
+
+	track, err := service.GetTrackByID(context.Background(), "3135556")
+	require.NoError(t, err)
+
+	assert.Equal(t, "deezer", track.Platform)
+	assert.Equal(t, "3135556", track.ExternalID)
+	assert.Equal(t, "https://www.deezer.com/track/3135556", track.URL)
+	assert.Equal(t, "Harder, Better, Faster, Stronger", track.Title)
+	assert.Equal(t, []string{"Daft Punk"}, track.Artists)
+	assert.Equal(t, "Discovery", track.Album)
+	assert.Equal(t, "GBDUW0000059", track.ISRC)
+	assert.Equal(t, 224000, track.Duration)
+	assert.Equal(t, "2001-03-07", track.ReleaseDate)
+	assert.True(t, track.Available)
+}
+
+func TestDeezerService_GetTrackByID_ShortLink(t *testing.T) {
+	service := newDeezerTestService(t, map[string]string{"/track/3135556": deezerTestTrack})
+	shortLinks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://www.deezer.com/track/3135556?utm_source=deezer", http.StatusFound)
+	}))
+	t.Cleanup(shortLinks.Close)
+	service.shortLinkURL = shortLinks.URL
+
+	track, err := service.GetTrackByID(context.Background(), "S3RMbJ8Xn1qQ3WY36")
+	require.NoError(t, err)
+	assert.Equal(t, "3135556", track.ExternalID)
+}
+
+func TestDeezerService_GetTrackByURL(t *testing.T) {
+	service := newDeezerTestService(t, map[string]string{"/track/3135556": deezerTestTrack})
+
+	track, err := service.GetTrackByURL(context.Background(), "https://www.deezer.com/en/track/3135556")
+	require.NoError(t, err)
+	assert.Equal(t, "3135556", track.ExternalID)
+	assert.Equal(t, "Harder, Better, Faster, Stronger", track.Title)
+
+	_, err = service.GetTrackByURL(context.Background(), "https://www.deezer.com/album/302127")
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, "parse_url", platformErr.Operation)
+}
+
+func TestDeezerService_GetTrackByISRC(t *testing.T) {
+	service := newDeezerTestService(t, map[string]string{"/track/isrc:GBDUW0000059": deezerTestTrack})
+
+	track, err := service.GetTrackByISRC(context.Background(), "GBDUW0000059")
+	require.NoError(t, err)
+	assert.Equal(t, "3135556", track.ExternalID)
+
+	_, err = service.GetTrackByISRC(context.Background(), "USRC17607839")
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, "search_isrc", platformErr.Operation)
+	assert.Equal(t, ErrorCategoryNoResults, platformErr.Category)
+}
+
+func TestDeezerService_SearchTrack(t *testing.T) {
+	service := newDeezerTestService(t, map[string]string{
+		"/search/track": `{"data":[{"id":3135556,"readable":true,"title":"Harder, Better, Faster, Stronger","duration":224,"explicit_lyrics":true,"artist":{"name":"Daft Punk"},"album":{"title":"Discovery"}}],"total":1}`,
+	})
+
+	tracks, err := service.SearchTrack(context.Background(), SearchQuery{Query: "daft punk harder"})
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+	assert.Equal(t, []string{"Daft Punk"}, tracks[0].Artists)
+	assert.Equal(t, "https://www.deezer.com/track/3135556", tracks[0].URL)
+
+	tracks, err = service.SearchTrack(context.Background(), SearchQuery{Query: "daft punk harder", Explicit: ExplicitExclude})
+	require.NoError(t, err)
+	assert.Empty(t, tracks)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntityKind identifies what a search result refers to
+type EntityKind string
+
+const (
+	// EntityTrack is a single track/song; an empty Kind also means a track
+	EntityTrack EntityKind = "track"
+	// EntityAlbum is an album or single release
+	EntityAlbum EntityKind = "album"
+	// EntityArtist is an artist profile
+	EntityArtist EntityKind = "artist"
+)
+
+// ParseEntityKinds parses the extra entity kinds a search may return besides
+// tracks (e.g. ["album", "artist"]). Tracks are always included.
+func ParseEntityKinds(values []string) ([]EntityKind, error) {
+	kinds := make([]EntityKind, 0, len(values))
+	for _, value := range values {
+		switch kind := EntityKind(strings.ToLower(strings.TrimSpace(value))); kind {
+		case "", EntityTrack:
+			continue
+		case EntityAlbum, EntityArtist:
+			kinds = append(kinds, kind)
+		default:
+			return nil, fmt.Errorf("unknown search entity kind %q (expected album or artist)", value)
+		}
+	}
+	return kinds, nil
+}
+
+// IsTrack reports whether the result is a track
+func (t *TrackInfo) IsTrack() bool {
+	return t.Kind == "" || t.Kind == EntityTrack
+}
+
+// includesKind reports whether the query asked for results of the given kind
+func (q SearchQuery) includesKind(kind EntityKind) bool {
+	if kind == "" || kind == EntityTrack {
+		return true
+	}
+	for _, included := range q.IncludeKinds {
+		if included == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// entityKindsCacheKey returns a cache-key suffix for the query's extra kinds;
+// empty for track-only searches so existing cache entries stay valid
+func (q SearchQuery) entityKindsCacheKey() string {
+	if len(q.IncludeKinds) == 0 {
+		return ""
+	}
+	kinds := make([]string, len(q.IncludeKinds))
+	for i, kind := range q.IncludeKinds {
+		kinds[i] = string(kind)
+	}
+	return ":kinds:" + strings.Join(kinds, ",")
+}
+
+// FilterEntityKinds drops results of kinds the query didn't ask for. Platforms
+// can broaden a search beyond the requested types, so every search applies it
+// after conversion.
+func FilterEntityKinds(results []*TrackInfo, query SearchQuery) []*TrackInfo {
+	filtered := make([]*TrackInfo, 0, len(results))
+	for _, result := range results {
+		if result != nil && query.includesKind(result.Kind) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
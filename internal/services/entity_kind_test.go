@@ -0,0 +1,135 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEntityKinds(t *testing.T) {
+	kinds, err := ParseEntityKinds([]string{" Album", "track", "", "artist"})
+	require.NoError(t, err)
+	assert.Equal(t, []EntityKind{EntityAlbum, EntityArtist}, kinds)
+
+	kinds, err = ParseEntityKinds(nil)
+	require.NoError(t, err)
+	assert.Empty(t, kinds)
+
+	_, err = ParseEntityKinds([]string{"playlist"})
+	assert.Error(t, err)
+}
+
+func TestFilterEntityKinds(t *testing.T) {
+	results := []*TrackInfo{
+		{ExternalID: "t1"},
+		{ExternalID: "t2", Kind: EntityTrack},
+		{ExternalID: "al1", Kind: EntityAlbum},
+		{ExternalID: "ar1", Kind: EntityArtist},
+		nil,
+	}
+
+	tracksOnly := FilterEntityKinds(results, SearchQuery{})
+	require.Len(t, tracksOnly, 2)
+	assert.Equal(t, "t1", tracksOnly[0].ExternalID)
+	assert.Equal(t, "t2", tracksOnly[1].ExternalID)
+
+	withAlbums := FilterEntityKinds(results, SearchQuery{IncludeKinds: []EntityKind{EntityAlbum}})
+	require.Len(t, withAlbums, 3)
+	assert.Equal(t, EntityAlbum, withAlbums[2].Kind)
+}
+
+const spotifyMixedSearchResponse = `{
+	"tracks": {"items": [
+		{"id": "track1", "type": "track", "name": "Song One", "artists": [{"id": "a1", "name": "Artist"}], "album": {"id": "al1", "name": "Album One"}},
+		{"id": "episode1", "type": "episode", "name": "Podcast Episode"}
+	]},
+	"albums": {"items": [
+		{"id": "al1", "name": "Album One", "release_date": "2020-01-01", "artists": [{"id": "a1", "name": "Artist"}], "images": [{"url": "https://i.scdn.co/album.jpg", "width": 640}]}
+	]},
+	"artists": {"items": [
+		{"id": "a1", "name": "Artist", "popularity": 70, "genres": ["pop"]}
+	]}
+}`
+
+func TestSpotifyConvertSearchResult_TracksOnlyByDefault(t *testing.T) {
+	var result SpotifySearchResult
+	require.NoError(t, json.Unmarshal([]byte(spotifyMixedSearchResponse), &result))
+	s := &spotifyService{}
+
+	tracks := s.convertSpotifySearchResult(&result, SearchQuery{})
+	require.Len(t, tracks, 1)
+	assert.Equal(t, "track1", tracks[0].ExternalID)
+	assert.True(t, tracks[0].IsTrack())
+	assert.Equal(t, "track", spotifySearchTypes(SearchQuery{}))
+}
+
+func TestSpotifyConvertSearchResult_IncludeKinds(t *testing.T) {
+	var result SpotifySearchResult
+	require.NoError(t, json.Unmarshal([]byte(spotifyMixedSearchResponse), &result))
+	s := &spotifyService{}
+	query := SearchQuery{IncludeKinds: []EntityKind{EntityAlbum, EntityArtist}}
+
+	results := s.convertSpotifySearchResult(&result, query)
+	require.Len(t, results, 3)
+	assert.Equal(t, "track,album,artist", spotifySearchTypes(query))
+
+	album := results[1]
+	assert.Equal(t, EntityAlbum, album.Kind)
+	assert.Equal(t, "https://open.spotify.com/album/al1", album.URL)
+	assert.Equal(t, []string{"Artist"}, album.Artists)
+	assert.Equal(t, "https://i.scdn.co/album.jpg", album.ImageURL)
+
+	artist := results[2]
+	assert.Equal(t, EntityArtist, artist.Kind)
+	assert.Equal(t, "https://open.spotify.com/artist/a1", artist.URL)
+	assert.Equal(t, 70, artist.Popularity)
+}
+
+const appleMusicMixedSearchResponse = `{
+	"results": {
+		"songs": {"data": [
+			{"id": "100", "type": "songs", "attributes": {"name": "Song One", "artistName": "Artist", "isrc": "USUM71703861"}},
+			{"id": "200", "type": "music-videos", "attributes": {"name": "Song One (Video)", "artistName": "Artist"}}
+		]},
+		"albums": {"data": [
+			{"id": "300", "type": "albums", "attributes": {"name": "Album One", "artistName": "Artist", "url": "https://music.apple.com/us/album/album-one/300", "artwork": {"url": "https://is1.mzstatic.com/{w}x{h}bb.jpg"}}}
+		]},
+		"artists": {"data": [
+			{"id": "400", "type": "artists", "attributes": {"name": "Artist", "url": "https://music.apple.com/us/artist/artist/400"}}
+		]}
+	}
+}`
+
+func TestAppleMusicConvertSearchResult_TracksOnlyByDefault(t *testing.T) {
+	var result AppleMusicSearchResult
+	require.NoError(t, json.Unmarshal([]byte(appleMusicMixedSearchResponse), &result))
+	s := &appleMusicService{}
+
+	tracks := s.convertAppleMusicSearchResult(&result, SearchQuery{})
+	require.Len(t, tracks, 1)
+	assert.Equal(t, "100", tracks[0].ExternalID)
+	assert.True(t, tracks[0].IsTrack())
+	assert.Equal(t, "songs", appleMusicSearchTypes(SearchQuery{}))
+}
+
+func TestAppleMusicConvertSearchResult_IncludeKinds(t *testing.T) {
+	var result AppleMusicSearchResult
+	require.NoError(t, json.Unmarshal([]byte(appleMusicMixedSearchResponse), &result))
+	s := &appleMusicService{}
+	query := SearchQuery{IncludeKinds: []EntityKind{EntityAlbum, EntityArtist}}
+
+	results := s.convertAppleMusicSearchResult(&result, query)
+	require.Len(t, results, 3)
+	assert.Equal(t, "songs,albums,artists", appleMusicSearchTypes(query))
+
+	album := results[1]
+	assert.Equal(t, EntityAlbum, album.Kind)
+	assert.Equal(t, "https://music.apple.com/us/album/album-one/300", album.URL)
+	assert.Equal(t, "https://is1.mzstatic.com/400x400bb.jpg", album.ImageURL)
+
+	artist := results[2]
+	assert.Equal(t, EntityArtist, artist.Kind)
+	assert.Equal(t, []string{"Artist"}, artist.Artists)
+}
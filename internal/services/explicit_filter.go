@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplicitFilter controls whether search results may contain explicit tracks
+type ExplicitFilter string
+
+const (
+	// ExplicitInclude returns explicit and clean tracks (the default)
+	ExplicitInclude ExplicitFilter = "include"
+	// ExplicitExclude returns clean tracks only
+	ExplicitExclude ExplicitFilter = "exclude"
+	// ExplicitOnly returns explicit tracks only
+	ExplicitOnly ExplicitFilter = "only"
+)
+
+// ParseExplicitFilter parses a request value; an empty value means include
+func ParseExplicitFilter(value string) (ExplicitFilter, error) {
+	switch filter := ExplicitFilter(strings.ToLower(strings.TrimSpace(value))); filter {
+	case "":
+		return ExplicitInclude, nil
+	case ExplicitInclude, ExplicitExclude, ExplicitOnly:
+		return filter, nil
+	default:
+		return "", fmt.Errorf("unknown explicit filter %q (expected include, exclude or only)", value)
+	}
+}
+
+// Matches reports whether a track with the given explicit flag passes the filter
+func (f ExplicitFilter) Matches(explicit bool) bool {
+	switch f {
+	case ExplicitExclude:
+		return !explicit
+	case ExplicitOnly:
+		return explicit
+	default:
+		return true
+	}
+}
+
+// FilterExplicit drops tracks that don't pass the filter. Platforms whose search
+// API can't express the filter (or only part of it) rely on this post-filter.
+func FilterExplicit(tracks []*TrackInfo, filter ExplicitFilter) []*TrackInfo {
+	if filter == "" || filter == ExplicitInclude {
+		return tracks
+	}
+
+	filtered := make([]*TrackInfo, 0, len(tracks))
+	for _, track := range tracks {
+		if track != nil && filter.Matches(track.Explicit) {
+			filtered = append(filtered, track)
+		}
+	}
+	return filtered
+}
+
+// tidalExplicitFilterParam maps the filter to Tidal's explicitFilter search parameter
+func tidalExplicitFilterParam(filter ExplicitFilter) string {
+	switch filter {
+	case ExplicitExclude:
+		return "exclude"
+	case ExplicitOnly:
+		return "include"
+	default:
+		return "include,exclude"
+	}
+}
+
+// appleMusicExplicitParams maps the filter to Apple Music catalog search parameters.
+// Apple Music can only restrict explicit content away; "only" is post-filtered.
+func appleMusicExplicitParams(filter ExplicitFilter) map[string]string {
+	if filter == ExplicitExclude {
+		return map[string]string{"restrict": "explicit"}
+	}
+	return nil
+}
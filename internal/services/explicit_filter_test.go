@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExplicitFilter(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ExplicitFilter
+		wantErr  bool
+	}{
+		{input: "", expected: ExplicitInclude},
+		{input: "include", expected: ExplicitInclude},
+		{input: " Exclude ", expected: ExplicitExclude},
+		{input: "only", expected: ExplicitOnly},
+		{input: "clean", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			filter, err := ParseExplicitFilter(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
+
+func TestFilterExplicit_SpotifyPostFilter(t *testing.T) {
+	explicit := &TrackInfo{Platform: "spotify", ExternalID: "e1", Explicit: true}
+	clean := &TrackInfo{Platform: "spotify", ExternalID: "c1"}
+	tracks := []*TrackInfo{explicit, clean}
+
+	assert.Equal(t, tracks, FilterExplicit(tracks, ExplicitInclude))
+	assert.Equal(t, tracks, FilterExplicit(tracks, ""))
+	assert.Equal(t, []*TrackInfo{clean}, FilterExplicit(tracks, ExplicitExclude))
+	assert.Equal(t, []*TrackInfo{explicit}, FilterExplicit(tracks, ExplicitOnly))
+}
+
+func TestTidalExplicitFilterParam(t *testing.T) {
+	assert.Equal(t, "include,exclude", tidalExplicitFilterParam(""))
+	assert.Equal(t, "include,exclude", tidalExplicitFilterParam(ExplicitInclude))
+	assert.Equal(t, "exclude", tidalExplicitFilterParam(ExplicitExclude))
+	assert.Equal(t, "include", tidalExplicitFilterParam(ExplicitOnly))
+}
+
+func TestAppleMusicExplicitParams(t *testing.T) {
+	assert.Nil(t, appleMusicExplicitParams(ExplicitInclude))
+	assert.Equal(t, map[string]string{"restrict": "explicit"}, appleMusicExplicitParams(ExplicitExclude))
+	assert.Nil(t, appleMusicExplicitParams(ExplicitOnly), "only has no API equivalent and is post-filtered")
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"songshare/internal/config"
+	"songshare/internal/metrics"
+)
+
+// newPlatformHTTPClient returns an HTTP client for a platform's API calls,
+// routed through the platform's proxy and carrying its extra headers
+func newPlatformHTTPClient(cfg *config.PlatformConfig, timeout time.Duration) (*http.Client, error) {
+	transport, err := newPlatformTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// newPlatformTransport applies a platform's proxy and extra headers to the
+// default transport
+func newPlatformTransport(cfg *config.PlatformConfig) (http.RoundTripper, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyURL != "" {
+		proxyURL, err := config.ParseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.Name, err)
+		}
+		base.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if len(cfg.Headers) == 0 {
+		return base, nil
+	}
+	return &headerTransport{base: base, headers: cfg.Headers}, nil
+}
+
+// headerTransport sets static headers on every outgoing request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip sends a copy of the request with the extra headers set
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// httpConfigurable is implemented by services built without a platform
+// config, whose HTTP settings are applied afterwards
+type httpConfigurable interface {
+	setTransport(transport http.RoundTripper)
+}
+
+// ConfigurePlatformHTTP routes a service built from credentials alone, such as
+// Spotify or Apple Music, through its platform's proxy and extra headers.
+// Services built from a PlatformConfig already apply them.
+func ConfigurePlatformHTTP(service PlatformService, cfg *config.PlatformConfig) error {
+	configurable, ok := service.(httpConfigurable)
+	if !ok || cfg == nil || (cfg.ProxyURL == "" && len(cfg.Headers) == 0) {
+		return nil
+	}
+
+	transport, err := newPlatformTransport(cfg)
+	if err != nil {
+		return err
+	}
+	configurable.setTransport(transport)
+	return nil
+}
+
+// observeRestyRequest records a platform API request made with resty, labelled
+// with the response status or "error" when none arrived
+func observeRestyRequest(platform, operation string, start time.Time, resp *resty.Response, err error) {
+	status := "error"
+	if err == nil && resp != nil {
+		status = strconv.Itoa(resp.StatusCode())
+	}
+	metrics.ObservePlatformRequest(platform, operation, status, time.Since(start))
+}
+
+// maxRetryAfter caps how long a rate-limited request waits before its retry
+const maxRetryAfter = 30 * time.Second
+
+// defaultRetryAfter is the wait when a 429 response has no usable Retry-After
+const defaultRetryAfter = time.Second
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an
+// HTTP date, falling back to defaultRetryAfter when it is missing or malformed
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return defaultRetryAfter
+}
+
+// waitRetryAfter sleeps for the platform's requested delay, capped at
+// maxRetryAfter, returning early with the context's error if it is done first
+func waitRetryAfter(ctx context.Context, retryAfter time.Duration) error {
+	timer := time.NewTimer(min(retryAfter, maxRetryAfter))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitedError is returned when a platform still answers 429 after the
+// request was retried once
+func rateLimitedError(platform string, retryAfter time.Duration) *PlatformError {
+	return &PlatformError{
+		Platform:   platform,
+		Operation:  "rate_limited",
+		Message:    fmt.Sprintf("still rate limited after retrying, retry after %s", retryAfter),
+		Category:   ErrorCategoryRateLimited,
+		RetryAfter: retryAfter,
+	}
+}
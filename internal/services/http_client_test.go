@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformHTTPClient_UsesProxyAndHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var proxied []*http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.Clone(context.Background()))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": []}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	service, err := NewYouTubeMusicService(&config.PlatformConfig{
+		Name:       "youtube_music",
+		AuthMethod: config.AuthMethodAPIKey,
+		APIKey:     "test-key",
+		BaseURL:    "http://youtube.example/v3",
+		Timeout:    5,
+		ProxyURL:   proxy.URL,
+		Headers:    map[string]string{"X-Gateway-Key": "gateway-secret"},
+	})
+	require.NoError(t, err)
+
+	_, _ = service.GetTrackByID(context.Background(), "dQw4w9WgXcQ")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, proxied, "request did not go through the proxy")
+	// A proxied plain HTTP request carries the absolute target URL
+	assert.Equal(t, "youtube.example", proxied[0].URL.Host)
+	assert.Equal(t, "/v3/videos", proxied[0].URL.Path)
+	assert.Equal(t, "gateway-secret", proxied[0].Header.Get("X-Gateway-Key"))
+}
+
+func TestPlatformHTTPClient_RejectsMalformedProxy(t *testing.T) {
+	_, err := NewYouTubeMusicService(&config.PlatformConfig{
+		Name:       "youtube_music",
+		AuthMethod: config.AuthMethodAPIKey,
+		APIKey:     "test-key",
+		ProxyURL:   "proxy.example:3128",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "proxy_url")
+
+	err = ConfigurePlatformHTTP(NewSpotifyService("id", "secret", nil), &config.PlatformConfig{Name: "spotify", ProxyURL: "ftp://proxy.example"})
+	assert.Error(t, err)
+}
+
+func TestConfigurePlatformHTTP_UnconfiguredService(t *testing.T) {
+	cfg := &config.PlatformConfig{Name: "spotify", ProxyURL: "http://proxy.example:3128"}
+	assert.NoError(t, ConfigurePlatformHTTP(NewSpotifyService("", "", nil), cfg))
+	assert.NoError(t, ConfigurePlatformHTTP(NewSpotifyService("id", "secret", nil), nil))
+}
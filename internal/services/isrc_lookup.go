@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+type isrcHintContextKey struct{}
+
+// ISRCHint is the title and artist of the song behind an ISRC lookup, which a
+// platform may search for when it doesn't know the ISRC
+type ISRCHint struct {
+	Title  string
+	Artist string
+}
+
+// WithISRCHint returns a context whose ISRC lookups may fall back to searching
+// for title and artist
+func WithISRCHint(ctx context.Context, title, artist string) context.Context {
+	return context.WithValue(ctx, isrcHintContextKey{}, ISRCHint{Title: title, Artist: artist})
+}
+
+// ISRCHintFromContext returns the ISRC lookup hint, if the context has a usable one
+func ISRCHintFromContext(ctx context.Context) (ISRCHint, bool) {
+	hint, ok := ctx.Value(isrcHintContextKey{}).(ISRCHint)
+	return hint, ok && hint.Title != "" && hint.Artist != ""
+}
+
+// LookupISRCAllPlatforms queries every platform for an ISRC concurrently and
+// returns the tracks found keyed by platform name. Platforms that fail or
+// don't know the ISRC are left out of the result.
+func LookupISRCAllPlatforms(ctx context.Context, isrc string, platforms []PlatformService) map[string]*TrackInfo {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		tracks = make(map[string]*TrackInfo)
+	)
+
+	for _, platform := range platforms {
+		if platform == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(platform PlatformService) {
+			defer wg.Done()
+
+			track, err := platform.GetTrackByISRC(ctx, isrc)
+			if err != nil {
+				slog.Debug("ISRC lookup failed", "platform", platform.GetPlatformName(), "isrc", isrc, "error", err)
+				return
+			}
+			if track == nil {
+				return
+			}
+
+			mu.Lock()
+			tracks[platform.GetPlatformName()] = track
+			mu.Unlock()
+		}(platform)
+	}
+
+	wg.Wait()
+	return tracks
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLookupISRCAllPlatforms(t *testing.T) {
+	isrc := "USUM71703861"
+
+	spotify := NewMockPlatformService("spotify")
+	spotify.On("GetTrackByISRC", mock.Anything, isrc).
+		Return(createTestTrackInfo("spotify", "sp1", "https://open.spotify.com/track/sp1"), nil)
+
+	appleMusic := NewMockPlatformService("apple_music")
+	appleMusic.On("GetTrackByISRC", mock.Anything, isrc).
+		Return(nil, &PlatformError{Platform: "apple_music", Operation: "get_by_isrc", Message: "not found"})
+
+	tidal := NewMockPlatformService("tidal")
+	tidal.On("GetTrackByISRC", mock.Anything, isrc).Return(nil, errors.New("timeout"))
+
+	tracks := LookupISRCAllPlatforms(context.Background(), isrc, []PlatformService{spotify, appleMusic, nil, tidal})
+
+	assert.Len(t, tracks, 1)
+	assert.Equal(t, "sp1", tracks["spotify"].ExternalID)
+}
+
+func TestLookupISRCAllPlatforms_NoPlatforms(t *testing.T) {
+	assert.Empty(t, LookupISRCAllPlatforms(context.Background(), "USUM71703861", nil))
+}
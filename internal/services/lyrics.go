@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported lyrics providers
+const (
+	LyricsProviderMusixmatch = "musixmatch"
+)
+
+// LyricsProvider finds a link to a song's lyrics on a third-party site.
+// SongShare never hosts lyrics; it only links out.
+type LyricsProvider interface {
+	// FindLyricsURL returns the lyrics page URL, or "" when there is no match
+	FindLyricsURL(ctx context.Context, isrc, title, artist string) (string, error)
+}
+
+// NewLyricsProvider returns the named provider, or nil when name or apiKey is empty
+func NewLyricsProvider(name, apiKey string) (LyricsProvider, error) {
+	if name == "" || apiKey == "" {
+		return nil, nil
+	}
+	switch strings.ToLower(name) {
+	case LyricsProviderMusixmatch:
+		return NewMusixmatchProvider(apiKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported lyrics provider %q", name)
+	}
+}
+
+const musixmatchBaseURL = "https://api.musixmatch.com/ws/1.1"
+
+// MusixmatchProvider looks lyrics up on Musixmatch, by ISRC first and then by
+// title and artist
+type MusixmatchProvider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewMusixmatchProvider creates a Musixmatch lyrics provider
+func NewMusixmatchProvider(apiKey string) *MusixmatchProvider {
+	return &MusixmatchProvider{
+		apiKey:     apiKey,
+		baseURL:    musixmatchBaseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// musixmatchTrackResponse is the envelope returned by track.get and matcher.track.get
+type musixmatchTrackResponse struct {
+	Message struct {
+		Header struct {
+			StatusCode int `json:"status_code"`
+		} `json:"header"`
+		Body json.RawMessage `json:"body"`
+	} `json:"message"`
+}
+
+type musixmatchTrackBody struct {
+	Track struct {
+		HasLyrics     int    `json:"has_lyrics"`
+		TrackShareURL string `json:"track_share_url"`
+	} `json:"track"`
+}
+
+// FindLyricsURL implements LyricsProvider
+func (m *MusixmatchProvider) FindLyricsURL(ctx context.Context, isrc, title, artist string) (string, error) {
+	if isrc != "" {
+		lyricsURL, err := m.lookup(ctx, "track.get", url.Values{"track_isrc": {isrc}})
+		if err != nil || lyricsURL != "" {
+			return lyricsURL, err
+		}
+	}
+	if title == "" || artist == "" {
+		return "", nil
+	}
+	return m.lookup(ctx, "matcher.track.get", url.Values{"q_track": {title}, "q_artist": {artist}})
+}
+
+// lookup calls a Musixmatch track method and returns the share URL when the
+// matched track has lyrics
+func (m *MusixmatchProvider) lookup(ctx context.Context, method string, params url.Values) (string, error) {
+	params.Set("apikey", m.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.baseURL+"/"+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create lyrics request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query lyrics provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("lyrics provider returned status %d", resp.StatusCode)
+	}
+
+	var envelope musixmatchTrackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return "", fmt.Errorf("failed to decode lyrics response: %w", err)
+	}
+
+	// Musixmatch reports errors in the body header; 404 means no match
+	switch status := envelope.Message.Header.StatusCode; status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("lyrics provider returned status %d", status)
+	}
+
+	var body musixmatchTrackBody
+	if err := json.Unmarshal(envelope.Message.Body, &body); err != nil {
+		return "", fmt.Errorf("failed to decode lyrics track: %w", err)
+	}
+	if body.Track.HasLyrics == 0 {
+		return "", nil
+	}
+	return body.Track.TrackShareURL, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMusixmatch(t *testing.T, handler http.HandlerFunc) *MusixmatchProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	provider := NewMusixmatchProvider("test-key")
+	provider.baseURL = server.URL
+	return provider
+}
+
+func musixmatchTrack(w http.ResponseWriter, hasLyrics int, shareURL string) {
+	fmt.Fprintf(w, `{"message":{"header":{"status_code":200},"body":{"track":{"has_lyrics":%d,"track_share_url":%q}}}}`, hasLyrics, shareURL)
+}
+
+func TestMusixmatchProvider_FindsByISRC(t *testing.T) {
+	provider := newTestMusixmatch(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/track.get", r.URL.Path)
+		assert.Equal(t, "USUM71703861", r.URL.Query().Get("track_isrc"))
+		assert.Equal(t, "test-key", r.URL.Query().Get("apikey"))
+		musixmatchTrack(w, 1, "https://www.musixmatch.com/lyrics/Artist/Song")
+	})
+
+	lyricsURL, err := provider.FindLyricsURL(context.Background(), "USUM71703861", "Song", "Artist")
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.musixmatch.com/lyrics/Artist/Song", lyricsURL)
+}
+
+func TestMusixmatchProvider_FallsBackToTitleAndArtist(t *testing.T) {
+	provider := newTestMusixmatch(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/track.get" {
+			fmt.Fprint(w, `{"message":{"header":{"status_code":404},"body":[]}}`)
+			return
+		}
+		assert.Equal(t, "/matcher.track.get", r.URL.Path)
+		assert.Equal(t, "Song", r.URL.Query().Get("q_track"))
+		musixmatchTrack(w, 1, "https://www.musixmatch.com/lyrics/Artist/Song")
+	})
+
+	lyricsURL, err := provider.FindLyricsURL(context.Background(), "USUM71703861", "Song", "Artist")
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.musixmatch.com/lyrics/Artist/Song", lyricsURL)
+}
+
+func TestMusixmatchProvider_NoMatch(t *testing.T) {
+	provider := newTestMusixmatch(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/track.get" {
+			fmt.Fprint(w, `{"message":{"header":{"status_code":404},"body":[]}}`)
+			return
+		}
+		// Matched, but instrumental
+		musixmatchTrack(w, 0, "https://www.musixmatch.com/lyrics/Artist/Instrumental")
+	})
+
+	lyricsURL, err := provider.FindLyricsURL(context.Background(), "USUM71703861", "Instrumental", "Artist")
+	require.NoError(t, err)
+	assert.Empty(t, lyricsURL)
+}
+
+func TestNewLyricsProvider(t *testing.T) {
+	provider, err := NewLyricsProvider("", "key")
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = NewLyricsProvider("musixmatch", "")
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = NewLyricsProvider("Musixmatch", "key")
+	assert.NoError(t, err)
+	assert.IsType(t, &MusixmatchProvider{}, provider)
+
+	_, err = NewLyricsProvider("genius", "key")
+	assert.Error(t, err)
+}
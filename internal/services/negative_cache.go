@@ -0,0 +1,48 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"time"
+
+	"songshare/internal/cache"
+	"songshare/internal/config"
+)
+
+// defaultNegativeCacheTTL is how long a "not found" track lookup is cached
+// when the platform config doesn't set one
+const defaultNegativeCacheTTL = 10 * time.Minute
+
+// notFoundSentinel is cached under a track's key when the platform reported it
+// missing. The leading NUL byte makes it invalid JSON, so it can never be a
+// serialized TrackInfo.
+var notFoundSentinel = []byte("\x00not_found")
+
+// isNotFoundSentinel reports whether a cached value records a missing track
+func isNotFoundSentinel(data []byte) bool {
+	return bytes.Equal(data, notFoundSentinel)
+}
+
+// cacheNotFound records that key's track is missing for ttl
+func cacheNotFound(ctx context.Context, c cache.Cache, key string, ttl time.Duration) {
+	if err := c.Set(ctx, key, notFoundSentinel, ttl); err != nil {
+		slog.Error("Failed to cache missing track", "key", key, "error", err, requestIDAttr(ctx))
+	}
+}
+
+// negativeCacheConfigurable is implemented by services that cache "not found"
+// track lookups
+type negativeCacheConfigurable interface {
+	setNegativeCacheTTL(ttl time.Duration)
+}
+
+// ConfigureNegativeCache applies a platform's negative cache TTL to a service
+// built from credentials alone, such as Spotify or Apple Music
+func ConfigureNegativeCache(service PlatformService, cfg *config.PlatformConfig) {
+	configurable, ok := service.(negativeCacheConfigurable)
+	if !ok || cfg == nil || cfg.NegativeCacheTTL <= 0 {
+		return
+	}
+	configurable.setNegativeCacheTTL(time.Duration(cfg.NegativeCacheTTL) * time.Second)
+}
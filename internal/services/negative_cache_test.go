@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"songshare/internal/cache"
+	"songshare/internal/config"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// memoryCache is an in-memory cache recording each entry's TTL
+type memoryCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if value, ok := m.data[key]; ok {
+		return value, nil
+	}
+	return nil, &cache.CacheError{Operation: "get", Key: key}
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+	m.ttls[key] = expiration
+	return nil
+}
+
+func (m *memoryCache) Delete(ctx context.Context, key string) error         { return nil }
+func (m *memoryCache) Exists(ctx context.Context, key string) (bool, error) { return false, nil }
+func (m *memoryCache) Close() error                                         { return nil }
+func (m *memoryCache) Health(ctx context.Context) error                     { return nil }
+
+// notFoundServer answers every request with 404, counting them
+func notFoundServer(t *testing.T) (*url.URL, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+	return target, &requests
+}
+
+func TestSpotifyGetTrackByID_CachesNotFound(t *testing.T) {
+	target, requests := notFoundServer(t)
+	memory := newMemoryCache()
+	service := &spotifyService{
+		client:           resty.New().SetTransport(rewriteTransport{target: target}),
+		tokenSource:      &clientcredentials.Config{},
+		accessToken:      "test-token",
+		tokenExpiry:      time.Now().Add(time.Hour),
+		cache:            memory,
+		negativeCacheTTL: defaultNegativeCacheTTL,
+	}
+	ConfigureNegativeCache(service, &config.PlatformConfig{Name: "spotify", NegativeCacheTTL: 60})
+
+	for range 3 {
+		_, err := service.GetTrackByID(context.Background(), "typo")
+		assert.Equal(t, ErrorCategoryNoResults, ClassifyError(err))
+		assert.Contains(t, err.Error(), "track not found")
+	}
+	assert.Equal(t, int32(1), requests.Load(), "later lookups are answered from the cache")
+	assert.Equal(t, time.Minute, memory.ttls["api:spotify:track:typo"])
+}
+
+func TestAppleMusicGetTrackByID_CachesNotFound(t *testing.T) {
+	target, requests := notFoundServer(t)
+	memory := newMemoryCache()
+	service := &appleMusicService{
+		client:           resty.New().SetTransport(rewriteTransport{target: target}),
+		jwtToken:         "test-token",
+		tokenExpiry:      time.Now().Add(time.Hour),
+		cache:            memory,
+		negativeCacheTTL: defaultNegativeCacheTTL,
+	}
+
+	for range 2 {
+		_, err := service.GetTrackByID(context.Background(), "123")
+		assert.Equal(t, ErrorCategoryNoResults, ClassifyError(err))
+	}
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, defaultNegativeCacheTTL, memory.ttls["api:apple_music:track:123"])
+}
+
+func TestNotFoundSentinel_NeverATrack(t *testing.T) {
+	var track TrackInfo
+	assert.Error(t, json.Unmarshal(notFoundSentinel, &track))
+
+	data, err := json.Marshal(&TrackInfo{Title: "not_found"})
+	require.NoError(t, err)
+	assert.False(t, isNotFoundSentinel(data))
+}
@@ -2,9 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	neturl "net/url"
 	"regexp"
+	"strings"
 	"sync"
+	"time"
 
 	"songshare/internal/models"
 )
@@ -20,6 +27,9 @@ type PlatformService interface {
 	// GetTrackByID fetches track information using platform-specific ID
 	GetTrackByID(ctx context.Context, trackID string) (*TrackInfo, error)
 
+	// GetTrackByURL fetches track information for a platform URL
+	GetTrackByURL(ctx context.Context, url string) (*TrackInfo, error)
+
 	// SearchTrack searches for tracks on the platform
 	SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error)
 
@@ -33,8 +43,37 @@ type PlatformService interface {
 	Health(ctx context.Context) error
 }
 
+// ConfigurableService is implemented by platform services that can be built
+// without credentials and report whether they are usable
+type ConfigurableService interface {
+	IsConfigured() bool
+}
+
+// IsConfigured reports whether service can make API calls. Services that don't
+// implement ConfigurableService are assumed configured.
+func IsConfigured(service PlatformService) bool {
+	if configurable, ok := service.(ConfigurableService); ok {
+		return configurable.IsConfigured()
+	}
+	return true
+}
+
+// trackByURL is the usual GetTrackByURL: it parses the track ID out of url
+// and fetches the track by it
+func trackByURL(ctx context.Context, service PlatformService, url string) (*TrackInfo, error) {
+	parsed, err := service.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return service.GetTrackByID(ctx, parsed.ExternalID)
+}
+
 // TrackInfo represents track information from a platform
 type TrackInfo struct {
+	// Kind is empty for tracks; album and artist results only appear when the
+	// search asked for them via SearchQuery.IncludeKinds
+	Kind EntityKind `json:"kind,omitempty"`
+
 	// Platform identifiers
 	Platform   string `json:"platform"`
 	ExternalID string `json:"external_id"`
@@ -56,6 +95,18 @@ type TrackInfo struct {
 
 	// Platform-specific data
 	Available bool `json:"available"`
+
+	// MatchConfidence is below 1 when the track was found by a fuzzy fallback
+	// rather than its identifier; 0 means an exact match
+	MatchConfidence float64 `json:"match_confidence,omitempty"`
+}
+
+// LinkConfidence is the confidence to store on a platform link to the track
+func (t *TrackInfo) LinkConfidence() float64 {
+	if t.MatchConfidence > 0 {
+		return t.MatchConfidence
+	}
+	return 1.0
 }
 
 // SearchQuery represents a search query for tracks
@@ -66,16 +117,33 @@ type SearchQuery struct {
 	ISRC   string `json:"isrc,omitempty"`
 	Query  string `json:"query,omitempty"` // Free-form search query
 	Limit  int    `json:"limit,omitempty"`
+
+	// Explicit filters explicit content; empty means include
+	Explicit ExplicitFilter `json:"explicit,omitempty"`
+
+	// IncludeKinds lists extra entity kinds (albums, artists) to return
+	// alongside tracks; empty means tracks only
+	IncludeKinds []EntityKind `json:"include_kinds,omitempty"`
 }
 
-// ToSong converts TrackInfo to a models.Song
+// ToSong converts TrackInfo to a models.Song. Only a valid ISRC is kept, in
+// normalized form; a malformed one leaves the song without an ISRC.
 func (t *TrackInfo) ToSong() *models.Song {
 	song := models.NewSong(t.Title, joinArtists(t.Artists))
+	if len(t.Artists) > 1 {
+		song.Artists = append([]string(nil), t.Artists...)
+	}
 	song.Album = t.Album
-	song.ISRC = t.ISRC
+	if isrc, err := models.NormalizeISRC(t.ISRC); err == nil {
+		song.ISRC = isrc
+	} else if t.ISRC != "" {
+		slog.Warn("Dropping malformed ISRC", "platform", t.Platform, "external_id", t.ExternalID, "isrc", t.ISRC)
+	}
 
 	// Add platform link
-	song.AddPlatformLink(t.Platform, t.ExternalID, t.URL, 1.0)
+	if err := song.AddPlatformLink(t.Platform, t.ExternalID, t.URL, t.LinkConfidence()); err != nil {
+		slog.Warn("Rejected platform link", "platform", t.Platform, "external_id", t.ExternalID, "error", err)
+	}
 
 	// Set metadata
 	song.Metadata.Duration = t.Duration
@@ -103,15 +171,47 @@ func joinArtists(artists []string) string {
 	return result
 }
 
+// Resource types a platform URL can point at
+const (
+	ResourceTypeTrack    = "track"
+	ResourceTypeAlbum    = "album"
+	ResourceTypePlaylist = "playlist"
+)
+
 // URLPattern represents a URL pattern for parsing platform URLs
 type URLPattern struct {
-	Regex        *regexp.Regexp
-	Platform     string
-	TrackIDIndex int      // Index of the track ID capture group
-	Description  string   // Human-readable description of the pattern
-	Examples     []string // Example URLs this pattern should match
+	Regex             *regexp.Regexp
+	Platform          string
+	TrackIDIndex      int      // Index of the track (or other resource) ID capture group
+	ResourceType      string   // Resource the pattern matches; empty means ResourceTypeTrack
+	ResourceTypeIndex int      // Index of a capture group holding the resource type; overrides ResourceType when set
+	Description       string   // Human-readable description of the pattern
+	Examples          []string // Example URLs this pattern should match
+}
+
+// resourceType returns the resource type of a URL matched by the pattern
+func (p URLPattern) resourceType(matches []string) string {
+	if p.ResourceTypeIndex > 0 && len(matches) > p.ResourceTypeIndex && matches[p.ResourceTypeIndex] != "" {
+		return matches[p.ResourceTypeIndex]
+	}
+	if p.ResourceType == "" {
+		return ResourceTypeTrack
+	}
+	return p.ResourceType
+}
+
+// matchesTracks reports whether the pattern can match track URLs
+func (p URLPattern) matchesTracks() bool {
+	return p.ResourceTypeIndex == 0 && (p.ResourceType == "" || p.ResourceType == ResourceTypeTrack)
 }
 
+// spotifyURLPrefix matches the start of a Spotify web URL up to the resource
+// path. open.spotify.com, the legacy play.spotify.com and bare spotify.com all
+// serve the same IDs, with an optional intl-xx locale segment. The host must
+// start the URL or follow the scheme, so www.spotify.com (the marketing site,
+// which has no track pages) and look-alike domains don't match.
+const spotifyURLPrefix = `(?:^|https?://)(?:(?:open|play)\.)?spotify\.com/(?:intl-[a-z]+/)?`
+
 // URLPatternRegistry manages URL patterns for all platforms
 type URLPatternRegistry struct {
 	patterns []URLPattern
@@ -122,22 +222,33 @@ type URLPatternRegistry struct {
 var patternRegistry = &URLPatternRegistry{
 	patterns: []URLPattern{
 		{
-			Regex:        regexp.MustCompile(`(?:https?://)?music\.apple\.com/[a-z]{2}/(?:album|song)/(?:[^/]+/)?(\d+)`),
+			Regex:        regexp.MustCompile(`(?:https?://)?music\.apple\.com/[a-z]{2}/album/(?:[^/?]+/)?(\d+)\?(?:[^#]*&)?i=\d+`),
 			Platform:     "apple_music",
 			TrackIDIndex: 1,
-			Description:  "Apple Music track URLs",
+			Description:  "Apple Music album URLs with track ID parameter",
 			Examples: []string{
 				"https://music.apple.com/us/album/bohemian-rhapsody/1440806041?i=1440806053",
+			},
+		},
+		{
+			Regex:        regexp.MustCompile(`(?:https?://)?music\.apple\.com/[a-z]{2}/song/(?:[^/]+/)?(\d+)`),
+			Platform:     "apple_music",
+			TrackIDIndex: 1,
+			Description:  "Apple Music track URLs",
+			Examples: []string{
 				"music.apple.com/us/song/1440806053",
+				"https://music.apple.com/us/song/bohemian-rhapsody/1440806053",
 			},
 		},
 		{
-			Regex:        regexp.MustCompile(`(?:https?://)?(?:open\.)?spotify\.com/track/([a-zA-Z0-9]+)`),
+			Regex:        regexp.MustCompile(spotifyURLPrefix + `track/([a-zA-Z0-9]+)`),
 			Platform:     "spotify",
 			TrackIDIndex: 1,
 			Description:  "Spotify track URLs",
 			Examples: []string{
 				"https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+				"https://open.spotify.com/intl-de/track/4iV5W9uYEdYUVa79Axb7Rh",
+				"https://play.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
 				"spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
 			},
 		},
@@ -161,6 +272,41 @@ var patternRegistry = &URLPatternRegistry{
 				"https://tidal.com/browse/album/77646164?play=true&trackId=77646168",
 			},
 		},
+		// Album and playlist patterns come after the track patterns, so album
+		// URLs that name a track parse as that track
+		{
+			Regex:             regexp.MustCompile(`(?:https?://)?music\.apple\.com/[a-z]{2}/(album|playlist)/(?:[^/?]+/)?((?:pl\.)?[a-zA-Z0-9-]+)`),
+			Platform:          "apple_music",
+			TrackIDIndex:      2,
+			ResourceTypeIndex: 1,
+			Description:       "Apple Music album and playlist URLs",
+			Examples: []string{
+				"https://music.apple.com/us/album/a-night-at-the-opera/1440806041",
+				"https://music.apple.com/us/playlist/todays-hits/pl.f4d106fed2bd41149aaacabb233eb5eb",
+			},
+		},
+		{
+			Regex:             regexp.MustCompile(spotifyURLPrefix + `(album|playlist)/([a-zA-Z0-9]+)`),
+			Platform:          "spotify",
+			TrackIDIndex:      2,
+			ResourceTypeIndex: 1,
+			Description:       "Spotify album and playlist URLs",
+			Examples: []string{
+				"https://open.spotify.com/album/6i6folBtxKV28WX3msQ4FE",
+				"https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M",
+			},
+		},
+		{
+			Regex:             regexp.MustCompile(`(?:https?://)?(?:www\.)?(?:listen\.)?tidal\.com/(?:browse/)?(album|playlist)/([a-zA-Z0-9-]+)`),
+			Platform:          "tidal",
+			TrackIDIndex:      2,
+			ResourceTypeIndex: 1,
+			Description:       "Tidal album and playlist URLs",
+			Examples: []string{
+				"https://tidal.com/browse/album/77646164",
+				"https://listen.tidal.com/playlist/1b087082-ab54-4e7d-a0d3-b1cf1cf18ebc",
+			},
+		},
 	},
 }
 
@@ -262,18 +408,38 @@ func RegisterURLPattern(platform string, regexPattern string, trackIDIndex int,
 	return patternRegistry.RegisterURLPattern(pattern)
 }
 
-// ParsePlatformURL attempts to parse a URL and determine which platform it belongs to
+// ParsePlatformURL attempts to parse a URL and determine which platform it belongs to.
+// Only track URLs parse; album and playlist URLs are reported as errors.
 func ParsePlatformURL(url string) (platform string, trackID string, err error) {
+	platform, resourceType, id, err := ParsePlatformResourceURL(url)
+	if err != nil {
+		return "", "", err
+	}
+	if resourceType != ResourceTypeTrack {
+		return "", "", &PlatformError{
+			Platform:  platform,
+			Operation: "parse_url",
+			Message:   fmt.Sprintf("%s URL is not a track URL", resourceType),
+			URL:       url,
+		}
+	}
+	return platform, id, nil
+}
+
+// ParsePlatformResourceURL parses a URL into its platform, the type of
+// resource it points at (ResourceTypeTrack, ResourceTypeAlbum or
+// ResourceTypePlaylist) and the resource ID
+func ParsePlatformResourceURL(url string) (platform, resourceType, id string, err error) {
 	patterns := patternRegistry.GetPatterns()
 
 	for _, pattern := range patterns {
 		matches := pattern.Regex.FindStringSubmatch(url)
 		if len(matches) > pattern.TrackIDIndex {
-			return pattern.Platform, matches[pattern.TrackIDIndex], nil
+			return pattern.Platform, pattern.resourceType(matches), matches[pattern.TrackIDIndex], nil
 		}
 	}
 
-	return "", "", &PlatformError{
+	return "", "", "", &PlatformError{
 		Platform:  "unknown",
 		Operation: "parse_url",
 		Message:   "unsupported platform URL",
@@ -281,6 +447,114 @@ func ParsePlatformURL(url string) (platform string, trackID string, err error) {
 	}
 }
 
+// Short links produced by platform share sheets, which must be expanded
+// before they parse
+var shortLinkHosts = map[string]bool{
+	"spotify.link":     true,
+	"spotify.app.link": true,
+	"apple.co":         true,
+}
+
+// Short link expansion limits
+const (
+	shortLinkMaxRedirects = 5
+	shortLinkTimeout      = 5 * time.Second
+)
+
+// shortLinkClient follows short link redirects, stopping at the first URL
+// that parses as a platform URL so the platform's own page is never fetched
+var shortLinkClient = &http.Client{
+	Timeout: shortLinkTimeout,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) > shortLinkMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", shortLinkMaxRedirects)
+		}
+		if _, _, _, err := ParsePlatformResourceURL(req.URL.String()); err == nil {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	},
+}
+
+// IsShortURL reports whether rawURL is a platform short link such as
+// https://spotify.link/abc123 or https://apple.co/xyz
+func IsShortURL(rawURL string) bool {
+	parsed, err := neturl.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	return shortLinkHosts[strings.ToLower(parsed.Hostname())]
+}
+
+// ResolveShortURL expands a platform short link into the URL it redirects to,
+// following at most 5 redirects within 5 seconds without reading any body.
+// Other URLs are returned unchanged.
+func ResolveShortURL(ctx context.Context, url string) (string, error) {
+	if !IsShortURL(url) {
+		return url, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, shortLinkTimeout)
+	defer cancel()
+
+	// Some link services reject HEAD, so fall back to a GET whose body is discarded
+	resp, err := followShortURL(ctx, http.MethodHead, url)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		resp, err = followShortURL(ctx, http.MethodGet, url)
+	}
+	if err != nil {
+		return "", &PlatformError{Platform: "unknown", Operation: "expand_short_url", URL: url, Err: err}
+	}
+
+	expanded := resp.Request.URL
+	if location, err := resp.Location(); err == nil {
+		expanded = location
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return "", &PlatformError{
+			Platform:  "unknown",
+			Operation: "expand_short_url",
+			Message:   fmt.Sprintf("short link returned status %d", resp.StatusCode),
+			URL:       url,
+			Category:  CategoryForStatus(resp.StatusCode),
+		}
+	}
+	return expanded.String(), nil
+}
+
+// followShortURL requests url with method, following redirects
+func followShortURL(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := shortLinkClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// MatchesPlatformURL reports whether url matches a registered track pattern for
+// platform. Platforms without registered patterns cannot be checked and always match.
+func MatchesPlatformURL(platform, url string) bool {
+	hasPattern := false
+	for _, pattern := range patternRegistry.GetPatterns() {
+		if pattern.Platform != platform || !pattern.matchesTracks() {
+			continue
+		}
+		hasPattern = true
+		if pattern.Regex.MatchString(url) {
+			return true
+		}
+	}
+	return !hasPattern
+}
+
+func init() {
+	models.SetPlatformURLValidator(MatchesPlatformURL)
+}
+
 // GetURLPatterns returns all registered URL patterns (for debugging/documentation)
 func GetURLPatterns() []URLPattern {
 	return patternRegistry.GetPatterns()
@@ -289,7 +563,7 @@ func GetURLPatterns() []URLPattern {
 // Legacy pattern variables for backward compatibility
 var (
 	SpotifyURLPattern = URLPattern{
-		Regex:        regexp.MustCompile(`(?:https?://)?(?:open\.)?spotify\.com/track/([a-zA-Z0-9]+)`),
+		Regex:        regexp.MustCompile(spotifyURLPrefix + `track/([a-zA-Z0-9]+)`),
 		Platform:     "spotify",
 		TrackIDIndex: 1,
 	}
@@ -301,13 +575,27 @@ var (
 	}
 )
 
+// Platform error categories, used to tell users why a platform returned nothing
+const (
+	ErrorCategoryAuth          = "auth_error"
+	ErrorCategoryRateLimited   = "rate_limited"
+	ErrorCategoryTimeout       = "timeout"
+	ErrorCategoryUpstream      = "upstream_error"
+	ErrorCategoryNoResults     = "no_results"
+	ErrorCategoryNotConfigured = "not_configured" // Platform has no credentials
+	ErrorCategoryUnavailable   = "unavailable"    // Platform's recent health check failed
+)
+
 // PlatformError represents an error from a platform service
 type PlatformError struct {
 	Platform  string
 	Operation string
 	Message   string
 	URL       string
-	Err       error
+	Category  string // One of the ErrorCategory constants; empty if unclassified
+	// RetryAfter is the wait the platform asked for when it rate limited us
+	RetryAfter time.Duration
+	Err        error
 }
 
 func (e *PlatformError) Error() string {
@@ -327,3 +615,51 @@ func (e *PlatformError) Error() string {
 func (e *PlatformError) Unwrap() error {
 	return e.Err
 }
+
+// CategoryForStatus maps an upstream HTTP status code to an error category
+func CategoryForStatus(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorCategoryAuth
+	case status == http.StatusTooManyRequests:
+		return ErrorCategoryRateLimited
+	case status == http.StatusNotFound:
+		return ErrorCategoryNoResults
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrorCategoryTimeout
+	default:
+		return ErrorCategoryUpstream
+	}
+}
+
+// ClassifyError returns the category of a platform error. Explicit categories
+// anywhere in the wrap chain win; otherwise auth operations, deadlines and
+// network timeouts are recognized, and everything else is an upstream error.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		platformErr, ok := current.(*PlatformError)
+		if !ok {
+			continue
+		}
+		if platformErr.Category != "" {
+			return platformErr.Category
+		}
+		if platformErr.Operation == "auth" {
+			return ErrorCategoryAuth
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCategoryTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorCategoryTimeout
+	}
+
+	return ErrorCategoryUpstream
+}
@@ -1,7 +1,13 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"regexp"
 	"testing"
 
@@ -149,11 +155,28 @@ func TestSpotifyURLPattern(t *testing.T) {
 			expectedID:  "4iV5W9uYEdYUVa79Axb7Rh",
 		},
 		{
-			name:        "Spotify URL with www",
-			url:         "https://www.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+			name:        "Legacy play.spotify.com URL",
+			url:         "https://play.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+			shouldMatch: true,
+			expectedID:  "4iV5W9uYEdYUVa79Axb7Rh",
+		},
+		{
+			name:        "Localized Spotify URL",
+			url:         "https://open.spotify.com/intl-de/track/4iV5W9uYEdYUVa79Axb7Rh",
 			shouldMatch: true,
 			expectedID:  "4iV5W9uYEdYUVa79Axb7Rh",
 		},
+		{
+			// www.spotify.com is the marketing site; it has no track pages
+			name:        "Spotify marketing site URL",
+			url:         "https://www.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+			shouldMatch: false,
+		},
+		{
+			name:        "Look-alike Spotify domain",
+			url:         "https://notspotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+			shouldMatch: false,
+		},
 		{
 			name:        "Non-Spotify URL",
 			url:         "https://music.apple.com/us/song/test/123",
@@ -176,6 +199,36 @@ func TestSpotifyURLPattern(t *testing.T) {
 	}
 }
 
+func TestParsePlatformURL_SpotifyHostVariants(t *testing.T) {
+	service := NewSpotifyService("", "", nil)
+	for _, url := range []string{
+		"https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		"http://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		"https://open.spotify.com/intl-fr/track/4iV5W9uYEdYUVa79Axb7Rh?si=abc123",
+		"https://play.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		"https://spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		"open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+	} {
+		platform, trackID, err := ParsePlatformURL(url)
+		require.NoError(t, err, url)
+		assert.Equal(t, "spotify", platform, url)
+		assert.Equal(t, "4iV5W9uYEdYUVa79Axb7Rh", trackID, url)
+
+		// Every variant canonicalizes to the same open.spotify.com link
+		track, err := service.ParseURL(url)
+		require.NoError(t, err, url)
+		assert.Equal(t, "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh", track.URL, url)
+	}
+
+	for _, url := range []string{
+		"https://www.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		"https://evil-spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+	} {
+		_, _, err := ParsePlatformURL(url)
+		assert.Error(t, err, url)
+	}
+}
+
 func TestAppleMusicURLPattern(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -235,6 +288,33 @@ func TestAppleMusicURLPattern(t *testing.T) {
 	}
 }
 
+func TestTrackByURL(t *testing.T) {
+	url := "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh"
+	want := &TrackInfo{Platform: "spotify", ExternalID: "4iV5W9uYEdYUVa79Axb7Rh", Title: "Never Gonna Give You Up"}
+
+	t.Run("Fetches the parsed track ID", func(t *testing.T) {
+		service := NewMockPlatformService("spotify")
+		service.On("ParseURL", url).Return(&TrackInfo{Platform: "spotify", ExternalID: want.ExternalID}, nil)
+		service.On("GetTrackByID", context.Background(), want.ExternalID).Return(want, nil)
+
+		track, err := trackByURL(context.Background(), service, url)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, track)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Parse failure skips the fetch", func(t *testing.T) {
+		service := NewMockPlatformService("spotify")
+		service.On("ParseURL", "https://example.com").Return(nil, &PlatformError{Platform: "spotify", Operation: "parse_url"})
+
+		_, err := trackByURL(context.Background(), service, "https://example.com")
+
+		require.Error(t, err)
+		service.AssertNotCalled(t, "GetTrackByID", context.Background(), "")
+	})
+}
+
 func TestPlatformError(t *testing.T) {
 	err := &PlatformError{
 		Platform:  "spotify",
@@ -325,6 +405,8 @@ func TestTrackInfo_ToSong_MultipleArtists(t *testing.T) {
 
 	// Should join multiple artists with commas
 	assert.Equal(t, "Artist One, Artist Two, Artist Three", song.Artist)
+	// and keep the credited list, since a name may itself contain a comma
+	assert.Equal(t, []string{"Artist One", "Artist Two", "Artist Three"}, song.Artists)
 }
 
 func TestTrackInfo_ToSong_EmptyFields(t *testing.T) {
@@ -341,6 +423,7 @@ func TestTrackInfo_ToSong_EmptyFields(t *testing.T) {
 
 	assert.Equal(t, "Minimal Song", song.Title)
 	assert.Equal(t, "Solo Artist", song.Artist)
+	assert.Empty(t, song.Artists)
 	assert.Empty(t, song.Album)
 	assert.Empty(t, song.ISRC)
 	assert.Zero(t, song.Metadata.Duration)
@@ -733,3 +816,196 @@ func BenchmarkURLPatternRegistry_RegisterPattern(b *testing.B) {
 		_ = registry.RegisterURLPattern(pattern)
 	}
 }
+
+func TestMatchesPlatformURL(t *testing.T) {
+	assert.True(t, MatchesPlatformURL("spotify", "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh"))
+	assert.True(t, MatchesPlatformURL("tidal", buildTidalURL("77646168")))
+	assert.False(t, MatchesPlatformURL("apple_music", "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh"))
+	assert.True(t, MatchesPlatformURL("no_pattern_platform", "https://example.com/anything"))
+}
+
+func TestParsePlatformResourceURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		platform     string
+		resourceType string
+		id           string
+	}{
+		{"https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh", "spotify", ResourceTypeTrack, "4iV5W9uYEdYUVa79Axb7Rh"},
+		{"https://open.spotify.com/album/6i6folBtxKV28WX3msQ4FE", "spotify", ResourceTypeAlbum, "6i6folBtxKV28WX3msQ4FE"},
+		{"https://open.spotify.com/intl-de/playlist/37i9dQZF1DXcBWIGoYBM5M", "spotify", ResourceTypePlaylist, "37i9dQZF1DXcBWIGoYBM5M"},
+		{"https://music.apple.com/us/song/bohemian-rhapsody/1440857781", "apple_music", ResourceTypeTrack, "1440857781"},
+		{"https://music.apple.com/us/album/a-night-at-the-opera/1440857777?i=1440857781", "apple_music", ResourceTypeTrack, "1440857777"},
+		{"https://music.apple.com/us/album/a-night-at-the-opera/1440857777?l=en&i=1440857781", "apple_music", ResourceTypeTrack, "1440857777"},
+		{"https://music.apple.com/us/album/a-night-at-the-opera/1440857777", "apple_music", ResourceTypeAlbum, "1440857777"},
+		{"https://music.apple.com/gb/playlist/todays-hits/pl.f4d106fed2bd41149aaacabb233eb5eb", "apple_music", ResourceTypePlaylist, "pl.f4d106fed2bd41149aaacabb233eb5eb"},
+		{"https://tidal.com/browse/album/77646164?play=true&trackId=77646168", "tidal", ResourceTypeTrack, "77646168"},
+		{"https://tidal.com/browse/album/77646164", "tidal", ResourceTypeAlbum, "77646164"},
+		{"https://listen.tidal.com/playlist/1b087082-ab54-4e7d-a0d3-b1cf1cf18ebc", "tidal", ResourceTypePlaylist, "1b087082-ab54-4e7d-a0d3-b1cf1cf18ebc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			platform, resourceType, id, err := ParsePlatformResourceURL(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.platform, platform)
+			assert.Equal(t, tt.resourceType, resourceType)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+
+	_, _, _, err := ParsePlatformResourceURL("https://example.com/album/123")
+	var platformErr *PlatformError
+	assert.ErrorAs(t, err, &platformErr)
+}
+
+func TestParsePlatformURL_RejectsAlbumsAndPlaylists(t *testing.T) {
+	for _, url := range []string{
+		"https://open.spotify.com/album/6i6folBtxKV28WX3msQ4FE",
+		"https://music.apple.com/us/album/a-night-at-the-opera/1440857777",
+		"https://listen.tidal.com/playlist/1b087082-ab54-4e7d-a0d3-b1cf1cf18ebc",
+	} {
+		platform, trackID, err := ParsePlatformURL(url)
+		assert.Error(t, err, url)
+		assert.Empty(t, platform)
+		assert.Empty(t, trackID)
+	}
+	assert.False(t, MatchesPlatformURL("spotify", "https://open.spotify.com/album/6i6folBtxKV28WX3msQ4FE"))
+}
+
+func TestTrackInfo_ToSong_RejectsMismatchedURL(t *testing.T) {
+	track := &TrackInfo{
+		Platform:   "apple_music",
+		ExternalID: "4iV5W9uYEdYUVa79Axb7Rh",
+		URL:        "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		Title:      "Test Song",
+		Artists:    []string{"Test Artist"},
+	}
+
+	song := track.ToSong()
+	assert.False(t, song.HasPlatform("apple_music"))
+}
+
+func TestClassifyError(t *testing.T) {
+	timeoutErr := &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "Nil error", err: nil, expected: ""},
+		{name: "Explicit category", err: &PlatformError{Platform: "spotify", Category: ErrorCategoryRateLimited}, expected: ErrorCategoryRateLimited},
+		{name: "Auth operation", err: &PlatformError{Platform: "spotify", Operation: "auth", Message: "failed to get access token"}, expected: ErrorCategoryAuth},
+		{name: "Wrapped category", err: &PlatformError{Platform: "tidal", Operation: "search", Err: &PlatformError{Category: ErrorCategoryAuth}}, expected: ErrorCategoryAuth},
+		{name: "fmt wrapped auth", err: fmt.Errorf("failed to get valid token: %w", &PlatformError{Operation: "auth"}), expected: ErrorCategoryAuth},
+		{name: "Deadline exceeded", err: &PlatformError{Platform: "spotify", Operation: "search", Err: context.DeadlineExceeded}, expected: ErrorCategoryTimeout},
+		{name: "Network timeout", err: fmt.Errorf("request failed: %w", timeoutErr), expected: ErrorCategoryTimeout},
+		{name: "Unknown error", err: errors.New("boom"), expected: ErrorCategoryUpstream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClassifyError(tt.err))
+		})
+	}
+}
+
+func TestCategoryForStatus(t *testing.T) {
+	assert.Equal(t, ErrorCategoryAuth, CategoryForStatus(http.StatusUnauthorized))
+	assert.Equal(t, ErrorCategoryAuth, CategoryForStatus(http.StatusForbidden))
+	assert.Equal(t, ErrorCategoryRateLimited, CategoryForStatus(http.StatusTooManyRequests))
+	assert.Equal(t, ErrorCategoryNoResults, CategoryForStatus(http.StatusNotFound))
+	assert.Equal(t, ErrorCategoryTimeout, CategoryForStatus(http.StatusGatewayTimeout))
+	assert.Equal(t, ErrorCategoryUpstream, CategoryForStatus(http.StatusInternalServerError))
+}
+
+func TestTrackInfo_ToSong_NormalizesISRC(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"GBUM71505078", "GBUM71505078"},
+		{"gb-um7-15-05078", "GBUM71505078"},
+		{"GB UM7 15 05078", "GBUM71505078"},
+		{"GBUM7150507", ""}, // too short
+		{"not-an-isrc", ""}, // garbage is dropped, not stored
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			song := (&TrackInfo{Platform: "spotify", Title: "Bohemian Rhapsody", ISRC: tt.raw}).ToSong()
+			assert.Equal(t, tt.expected, song.ISRC)
+		})
+	}
+}
+
+// shortLinkServer stands in for every host the short link client contacts,
+// redirecting /hop paths to the next hop and /s/ links to the given targets
+func shortLinkServer(t *testing.T, targets map[string]string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && r.URL.Path == "/s/no-head" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.URL.Path == "/hop" {
+			http.Redirect(w, r, "/hop", http.StatusFound)
+			return
+		}
+		target, ok := targets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	}))
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	original := shortLinkClient.Transport
+	shortLinkClient.Transport = rewriteTransport{target: target}
+	t.Cleanup(func() { shortLinkClient.Transport = original })
+}
+
+func TestResolveShortURL(t *testing.T) {
+	shortLinkServer(t, map[string]string{
+		"/s/spotify":    "/s/branch-hop",
+		"/s/branch-hop": "https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv?si=abc",
+		"/s/no-head":    "https://music.apple.com/us/song/bohemian-rhapsody/1440806053",
+		"/s/loop":       "/hop",
+	})
+	ctx := context.Background()
+
+	expanded, err := ResolveShortURL(ctx, "https://spotify.link/s/spotify")
+	require.NoError(t, err)
+	assert.Equal(t, "https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv?si=abc", expanded)
+	platform, trackID, err := ParsePlatformURL(expanded)
+	require.NoError(t, err)
+	assert.Equal(t, "spotify", platform)
+	assert.Equal(t, "4u7EnebtmKWzUH433cf5Qv", trackID)
+
+	expanded, err = ResolveShortURL(ctx, "https://apple.co/s/no-head")
+	require.NoError(t, err)
+	assert.Equal(t, "https://music.apple.com/us/song/bohemian-rhapsody/1440806053", expanded)
+
+	_, err = ResolveShortURL(ctx, "https://apple.co/s/loop")
+	assert.ErrorContains(t, err, "stopped after 5 redirects")
+
+	_, err = ResolveShortURL(ctx, "https://apple.co/s/missing")
+	assert.Equal(t, ErrorCategoryNoResults, ClassifyError(err))
+
+	// Full platform URLs are returned without a request
+	full := "https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv"
+	expanded, err = ResolveShortURL(ctx, full)
+	require.NoError(t, err)
+	assert.Equal(t, full, expanded)
+}
+
+func TestIsShortURL(t *testing.T) {
+	assert.True(t, IsShortURL("https://spotify.link/abc123"))
+	assert.True(t, IsShortURL("https://APPLE.CO/xyz"))
+	assert.False(t, IsShortURL("https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv"))
+	assert.False(t, IsShortURL("spotify.link/abc123"))
+	assert.False(t, IsShortURL("https://evil.example/spotify.link"))
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"regexp"
+)
+
+// Playlist kinds a collection can be resolved from
+const (
+	PlaylistKindPlaylist = "playlist"
+	PlaylistKindAlbum    = "album"
+)
+
+// PlaylistRef identifies a playlist or album on a platform
+type PlaylistRef struct {
+	Platform string `json:"platform"`
+	Kind     string `json:"kind"` // PlaylistKindPlaylist or PlaylistKindAlbum
+	ID       string `json:"id"`
+}
+
+// PlaylistPage is one page of a playlist's tracks
+type PlaylistPage struct {
+	Name     string       // Playlist or album name; set on the first page
+	ImageURL string       // Cover art, when the platform has one; set on the first page
+	Tracks   []*TrackInfo // Tracks on this page; podcast episodes and videos are skipped
+	Total    int          // Total entries in the playlist, when the platform reports it
+	Next     int          // Offset of the next page; 0 when this is the last page
+}
+
+// PlaylistService is implemented by platform services that can list the
+// tracks of a playlist or album, one page at a time
+type PlaylistService interface {
+	GetPlaylistPage(ctx context.Context, ref PlaylistRef, offset int) (*PlaylistPage, error)
+}
+
+// playlistURLPatterns match playlist and album share URLs; the first capture
+// group is the kind and the second the ID
+var playlistURLPatterns = map[string]*regexp.Regexp{
+	"spotify":     regexp.MustCompile(`^(?:https?://)?(?:open\.)?spotify\.com/(?:intl-[a-z]+/)?(playlist|album)/([a-zA-Z0-9]+)`),
+	"apple_music": regexp.MustCompile(`^(?:https?://)?music\.apple\.com/[a-z]{2}/(playlist|album)/(?:[^/?]+/)?((?:pl\.)?[a-zA-Z0-9-]+)(?:[/?#]|$)`),
+}
+
+// ParsePlaylistURL identifies the platform, kind and ID of a playlist or album URL
+func ParsePlaylistURL(url string) (PlaylistRef, error) {
+	for platform, pattern := range playlistURLPatterns {
+		if matches := pattern.FindStringSubmatch(url); matches != nil {
+			return PlaylistRef{Platform: platform, Kind: matches[1], ID: matches[2]}, nil
+		}
+	}
+	return PlaylistRef{}, &PlatformError{
+		Platform:  "unknown",
+		Operation: "parse_playlist_url",
+		Message:   "unsupported playlist or album URL",
+		URL:       url,
+	}
+}
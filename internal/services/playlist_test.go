@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlaylistURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected PlaylistRef
+	}{
+		{
+			name:     "spotify playlist",
+			url:      "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M?si=abc",
+			expected: PlaylistRef{Platform: "spotify", Kind: PlaylistKindPlaylist, ID: "37i9dQZF1DXcBWIGoYBM5M"},
+		},
+		{
+			name:     "spotify localized album",
+			url:      "https://open.spotify.com/intl-de/album/6dVIqQ8qmQ5GBnJ9shOYGE",
+			expected: PlaylistRef{Platform: "spotify", Kind: PlaylistKindAlbum, ID: "6dVIqQ8qmQ5GBnJ9shOYGE"},
+		},
+		{
+			name:     "apple music playlist",
+			url:      "https://music.apple.com/us/playlist/todays-hits/pl.f4d106fed2bd41149aaacabb233eb5eb",
+			expected: PlaylistRef{Platform: "apple_music", Kind: PlaylistKindPlaylist, ID: "pl.f4d106fed2bd41149aaacabb233eb5eb"},
+		},
+		{
+			name:     "apple music album",
+			url:      "https://music.apple.com/us/album/a-night-at-the-opera/1440806041",
+			expected: PlaylistRef{Platform: "apple_music", Kind: PlaylistKindAlbum, ID: "1440806041"},
+		},
+		{
+			name:     "apple music album without slug",
+			url:      "https://music.apple.com/gb/album/1440806041?l=en",
+			expected: PlaylistRef{Platform: "apple_music", Kind: PlaylistKindAlbum, ID: "1440806041"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParsePlaylistURL(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ref)
+		})
+	}
+}
+
+func TestParsePlaylistURL_Unsupported(t *testing.T) {
+	for _, url := range []string{
+		"https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		"https://tidal.com/browse/playlist/123",
+		"not a url",
+	} {
+		_, err := ParsePlaylistURL(url)
+		assert.Error(t, err, url)
+	}
+}
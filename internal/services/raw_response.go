@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// rawResponseMaxBytes caps a logged response body, so a large search page
+// can't flood the logs
+const rawResponseMaxBytes = 8 << 10
+
+// rawResponseRedacted replaces the values of credential fields
+const rawResponseRedacted = "[REDACTED]"
+
+// rawResponseSecretKeys are substrings of JSON field names whose values are
+// never logged
+var rawResponseSecretKeys = []string{"token", "secret", "password", "authorization", "api_key", "apikey"}
+
+var (
+	rawResponseSampleRateMu sync.RWMutex
+	rawResponseSampleRate   float64
+)
+
+// SetRawResponseSampleRate sets the share of platform API responses logged
+// raw for debugging, from 0 (off, the default) to 1 (every response)
+func SetRawResponseSampleRate(rate float64) {
+	rawResponseSampleRateMu.Lock()
+	defer rawResponseSampleRateMu.Unlock()
+	rawResponseSampleRate = min(max(rate, 0), 1)
+}
+
+type rawResponseDebugContextKey struct{}
+
+// WithRawResponseDebug returns a context whose platform API responses are all
+// logged raw, regardless of the sample rate
+func WithRawResponseDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawResponseDebugContextKey{}, true)
+}
+
+// RawResponseDebugFromContext reports whether ctx was marked by
+// WithRawResponseDebug
+func RawResponseDebugFromContext(ctx context.Context) bool {
+	forced, _ := ctx.Value(rawResponseDebugContextKey{}).(bool)
+	return forced
+}
+
+// shouldLogRawResponse reports whether a response fetched with ctx is logged:
+// always when the request asked for it, otherwise at the sample rate
+func shouldLogRawResponse(ctx context.Context) bool {
+	if RawResponseDebugFromContext(ctx) {
+		return true
+	}
+	rawResponseSampleRateMu.RLock()
+	rate := rawResponseSampleRate
+	rawResponseSampleRateMu.RUnlock()
+	return rate > 0 && rand.Float64() < rate
+}
+
+// logRawResponse logs a platform API response body, redacted and truncated,
+// when it is sampled
+func logRawResponse(ctx context.Context, platform, operation string, status int, body []byte) {
+	if !shouldLogRawResponse(ctx) {
+		return
+	}
+	slog.Info("Platform raw response",
+		"platform", platform,
+		"operation", operation,
+		"status", status,
+		"body", redactRawResponse(body),
+		requestIDAttr(ctx),
+	)
+}
+
+// logRestyRawResponse is logRawResponse for a resty response; failed requests
+// have no body to log
+func logRestyRawResponse(ctx context.Context, platform, operation string, resp *resty.Response, err error) {
+	if err != nil || resp == nil {
+		return
+	}
+	logRawResponse(ctx, platform, operation, resp.StatusCode(), resp.Body())
+}
+
+// redactRawResponse replaces credential values in a JSON body and truncates
+// it to rawResponseMaxBytes. Bodies that aren't JSON are only truncated.
+func redactRawResponse(body []byte) string {
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		if redacted, err := json.Marshal(redactRawValue(decoded)); err == nil {
+			body = redacted
+		}
+	}
+	if len(body) > rawResponseMaxBytes {
+		return string(body[:rawResponseMaxBytes]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// redactRawValue walks a decoded JSON value, redacting credential fields
+func redactRawValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isRawResponseSecretKey(key) {
+				v[key] = rawResponseRedacted
+			} else {
+				v[key] = redactRawValue(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactRawValue(item)
+		}
+	}
+	return value
+}
+
+func isRawResponseSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range rawResponseSecretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureDefaultLog sends slog's default logger to a buffer for the test
+func captureDefaultLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func newRawResponseTidalService(t *testing.T) *TidalService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"12345","type":"tracks","attributes":{"title":"Odd Title","isrc":"USUM71703861"}},"meta":{"refresh_token":"leaked"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewTidalService(&config.PlatformConfig{
+		Name:         "tidal",
+		AuthMethod:   config.AuthMethodOAuth2,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL,
+		Timeout:      5,
+	}, nil)
+	require.NoError(t, err)
+	return service
+}
+
+func TestLogRawResponse_Tidal(t *testing.T) {
+	service := newRawResponseTidalService(t)
+
+	t.Run("Forced by the request", func(t *testing.T) {
+		logs := captureDefaultLog(t)
+
+		_, _ = service.GetTrackByID(WithRawResponseDebug(context.Background()), "12345")
+
+		assert.Contains(t, logs.String(), "Platform raw response")
+		assert.Contains(t, logs.String(), "Odd Title")
+		assert.Contains(t, logs.String(), rawResponseRedacted)
+		assert.NotContains(t, logs.String(), "leaked")
+	})
+
+	t.Run("Sampled at full rate", func(t *testing.T) {
+		SetRawResponseSampleRate(1)
+		t.Cleanup(func() { SetRawResponseSampleRate(0) })
+		logs := captureDefaultLog(t)
+
+		_, _ = service.GetTrackByID(context.Background(), "12345")
+
+		assert.Contains(t, logs.String(), "Platform raw response")
+	})
+
+	t.Run("Omitted by default", func(t *testing.T) {
+		logs := captureDefaultLog(t)
+
+		_, _ = service.GetTrackByID(context.Background(), "12345")
+
+		assert.NotContains(t, logs.String(), "Platform raw response")
+		assert.NotContains(t, logs.String(), "Odd Title")
+	})
+}
+
+func TestRedactRawResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"Nested credentials", `{"auth":{"access_token":"a","Client_Secret":"b"},"items":[{"apiKey":"c","name":"d"}]}`, `{"auth":{"Client_Secret":"[REDACTED]","access_token":"[REDACTED]"},"items":[{"apiKey":"[REDACTED]","name":"d"}]}`},
+		{"Nothing to redact", `{"title":"Song"}`, `{"title":"Song"}`},
+		{"Not JSON", `<html>Bad Gateway</html>`, `<html>Bad Gateway</html>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, redactRawResponse([]byte(tt.body)))
+		})
+	}
+
+	long := redactRawResponse(bytes.Repeat([]byte("x"), rawResponseMaxBytes+10))
+	assert.Len(t, long, rawResponseMaxBytes+len("...(truncated)"))
+}
+
+func TestSetRawResponseSampleRate_Clamps(t *testing.T) {
+	t.Cleanup(func() { SetRawResponseSampleRate(0) })
+
+	SetRawResponseSampleRate(-1)
+	assert.False(t, shouldLogRawResponse(context.Background()))
+
+	SetRawResponseSampleRate(5)
+	assert.True(t, shouldLogRawResponse(context.Background()))
+}
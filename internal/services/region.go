@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// DefaultRegion is the catalog region used when none is configured or requested
+const DefaultRegion = "US"
+
+type regionContextKey struct{}
+
+// WithRegion returns a context that asks platform services to query the
+// catalog for region (an ISO 3166-1 alpha-2 country code)
+func WithRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionContextKey{}, region)
+}
+
+// RegionFromContext returns the per-request region override, or "" if none
+func RegionFromContext(ctx context.Context) string {
+	region, _ := ctx.Value(regionContextKey{}).(string)
+	return region
+}
+
+// NormalizeRegion upper-cases a country code and reports whether it is a
+// well-formed two-letter code
+func NormalizeRegion(region string) (string, bool) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if len(region) != 2 || region[0] < 'A' || region[0] > 'Z' || region[1] < 'A' || region[1] > 'Z' {
+		return "", false
+	}
+	return region, true
+}
+
+// defaultSupportedRegions are the catalog regions each platform serves, used
+// when its config lists none (PLATFORM_<NAME>_REGIONS). Platforms missing here
+// accept any well-formed region.
+var defaultSupportedRegions = map[string][]string{
+	"tidal": {
+		"AD", "AE", "AR", "AT", "AU", "BA", "BE", "BG", "BR", "CA", "CH", "CL",
+		"CO", "CY", "CZ", "DE", "DK", "DO", "EE", "ES", "FI", "FR", "GB", "GR",
+		"HK", "HR", "HU", "IE", "IL", "IS", "IT", "JM", "KE", "LI", "LT", "LU",
+		"LV", "MC", "ME", "MT", "MX", "MY", "NG", "NL", "NO", "NZ", "PE", "PL",
+		"PR", "PT", "RO", "RS", "SE", "SG", "SI", "SK", "TH", "UG", "US", "ZA",
+	},
+}
+
+// supportedRegions returns the regions platform serves: the configured list
+// when there is one, otherwise its defaults. Nil means any region.
+func supportedRegions(platform string, configured []string) []string {
+	if len(configured) == 0 {
+		return defaultSupportedRegions[platform]
+	}
+	regions := make([]string, 0, len(configured))
+	for _, region := range configured {
+		if normalized, ok := NormalizeRegion(region); ok {
+			regions = append(regions, normalized)
+		}
+	}
+	return regions
+}
+
+// resolveRegion returns the catalog region to query platform in: the
+// per-request region when the platform serves it, otherwise fallback. Asking
+// a platform for a region it doesn't serve fails with an unhelpful error, so
+// such requests are logged and answered from the default catalog instead.
+func resolveRegion(ctx context.Context, platform string, configured []string, fallback string) string {
+	region := RegionFromContext(ctx)
+	if region == "" {
+		return fallback
+	}
+	supported := supportedRegions(platform, configured)
+	if supported != nil && !slices.Contains(supported, region) {
+		slog.Warn("Unsupported region requested, using the default", "platform", platform, "region", region, "default", fallback, requestIDAttr(ctx))
+		return fallback
+	}
+	return region
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a context carrying the ID of the HTTP request that
+// platform calls made with it serve
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID set by WithRequestID, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// requestIDAttr is the request_id log attribute for ctx; it is empty, and so
+// left out of the log line, when ctx carries no request ID
+func requestIDAttr(ctx context.Context) slog.Attr {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return slog.String("request_id", requestID)
+	}
+	return slog.Attr{}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDAttr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	logger.Warn("Tidal rate limited, retrying", requestIDAttr(WithRequestID(context.Background(), "req-123")))
+	assert.Contains(t, buf.String(), "request_id=req-123")
+
+	buf.Reset()
+	logger.Warn("Tidal rate limited, retrying", requestIDAttr(context.Background()))
+	assert.NotContains(t, buf.String(), "request_id")
+	assert.Empty(t, RequestIDFromContext(context.Background()))
+}
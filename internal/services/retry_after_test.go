@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// rewriteTransport sends every request to a test server instead of its host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// rateLimitedServer answers 429 with Retry-After for the first limited
+// requests and body afterwards, counting every request
+func rateLimitedServer(t *testing.T, limited int32, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		if requests.Add(1) <= limited {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// newSpotifyRetryTestService returns a Spotify service with a valid token whose
+// API calls go to server
+func newSpotifyRetryTestService(server *httptest.Server) *spotifyService {
+	target, _ := url.Parse(server.URL)
+	return &spotifyService{
+		client:      resty.New().SetTransport(rewriteTransport{target: target}),
+		tokenSource: &clientcredentials.Config{},
+		accessToken: "test-token",
+		tokenExpiry: time.Now().Add(time.Hour),
+		cache:       newMemoryCache(),
+	}
+}
+
+func TestSpotifyGetTrackByID_RetriesAfterRateLimit(t *testing.T) {
+	server, requests := rateLimitedServer(t, 1, `{"id": "4u7EnebtmKWzUH433cf5Qv", "name": "Bohemian Rhapsody", "artists": [{"name": "Queen"}]}`)
+	service := newSpotifyRetryTestService(server)
+
+	track, err := service.GetTrackByID(context.Background(), "4u7EnebtmKWzUH433cf5Qv")
+	require.NoError(t, err)
+	assert.Equal(t, "Bohemian Rhapsody", track.Title)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestSpotifySearchTrack_RateLimitedTwice(t *testing.T) {
+	server, requests := rateLimitedServer(t, 2, `{}`)
+	service := newSpotifyRetryTestService(server)
+
+	_, err := service.SearchTrack(context.Background(), SearchQuery{Title: "Bohemian Rhapsody", Artist: "Queen"})
+	require.Error(t, err)
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, "rate_limited", platformErr.Operation)
+	assert.Equal(t, ErrorCategoryRateLimited, platformErr.Category)
+	assert.Equal(t, time.Duration(0), platformErr.RetryAfter)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func newTidalRetryTestService(t *testing.T, server *httptest.Server) *TidalService {
+	t.Helper()
+	service, err := NewTidalService(&config.PlatformConfig{
+		Name:         "tidal",
+		AuthMethod:   config.AuthMethodOAuth2,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL,
+		Timeout:      5,
+	}, nil)
+	require.NoError(t, err)
+	return service
+}
+
+func TestTidalRawAPIRequest_RetriesAfterRateLimit(t *testing.T) {
+	server, requests := rateLimitedServer(t, 1, `{"data": []}`)
+	service := newTidalRetryTestService(t, server)
+
+	body, err := service.makeRawAPIRequest(context.Background(), "GET", "/tracks", nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": []}`, string(body))
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestTidalRawAPIRequest_RateLimitedTwice(t *testing.T) {
+	server, requests := rateLimitedServer(t, 2, `{"data": []}`)
+	service := newTidalRetryTestService(t, server)
+
+	_, err := service.makeRawAPIRequest(context.Background(), "GET", "/tracks", nil)
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, "rate_limited", platformErr.Operation)
+	assert.Equal(t, ErrorCategoryRateLimited, ClassifyError(err))
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestWaitRetryAfter_StopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := waitRetryAfter(ctx, time.Hour)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{"5", 5 * time.Second},
+		{" 120 ", 2 * time.Minute},
+		{"Mon, 01 Jan 2024 12:00:10 GMT", 10 * time.Second},
+		{"Mon, 01 Jan 2024 11:59:00 GMT", 0},
+		{"", defaultRetryAfter},
+		{"-3", defaultRetryAfter},
+		{"soon", defaultRetryAfter},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.header), func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseRetryAfter(tt.header, now))
+		})
+	}
+}
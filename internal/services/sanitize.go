@@ -0,0 +1,55 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"songshare/internal/models"
+)
+
+// Sanitize normalizes a platform's partial or inconsistent metadata so every
+// TrackInfo looks the same downstream: text is trimmed, Artists is never nil
+// and holds no blank names, the ISRC is canonical or empty, ImageURL is an
+// absolute http(s) URL or empty, and Duration and Popularity stay in range.
+// Converters call it on each track they build; it returns t for chaining.
+func (t *TrackInfo) Sanitize() *TrackInfo {
+	t.Title = strings.TrimSpace(t.Title)
+	t.Album = strings.TrimSpace(t.Album)
+	t.ReleaseDate = strings.TrimSpace(t.ReleaseDate)
+
+	artists := make([]string, 0, len(t.Artists))
+	for _, artist := range t.Artists {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			artists = append(artists, artist)
+		}
+	}
+	t.Artists = artists
+
+	if isrc := strings.TrimSpace(t.ISRC); isrc != "" {
+		t.ISRC = models.CanonicalISRC(isrc)
+	} else {
+		t.ISRC = ""
+	}
+
+	t.ImageURL = sanitizeImageURL(t.ImageURL)
+
+	if t.Duration < 0 {
+		t.Duration = 0
+	}
+	t.Popularity = min(max(t.Popularity, 0), 100)
+	return t
+}
+
+// sanitizeImageURL returns raw if it is an absolute http(s) URL, upgrading
+// protocol-relative URLs to https, and "" otherwise
+func sanitizeImageURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "//") {
+		raw = "https:" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ""
+	}
+	return raw
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertSanitized checks the invariants every converted track must satisfy
+func assertSanitized(t *testing.T, track *TrackInfo) {
+	t.Helper()
+	require.NotNil(t, track)
+	assert.NotNil(t, track.Artists)
+	for _, artist := range track.Artists {
+		assert.NotEmpty(t, artist)
+	}
+	assert.GreaterOrEqual(t, track.Duration, 0)
+	assert.GreaterOrEqual(t, track.Popularity, 0)
+	assert.LessOrEqual(t, track.Popularity, 100)
+	if track.ImageURL != "" {
+		assert.Regexp(t, `^https?://[^/]+`, track.ImageURL)
+	}
+}
+
+func TestSanitize_PartialSpotifyTrack(t *testing.T) {
+	var track SpotifyTrack
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "4u7EnebtmKWzUH433cf5Qv",
+		"name": " Bohemian Rhapsody ",
+		"artists": null,
+		"album": {"name": "", "images": [{"url": "", "width": 640}]},
+		"duration_ms": -1,
+		"popularity": 140,
+		"external_ids": {}
+	}`), &track))
+
+	info := (&spotifyService{}).convertSpotifyTrack(&track)
+	assertSanitized(t, info)
+	assert.Equal(t, "Bohemian Rhapsody", info.Title)
+	assert.Equal(t, []string{}, info.Artists)
+	assert.Empty(t, info.ImageURL)
+	assert.Empty(t, info.ISRC)
+	assert.Equal(t, 0, info.Duration)
+	assert.Equal(t, 100, info.Popularity)
+}
+
+func TestSanitize_PartialAppleMusicTrack(t *testing.T) {
+	var song AppleMusicSong
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "1440806041",
+		"attributes": {
+			"name": "Bohemian Rhapsody",
+			"artistName": "  ",
+			"isrc": "gb-um7-10-29604",
+			"artwork": {"url": "not a url"}
+		}
+	}`), &song))
+
+	info := (&appleMusicService{}).convertAppleMusicTrack(&song)
+	assertSanitized(t, info)
+	assert.Equal(t, []string{}, info.Artists)
+	assert.Equal(t, "GBUM71029604", info.ISRC)
+	assert.Empty(t, info.ImageURL)
+	assert.False(t, info.Explicit)
+}
+
+func TestSanitize_PartialTidalResource(t *testing.T) {
+	// No relationships: no artists, album or artwork
+	resource := map[string]interface{}{
+		"id":         "77646168",
+		"attributes": map[string]interface{}{"title": "Bohemian Rhapsody"},
+	}
+
+	info := (&TidalService{}).parseTrackFromResource(context.Background(), resource, nil)
+	assertSanitized(t, info)
+	assert.Equal(t, []string{}, info.Artists)
+	assert.Empty(t, info.ISRC)
+	assert.Empty(t, info.ImageURL)
+}
+
+func TestSanitize_ImageURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"https://i.scdn.co/image/abc", "https://i.scdn.co/image/abc"},
+		{"//resources.tidal.com/images/abc/640x640.jpg", "https://resources.tidal.com/images/abc/640x640.jpg"},
+		{" http://example.com/art.jpg ", "http://example.com/art.jpg"},
+		{"null", ""},
+		{"/images/abc.jpg", ""},
+		{"javascript:alert(1)", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			info := (&TrackInfo{ImageURL: tt.raw}).Sanitize()
+			assert.Equal(t, tt.expected, info.ImageURL)
+		})
+	}
+}
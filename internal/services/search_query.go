@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SearchQueryStrategy controls how a SearchQuery is turned into a platform query string
+type SearchQueryStrategy string
+
+const (
+	// SearchStrategyFieldScoped uses an ISRC filter when available, otherwise
+	// field-scoped terms: track:"X" artist:"Y" album:"Z"
+	SearchStrategyFieldScoped SearchQueryStrategy = "field_scoped"
+	// SearchStrategyISRCFilter uses an ISRC filter when available, otherwise
+	// a single combined string: "X Y Z"
+	SearchStrategyISRCFilter SearchQueryStrategy = "isrc_filter"
+	// SearchStrategyCombined always uses a single combined string and ignores ISRC
+	SearchStrategyCombined SearchQueryStrategy = "combined"
+)
+
+// defaultSearchQueryStrategies reflects what each platform's search API handles best
+var defaultSearchQueryStrategies = map[string]SearchQueryStrategy{
+	"spotify":       SearchStrategyFieldScoped,
+	"apple_music":   SearchStrategyISRCFilter,
+	"tidal":         SearchStrategyCombined,
+	"youtube_music": SearchStrategyCombined,
+	"deezer":        SearchStrategyCombined,
+}
+
+var (
+	searchQueryStrategies   = copySearchQueryStrategies(defaultSearchQueryStrategies)
+	searchQueryStrategiesMu sync.RWMutex
+)
+
+func copySearchQueryStrategies(src map[string]SearchQueryStrategy) map[string]SearchQueryStrategy {
+	dst := make(map[string]SearchQueryStrategy, len(src))
+	for platform, strategy := range src {
+		dst[platform] = strategy
+	}
+	return dst
+}
+
+// IsValid reports whether s is a known strategy
+func (s SearchQueryStrategy) IsValid() bool {
+	switch s {
+	case SearchStrategyFieldScoped, SearchStrategyISRCFilter, SearchStrategyCombined:
+		return true
+	}
+	return false
+}
+
+// GetSearchQueryStrategy returns the strategy configured for platform,
+// falling back to combined for platforms without one
+func GetSearchQueryStrategy(platform string) SearchQueryStrategy {
+	searchQueryStrategiesMu.RLock()
+	defer searchQueryStrategiesMu.RUnlock()
+	if strategy, ok := searchQueryStrategies[platform]; ok {
+		return strategy
+	}
+	return SearchStrategyCombined
+}
+
+// SetSearchQueryStrategy overrides the strategy used for platform
+func SetSearchQueryStrategy(platform string, strategy SearchQueryStrategy) error {
+	if !strategy.IsValid() {
+		return fmt.Errorf("unknown search query strategy %q for %s", strategy, platform)
+	}
+	searchQueryStrategiesMu.Lock()
+	defer searchQueryStrategiesMu.Unlock()
+	searchQueryStrategies[platform] = strategy
+	return nil
+}
+
+// ConfigureSearchQueryStrategies applies platform -> strategy overrides, e.g.
+// from SEARCH_QUERY_STRATEGIES. Nothing is applied if any entry is invalid.
+func ConfigureSearchQueryStrategies(overrides map[string]string) error {
+	for platform, strategy := range overrides {
+		if !SearchQueryStrategy(strategy).IsValid() {
+			return fmt.Errorf("unknown search query strategy %q for %s", strategy, platform)
+		}
+	}
+	for platform, strategy := range overrides {
+		if err := SetSearchQueryStrategy(platform, SearchQueryStrategy(strategy)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildSearchQuery renders query using strategy. A free-form Query is always
+// used verbatim. Returns "" when there is nothing to search for, leaving the
+// platform to choose its own fallback.
+func BuildSearchQuery(strategy SearchQueryStrategy, query SearchQuery) string {
+	if query.ISRC != "" && strategy != SearchStrategyCombined {
+		return fmt.Sprintf("isrc:%s", query.ISRC)
+	}
+
+	if query.Query != "" {
+		return query.Query
+	}
+
+	var parts []string
+	if strategy == SearchStrategyFieldScoped {
+		if query.Title != "" {
+			parts = append(parts, fmt.Sprintf("track:\"%s\"", query.Title))
+		}
+		if query.Artist != "" {
+			parts = append(parts, fmt.Sprintf("artist:\"%s\"", query.Artist))
+		}
+		if query.Album != "" {
+			parts = append(parts, fmt.Sprintf("album:\"%s\"", query.Album))
+		}
+	} else {
+		for _, term := range []string{query.Title, query.Artist, query.Album} {
+			if term != "" {
+				parts = append(parts, term)
+			}
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	fields := SearchQuery{Title: "Halo", Artist: "Beyoncé", Album: "I Am... Sasha Fierce"}
+	withISRC := SearchQuery{Title: "Halo", Artist: "Beyoncé", ISRC: "USSM10804557"}
+	freeForm := SearchQuery{Title: "Halo", Query: "halo beyonce"}
+
+	tests := []struct {
+		name     string
+		strategy SearchQueryStrategy
+		query    SearchQuery
+		expected string
+	}{
+		{"Field scoped fields", SearchStrategyFieldScoped, fields, `track:"Halo" artist:"Beyoncé" album:"I Am... Sasha Fierce"`},
+		{"Field scoped ISRC", SearchStrategyFieldScoped, withISRC, "isrc:USSM10804557"},
+		{"Field scoped free-form", SearchStrategyFieldScoped, freeForm, "halo beyonce"},
+		{"Field scoped empty", SearchStrategyFieldScoped, SearchQuery{}, ""},
+
+		{"ISRC filter fields", SearchStrategyISRCFilter, fields, "Halo Beyoncé I Am... Sasha Fierce"},
+		{"ISRC filter ISRC", SearchStrategyISRCFilter, withISRC, "isrc:USSM10804557"},
+		{"ISRC filter free-form", SearchStrategyISRCFilter, freeForm, "halo beyonce"},
+
+		{"Combined fields", SearchStrategyCombined, fields, "Halo Beyoncé I Am... Sasha Fierce"},
+		{"Combined ignores ISRC", SearchStrategyCombined, withISRC, "Halo Beyoncé"},
+		{"Combined free-form", SearchStrategyCombined, freeForm, "halo beyonce"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BuildSearchQuery(tt.strategy, tt.query))
+		})
+	}
+}
+
+func TestSearchQueryStrategyDefaults(t *testing.T) {
+	assert.Equal(t, SearchStrategyFieldScoped, GetSearchQueryStrategy("spotify"))
+	assert.Equal(t, SearchStrategyISRCFilter, GetSearchQueryStrategy("apple_music"))
+	assert.Equal(t, SearchStrategyCombined, GetSearchQueryStrategy("tidal"))
+	assert.Equal(t, SearchStrategyCombined, GetSearchQueryStrategy("unknown_platform"))
+}
+
+func TestConfigureSearchQueryStrategies(t *testing.T) {
+	t.Cleanup(func() {
+		searchQueryStrategiesMu.Lock()
+		searchQueryStrategies = copySearchQueryStrategies(defaultSearchQueryStrategies)
+		searchQueryStrategiesMu.Unlock()
+	})
+
+	require.NoError(t, ConfigureSearchQueryStrategies(map[string]string{"tidal": "isrc_filter"}))
+	assert.Equal(t, SearchStrategyISRCFilter, GetSearchQueryStrategy("tidal"))
+
+	err := ConfigureSearchQueryStrategies(map[string]string{"spotify": "combined", "apple_music": "fuzzy"})
+	require.Error(t, err)
+	assert.Equal(t, SearchStrategyFieldScoped, GetSearchQueryStrategy("spotify"), "invalid overrides apply nothing")
+}
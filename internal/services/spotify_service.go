@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 	"songshare/internal/cache"
 )
@@ -21,10 +22,16 @@ type spotifyService struct {
 	clientID     string
 	clientSecret string
 	tokenSource  *clientcredentials.Config
+	tokenClient  *http.Client // token exchange client; nil uses the default
 	accessToken  string
 	tokenExpiry  time.Time
 	cache        cache.Cache
-	mu           sync.RWMutex
+	// tokenCache is the unwrapped shared cache holding the access token, so
+	// token lookups stay out of CacheStats
+	tokenCache cache.Cache
+	// negativeCacheTTL is how long a track ID Spotify reports missing is cached
+	negativeCacheTTL time.Duration
+	mu               sync.RWMutex
 }
 
 // Spotify API endpoints
@@ -40,12 +47,22 @@ const (
 	spotifyISRCCacheTTL   = 24 * time.Hour // ISRC-based lookups (very stable)
 )
 
-// NewSpotifyService creates a new Spotify service
+// NewSpotifyService creates a new Spotify service. Without credentials the
+// service is unconfigured: IsConfigured is false and API calls return a
+// not_configured PlatformError instead of failing at token exchange.
 func NewSpotifyService(clientID, clientSecret string, cache cache.Cache) PlatformService {
+	if clientID == "" || clientSecret == "" {
+		slog.Warn("Spotify credentials not set, Spotify is disabled")
+		return &spotifyService{cache: newCountingCache(cache)}
+	}
+
 	tokenSource := &clientcredentials.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		TokenURL:     spotifyTokenURL,
+		// Spotify takes credentials in the header; auto-detection would send a
+		// second request for every rejected or failed exchange
+		AuthStyle: oauth2.AuthStyleInHeader,
 	}
 
 	client := resty.New().
@@ -55,12 +72,38 @@ func NewSpotifyService(clientID, clientSecret string, cache cache.Cache) Platfor
 		SetRetryMaxWaitTime(5 * time.Second)
 
 	return &spotifyService{
-		client:       client,
-		clientID:     clientID,
-		clientSecret: clientSecret,
-		tokenSource:  tokenSource,
-		cache:        cache,
+		client:           client,
+		clientID:         clientID,
+		clientSecret:     clientSecret,
+		tokenSource:      tokenSource,
+		cache:            newCountingCache(cache),
+		tokenCache:       platformCache(cache),
+		negativeCacheTTL: defaultNegativeCacheTTL,
+	}
+}
+
+// setTransport sends API calls and token exchanges through transport
+func (s *spotifyService) setTransport(transport http.RoundTripper) {
+	if s.client == nil {
+		return
 	}
+	s.client.SetTransport(transport)
+	s.tokenClient = &http.Client{Transport: transport, Timeout: 10 * time.Second}
+}
+
+// setNegativeCacheTTL sets how long missing track IDs are cached
+func (s *spotifyService) setNegativeCacheTTL(ttl time.Duration) {
+	s.negativeCacheTTL = ttl
+}
+
+// CacheStats reports the service's track and search cache use
+func (s *spotifyService) CacheStats() (hits, misses, entries int) {
+	return cacheCounts(s.cache)
+}
+
+// ResetCacheStats zeroes the cache counters
+func (s *spotifyService) ResetCacheStats() {
+	resetCacheCounts(s.cache)
 }
 
 // GetPlatformName returns the platform name
@@ -68,6 +111,21 @@ func (s *spotifyService) GetPlatformName() string {
 	return "spotify"
 }
 
+// IsConfigured reports whether the service has credentials for API calls
+func (s *spotifyService) IsConfigured() bool {
+	return s.tokenSource != nil
+}
+
+// notConfiguredError is returned by API operations on an unconfigured service
+func (s *spotifyService) notConfiguredError(operation string) error {
+	return &PlatformError{
+		Platform:  "spotify",
+		Operation: operation,
+		Message:   "Spotify credentials are not configured",
+		Category:  ErrorCategoryNotConfigured,
+	}
+}
+
 // ParseURL extracts track ID from Spotify URL
 func (s *spotifyService) ParseURL(url string) (*TrackInfo, error) {
 	matches := SpotifyURLPattern.Regex.FindStringSubmatch(url)
@@ -93,9 +151,16 @@ func (s *spotifyService) ParseURL(url string) (*TrackInfo, error) {
 
 // GetTrackByID fetches track details from Spotify API
 func (s *spotifyService) GetTrackByID(ctx context.Context, trackID string) (*TrackInfo, error) {
+	if !s.IsConfigured() {
+		return nil, s.notConfiguredError("get_track")
+	}
+
 	// Check cache first
 	cacheKey := fmt.Sprintf("api:spotify:track:%s", trackID)
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != nil {
+		if isNotFoundSentinel(cached) {
+			return nil, s.trackNotFoundError()
+		}
 		var trackInfo TrackInfo
 		if err := json.Unmarshal(cached, &trackInfo); err == nil {
 			return &trackInfo, nil
@@ -111,11 +176,13 @@ func (s *spotifyService) GetTrackByID(ctx context.Context, trackID string) (*Tra
 	s.mu.RUnlock()
 
 	var spotifyTrack SpotifyTrack
-	resp, err := s.client.R().
-		SetContext(ctx).
-		SetAuthToken(token).
-		SetResult(&spotifyTrack).
-		Get(fmt.Sprintf("%s/tracks/%s", spotifyAPIURL, trackID))
+	resp, err := s.sendWithRetryAfter(ctx, "get_track", func() (*resty.Response, error) {
+		return s.client.R().
+			SetContext(ctx).
+			SetAuthToken(token).
+			SetResult(&spotifyTrack).
+			Get(fmt.Sprintf("%s/tracks/%s", spotifyAPIURL, trackID))
+	})
 
 	if err != nil {
 		return nil, &PlatformError{
@@ -127,11 +194,12 @@ func (s *spotifyService) GetTrackByID(ctx context.Context, trackID string) (*Tra
 	}
 
 	if resp.StatusCode() == http.StatusNotFound {
-		return nil, &PlatformError{
-			Platform:  "spotify",
-			Operation: "get_track",
-			Message:   "track not found",
-		}
+		cacheNotFound(ctx, s.cache, cacheKey, s.negativeCacheTTL)
+		return nil, s.trackNotFoundError()
+	}
+
+	if resp.StatusCode() == http.StatusTooManyRequests {
+		return nil, s.rateLimitedError(resp)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
@@ -139,6 +207,7 @@ func (s *spotifyService) GetTrackByID(ctx context.Context, trackID string) (*Tra
 			Platform:  "spotify",
 			Operation: "get_track",
 			Message:   fmt.Sprintf("API returned status %d", resp.StatusCode()),
+			Category:  CategoryForStatus(resp.StatusCode()),
 		}
 	}
 
@@ -147,15 +216,34 @@ func (s *spotifyService) GetTrackByID(ctx context.Context, trackID string) (*Tra
 	// Cache the result
 	if data, err := json.Marshal(trackInfo); err == nil {
 		if err := s.cache.Set(ctx, cacheKey, data, spotifyTrackCacheTTL); err != nil {
-			slog.Error("Failed to cache Spotify track", "trackID", trackID, "error", err)
+			slog.Error("Failed to cache Spotify track", "trackID", trackID, "error", err, requestIDAttr(ctx))
 		}
 	}
 
 	return trackInfo, nil
 }
 
+// GetTrackByURL fetches track details for a Spotify track URL
+func (s *spotifyService) GetTrackByURL(ctx context.Context, url string) (*TrackInfo, error) {
+	return trackByURL(ctx, s, url)
+}
+
+// trackNotFoundError is returned for a track ID Spotify reports missing
+func (s *spotifyService) trackNotFoundError() error {
+	return &PlatformError{
+		Platform:  "spotify",
+		Operation: "get_track",
+		Message:   "track not found",
+		Category:  ErrorCategoryNoResults,
+	}
+}
+
 // SearchTrack searches for tracks on Spotify
 func (s *spotifyService) SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error) {
+	if !s.IsConfigured() {
+		return nil, s.notConfiguredError("search")
+	}
+
 	searchQuery := s.buildSearchQuery(query)
 	limit := query.Limit
 	if limit == 0 {
@@ -166,11 +254,11 @@ func (s *spotifyService) SearchTrack(ctx context.Context, query SearchQuery) ([]
 	}
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("api:spotify:search:%s:limit:%d", searchQuery, limit)
+	cacheKey := fmt.Sprintf("api:spotify:search:%s:limit:%d", searchQuery, limit) + query.entityKindsCacheKey()
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != nil {
 		var tracks []*TrackInfo
 		if err := json.Unmarshal(cached, &tracks); err == nil {
-			return tracks, nil
+			return FilterExplicit(tracks, query.Explicit), nil
 		}
 	}
 
@@ -183,16 +271,18 @@ func (s *spotifyService) SearchTrack(ctx context.Context, query SearchQuery) ([]
 	s.mu.RUnlock()
 
 	var searchResult SpotifySearchResult
-	resp, err := s.client.R().
-		SetContext(ctx).
-		SetAuthToken(token).
-		SetQueryParams(map[string]string{
-			"q":     searchQuery,
-			"type":  "track",
-			"limit": fmt.Sprintf("%d", limit),
-		}).
-		SetResult(&searchResult).
-		Get(fmt.Sprintf("%s/search", spotifyAPIURL))
+	resp, err := s.sendWithRetryAfter(ctx, "search", func() (*resty.Response, error) {
+		return s.client.R().
+			SetContext(ctx).
+			SetAuthToken(token).
+			SetQueryParams(map[string]string{
+				"q":     searchQuery,
+				"type":  spotifySearchTypes(query),
+				"limit": fmt.Sprintf("%d", limit),
+			}).
+			SetResult(&searchResult).
+			Get(fmt.Sprintf("%s/search", spotifyAPIURL))
+	})
 
 	if err != nil {
 		return nil, &PlatformError{
@@ -203,18 +293,20 @@ func (s *spotifyService) SearchTrack(ctx context.Context, query SearchQuery) ([]
 		}
 	}
 
+	if resp.StatusCode() == http.StatusTooManyRequests {
+		return nil, s.rateLimitedError(resp)
+	}
+
 	if resp.StatusCode() != http.StatusOK {
 		return nil, &PlatformError{
 			Platform:  "spotify",
 			Operation: "search",
 			Message:   fmt.Sprintf("API returned status %d", resp.StatusCode()),
+			Category:  CategoryForStatus(resp.StatusCode()),
 		}
 	}
 
-	tracks := make([]*TrackInfo, 0, len(searchResult.Tracks.Items))
-	for _, track := range searchResult.Tracks.Items {
-		tracks = append(tracks, s.convertSpotifyTrack(&track))
-	}
+	tracks := s.convertSpotifySearchResult(&searchResult, query)
 
 	// Cache the results
 	if data, err := json.Marshal(tracks); err == nil {
@@ -225,11 +317,13 @@ func (s *spotifyService) SearchTrack(ctx context.Context, query SearchQuery) ([]
 		}
 
 		if err := s.cache.Set(ctx, cacheKey, data, cacheTTL); err != nil {
-			slog.Error("Failed to cache Spotify search results", "query", searchQuery, "error", err)
+			slog.Error("Failed to cache Spotify search results", "query", searchQuery, "error", err, requestIDAttr(ctx))
 		}
 	}
 
-	return tracks, nil
+	// Spotify's search API has no explicit-content parameter, so results are post-filtered
+	// (the cache keeps the unfiltered results for other filters to reuse)
+	return FilterExplicit(tracks, query.Explicit), nil
 }
 
 // GetTrackByISRC finds track by ISRC code
@@ -249,6 +343,7 @@ func (s *spotifyService) GetTrackByISRC(ctx context.Context, isrc string) (*Trac
 			Platform:  "spotify",
 			Operation: "get_by_isrc",
 			Message:   "no tracks found with ISRC " + isrc,
+			Category:  ErrorCategoryNoResults,
 		}
 	}
 
@@ -262,6 +357,9 @@ func (s *spotifyService) BuildURL(trackID string) string {
 
 // Health checks Spotify API health
 func (s *spotifyService) Health(ctx context.Context) error {
+	if !s.IsConfigured() {
+		return s.notConfiguredError("health")
+	}
 	return s.ensureValidToken(ctx)
 }
 
@@ -282,51 +380,132 @@ func (s *spotifyService) ensureValidToken(ctx context.Context) error {
 		return nil
 	}
 
+	// Reuse a token another instance or a previous run already fetched
+	if cached, ok := loadCachedOAuthToken(ctx, s.tokenCache, "spotify", time.Now()); ok {
+		s.accessToken = cached.AccessToken
+		s.tokenExpiry = cached.Expiry
+		slog.Info("Spotify access token loaded from cache", "expires_at", cached.Expiry)
+		return nil
+	}
+
 	// Get new token
-	token, err := s.tokenSource.Token(ctx)
+	if s.tokenClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, s.tokenClient)
+	}
+	var token *oauth2.Token
+	err := retryTokenRefresh(ctx, "spotify", func(ctx context.Context) error {
+		var err error
+		token, err = s.tokenSource.Token(ctx)
+		return err
+	})
 	if err != nil {
-		return &PlatformError{
-			Platform:  "spotify",
-			Operation: "auth",
-			Message:   "failed to get access token",
-			Err:       err,
-		}
+		return err
 	}
 
 	s.accessToken = token.AccessToken
 	s.tokenExpiry = token.Expiry
+	storeCachedOAuthToken(ctx, s.tokenCache, "spotify", cachedOAuthToken{AccessToken: token.AccessToken, Expiry: token.Expiry}, time.Now())
 
 	slog.Info("Spotify access token refreshed", "expires_at", token.Expiry)
 
 	return nil
 }
 
-// buildSearchQuery constructs a search query string for Spotify
+// buildSearchQuery constructs a search query string for Spotify using its configured strategy
 func (s *spotifyService) buildSearchQuery(query SearchQuery) string {
-	if query.ISRC != "" {
-		return fmt.Sprintf("isrc:%s", query.ISRC)
+	searchQuery := BuildSearchQuery(GetSearchQueryStrategy("spotify"), query)
+	if searchQuery == "" {
+		return "*" // Return all tracks if no search criteria
 	}
+	return searchQuery
+}
 
-	if query.Query != "" {
-		return query.Query
+// spotifySearchTypes returns the search type parameter: tracks, plus any extra
+// entity kinds the query asked for
+func spotifySearchTypes(query SearchQuery) string {
+	types := []string{"track"}
+	for _, kind := range []EntityKind{EntityAlbum, EntityArtist} {
+		if query.includesKind(kind) {
+			types = append(types, string(kind))
+		}
 	}
+	return strings.Join(types, ",")
+}
 
-	var parts []string
-	if query.Title != "" {
-		parts = append(parts, fmt.Sprintf("track:\"%s\"", query.Title))
+// convertSpotifySearchResult converts a search response to TrackInfo, dropping
+// any entity the query didn't ask for
+func (s *spotifyService) convertSpotifySearchResult(result *SpotifySearchResult, query SearchQuery) []*TrackInfo {
+	converted := make([]*TrackInfo, 0, len(result.Tracks.Items))
+	for _, track := range result.Tracks.Items {
+		// Guard against non-track items if the API ever broadens the results
+		if track.ID == "" || (track.Type != "" && track.Type != "track") {
+			continue
+		}
+		converted = append(converted, s.convertSpotifyTrack(&track))
 	}
-	if query.Artist != "" {
-		parts = append(parts, fmt.Sprintf("artist:\"%s\"", query.Artist))
+
+	if query.includesKind(EntityAlbum) {
+		for _, album := range result.Albums.Items {
+			converted = append(converted, s.convertSpotifyAlbum(&album))
+		}
 	}
-	if query.Album != "" {
-		parts = append(parts, fmt.Sprintf("album:\"%s\"", query.Album))
+	if query.includesKind(EntityArtist) {
+		for _, artist := range result.Artists.Items {
+			converted = append(converted, s.convertSpotifyArtist(&artist))
+		}
 	}
 
-	if len(parts) == 0 {
-		return "*" // Return all tracks if no search criteria
+	return FilterEntityKinds(converted, query)
+}
+
+// convertSpotifyAlbum converts a Spotify album search result to TrackInfo
+func (s *spotifyService) convertSpotifyAlbum(album *SpotifyAlbum) *TrackInfo {
+	artists := make([]string, len(album.Artists))
+	for i, artist := range album.Artists {
+		artists[i] = artist.Name
 	}
 
-	return strings.Join(parts, " ")
+	return (&TrackInfo{
+		Kind:        EntityAlbum,
+		Platform:    "spotify",
+		ExternalID:  album.ID,
+		URL:         fmt.Sprintf("https://open.spotify.com/album/%s", album.ID),
+		Title:       album.Name,
+		Artists:     artists,
+		Album:       album.Name,
+		ReleaseDate: album.ReleaseDate,
+		ImageURL:    spotifyImageURL(album.Images),
+		Available:   true,
+	}).Sanitize()
+}
+
+// convertSpotifyArtist converts a Spotify artist search result to TrackInfo
+func (s *spotifyService) convertSpotifyArtist(artist *SpotifyArtist) *TrackInfo {
+	return (&TrackInfo{
+		Kind:       EntityArtist,
+		Platform:   "spotify",
+		ExternalID: artist.ID,
+		URL:        fmt.Sprintf("https://open.spotify.com/artist/%s", artist.ID),
+		Title:      artist.Name,
+		Artists:    []string{artist.Name},
+		Genres:     artist.Genres,
+		Popularity: artist.Popularity,
+		ImageURL:   spotifyImageURL(artist.Images),
+		Available:  true,
+	}).Sanitize()
+}
+
+// spotifyImageURL picks a medium-sized image, falling back to the first one
+func spotifyImageURL(images []SpotifyImage) string {
+	if len(images) == 0 {
+		return ""
+	}
+	for _, img := range images {
+		if img.Width >= 300 && img.Width <= 640 {
+			return img.URL
+		}
+	}
+	return images[0].URL
 }
 
 // convertSpotifyTrack converts Spotify API response to TrackInfo
@@ -336,19 +515,7 @@ func (s *spotifyService) convertSpotifyTrack(track *SpotifyTrack) *TrackInfo {
 		artists[i] = artist.Name
 	}
 
-	// Get image URL (prefer medium size)
-	var imageURL string
-	if len(track.Album.Images) > 0 {
-		imageURL = track.Album.Images[0].URL
-		for _, img := range track.Album.Images {
-			if img.Width >= 300 && img.Width <= 640 {
-				imageURL = img.URL
-				break
-			}
-		}
-	}
-
-	return &TrackInfo{
+	return (&TrackInfo{
 		Platform:    "spotify",
 		ExternalID:  track.ID,
 		URL:         s.BuildURL(track.ID),
@@ -360,14 +527,15 @@ func (s *spotifyService) convertSpotifyTrack(track *SpotifyTrack) *TrackInfo {
 		ReleaseDate: track.Album.ReleaseDate,
 		Explicit:    track.Explicit,
 		Popularity:  track.Popularity,
-		ImageURL:    imageURL,
+		ImageURL:    spotifyImageURL(track.Album.Images),
 		Available:   true,
-	}
+	}).Sanitize()
 }
 
 // Spotify API response structures
 type SpotifyTrack struct {
 	ID          string             `json:"id"`
+	Type        string             `json:"type"`
 	Name        string             `json:"name"`
 	Artists     []SpotifyArtist    `json:"artists"`
 	Album       SpotifyAlbum       `json:"album"`
@@ -378,15 +546,19 @@ type SpotifyTrack struct {
 }
 
 type SpotifyArtist struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Genres     []string       `json:"genres,omitempty"`
+	Popularity int            `json:"popularity,omitempty"`
+	Images     []SpotifyImage `json:"images,omitempty"`
 }
 
 type SpotifyAlbum struct {
-	ID          string         `json:"id"`
-	Name        string         `json:"name"`
-	ReleaseDate string         `json:"release_date"`
-	Images      []SpotifyImage `json:"images"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	ReleaseDate string          `json:"release_date"`
+	Images      []SpotifyImage  `json:"images"`
+	Artists     []SpotifyArtist `json:"artists,omitempty"`
 }
 
 type SpotifyImage struct {
@@ -400,10 +572,213 @@ type SpotifyExternalIDs struct {
 }
 
 type SpotifySearchResult struct {
-	Tracks SpotifyTracksPaging `json:"tracks"`
+	Tracks  SpotifyTracksPaging  `json:"tracks"`
+	Albums  SpotifyAlbumsPaging  `json:"albums"`
+	Artists SpotifyArtistsPaging `json:"artists"`
 }
 
 type SpotifyTracksPaging struct {
 	Items []SpotifyTrack `json:"items"`
 	Total int            `json:"total"`
 }
+
+type SpotifyAlbumsPaging struct {
+	Items []SpotifyAlbum `json:"items"`
+}
+
+type SpotifyArtistsPaging struct {
+	Items []SpotifyArtist `json:"items"`
+}
+
+// Spotify page sizes for playlist and album track listings (the API maximums)
+const (
+	spotifyPlaylistPageSize = 100
+	spotifyAlbumPageSize    = 50
+)
+
+// GetPlaylistPage fetches one page of a Spotify playlist's or album's tracks.
+// Album track listings omit ISRCs, so their full tracks are fetched in one batch.
+func (s *spotifyService) GetPlaylistPage(ctx context.Context, ref PlaylistRef, offset int) (*PlaylistPage, error) {
+	if !s.IsConfigured() {
+		return nil, s.notConfiguredError("get_playlist")
+	}
+
+	page := &PlaylistPage{}
+	if offset == 0 {
+		var container struct {
+			Name   string         `json:"name"`
+			Images []SpotifyImage `json:"images"`
+		}
+		path := fmt.Sprintf("%s/playlists/%s", spotifyAPIURL, ref.ID)
+		params := map[string]string{"fields": "name,images"}
+		if ref.Kind == PlaylistKindAlbum {
+			path = fmt.Sprintf("%s/albums/%s", spotifyAPIURL, ref.ID)
+			params = nil
+		}
+		if err := s.getJSON(ctx, "get_playlist", path, params, &container); err != nil {
+			return nil, err
+		}
+		page.Name = container.Name
+		if len(container.Images) > 0 {
+			page.ImageURL = container.Images[0].URL
+		}
+	}
+
+	if ref.Kind == PlaylistKindAlbum {
+		return s.getAlbumTracksPage(ctx, ref.ID, offset, page)
+	}
+
+	var result SpotifyPlaylistTracksPaging
+	params := map[string]string{
+		"offset": fmt.Sprintf("%d", offset),
+		"limit":  fmt.Sprintf("%d", spotifyPlaylistPageSize),
+	}
+	if err := s.getJSON(ctx, "get_playlist", fmt.Sprintf("%s/playlists/%s/tracks", spotifyAPIURL, ref.ID), params, &result); err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Items {
+		// Local files have no ID and episodes aren't songs
+		if item.Track == nil || item.Track.ID == "" || item.Track.Type != "track" {
+			continue
+		}
+		page.Tracks = append(page.Tracks, s.convertSpotifyTrack(item.Track))
+	}
+	page.Total = result.Total
+	if result.Next != "" {
+		page.Next = offset + len(result.Items)
+	}
+	return page, nil
+}
+
+// getAlbumTracksPage fills page with one page of an album's tracks
+func (s *spotifyService) getAlbumTracksPage(ctx context.Context, albumID string, offset int, page *PlaylistPage) (*PlaylistPage, error) {
+	var listing struct {
+		Items []SpotifyTrack `json:"items"`
+		Total int            `json:"total"`
+		Next  string         `json:"next"`
+	}
+	params := map[string]string{
+		"offset": fmt.Sprintf("%d", offset),
+		"limit":  fmt.Sprintf("%d", spotifyAlbumPageSize),
+	}
+	if err := s.getJSON(ctx, "get_album", fmt.Sprintf("%s/albums/%s/tracks", spotifyAPIURL, albumID), params, &listing); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(listing.Items))
+	for _, item := range listing.Items {
+		ids = append(ids, item.ID)
+	}
+	if len(ids) > 0 {
+		var full struct {
+			Tracks []*SpotifyTrack `json:"tracks"`
+		}
+		if err := s.getJSON(ctx, "get_album", fmt.Sprintf("%s/tracks", spotifyAPIURL), map[string]string{"ids": strings.Join(ids, ",")}, &full); err != nil {
+			return nil, err
+		}
+		for _, track := range full.Tracks {
+			if track != nil {
+				page.Tracks = append(page.Tracks, s.convertSpotifyTrack(track))
+			}
+		}
+	}
+
+	page.Total = listing.Total
+	if listing.Next != "" {
+		page.Next = offset + len(listing.Items)
+	}
+	return page, nil
+}
+
+// getJSON performs an authenticated GET against the Spotify API and decodes the response into result
+func (s *spotifyService) getJSON(ctx context.Context, operation, url string, params map[string]string, result interface{}) error {
+	if err := s.ensureValidToken(ctx); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	token := s.accessToken
+	s.mu.RUnlock()
+
+	resp, err := s.sendWithRetryAfter(ctx, operation, func() (*resty.Response, error) {
+		return s.client.R().
+			SetContext(ctx).
+			SetAuthToken(token).
+			SetQueryParams(params).
+			SetResult(result).
+			Get(url)
+	})
+	if err != nil {
+		return &PlatformError{
+			Platform:  "spotify",
+			Operation: operation,
+			Message:   "request failed",
+			Err:       err,
+		}
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return &PlatformError{
+			Platform:  "spotify",
+			Operation: operation,
+			Message:   "not found",
+			Category:  ErrorCategoryNoResults,
+		}
+	}
+
+	if resp.StatusCode() == http.StatusTooManyRequests {
+		return s.rateLimitedError(resp)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return &PlatformError{
+			Platform:  "spotify",
+			Operation: operation,
+			Message:   fmt.Sprintf("API returned status %d", resp.StatusCode()),
+			Category:  CategoryForStatus(resp.StatusCode()),
+		}
+	}
+	return nil
+}
+
+// sendWithRetryAfter sends a request built by send and, when Spotify answers
+// 429, waits out its Retry-After and sends it once more. A response still rate
+// limited after that (or when ctx ends during the wait) is returned as is for
+// the caller to report with rateLimitedError.
+func (s *spotifyService) sendWithRetryAfter(ctx context.Context, operation string, send func() (*resty.Response, error)) (*resty.Response, error) {
+	start := time.Now()
+	resp, err := send()
+	observeRestyRequest("spotify", operation, start, resp, err)
+	logRestyRawResponse(ctx, "spotify", operation, resp, err)
+	if err != nil || resp.StatusCode() != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	retryAfter := parseRetryAfter(resp.Header().Get("Retry-After"), time.Now())
+	slog.Warn("Spotify rate limited, retrying", "operation", operation, "retry_after", retryAfter, requestIDAttr(ctx))
+	if err := waitRetryAfter(ctx, retryAfter); err != nil {
+		return resp, nil
+	}
+
+	start = time.Now()
+	resp, err = send()
+	observeRestyRequest("spotify", operation, start, resp, err)
+	logRestyRawResponse(ctx, "spotify", operation, resp, err)
+	return resp, err
+}
+
+// rateLimitedError reports a response Spotify rate limited despite the retry
+func (s *spotifyService) rateLimitedError(resp *resty.Response) error {
+	return rateLimitedError("spotify", parseRetryAfter(resp.Header().Get("Retry-After"), time.Now()))
+}
+
+// SpotifyPlaylistTracksPaging is a page of playlist entries; Track is null for
+// unavailable entries and may be a podcast episode
+type SpotifyPlaylistTracksPaging struct {
+	Items []struct {
+		Track *SpotifyTrack `json:"track"`
+	} `json:"items"`
+	Total int    `json:"total"`
+	Next  string `json:"next"`
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpotifyService_EmptyCredentials(t *testing.T) {
+	service := NewSpotifyService("", "", nil)
+
+	assert.False(t, IsConfigured(service))
+	assert.True(t, IsConfigured(NewSpotifyService("client-id", "client-secret", nil)))
+
+	// URL handling needs no credentials
+	info, err := service.ParseURL("https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh")
+	require.NoError(t, err)
+	assert.Equal(t, "4iV5W9uYEdYUVa79Axb7Rh", info.ExternalID)
+
+	// API calls fail fast with a not_configured error
+	_, err = service.SearchTrack(context.Background(), SearchQuery{Query: "test"})
+	require.Error(t, err)
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, ErrorCategoryNotConfigured, platformErr.Category)
+
+	_, err = service.GetTrackByID(context.Background(), "4iV5W9uYEdYUVa79Axb7Rh")
+	assert.Equal(t, ErrorCategoryNotConfigured, ClassifyError(err))
+	_, err = service.GetTrackByISRC(context.Background(), "USUM71703861")
+	assert.Equal(t, ErrorCategoryNotConfigured, ClassifyError(err))
+	assert.Equal(t, ErrorCategoryNotConfigured, ClassifyError(service.Health(context.Background())))
+}
@@ -62,6 +62,14 @@ func (m *MockSongRepository) FindSimilar(ctx context.Context, song *models.Song,
 	return args.Get(0).([]*models.Song), args.Error(1)
 }
 
+func (m *MockSongRepository) FindByPreviousISRC(ctx context.Context, isrc string) (*models.Song, error) {
+	args := m.Called(ctx, isrc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Song), args.Error(1)
+}
+
 func (m *MockSongRepository) FindByIDPrefix(ctx context.Context, prefix string) (*models.Song, error) {
 	args := m.Called(ctx, prefix)
 	if args.Get(0) == nil {
@@ -80,6 +88,11 @@ func (m *MockSongRepository) SaveMany(ctx context.Context, songs []*models.Song)
 	return args.Error(0)
 }
 
+func (m *MockSongRepository) UpsertMany(ctx context.Context, songs []*models.Song) error {
+	args := m.Called(ctx, songs)
+	return args.Error(0)
+}
+
 func (m *MockSongRepository) DeleteByID(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -122,6 +135,14 @@ func (m *MockPlatformService) GetTrackByID(ctx context.Context, trackID string)
 	return args.Get(0).(*TrackInfo), args.Error(1)
 }
 
+func (m *MockPlatformService) GetTrackByURL(ctx context.Context, url string) (*TrackInfo, error) {
+	args := m.Called(ctx, url)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*TrackInfo), args.Error(1)
+}
+
 func (m *MockPlatformService) SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error) {
 	args := m.Called(ctx, query)
 	return args.Get(0).([]*TrackInfo), args.Error(1)
@@ -177,6 +198,14 @@ func (m *MockPlatformServiceForHandlers) GetTrackByID(ctx context.Context, track
 	return args.Get(0).(*TrackInfo), args.Error(1)
 }
 
+func (m *MockPlatformServiceForHandlers) GetTrackByURL(ctx context.Context, url string) (*TrackInfo, error) {
+	args := m.Called(ctx, url)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*TrackInfo), args.Error(1)
+}
+
 func (m *MockPlatformServiceForHandlers) SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error) {
 	args := m.Called(ctx, query)
 	return args.Get(0).([]*TrackInfo), args.Error(1)
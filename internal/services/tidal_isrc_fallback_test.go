@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tidalFallbackSearchResponse lists the recording under a different ISRC
+const tidalFallbackSearchResponse = `{
+	"data": {"id": "bohemian rhapsody queen", "type": "searchResults"},
+	"included": [
+		{
+			"id": "1234567",
+			"type": "tracks",
+			"attributes": {"title": "Bohemian Rhapsody", "isrc": "GBUM71029605", "duration": 354},
+			"relationships": {"artists": {"data": [{"id": "1", "type": "artists"}]}}
+		},
+		{"id": "1", "type": "artists", "attributes": {"name": "Queen"}}
+	]
+}`
+
+// newTidalFallbackTestService returns a Tidal service whose ISRC filter finds
+// nothing while search finds the recording
+func newTidalFallbackTestService(t *testing.T, fallback bool) *TidalService {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/token":
+			_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+		case r.URL.Path == "/tracks":
+			_, _ = w.Write([]byte(`{"data":[],"included":[]}`))
+		case strings.HasPrefix(r.URL.Path, "/searchResults/"):
+			_, _ = w.Write([]byte(tidalFallbackSearchResponse))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewTidalService(&config.PlatformConfig{
+		Name:         "tidal",
+		AuthMethod:   config.AuthMethodOAuth2,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL,
+		Timeout:      5,
+		ExtraConfig:  map[string]string{config.ExtraConfigISRCSearchFallback: strconv.FormatBool(fallback)},
+	}, nil)
+	require.NoError(t, err)
+	return service
+}
+
+func TestTidalService_GetTrackByISRC_FallsBackToSearch(t *testing.T) {
+	service := newTidalFallbackTestService(t, true)
+	ctx := WithISRCHint(context.Background(), "Bohemian Rhapsody", "Queen")
+
+	track, err := service.GetTrackByISRC(ctx, "GBUM71029604")
+	require.NoError(t, err)
+	require.NotNil(t, track)
+	assert.Equal(t, "1234567", track.ExternalID)
+	assert.Equal(t, "GBUM71029605", track.ISRC)
+	assert.Equal(t, tidalISRCFallbackConfidence, track.LinkConfidence())
+}
+
+func TestTidalService_GetTrackByISRC_NoFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback bool
+		ctx      context.Context
+	}{
+		{"without hint", true, context.Background()},
+		{"hint for another song", true, WithISRCHint(context.Background(), "Under Pressure", "Queen")},
+		{"fallback disabled", false, WithISRCHint(context.Background(), "Bohemian Rhapsody", "Queen")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTidalFallbackTestService(t, tt.fallback)
+
+			track, err := service.GetTrackByISRC(tt.ctx, "GBUM71029604")
+			assert.Error(t, err)
+			assert.Nil(t, track)
+		})
+	}
+}
@@ -163,7 +163,7 @@ func (t *TidalTrack) ToTrackInfo() *TrackInfo {
 	// Convert duration from seconds to milliseconds
 	durationMs := t.Duration * 1000
 
-	return &TrackInfo{
+	return (&TrackInfo{
 		Platform:    "tidal",
 		ExternalID:  t.ID,
 		URL:         buildTidalURL(t.ID),
@@ -177,12 +177,12 @@ func (t *TidalTrack) ToTrackInfo() *TrackInfo {
 		Popularity:  t.Popularity,
 		ImageURL:    imageURL,
 		Available:   t.Available,
-	}
+	}).Sanitize()
 }
 
-// buildTidalURL constructs a Tidal URL from a track ID
+// buildTidalURL constructs the canonical Tidal URL from a track ID
 func buildTidalURL(trackID string) string {
-	return "https://tidal.com/track/" + trackID
+	return "https://tidal.com/browse/track/" + trackID
 }
 
 // TidalAPIError represents an error response from Tidal API
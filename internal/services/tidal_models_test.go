@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTidalURL_Canonical(t *testing.T) {
+	assert.Equal(t, "https://tidal.com/browse/track/77646168", buildTidalURL("77646168"))
+}
+
+func TestParseTidalTrackID(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		expectedID string
+	}{
+		{name: "Browse track URL", url: "https://tidal.com/browse/track/77646168", expectedID: "77646168"},
+		{name: "Short track URL", url: "https://tidal.com/track/77646168", expectedID: "77646168"},
+		{name: "Listen subdomain", url: "https://listen.tidal.com/track/77646168", expectedID: "77646168"},
+		{name: "Trailing u param", url: "https://tidal.com/browse/track/77646168?u", expectedID: "77646168"},
+		{name: "Short URL with u param", url: "https://tidal.com/track/77646168?u", expectedID: "77646168"},
+		{name: "Album URL with trackId", url: "https://tidal.com/browse/album/77646164?play=true&trackId=77646168", expectedID: "77646168"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trackID, err := ParseTidalTrackID(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, trackID)
+
+			platform, platformTrackID, err := ParsePlatformURL(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, "tidal", platform)
+			assert.Equal(t, tt.expectedID, platformTrackID)
+		})
+	}
+}
+
+func TestParseTidalTrackID_RejectsOtherPlatforms(t *testing.T) {
+	_, err := ParseTidalTrackID("https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh")
+
+	var platformErr *PlatformError
+	require.ErrorAs(t, err, &platformErr)
+	assert.Equal(t, "tidal", platformErr.Platform)
+}
+
+func TestTidalURL_RoundTrip(t *testing.T) {
+	service := &TidalService{}
+
+	for _, trackID := range []string{"77646168", "123456789", "1"} {
+		url := service.BuildURL(trackID)
+
+		parsedID, err := ParseTidalTrackID(url)
+		require.NoError(t, err)
+		assert.Equal(t, trackID, parsedID)
+		assert.Equal(t, url, service.BuildURL(parsedID))
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTidalRegionTestService returns a Tidal service backed by a fake API that
+// records the countryCode of every catalog request
+func newTidalRegionTestService(t *testing.T, country string, regions []string) (*TidalService, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var countryCodes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		mu.Lock()
+		countryCodes = append(countryCodes, r.URL.Query().Get("countryCode"))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"data":[],"included":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewTidalService(&config.PlatformConfig{
+		Name:         "tidal",
+		AuthMethod:   config.AuthMethodOAuth2,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL,
+		Country:      country,
+		Regions:      regions,
+		Timeout:      5,
+	}, nil)
+	require.NoError(t, err)
+
+	return service, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), countryCodes...)
+	}
+}
+
+// callAllTidalEndpoints exercises every Tidal catalog call; responses are
+// deliberately empty, so only the recorded request params matter
+func callAllTidalEndpoints(ctx context.Context, service *TidalService) {
+	_, _ = service.GetTrackByID(ctx, "12345")
+	_, _ = service.SearchTrack(ctx, SearchQuery{Query: "test song"})
+	_, _ = service.GetTrackByISRC(ctx, "USUM71703861")
+	_ = service.Health(ctx)
+}
+
+func TestTidalService_CountryCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		regions  []string
+		region   string
+		expected string
+	}{
+		{name: "Default", expected: "US"},
+		{name: "Configured", country: "gb", expected: "GB"},
+		{name: "Invalid configured falls back", country: "Britain", expected: "US"},
+		{name: "Per-request override", country: "GB", region: "DE", expected: "DE"},
+		{name: "Unsupported override falls back", country: "GB", region: "JP", expected: "GB"},
+		{name: "Configured regions allow override", regions: []string{"us", " JP"}, region: "JP", expected: "JP"},
+		{name: "Configured regions reject override", regions: []string{"US", "JP"}, region: "DE", expected: "US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, countryCodes := newTidalRegionTestService(t, tt.country, tt.regions)
+
+			ctx := context.Background()
+			if tt.region != "" {
+				ctx = WithRegion(ctx, tt.region)
+			}
+			callAllTidalEndpoints(ctx, service)
+
+			codes := countryCodes()
+			require.Len(t, codes, 4)
+			for _, code := range codes {
+				assert.Equal(t, tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestNormalizeRegion(t *testing.T) {
+	region, ok := NormalizeRegion(" gb ")
+	assert.True(t, ok)
+	assert.Equal(t, "GB", region)
+
+	for _, invalid := range []string{"", "G", "GBR", "1A", "g-"} {
+		_, ok := NormalizeRegion(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestResolveRegion(t *testing.T) {
+	logs := captureDefaultLog(t)
+	ctx := WithRegion(context.Background(), "JP")
+
+	assert.Equal(t, "US", resolveRegion(context.Background(), "tidal", nil, "US"))
+	assert.Equal(t, "JP", resolveRegion(ctx, "youtube_music", nil, ""), "platforms without a list accept any region")
+	assert.Empty(t, logs.String())
+
+	assert.Equal(t, "US", resolveRegion(ctx, "tidal", nil, "US"))
+	assert.Contains(t, logs.String(), "Unsupported region requested")
+	assert.Contains(t, logs.String(), "region=JP")
+}
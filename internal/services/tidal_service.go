@@ -6,17 +6,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	"songshare/internal/cache"
 	"songshare/internal/config"
+	"songshare/internal/models"
 
 	"github.com/google/jsonapi"
 )
 
+// tidalISRCFallbackConfidence is the link confidence of a track found by
+// searching title and artist after an ISRC lookup missed
+const tidalISRCFallbackConfidence = 0.8
+
 // TidalService implements the PlatformService interface for Tidal
 type TidalService struct {
 	config      *config.PlatformConfig
@@ -24,10 +31,14 @@ type TidalService struct {
 	accessToken string
 	tokenExpiry time.Time
 	tokenMu     sync.RWMutex
+	// tokenCache shares the access token across instances and restarts; nil
+	// fetches a token per instance
+	tokenCache cache.Cache
 }
 
-// NewTidalService creates a new Tidal service instance
-func NewTidalService(cfg *config.PlatformConfig) (*TidalService, error) {
+// NewTidalService creates a new Tidal service instance. The access token is
+// shared through tokenCache when it is not nil.
+func NewTidalService(cfg *config.PlatformConfig, tokenCache cache.Cache) (*TidalService, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("tidal configuration is required")
 	}
@@ -36,13 +47,15 @@ func NewTidalService(cfg *config.PlatformConfig) (*TidalService, error) {
 		return nil, fmt.Errorf("tidal requires OAuth2 authentication, got %s", cfg.AuthMethod)
 	}
 
-	httpClient := &http.Client{
-		Timeout: time.Duration(cfg.Timeout) * time.Second,
+	httpClient, err := newPlatformHTTPClient(cfg, time.Duration(cfg.Timeout)*time.Second)
+	if err != nil {
+		return nil, err
 	}
 
 	service := &TidalService{
 		config:     cfg,
 		httpClient: httpClient,
+		tokenCache: platformCache(tokenCache),
 	}
 
 	// Get initial access token
@@ -78,7 +91,7 @@ func (t *TidalService) ParseURL(url string) (*TrackInfo, error) {
 func (t *TidalService) GetTrackByID(ctx context.Context, trackID string) (*TrackInfo, error) {
 	endpoint := fmt.Sprintf("/tracks/%s", trackID)
 	params := url.Values{
-		"countryCode": {"US"},
+		"countryCode": {t.countryCode(ctx)},
 		"include":     {"artists,albums,providers"},
 	}
 
@@ -115,6 +128,16 @@ func (t *TidalService) GetTrackByID(ctx context.Context, trackID string) (*Track
 	return trackInfo, nil
 }
 
+// GetTrackByURL fetches track details for a Tidal track URL. Unlike ParseURL,
+// which already fetches the track, it honours ctx.
+func (t *TidalService) GetTrackByURL(ctx context.Context, url string) (*TrackInfo, error) {
+	trackID, err := ParseTidalTrackID(url)
+	if err != nil {
+		return nil, err
+	}
+	return t.GetTrackByID(ctx, trackID)
+}
+
 // SearchTrack searches for tracks on Tidal
 func (t *TidalService) SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error) {
 	// Build search query string
@@ -133,8 +156,8 @@ func (t *TidalService) SearchTrack(ctx context.Context, query SearchQuery) ([]*T
 	// Get the search result with included tracks
 	endpoint := fmt.Sprintf("/searchResults/%s", encodedQuery)
 	params := url.Values{
-		"countryCode":    {"US"},
-		"explicitFilter": {"include,exclude"},
+		"countryCode":    {t.countryCode(ctx)},
+		"explicitFilter": {tidalExplicitFilterParam(query.Explicit)},
 		// Be generous with includes to ensure album and artworks are present across API variants
 		"include": {"tracks,tracks.artists,tracks.album,tracks.albums,tracks.album.coverArt,tracks.albums.coverArt,albums,albums.artworks"},
 	}
@@ -189,7 +212,7 @@ func (t *TidalService) SearchTrack(ctx context.Context, query SearchQuery) ([]*T
 		}
 	}
 
-	return trackInfos, nil
+	return FilterExplicit(trackInfos, query.Explicit), nil
 }
 
 // GetTrackByISRC finds a track by its ISRC code
@@ -205,7 +228,7 @@ func (t *TidalService) GetTrackByISRC(ctx context.Context, isrc string) (*TrackI
 	// Search for tracks with the specific ISRC using proper filter format
 	endpoint := "/tracks"
 	params := url.Values{
-		"countryCode":  {"US"},
+		"countryCode":  {t.countryCode(ctx)},
 		"filter[isrc]": {isrc},
 		"include":      {"artists,albums"},
 	}
@@ -232,6 +255,9 @@ func (t *TidalService) GetTrackByISRC(ctx context.Context, isrc string) (*TrackI
 	}
 
 	if len(response.Data) == 0 {
+		if track := t.searchISRCFallback(ctx, isrc); track != nil {
+			return track, nil
+		}
 		return nil, &PlatformError{
 			Platform:  "tidal",
 			Operation: "search_isrc",
@@ -252,12 +278,52 @@ func (t *TidalService) GetTrackByISRC(ctx context.Context, isrc string) (*TrackI
 	return trackInfo, nil
 }
 
+// searchISRCFallback searches the title and artist hinted in ctx when the ISRC
+// filter found nothing, since Tidal sometimes lists a recording under a
+// slightly different ISRC. Only a result matching both title and artist is
+// returned, at reduced confidence.
+func (t *TidalService) searchISRCFallback(ctx context.Context, isrc string) *TrackInfo {
+	hint, ok := ISRCHintFromContext(ctx)
+	if !ok || t.config.ExtraConfig[config.ExtraConfigISRCSearchFallback] != "true" {
+		return nil
+	}
+
+	tracks, err := t.SearchTrack(ctx, SearchQuery{Title: hint.Title, Artist: hint.Artist, Limit: 5})
+	if err != nil {
+		slog.Debug("Tidal ISRC fallback search failed", "isrc", isrc, "error", err)
+		return nil
+	}
+	for _, track := range tracks {
+		if track != nil && matchesISRCHint(track, hint) {
+			slog.Info("Tidal ISRC lookup fell back to search", "isrc", isrc, "track_id", track.ExternalID, "track_isrc", track.ISRC)
+			track.MatchConfidence = tidalISRCFallbackConfidence
+			return track
+		}
+	}
+	return nil
+}
+
+// matchesISRCHint reports whether track has the hinted title and one of the
+// hinted artists, compared after search normalization
+func matchesISRCHint(track *TrackInfo, hint ISRCHint) bool {
+	if models.NormalizeSearchText(track.Title) != models.NormalizeSearchText(hint.Title) {
+		return false
+	}
+	hintArtist := models.NormalizeSearchText(hint.Artist)
+	for _, artist := range track.Artists {
+		if normalized := models.NormalizeSearchText(artist); normalized != "" && strings.Contains(hintArtist, normalized) {
+			return true
+		}
+	}
+	return false
+}
+
 // Health checks if the Tidal service is healthy
 func (t *TidalService) Health(ctx context.Context) error {
 	// Try to make a simple API call to verify connectivity and authentication
 	endpoint := "/tracks"
 	params := url.Values{
-		"countryCode": {"US"},
+		"countryCode": {t.countryCode(ctx)},
 		"page[limit]": {"1"},
 	}
 
@@ -274,25 +340,19 @@ func (t *TidalService) Health(ctx context.Context) error {
 	return nil
 }
 
-// buildSearchQuery constructs a search query string from SearchQuery
-func (t *TidalService) buildSearchQuery(query SearchQuery) string {
-	if query.Query != "" {
-		return query.Query
-	}
-
-	var parts []string
-
-	if query.Title != "" {
-		parts = append(parts, query.Title)
-	}
-	if query.Artist != "" {
-		parts = append(parts, query.Artist)
-	}
-	if query.Album != "" {
-		parts = append(parts, query.Album)
+// countryCode returns the catalog region for a request: the per-request
+// override when Tidal serves it, then the configured country, then the default
+func (t *TidalService) countryCode(ctx context.Context) string {
+	fallback := DefaultRegion
+	if region, ok := NormalizeRegion(t.config.Country); ok {
+		fallback = region
 	}
+	return resolveRegion(ctx, "tidal", t.config.Regions, fallback)
+}
 
-	return strings.Join(parts, " ")
+// buildSearchQuery constructs a search query string using Tidal's configured strategy
+func (t *TidalService) buildSearchQuery(query SearchQuery) string {
+	return BuildSearchQuery(GetSearchQueryStrategy("tidal"), query)
 }
 
 // makeAPIRequest makes an authenticated request to the Tidal API
@@ -353,7 +413,12 @@ func (t *TidalService) makeAPIRequest(ctx context.Context, method, endpoint stri
 		if err := json.Unmarshal(respBody, &apiError); err == nil && len(apiError.Errors) > 0 {
 			return fmt.Errorf("tidal API error: %s - %s", apiError.Errors[0].Title, apiError.Errors[0].Detail)
 		}
-		return fmt.Errorf("tidal API error: %s (status: %d)", string(respBody), resp.StatusCode)
+		return &PlatformError{
+			Platform:  "tidal",
+			Operation: "api_request",
+			Message:   fmt.Sprintf("API returned status %d: %s", resp.StatusCode, string(respBody)),
+			Category:  CategoryForStatus(resp.StatusCode),
+		}
 	}
 
 	// Parse JSON:API response
@@ -379,8 +444,38 @@ func (t *TidalService) ensureValidToken(ctx context.Context) error {
 	return nil
 }
 
-// refreshToken gets a new access token using OAuth2 client credentials flow
+// refreshToken gets a new access token using OAuth2 client credentials flow,
+// reusing a still-valid token from the shared cache when there is one
 func (t *TidalService) refreshToken(ctx context.Context) error {
+	if cached, ok := loadCachedOAuthToken(ctx, t.tokenCache, "tidal", time.Now()); ok {
+		t.tokenMu.Lock()
+		t.accessToken = cached.AccessToken
+		t.tokenExpiry = cached.Expiry
+		t.tokenMu.Unlock()
+		return nil
+	}
+
+	var token cachedOAuthToken
+	err := retryTokenRefresh(ctx, "tidal", func(ctx context.Context) error {
+		var err error
+		token, err = t.requestToken(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	t.tokenMu.Lock()
+	t.accessToken = token.AccessToken
+	t.tokenExpiry = token.Expiry
+	t.tokenMu.Unlock()
+	storeCachedOAuthToken(ctx, t.tokenCache, "tidal", token, time.Now())
+
+	return nil
+}
+
+// requestToken makes one client credentials token request
+func (t *TidalService) requestToken(ctx context.Context) (cachedOAuthToken, error) {
 	data := url.Values{
 		"grant_type":    {"client_credentials"},
 		"client_id":     {t.config.ClientID},
@@ -390,7 +485,7 @@ func (t *TidalService) refreshToken(ctx context.Context) error {
 
 	req, err := http.NewRequestWithContext(ctx, "POST", t.config.TokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return fmt.Errorf("failed to create token request: %w", err)
+		return cachedOAuthToken{}, fmt.Errorf("failed to create token request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -398,17 +493,17 @@ func (t *TidalService) refreshToken(ctx context.Context) error {
 
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("token request failed: %w", err)
+		return cachedOAuthToken{}, fmt.Errorf("token request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read token response: %w", err)
+		return cachedOAuthToken{}, fmt.Errorf("failed to read token response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return cachedOAuthToken{}, &tokenEndpointError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var tokenResp struct {
@@ -419,20 +514,17 @@ func (t *TidalService) refreshToken(ctx context.Context) error {
 	}
 
 	if err := json.Unmarshal(respBody, &tokenResp); err != nil {
-		return fmt.Errorf("failed to parse token response: %w", err)
+		return cachedOAuthToken{}, fmt.Errorf("failed to parse token response: %w", err)
 	}
 
 	if tokenResp.AccessToken == "" {
-		return fmt.Errorf("received empty access token")
+		return cachedOAuthToken{}, fmt.Errorf("received empty access token")
 	}
 
-	// Update token info
-	t.tokenMu.Lock()
-	t.accessToken = tokenResp.AccessToken
-	t.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-	t.tokenMu.Unlock()
-
-	return nil
+	return cachedOAuthToken{
+		AccessToken: tokenResp.AccessToken,
+		Expiry:      time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}, nil
 }
 
 // makeRawAPIRequest makes an API request and returns the raw response body
@@ -461,27 +553,58 @@ func (t *TidalService) makeRawAPIRequest(ctx context.Context, method, endpoint s
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Accept", "application/vnd.api+json")
 
-	// Make request
-	resp, err := t.httpClient.Do(req)
+	resp, respBody, err := t.sendRawAPIRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Read response
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	// Wait out a rate limit once before giving up
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		slog.Warn("Tidal rate limited, retrying", "endpoint", endpoint, "retry_after", retryAfter, requestIDAttr(ctx))
+		if err := waitRetryAfter(ctx, retryAfter); err != nil {
+			return nil, rateLimitedError("tidal", retryAfter)
+		}
+
+		resp, respBody, err = t.sendRawAPIRequest(req.Clone(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, rateLimitedError("tidal", parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+		}
 	}
 
+	logRawResponse(ctx, "tidal", "api_request", resp.StatusCode, respBody)
+
 	// Check for API errors
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("tidal API error: %s (status: %d)", string(respBody), resp.StatusCode)
+		return nil, &PlatformError{
+			Platform:  "tidal",
+			Operation: "api_request",
+			Message:   fmt.Sprintf("API returned status %d: %s", resp.StatusCode, string(respBody)),
+			Category:  CategoryForStatus(resp.StatusCode),
+		}
 	}
 
 	return respBody, nil
 }
 
+// sendRawAPIRequest sends req and reads the whole response body
+func (t *TidalService) sendRawAPIRequest(req *http.Request) (*http.Response, []byte, error) {
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return resp, respBody, nil
+}
+
 // parseSearchResponse parses Tidal search response and extracts tracks
 func (t *TidalService) parseSearchResponse(ctx context.Context, respBody []byte) ([]*TrackInfo, error) {
 	// Try to parse the response as a TidalSearchResult using jsonapi
@@ -562,7 +685,7 @@ func (t *TidalService) parseTrackFromResource(ctx context.Context, resource map[
 	artists := t.parseArtistsFromRelationships(resource, included)
 	albumInfo := t.parseAlbumFromRelationships(ctx, resource, included)
 
-	return &TrackInfo{
+	return (&TrackInfo{
 		Platform:    "tidal",
 		ExternalID:  id,
 		URL:         buildTidalURL(id),
@@ -576,7 +699,7 @@ func (t *TidalService) parseTrackFromResource(ctx context.Context, resource map[
 		Popularity:  int(popularity),
 		ImageURL:    albumInfo.ImageURL,
 		Available:   streamReady,
-	}
+	}).Sanitize()
 }
 
 // AlbumInfo holds basic album information
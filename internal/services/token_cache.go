@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"songshare/internal/cache"
+)
+
+// oauthTokenCacheMargin is how long before expiry a shared token stops being
+// handed out, so no instance picks up a token that expires mid-request
+const oauthTokenCacheMargin = time.Minute
+
+// cachedOAuthToken is an access token shared through the cache so restarted
+// and sibling instances reuse it instead of exchanging credentials again
+type cachedOAuthToken struct {
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// oauthTokenCacheKey returns the cache key of platform's access token
+func oauthTokenCacheKey(platform string) string {
+	return "oauth:" + platform + ":token"
+}
+
+// loadCachedOAuthToken returns platform's shared access token when one is
+// cached and valid for longer than the margin. Cache errors count as a miss.
+func loadCachedOAuthToken(ctx context.Context, c cache.Cache, platform string, now time.Time) (cachedOAuthToken, bool) {
+	if c == nil {
+		return cachedOAuthToken{}, false
+	}
+	data, err := c.Get(ctx, oauthTokenCacheKey(platform))
+	if err != nil || data == nil {
+		return cachedOAuthToken{}, false
+	}
+	var token cachedOAuthToken
+	if err := json.Unmarshal(data, &token); err != nil || token.AccessToken == "" {
+		return cachedOAuthToken{}, false
+	}
+	if !now.Add(oauthTokenCacheMargin).Before(token.Expiry) {
+		return cachedOAuthToken{}, false
+	}
+	return token, true
+}
+
+// storeCachedOAuthToken shares platform's access token until the margin before
+// it expires. Instances refreshing at the same time each store their own
+// token; the last write wins and the others keep using theirs until it
+// expires, which is harmless because client credentials tokens are
+// independent of each other.
+func storeCachedOAuthToken(ctx context.Context, c cache.Cache, platform string, token cachedOAuthToken, now time.Time) {
+	if c == nil {
+		return
+	}
+	ttl := token.Expiry.Sub(now) - oauthTokenCacheMargin
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+	if err := c.Set(ctx, oauthTokenCacheKey(platform), data, ttl); err != nil {
+		slog.Warn("Failed to cache access token", "platform", platform, "error", err)
+	}
+}
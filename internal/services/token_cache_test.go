@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenServer issues a new access token per request, counting them
+func tokenServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "token-" + strconv.Itoa(int(n)),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func cacheOAuthToken(t *testing.T, memory *memoryCache, platform, accessToken string, expiry time.Time) {
+	t.Helper()
+	data, err := json.Marshal(cachedOAuthToken{AccessToken: accessToken, Expiry: expiry})
+	require.NoError(t, err)
+	require.NoError(t, memory.Set(context.Background(), oauthTokenCacheKey(platform), data, time.Hour))
+}
+
+func newTokenCacheTidalService(t *testing.T, server *httptest.Server, memory *memoryCache) *TidalService {
+	t.Helper()
+	service, err := NewTidalService(&config.PlatformConfig{
+		Name:         "tidal",
+		AuthMethod:   config.AuthMethodOAuth2,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL,
+		Timeout:      5,
+	}, memory)
+	require.NoError(t, err)
+	return service
+}
+
+func TestTidalService_SharesTokenThroughCache(t *testing.T) {
+	server, requests := tokenServer(t)
+	memory := newMemoryCache()
+
+	first := newTokenCacheTidalService(t, server, memory)
+	// A restarted or sibling instance reuses the cached token
+	second := newTokenCacheTidalService(t, server, memory)
+
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, "token-1", first.accessToken)
+	assert.Equal(t, "token-1", second.accessToken)
+	assert.Equal(t, first.tokenExpiry.Unix(), second.tokenExpiry.Unix())
+
+	// The cached copy lapses before the token does
+	ttl := memory.ttls[oauthTokenCacheKey("tidal")]
+	assert.InDelta(t, (time.Hour - oauthTokenCacheMargin).Seconds(), ttl.Seconds(), 5)
+}
+
+func TestTidalService_IgnoresNearlyExpiredCachedToken(t *testing.T) {
+	server, requests := tokenServer(t)
+	memory := newMemoryCache()
+	cacheOAuthToken(t, memory, "tidal", "stale", time.Now().Add(30*time.Second))
+
+	service := newTokenCacheTidalService(t, server, memory)
+
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, "token-1", service.accessToken)
+}
+
+func TestSpotifyEnsureValidToken_UsesCachedToken(t *testing.T) {
+	server, requests := tokenServer(t)
+	newService := func(memory *memoryCache) *spotifyService {
+		return &spotifyService{
+			tokenSource: &clientcredentials.Config{ClientID: "id", ClientSecret: "secret", TokenURL: server.URL},
+			cache:       newCountingCache(memory),
+			tokenCache:  memory,
+		}
+	}
+
+	t.Run("Cached token skips the exchange", func(t *testing.T) {
+		memory := newMemoryCache()
+		expiry := time.Now().Add(30 * time.Minute)
+		cacheOAuthToken(t, memory, "spotify", "shared-token", expiry)
+		service := newService(memory)
+
+		require.NoError(t, service.ensureValidToken(context.Background()))
+		assert.Equal(t, "shared-token", service.accessToken)
+		assert.Equal(t, expiry.Unix(), service.tokenExpiry.Unix())
+		assert.Equal(t, int32(0), requests.Load())
+
+		// Token reads don't count as track or search cache lookups
+		hits, misses, _ := service.CacheStats()
+		assert.Zero(t, hits+misses)
+	})
+
+	t.Run("Fetched token is shared", func(t *testing.T) {
+		memory := newMemoryCache()
+		require.NoError(t, newService(memory).ensureValidToken(context.Background()))
+		require.NoError(t, newService(memory).ensureValidToken(context.Background()))
+
+		assert.Equal(t, int32(1), requests.Load())
+		token, ok := loadCachedOAuthToken(context.Background(), memory, "spotify", time.Now())
+		require.True(t, ok)
+		assert.Equal(t, "token-1", token.AccessToken)
+	})
+}
+
+func TestLoadCachedOAuthToken_NilOrCorruptCache(t *testing.T) {
+	_, ok := loadCachedOAuthToken(context.Background(), nil, "spotify", time.Now())
+	assert.False(t, ok)
+
+	memory := newMemoryCache()
+	require.NoError(t, memory.Set(context.Background(), oauthTokenCacheKey("spotify"), []byte("not json"), time.Hour))
+	_, ok = loadCachedOAuthToken(context.Background(), memory, "spotify", time.Now())
+	assert.False(t, ok)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Token exchange retries: a transient token endpoint outage would otherwise
+// fail every request that needs a fresh token
+const (
+	tokenRefreshAttempts  = 3
+	tokenRefreshBaseDelay = 200 * time.Millisecond
+)
+
+// tokenEndpointError is a token endpoint's non-200 answer. The body is kept
+// for diagnostics; error responses carry no token.
+type tokenEndpointError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *tokenEndpointError) Error() string {
+	return fmt.Sprintf("token request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// tokenRefreshBackoff returns the wait before retrying after the given failed
+// attempt (1-based): the base delay doubled per attempt, jittered down by up
+// to half so instances that failed together don't retry together
+func tokenRefreshBackoff(attempt int) time.Duration {
+	delay := tokenRefreshBaseDelay << (attempt - 1)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryTokenRefresh calls fetch until it succeeds, retrying server errors and
+// network failures with backoff up to tokenRefreshAttempts times. Other
+// failures, such as rejected credentials, are returned at once. The final
+// error is an auth PlatformError.
+func retryTokenRefresh(ctx context.Context, platform string, fetch func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; attempt <= tokenRefreshAttempts; attempt++ {
+		if err = fetch(ctx); err == nil {
+			return nil
+		}
+		if !isRetryableTokenError(err) || attempt == tokenRefreshAttempts {
+			break
+		}
+		slog.Warn("Token request failed, retrying", "platform", platform, "attempt", attempt, "error", err)
+		if waitErr := waitRetryAfter(ctx, tokenRefreshBackoff(attempt)); waitErr != nil {
+			err = waitErr
+			break
+		}
+	}
+
+	return &PlatformError{
+		Platform:  platform,
+		Operation: "auth",
+		Message:   "failed to get access token",
+		Category:  tokenErrorCategory(err),
+		Err:       err,
+	}
+}
+
+// tokenErrorCategory tells an outage or timeout at the token endpoint apart
+// from a credentials problem, which is every other failure
+func tokenErrorCategory(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCategoryTimeout
+	case isRetryableTokenError(err):
+		return ErrorCategoryUpstream
+	}
+	return ErrorCategoryAuth
+}
+
+// isRetryableTokenError reports whether a token request failed in a way that
+// may pass on retry: a 5xx answer or a network error. Cancelled requests and
+// 4xx answers (bad credentials) are not retried.
+func isRetryableTokenError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.Response != nil {
+		return retrieveErr.Response.StatusCode >= http.StatusInternalServerError
+	}
+	var endpointErr *tokenEndpointError
+	if errors.As(err, &endpointErr) {
+		return endpointErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
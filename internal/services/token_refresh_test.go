@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// flakyTokenServer answers the first failures requests with status, then
+// issues a token
+func flakyTokenServer(t *testing.T, status int, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			http.Error(w, `{"error":"unavailable"}`, status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "fresh-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTidalServiceForTokenServer(server *httptest.Server) (*TidalService, error) {
+	return NewTidalService(&config.PlatformConfig{
+		Name:         "tidal",
+		AuthMethod:   config.AuthMethodOAuth2,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL,
+		Timeout:      5,
+	}, nil)
+}
+
+func newSpotifyServiceForTokenServer(server *httptest.Server) *spotifyService {
+	return &spotifyService{
+		tokenSource: &clientcredentials.Config{
+			ClientID:     "id",
+			ClientSecret: "secret",
+			TokenURL:     server.URL,
+			AuthStyle:    oauth2.AuthStyleInHeader,
+		},
+		cache: newCountingCache(nil),
+	}
+}
+
+func TestTidalRefreshToken_RetriesServerErrors(t *testing.T) {
+	server, requests := flakyTokenServer(t, http.StatusServiceUnavailable, 2)
+
+	service, err := newTidalServiceForTokenServer(server)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, "fresh-token", service.accessToken)
+}
+
+func TestSpotifyEnsureValidToken_RetriesServerErrors(t *testing.T) {
+	server, requests := flakyTokenServer(t, http.StatusBadGateway, 2)
+	service := newSpotifyServiceForTokenServer(server)
+
+	require.NoError(t, service.ensureValidToken(context.Background()))
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, "fresh-token", service.accessToken)
+}
+
+func TestTokenRefresh_Failures(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		wantRequests int32
+		wantCategory string
+	}{
+		{"Rejected credentials fail fast", http.StatusUnauthorized, 1, ErrorCategoryAuth},
+		{"Bad request fails fast", http.StatusBadRequest, 1, ErrorCategoryAuth},
+		{"Persistent outage gives up", http.StatusServiceUnavailable, tokenRefreshAttempts, ErrorCategoryUpstream},
+	}
+
+	for _, tt := range tests {
+		t.Run("Tidal: "+tt.name, func(t *testing.T) {
+			server, requests := flakyTokenServer(t, tt.status, tokenRefreshAttempts)
+
+			_, err := newTidalServiceForTokenServer(server)
+
+			var platformErr *PlatformError
+			require.True(t, errors.As(err, &platformErr))
+			assert.Equal(t, "auth", platformErr.Operation)
+			assert.Equal(t, tt.wantCategory, platformErr.Category)
+			assert.Equal(t, tt.wantRequests, requests.Load())
+		})
+
+		t.Run("Spotify: "+tt.name, func(t *testing.T) {
+			server, requests := flakyTokenServer(t, tt.status, tokenRefreshAttempts)
+
+			err := newSpotifyServiceForTokenServer(server).ensureValidToken(context.Background())
+
+			var platformErr *PlatformError
+			require.True(t, errors.As(err, &platformErr))
+			assert.Equal(t, "auth", platformErr.Operation)
+			assert.Equal(t, tt.wantCategory, platformErr.Category)
+			assert.Equal(t, tt.wantRequests, requests.Load())
+		})
+	}
+}
+
+func TestRetryTokenRefresh_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retryTokenRefresh(ctx, "tidal", func(ctx context.Context) error {
+		calls++
+		cancel()
+		return &tokenEndpointError{StatusCode: http.StatusServiceUnavailable}
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTokenRefreshBackoff_JitterBounds(t *testing.T) {
+	for attempt := 1; attempt < tokenRefreshAttempts; attempt++ {
+		full := tokenRefreshBaseDelay << (attempt - 1)
+		for range 20 {
+			delay := tokenRefreshBackoff(attempt)
+			assert.GreaterOrEqual(t, delay, full/2)
+			assert.LessOrEqual(t, delay, full)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DefaultTrackingParams are share-link query parameters that never identify a
+// track. Entries ending in "*" match any parameter with that prefix.
+var DefaultTrackingParams = []string{"si", "utm_*", "fbclid", "gclid", "igshid", "context", "nd", "ls"}
+
+// StripTrackingParams removes tracking query parameters and the fragment from a
+// platform URL, so share-link variants of the same track canonicalize to one URL.
+// Parameters that identify content (e.g. Apple Music's "i", Tidal's "trackId")
+// are kept. Unparseable URLs are returned unchanged.
+func StripTrackingParams(rawURL string, params []string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+
+	query := parsed.Query()
+	for key := range query {
+		if isTrackingParam(key, params) {
+			query.Del(key)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	return parsed.String()
+}
+
+// isTrackingParam reports whether key matches one of the tracking parameter patterns
+func isTrackingParam(key string, params []string) bool {
+	key = strings.ToLower(key)
+	for _, param := range params {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == param {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripTrackingParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{
+			name:     "spotify share link",
+			url:      "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?si=abc123",
+			expected: "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		},
+		{
+			name:     "utm prefix and fragment",
+			url:      "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?utm_source=copy-link&UTM_Medium=x#top",
+			expected: "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		},
+		{
+			name:     "apple music keeps track param",
+			url:      "https://music.apple.com/us/album/bohemian-rhapsody/1440806041?i=1440806053&ls",
+			expected: "https://music.apple.com/us/album/bohemian-rhapsody/1440806041?i=1440806053",
+		},
+		{
+			name:     "tidal keeps trackId",
+			url:      "https://tidal.com/browse/album/77646164?play=true&trackId=77646168&fbclid=x",
+			expected: "https://tidal.com/browse/album/77646164?play=true&trackId=77646168",
+		},
+		{
+			name:     "no query",
+			url:      "https://tidal.com/track/77646168",
+			expected: "https://tidal.com/track/77646168",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StripTrackingParams(tt.url, DefaultTrackingParams))
+		})
+	}
+}
+
+func TestStripTrackingParams_CustomList(t *testing.T) {
+	url := "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?si=abc&ref=home"
+	assert.Equal(t, "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?si=abc",
+		StripTrackingParams(url, []string{"ref"}))
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"songshare/internal/config"
+)
+
+// youtubeMusicDefaultBaseURL is the YouTube Data API used for YouTube Music lookups
+const youtubeMusicDefaultBaseURL = "https://www.googleapis.com/youtube/v3"
+
+// youtubeMusicCategoryID is YouTube's "Music" video category
+const youtubeMusicCategoryID = "10"
+
+// youtubeMusicURLPattern matches music.youtube.com watch links; the video ID is the track ID
+const youtubeMusicURLPattern = `(?:https?://)?music\.youtube\.com/watch\?(?:[^#]*&)?v=([A-Za-z0-9_-]{11})`
+
+func init() {
+	if err := RegisterURLPattern("youtube_music", youtubeMusicURLPattern, 1, "YouTube Music watch URLs", []string{
+		"https://music.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RDAMVMdQw4w9WgXcQ",
+		"music.youtube.com/watch?feature=share&v=dQw4w9WgXcQ",
+	}); err != nil {
+		panic(fmt.Sprintf("invalid YouTube Music URL pattern: %v", err))
+	}
+}
+
+// YouTubeMusicService implements the PlatformService interface for YouTube
+// Music, backed by the YouTube Data API. The API exposes no ISRCs, so songs
+// are only found by video ID or search.
+type YouTubeMusicService struct {
+	config     *config.PlatformConfig
+	httpClient *http.Client
+}
+
+// NewYouTubeMusicService creates a new YouTube Music service instance
+func NewYouTubeMusicService(cfg *config.PlatformConfig) (*YouTubeMusicService, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("youtube music configuration is required")
+	}
+
+	if cfg.AuthMethod != config.AuthMethodAPIKey {
+		return nil, fmt.Errorf("youtube music requires API key authentication, got %s", cfg.AuthMethod)
+	}
+
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("youtube music API key is required")
+	}
+
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	httpClient, err := newPlatformHTTPClient(cfg, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &YouTubeMusicService{
+		config:     cfg,
+		httpClient: httpClient,
+	}, nil
+}
+
+// GetPlatformName returns the platform name
+func (y *YouTubeMusicService) GetPlatformName() string {
+	return "youtube_music"
+}
+
+// BuildURL constructs a YouTube Music URL from a video ID
+func (y *YouTubeMusicService) BuildURL(trackID string) string {
+	return fmt.Sprintf("https://music.youtube.com/watch?v=%s", trackID)
+}
+
+// ParseURL extracts the video ID from a YouTube Music URL
+func (y *YouTubeMusicService) ParseURL(rawURL string) (*TrackInfo, error) {
+	matches := regexp.MustCompile(youtubeMusicURLPattern).FindStringSubmatch(rawURL)
+	if len(matches) < 2 {
+		return nil, &PlatformError{
+			Platform:  "youtube_music",
+			Operation: "parse_url",
+			Message:   "invalid YouTube Music URL format",
+			URL:       rawURL,
+		}
+	}
+
+	return &TrackInfo{
+		Platform:   "youtube_music",
+		ExternalID: matches[1],
+		URL:        y.BuildURL(matches[1]),
+		Available:  true,
+	}, nil
+}
+
+// GetTrackByID fetches a video's details by its ID
+func (y *YouTubeMusicService) GetTrackByID(ctx context.Context, trackID string) (*TrackInfo, error) {
+	params := url.Values{
+		"part": {"snippet,contentDetails"},
+		"id":   {trackID},
+	}
+
+	var response youtubeVideoListResponse
+	if err := y.get(ctx, "get_track", "/videos", params, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Items) == 0 {
+		return nil, &PlatformError{
+			Platform:  "youtube_music",
+			Operation: "get_track",
+			Message:   "track not found",
+			Category:  ErrorCategoryNoResults,
+		}
+	}
+
+	return y.convertVideo(response.Items[0].ID, response.Items[0].Snippet, response.Items[0].ContentDetails.Duration), nil
+}
+
+// GetTrackByURL fetches track details for a YouTube Music track URL
+func (y *YouTubeMusicService) GetTrackByURL(ctx context.Context, url string) (*TrackInfo, error) {
+	return trackByURL(ctx, y, url)
+}
+
+// SearchTrack searches music videos on YouTube
+func (y *YouTubeMusicService) SearchTrack(ctx context.Context, query SearchQuery) ([]*TrackInfo, error) {
+	searchQuery := BuildSearchQuery(GetSearchQueryStrategy("youtube_music"), query)
+	if searchQuery == "" {
+		return nil, &PlatformError{
+			Platform:  "youtube_music",
+			Operation: "search",
+			Message:   "empty search query",
+		}
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50 // YouTube Data API limit
+	}
+
+	params := url.Values{
+		"part":            {"snippet"},
+		"type":            {"video"},
+		"videoCategoryId": {youtubeMusicCategoryID},
+		"q":               {searchQuery},
+		"maxResults":      {strconv.Itoa(limit)},
+	}
+	// Without a supported region the API's own default applies
+	if region := resolveRegion(ctx, "youtube_music", y.config.Regions, ""); region != "" {
+		params.Set("regionCode", region)
+	}
+
+	var response youtubeSearchResponse
+	if err := y.get(ctx, "search", "/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*TrackInfo, 0, len(response.Items))
+	for _, item := range response.Items {
+		if item.ID.VideoID == "" {
+			continue
+		}
+		tracks = append(tracks, y.convertVideo(item.ID.VideoID, item.Snippet, ""))
+	}
+
+	// The API has no explicit-content filter, so results are post-filtered
+	return FilterExplicit(tracks, query.Explicit), nil
+}
+
+// GetTrackByISRC is unsupported: the YouTube Data API doesn't expose ISRCs
+func (y *YouTubeMusicService) GetTrackByISRC(ctx context.Context, isrc string) (*TrackInfo, error) {
+	return nil, &PlatformError{
+		Platform:  "youtube_music",
+		Operation: "search_isrc",
+		Message:   "ISRC lookup is not supported by YouTube Music",
+		Category:  ErrorCategoryNoResults,
+	}
+}
+
+// Health checks that the API accepts our key
+func (y *YouTubeMusicService) Health(ctx context.Context) error {
+	params := url.Values{
+		"part":            {"id"},
+		"chart":           {"mostPopular"},
+		"videoCategoryId": {youtubeMusicCategoryID},
+		"maxResults":      {"1"},
+	}
+	var response youtubeVideoListResponse
+	return y.get(ctx, "health", "/videos", params, &response)
+}
+
+// get performs a keyed GET against the YouTube Data API and decodes the response into result
+func (y *YouTubeMusicService) get(ctx context.Context, operation, endpoint string, params url.Values, result interface{}) error {
+	baseURL := y.config.BaseURL
+	if baseURL == "" {
+		baseURL = youtubeMusicDefaultBaseURL
+	}
+	params.Set("key", y.config.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := y.httpClient.Do(req)
+	if err != nil {
+		return &PlatformError{
+			Platform:  "youtube_music",
+			Operation: operation,
+			Message:   "request failed",
+			Err:       err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &PlatformError{
+			Platform:  "youtube_music",
+			Operation: operation,
+			Message:   fmt.Sprintf("API returned status %d", resp.StatusCode),
+			Category:  CategoryForStatus(resp.StatusCode),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", operation, err)
+	}
+	return nil
+}
+
+// convertVideo converts a video's snippet to TrackInfo. Auto-generated
+// "Artist - Topic" channels carry the artist name in the channel title.
+func (y *YouTubeMusicService) convertVideo(videoID string, snippet youtubeSnippet, isoDuration string) *TrackInfo {
+	artist := strings.TrimSuffix(snippet.ChannelTitle, " - Topic")
+
+	releaseDate := ""
+	if len(snippet.PublishedAt) >= len("2006-01-02") {
+		releaseDate = snippet.PublishedAt[:len("2006-01-02")]
+	}
+
+	return (&TrackInfo{
+		Platform:    "youtube_music",
+		ExternalID:  videoID,
+		URL:         y.BuildURL(videoID),
+		Title:       snippet.Title,
+		Artists:     []string{artist},
+		Duration:    parseISO8601Duration(isoDuration),
+		ReleaseDate: releaseDate,
+		ImageURL:    snippet.Thumbnails.best(),
+		Available:   true,
+	}).Sanitize()
+}
+
+// iso8601DurationPattern matches the PT#H#M#S durations the API returns
+var iso8601DurationPattern = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// parseISO8601Duration converts a duration like "PT3M33S" to milliseconds,
+// returning 0 when it can't be parsed
+func parseISO8601Duration(duration string) int {
+	matches := iso8601DurationPattern.FindStringSubmatch(duration)
+	if matches == nil {
+		return 0
+	}
+	total := 0
+	for i, unit := range []int{3600, 60, 1} {
+		if matches[i+1] == "" {
+			continue
+		}
+		value, _ := strconv.Atoi(matches[i+1])
+		total += value * unit
+	}
+	return total * 1000
+}
+
+// YouTube Data API response structures
+type youtubeVideoListResponse struct {
+	Items []struct {
+		ID             string         `json:"id"`
+		Snippet        youtubeSnippet `json:"snippet"`
+		ContentDetails struct {
+			Duration string `json:"duration"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+type youtubeSearchResponse struct {
+	Items []struct {
+		ID struct {
+			VideoID string `json:"videoId"`
+		} `json:"id"`
+		Snippet youtubeSnippet `json:"snippet"`
+	} `json:"items"`
+}
+
+type youtubeSnippet struct {
+	Title        string            `json:"title"`
+	ChannelTitle string            `json:"channelTitle"`
+	PublishedAt  string            `json:"publishedAt"`
+	Thumbnails   youtubeThumbnails `json:"thumbnails"`
+}
+
+type youtubeThumbnails map[string]struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// best returns the largest available thumbnail URL
+func (t youtubeThumbnails) best() string {
+	for _, size := range []string{"maxres", "high", "medium", "default"} {
+		if thumbnail, ok := t[size]; ok && thumbnail.URL != "" {
+			return thumbnail.URL
+		}
+	}
+	return ""
+}
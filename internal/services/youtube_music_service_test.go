@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"songshare/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newYouTubeMusicTestService returns a YouTube Music service backed by a fake
+// Data API serving the given endpoint bodies
+func newYouTubeMusicTestService(t *testing.T, responses map[string]string) (*YouTubeMusicService, *http.Request) {
+	t.Helper()
+
+	var lastRequest http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastRequest = *r
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewYouTubeMusicService(&config.PlatformConfig{
+		Name:       "youtube_music",
+		AuthMethod: config.AuthMethodAPIKey,
+		APIKey:     "test-key",
+		BaseURL:    server.URL,
+		Timeout:    5,
+	})
+	require.NoError(t, err)
+	return service, &lastRequest
+}
+
+func TestNewYouTubeMusicService_RequiresAPIKey(t *testing.T) {
+	_, err := NewYouTubeMusicService(&config.PlatformConfig{Name: "youtube_music", AuthMethod: config.AuthMethodAPIKey})
+	assert.Error(t, err)
+
+	_, err = NewYouTubeMusicService(nil)
+	assert.Error(t, err)
+}
+
+func TestYouTubeMusicURLPattern(t *testing.T) {
+	platform, trackID, err := ParsePlatformURL("https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RDAMVMdQw4w9WgXcQ")
+	require.NoError(t, err)
+	assert.Equal(t, "youtube_music", platform)
+	assert.Equal(t, "dQw4w9WgXcQ", trackID)
+}
+
+func TestYouTubeMusicService_GetTrackByID(t *testing.T) {
+	service, request := newYouTubeMusicTestService(t, map[string]string{
+		"/videos": `{"items":[{"id":"dQw4w9WgXcQ","snippet":{"title":"Never Gonna Give You Up","channelTitle":"Rick Astley - Topic","publishedAt":"2009-10-25T06:57:33Z","thumbnails":{"default":{"url":"https://i.ytimg.com/default.jpg"},"high":{"url":"https://i.ytimg.com/high.jpg"}}},"contentDetails":{"duration":"PT3M33S"}}]}`,
+	})
+
+	track, err := service.GetTrackByID(context.Background(), "dQw4w9WgXcQ")
+	require.NoError(t, err)
+
+	assert.Equal(t, "youtube_music", track.Platform)
+	assert.Equal(t, "dQw4w9WgXcQ", track.ExternalID)
+	assert.Equal(t, "https://music.youtube.com/watch?v=dQw4w9WgXcQ", track.URL)
+	assert.Equal(t, "Never Gonna Give You Up", track.Title)
+	assert.Equal(t, []string{"Rick Astley"}, track.Artists)
+	assert.Equal(t, 213000, track.Duration)
+	assert.Equal(t, "2009-10-25", track.ReleaseDate)
+	assert.Equal(t, "https://i.ytimg.com/high.jpg", track.ImageURL)
+	assert.Empty(t, track.ISRC)
+
+	assert.Equal(t, "test-key", request.URL.Query().Get("key"))
+	assert.Equal(t, "dQw4w9WgXcQ", request.URL.Query().Get("id"))
+}
+
+func TestYouTubeMusicService_GetTrackByID_NotFound(t *testing.T) {
+	service, _ := newYouTubeMusicTestService(t, map[string]string{"/videos": `{"items":[]}`})
+
+	_, err := service.GetTrackByID(context.Background(), "missing0000")
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, ErrorCategoryNoResults, platformErr.Category)
+}
+
+func TestYouTubeMusicService_SearchTrack(t *testing.T) {
+	service, request := newYouTubeMusicTestService(t, map[string]string{
+		"/search": `{"items":[{"id":{"videoId":"dQw4w9WgXcQ"},"snippet":{"title":"Never Gonna Give You Up","channelTitle":"Rick Astley"}},{"id":{"channelId":"UC123"},"snippet":{"title":"A channel"}}]}`,
+	})
+
+	tracks, err := service.SearchTrack(context.Background(), SearchQuery{Title: "Never Gonna Give You Up", Artist: "Rick Astley", Limit: 5})
+	require.NoError(t, err)
+
+	require.Len(t, tracks, 1, "non-video results are skipped")
+	assert.Equal(t, "dQw4w9WgXcQ", tracks[0].ExternalID)
+	assert.Equal(t, []string{"Rick Astley"}, tracks[0].Artists)
+
+	query := request.URL.Query()
+	assert.Equal(t, "video", query.Get("type"))
+	assert.Equal(t, youtubeMusicCategoryID, query.Get("videoCategoryId"))
+	assert.Equal(t, "5", query.Get("maxResults"))
+	assert.Contains(t, query.Get("q"), "Rick Astley")
+}
+
+func TestYouTubeMusicService_SearchTrack_APIError(t *testing.T) {
+	service, _ := newYouTubeMusicTestService(t, map[string]string{})
+
+	_, err := service.SearchTrack(context.Background(), SearchQuery{Query: "anything"})
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, "search", platformErr.Operation)
+}
+
+func TestYouTubeMusicService_GetTrackByISRC_Unsupported(t *testing.T) {
+	service, _ := newYouTubeMusicTestService(t, map[string]string{})
+
+	track, err := service.GetTrackByISRC(context.Background(), "USRC17607839")
+	assert.Nil(t, track)
+
+	var platformErr *PlatformError
+	require.True(t, errors.As(err, &platformErr))
+	assert.Equal(t, "youtube_music", platformErr.Platform)
+	assert.Equal(t, "search_isrc", platformErr.Operation)
+	assert.Contains(t, platformErr.Message, "ISRC lookup is not supported")
+}
+
+func TestParseISO8601Duration(t *testing.T) {
+	assert.Equal(t, 213000, parseISO8601Duration("PT3M33S"))
+	assert.Equal(t, 3723000, parseISO8601Duration("PT1H2M3S"))
+	assert.Equal(t, 45000, parseISO8601Duration("PT45S"))
+	assert.Equal(t, 0, parseISO8601Duration("P1D"))
+	assert.Equal(t, 0, parseISO8601Duration(""))
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 
 	"songshare/internal/models"
+	"songshare/internal/repositories"
 	"songshare/internal/services"
 
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockSongRepository is a mock implementation of SongRepository for testing
@@ -37,6 +39,14 @@ func (m *MockSongRepository) FindByISRC(ctx context.Context, isrc string) (*mode
 	return args.Get(0).(*models.Song), args.Error(1)
 }
 
+func (m *MockSongRepository) FindByISRCBatch(ctx context.Context, isrcs []string) (map[string]*models.Song, error) {
+	args := m.Called(ctx, isrcs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.Song), args.Error(1)
+}
+
 func (m *MockSongRepository) FindByTitleArtist(ctx context.Context, title, artist string) ([]*models.Song, error) {
 	args := m.Called(ctx, title, artist)
 	return args.Get(0).([]*models.Song), args.Error(1)
@@ -60,6 +70,14 @@ func (m *MockSongRepository) FindSimilar(ctx context.Context, song *models.Song,
 	return args.Get(0).([]*models.Song), args.Error(1)
 }
 
+func (m *MockSongRepository) FindByPreviousISRC(ctx context.Context, isrc string) (*models.Song, error) {
+	args := m.Called(ctx, isrc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Song), args.Error(1)
+}
+
 func (m *MockSongRepository) FindByIDPrefix(ctx context.Context, prefix string) (*models.Song, error) {
 	args := m.Called(ctx, prefix)
 	if args.Get(0) == nil {
@@ -78,6 +96,11 @@ func (m *MockSongRepository) SaveMany(ctx context.Context, songs []*models.Song)
 	return args.Error(0)
 }
 
+func (m *MockSongRepository) UpsertMany(ctx context.Context, songs []*models.Song) error {
+	args := m.Called(ctx, songs)
+	return args.Error(0)
+}
+
 func (m *MockSongRepository) DeleteByID(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -88,6 +111,84 @@ func (m *MockSongRepository) Count(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockSongRepository) FindRecentAfter(ctx context.Context, cursor *repositories.RecentCursor, limit int) ([]*models.Song, error) {
+	args := m.Called(ctx, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Song), args.Error(1)
+}
+
+func (m *MockSongRepository) FindMissingAlbumArt(ctx context.Context, afterID string, limit int) ([]*models.Song, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Song), args.Error(1)
+}
+
+func (m *MockSongRepository) FindMissingPlatform(ctx context.Context, platform, afterID string, limit int) ([]*models.Song, error) {
+	args := m.Called(ctx, platform, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Song), args.Error(1)
+}
+
+func (m *MockSongRepository) FindDuplicateISRCs(ctx context.Context) (map[string][]*models.Song, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]*models.Song), args.Error(1)
+}
+
+func (m *MockSongRepository) NormalizeISRCs(ctx context.Context) (int64, int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockSongRepository) FindAfterID(ctx context.Context, afterID string, limit int) ([]*models.Song, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Song), args.Error(1)
+}
+
+func (m *MockSongRepository) UpdateSearchText(ctx context.Context, songs []*models.Song) (int64, error) {
+	args := m.Called(ctx, songs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSongRepository) EnsureTextIndex(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockSongRepository) IncrementViews(ctx context.Context, counts map[primitive.ObjectID]int64) error {
+	args := m.Called(ctx, counts)
+	return args.Error(0)
+}
+
+// MockCollectionRepository is a mock implementation of CollectionRepository for testing
+type MockCollectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCollectionRepository) Save(ctx context.Context, collection *models.Collection) error {
+	args := m.Called(ctx, collection)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) FindByID(ctx context.Context, id string) (*models.Collection, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
 // MockPlatformService is a mock implementation of PlatformService for testing
 type MockPlatformService struct {
 	mock.Mock
@@ -120,6 +221,14 @@ func (m *MockPlatformService) GetTrackByID(ctx context.Context, trackID string)
 	return args.Get(0).(*services.TrackInfo), args.Error(1)
 }
 
+func (m *MockPlatformService) GetTrackByURL(ctx context.Context, url string) (*services.TrackInfo, error) {
+	args := m.Called(ctx, url)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TrackInfo), args.Error(1)
+}
+
 func (m *MockPlatformService) SearchTrack(ctx context.Context, query services.SearchQuery) ([]*services.TrackInfo, error) {
 	args := m.Called(ctx, query)
 	return args.Get(0).([]*services.TrackInfo), args.Error(1)
@@ -177,6 +286,12 @@ func ExpectSongRepositoryFindByISRC(mockRepo *MockSongRepository, isrc string, s
 	mockRepo.On("FindByISRC", mock.Anything, isrc).Return(song, err)
 }
 
+// ExpectSongRepositoryFindByTitleArtist sets up expectation for FindByTitleArtist
+// with any title and artist, returning songs as the candidates
+func ExpectSongRepositoryFindByTitleArtist(mockRepo *MockSongRepository, songs ...*models.Song) {
+	mockRepo.On("FindByTitleArtist", mock.Anything, mock.Anything, mock.Anything).Return(append([]*models.Song{}, songs...), nil)
+}
+
 // ExpectSongRepositorySave sets up expectation for Save
 func ExpectSongRepositorySave(mockRepo *MockSongRepository, song *models.Song, err error) {
 	mockRepo.On("Save", mock.Anything, song).Return(err)
@@ -191,3 +306,8 @@ func ExpectPlatformServiceParseURL(mock *MockPlatformService, url string, track
 func ExpectPlatformServiceGetTrackByID(mockService *MockPlatformService, trackID string, track *services.TrackInfo, err error) {
 	mockService.On("GetTrackByID", mock.Anything, trackID).Return(track, err)
 }
+
+// ExpectPlatformServiceGetTrackByURL sets up expectation for GetTrackByURL
+func ExpectPlatformServiceGetTrackByURL(mockService *MockPlatformService, url string, track *services.TrackInfo, err error) {
+	mockService.On("GetTrackByURL", mock.Anything, url).Return(track, err)
+}